@@ -1,12 +1,20 @@
 package entities
 
-import "time"
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
 
 type Account struct {
-	ID              int64     `gorm:"primaryKey"`
-	Amount          float32   `gorm:"not null"`
-	AvailableOrders int64     `gorm:"not null"`
-	Currency        string    `gorm:"not null"`
-	CreatedAt       time.Time `gorm:"autoCreateTime"`
-	UpdatedAt       time.Time `gorm:"autoUpdateTime"`
+	ID              int64           `gorm:"primaryKey"`
+	Name            string          `gorm:"not null;default:''"`
+	Amount          decimal.Decimal `gorm:"type:numeric(32,12);not null"`
+	AvailableOrders int64           `gorm:"not null"`
+	Currency        string          `gorm:"not null"`
+	// FeeModel names the fee model of FEES.MODELS the account is charged
+	// with, empty for the default one.
+	FeeModel  string    `gorm:"not null;default:''"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime"`
 }
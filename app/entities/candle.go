@@ -0,0 +1,17 @@
+package entities
+
+import "time"
+
+// Candle is a kline stored locally, identified by its symbol, interval and
+// open time so fetching the same kline twice updates it in place.
+type Candle struct {
+	Symbol    string    `gorm:"primaryKey;type:varchar(20)"`
+	Interval  string    `gorm:"primaryKey;type:varchar(5)"`
+	OpenTime  time.Time `gorm:"primaryKey"`
+	CloseTime time.Time `gorm:"not null"`
+	Open      float64   `gorm:"not null"`
+	High      float64   `gorm:"not null"`
+	Low       float64   `gorm:"not null"`
+	Close     float64   `gorm:"not null"`
+	Volume    float64   `gorm:"not null"`
+}
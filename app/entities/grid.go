@@ -0,0 +1,31 @@
+package entities
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+type GridLevelType string
+
+const (
+	GridBuy  GridLevelType = "buy"
+	GridSell GridLevelType = "sell"
+)
+
+type GridLevel struct {
+	Type  GridLevelType
+	Price float64
+}
+
+// GridState is the grid built by a strategy for a symbol. ConfigHash is the
+// hash of the configuration the grid was built with, a grid whose hash no
+// longer matches the strategy is rebuilt.
+type GridState struct {
+	StrategyID uint                           `gorm:"primaryKey"`
+	Symbol     string                         `gorm:"primaryKey;type:varchar(20)"`
+	ConfigHash string                         `gorm:"type:varchar(64);not null"`
+	Levels     datatypes.JSONSlice[GridLevel] `gorm:"type:jsonb"`
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
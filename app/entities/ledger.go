@@ -0,0 +1,33 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type LedgerEntryType string
+
+const (
+	Deposit      LedgerEntryType = "deposit"
+	Withdrawal   LedgerEntryType = "withdrawal"
+	OrderReserve LedgerEntryType = "order_reserve"
+	OrderRelease LedgerEntryType = "order_release"
+	RealizedPnL  LedgerEntryType = "realized_pnl"
+	Fee          LedgerEntryType = "fee"
+	Funding      LedgerEntryType = "funding"
+)
+
+// LedgerEntry is a cash movement of an account. Entries are only appended,
+// Amount is signed and Balance is the balance of the account after it.
+type LedgerEntry struct {
+	ID          uint            `gorm:"primaryKey"`
+	AccountID   int64           `gorm:"not null;index:idx_ledger_account_created"`
+	Type        LedgerEntryType `gorm:"type:varchar(20);not null"`
+	Amount      decimal.Decimal `gorm:"type:numeric(32,12);not null"`
+	Balance     decimal.Decimal `gorm:"type:numeric(32,12);not null"`
+	SignalID    *uint
+	OrderID     *uint
+	Description string
+	CreatedAt   time.Time `gorm:"not null;index:idx_ledger_account_created"`
+}
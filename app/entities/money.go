@@ -0,0 +1,10 @@
+package entities
+
+import "github.com/shopspring/decimal"
+
+// Prices, quantities and amounts are decimals stored in numeric columns, so
+// balances don't drift with float rounding. They are still written as JSON
+// numbers, as they were when they were floats.
+func init() {
+	decimal.MarshalJSONWithoutQuotes = true
+}
@@ -0,0 +1,47 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type RiskRule string
+
+const (
+	DailyLossRule RiskRule = "daily_loss"
+	DrawdownRule  RiskRule = "drawdown"
+)
+
+type RiskEventType string
+
+const (
+	RiskTrip  RiskEventType = "trip"
+	RiskReset RiskEventType = "reset"
+)
+
+// RiskState is the single row holding the circuit breaker of the risk
+// manager and the highest equity seen, drawdowns are measured from it.
+type RiskState struct {
+	ID        uint     `gorm:"primaryKey"`
+	Halted    bool     `gorm:"not null;default:false"`
+	Rule      RiskRule `gorm:"type:varchar(20);not null;default:''"`
+	Reason    string   `gorm:"not null;default:''"`
+	TrippedAt *time.Time
+	// ResetAt is when the breaker was last reset by hand, the daily loss of
+	// that day is counted from it.
+	ResetAt    *time.Time
+	EquityPeak decimal.Decimal `gorm:"type:numeric(32,12);not null;default:0"`
+	UpdatedAt  time.Time
+}
+
+// RiskEvent records a breaker tripping or being reset, with the equity at
+// that moment.
+type RiskEvent struct {
+	ID        uint            `gorm:"primaryKey"`
+	Type      RiskEventType   `gorm:"type:varchar(10);not null"`
+	Rule      RiskRule        `gorm:"type:varchar(20);not null;default:''"`
+	Reason    string          `gorm:"not null;default:''"`
+	Equity    decimal.Decimal `gorm:"type:numeric(32,12);not null"`
+	CreatedAt time.Time       `gorm:"not null;index"`
+}
@@ -2,15 +2,28 @@ package entities
 
 import (
 	"time"
+
+	"github.com/shopspring/decimal"
 )
 
 type SignalStatus string
 
+// Signals are written pending before their entry order is placed and
+// closing before their exit order is, so an order that went through is
+// never lost with a rolled back transaction nor placed twice. Entries the
+// exchange refused end failed.
 const (
-	Open   SignalStatus = "open"
-	Closed SignalStatus = "closed"
+	Pending SignalStatus = "pending"
+	Open    SignalStatus = "open"
+	Closing SignalStatus = "closing"
+	Closed  SignalStatus = "closed"
+	Failed  SignalStatus = "failed"
 )
 
+// ActiveStatuses are the statuses of the signals holding a position or a
+// reservation on their account.
+var ActiveStatuses = []SignalStatus{Pending, Open, Closing}
+
 type MarginType string
 
 const (
@@ -18,32 +31,73 @@ const (
 	Cross    MarginType = "cross"
 )
 
+// PositionSide is the direction of a signal, shorts profit from a falling
+// price and only trade on the futures market.
+type PositionSide string
+
+const (
+	Long  PositionSide = "long"
+	Short PositionSide = "short"
+)
+
+// Market is where the orders of a signal are placed.
+type Market string
+
+const (
+	Spot    Market = "spot"
+	Futures Market = "futures"
+)
+
+type ExecutionMode string
+
+const (
+	Simulated ExecutionMode = "simulated"
+	Live      ExecutionMode = "live"
+)
+
 type Signal struct {
 	ID         uint `gorm:"primaryKey"`
 	Symbol     string
 	Strategy   Strategy `gorm:"foreignKey:StrategyID"`
 	StrategyID uint
-	CreatedAt  time.Time
-	UpdatedAt  time.Time
-	Status     SignalStatus `gorm:"type:varchar(10);not null"`
-	Orders     []Order      `gorm:"foreignKey:SignalID"`
+	// AccountID is the account charged when the signal opened, its close is
+	// credited back to it even if the strategy moved to another account.
+	AccountID int64 `gorm:"not null;default:1"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	Status    SignalStatus  `gorm:"type:varchar(10);not null"`
+	Mode      ExecutionMode `gorm:"type:varchar(10);default:simulated"`
+	Side      PositionSide  `gorm:"type:varchar(10);not null;default:long"`
+	Market    Market        `gorm:"type:varchar(10);not null;default:spot"`
+	// HighWaterMark is the best price seen while the signal is open, the
+	// highest for longs and the lowest for shorts. It anchors trailing
+	// stops across worker restarts.
+	HighWaterMark decimal.Decimal `gorm:"type:numeric(32,12);not null;default:0"`
+	Orders        []Order         `gorm:"foreignKey:SignalID"`
 }
 
 type Order struct {
-	ID             uint       `gorm:"primaryKey"`
-	SignalID       uint       `gorm:"not null"`
-	BrokerOrderID  string     `gorm:"type:varchar(50);"`
-	EntryPrice     float32    `gorm:"not null"`
-	ExitPrice      float32    `gorm:"not null"`
-	Quantity       float32    `gorm:"not null"`
-	InvestedAmount float32    `gorm:"not null"`
-	MarginType     MarginType `gorm:"type:varchar(10);not null"`
-	EntryFee       float32    `gorm:"not null"`
-	ExitFee        float32    `gorm:"not null"`
-	Leverage       float32    `gorm:"not null"`
-	ExecutedQty    float32    `gorm:"not null"`
-	IsClosing      bool       `gorm:"default:false"`
-	Profit         float32    `gorm:"not null"`
-	CreatedAt      time.Time
-	UpdatedAt      time.Time
+	ID             uint            `gorm:"primaryKey"`
+	SignalID       uint            `gorm:"not null"`
+	BrokerOrderID  string          `gorm:"type:varchar(50);"`
+	ExitOrderID    string          `gorm:"type:varchar(50);"`
+	EntryPrice     decimal.Decimal `gorm:"type:numeric(32,12);not null"`
+	ExitPrice      decimal.Decimal `gorm:"type:numeric(32,12);not null"`
+	Quantity       decimal.Decimal `gorm:"type:numeric(32,12);not null"`
+	InvestedAmount decimal.Decimal `gorm:"type:numeric(32,12);not null"`
+	MarginType     MarginType      `gorm:"type:varchar(10);not null"`
+	EntryFee       decimal.Decimal `gorm:"type:numeric(32,12);not null"`
+	ExitFee        decimal.Decimal `gorm:"type:numeric(32,12);not null"`
+	Leverage       float32         `gorm:"not null"`
+	ExecutedQty    decimal.Decimal `gorm:"type:numeric(32,12);not null"`
+	IsClosing      bool            `gorm:"default:false"`
+	Profit         decimal.Decimal `gorm:"type:numeric(32,12);not null"`
+	// LiquidationPrice is where the exchange closes a futures position on
+	// its own, zero when unknown or on spot.
+	LiquidationPrice decimal.Decimal `gorm:"type:numeric(32,12);not null;default:0"`
+	// FundingFee is the funding paid while a futures position was open,
+	// negative when it was received.
+	FundingFee decimal.Decimal `gorm:"type:numeric(32,12);not null;default:0"`
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
 }
@@ -30,20 +30,27 @@ const (
 )
 
 type Strategy struct {
-	ID                    uint `gorm:"primaryKey"`
-	Name                  string
-	Description           string
-	Algorithm             Algorithm
-	Status                StrategyStatus
+	ID          uint `gorm:"primaryKey"`
+	Name        string
+	Description string
+	Algorithm   Algorithm
+	Status      StrategyStatus
+	// AccountID is the account the signals of the strategy draw capital
+	// from, strategies created before accounts existed use the first one.
+	AccountID             int64                       `gorm:"not null;default:1;index"`
 	MonitoredSymbols      datatypes.JSONSlice[string] `gorm:"type:jsonb"`
 	StrategyConfiguration StrategyConfiguration       `gorm:"embedded"`
 	CreatedAt             time.Time
 	UpdatedAt             time.Time
 }
 
+// StrategyConfiguration holds how often the strategy runs, its Cycle, and
+// the interval of the candles its algorithm analyses, the interval of the
+// cycle when empty.
 type StrategyConfiguration struct {
-	Cycle         Cycle
-	Configuration datatypes.JSON `gorm:"type:jsonb"`
+	Cycle          Cycle
+	CandleInterval string
+	Configuration  datatypes.JSON `gorm:"type:jsonb"`
 }
 
 type StrategyExecution struct {
@@ -55,50 +62,81 @@ type StrategyExecution struct {
 	ExecutedAt time.Time
 }
 
+// Cycle is the time between two runs of a strategy, in minutes.
 type Cycle int
 
 const (
-	OneMinute      Cycle = 1
-	FiveMinutes    Cycle = 5
+	OneMinute    Cycle = 1
+	ThreeMinutes Cycle = 3
+	FiveMinutes  Cycle = 5
+	// TenMinutes has no kline interval on Binance and is no longer
+	// accepted, strategies created with it run on every 5m candle.
 	TenMinutes     Cycle = 10
 	FifteenMinutes Cycle = 15
 	ThirtyMinutes  Cycle = 30
 	OneHour        Cycle = 60
+	TwoHours       Cycle = 2 * 60
+	FourHours      Cycle = 4 * 60
+	SixHours       Cycle = 6 * 60
+	EightHours     Cycle = 8 * 60
+	TwelveHours    Cycle = 12 * 60
+	OneDay         Cycle = 24 * 60
+	ThreeDays      Cycle = 3 * 24 * 60
+	OneWeek        Cycle = 7 * 24 * 60
 )
 
-func IsValidAlgorithm(algo string) bool {
-	switch Algorithm(algo) {
-	case Grid, Bollinger, Scalping:
-		return true
-	default:
-		return false
-	}
+// cycleIntervals are the Binance kline intervals matching the cycles.
+var cycleIntervals = map[Cycle]string{
+	OneMinute:      "1m",
+	ThreeMinutes:   "3m",
+	FiveMinutes:    "5m",
+	FifteenMinutes: "15m",
+	ThirtyMinutes:  "30m",
+	OneHour:        "1h",
+	TwoHours:       "2h",
+	FourHours:      "4h",
+	SixHours:       "6h",
+	EightHours:     "8h",
+	TwelveHours:    "12h",
+	OneDay:         "1d",
+	ThreeDays:      "3d",
+	OneWeek:        "1w",
 }
 
 func IsValidCycle(cycle int) bool {
-	switch Cycle(cycle) {
-	case OneMinute, FiveMinutes, TenMinutes, FifteenMinutes, ThirtyMinutes, OneHour:
-		return true
-	default:
-		return false
+	_, ok := cycleIntervals[Cycle(cycle)]
+	return ok
+}
+
+// Interval returns the kline interval as long as the cycle.
+func (c Cycle) Interval() string {
+	if c == TenMinutes {
+		return "5m"
 	}
+	return cycleIntervals[c]
 }
 
+func (c Cycle) Duration() time.Duration {
+	if c == TenMinutes {
+		return FiveMinutes.Duration()
+	}
+	return time.Duration(c) * time.Minute
+}
+
+// ExecutionMode tells whether signals of the strategy are sent to the
+// exchange or only simulated against the virtual account.
+func (s Strategy) ExecutionMode() ExecutionMode {
+	if s.Status == Productive {
+		return Live
+	}
+	return Simulated
+}
+
+// GetBrokerInterval returns the interval of the candles the strategy
+// analyses.
 func (s Strategy) GetBrokerInterval() string {
-	switch s.StrategyConfiguration.Cycle {
-	case OneMinute:
-		return "1m"
-	case FiveMinutes:
-		return "5m"
-	case TenMinutes:
-		return "10m"
-	case FifteenMinutes:
-		return "15m"
-	case ThirtyMinutes:
-		return "30m"
-	case OneHour:
-		return "1h"
-	default:
-		return ""
+	if s.StrategyConfiguration.CandleInterval != "" {
+		return s.StrategyConfiguration.CandleInterval
 	}
+	return s.StrategyConfiguration.Cycle.Interval()
 }
@@ -7,18 +7,24 @@ import (
 	"github.com/magiconair/properties/assert"
 )
 
-func TestValidAlgorithm(t *testing.T) {
-	assert.Equal(t, true, entities.IsValidAlgorithm("grid"))
-}
-
-func TestInvalidAlgorithm(t *testing.T) {
-	assert.Equal(t, false, entities.IsValidAlgorithm("test"))
-}
-
 func TestValidCycle(t *testing.T) {
-	assert.Equal(t, true, entities.IsValidCycle(10))
+	assert.Equal(t, true, entities.IsValidCycle(240))
+	assert.Equal(t, true, entities.IsValidCycle(int(entities.OneWeek)))
 }
 
 func TestInvalidCycle(t *testing.T) {
 	assert.Equal(t, false, entities.IsValidCycle(22))
+	// Binance has no 10m klines.
+	assert.Equal(t, false, entities.IsValidCycle(10))
+}
+
+func TestGetBrokerInterval(t *testing.T) {
+	strategy := entities.Strategy{StrategyConfiguration: entities.StrategyConfiguration{Cycle: entities.FourHours}}
+	assert.Equal(t, "4h", strategy.GetBrokerInterval())
+
+	strategy.StrategyConfiguration.CandleInterval = "15m"
+	assert.Equal(t, "15m", strategy.GetBrokerInterval())
+
+	legacy := entities.Strategy{StrategyConfiguration: entities.StrategyConfiguration{Cycle: entities.TenMinutes}}
+	assert.Equal(t, "5m", legacy.GetBrokerInterval())
 }
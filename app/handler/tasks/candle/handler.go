@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	tasks "go-trade-bot/app/workers/candle"
+	"log"
+	"time"
+
+	"github.com/hibiken/asynq"
+)
+
+type CandleUseCase interface {
+	Backfill(ctx context.Context, symbol string, interval string, from time.Time) (int, error)
+	RepairGaps(ctx context.Context, symbol string, interval string, start time.Time, end time.Time) (int, error)
+}
+
+type BackfillProcessor struct {
+	useCase CandleUseCase
+}
+
+func NewBackfillProcessor(uc CandleUseCase) *BackfillProcessor {
+	return &BackfillProcessor{
+		useCase: uc,
+	}
+}
+
+// HandleBackfillTask downloads the history requested and then repairs the
+// gaps left by earlier runs, so the series is complete from its start.
+func (p *BackfillProcessor) HandleBackfillTask(ctx context.Context, t *asynq.Task) error {
+	var payload tasks.BackfillPayload
+	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+		return err
+	}
+
+	saved, err := p.useCase.Backfill(ctx, payload.Symbol, payload.Interval, payload.From)
+	if err != nil {
+		log.Printf("Error backfilling %s %s: %v", payload.Symbol, payload.Interval, err)
+		return err
+	}
+
+	repaired, err := p.useCase.RepairGaps(ctx, payload.Symbol, payload.Interval, payload.From, time.Now())
+	if err != nil {
+		log.Printf("Error repairing gaps of %s %s: %v", payload.Symbol, payload.Interval, err)
+		return err
+	}
+
+	log.Printf("Backfill %s %s: %d candles saved, %d repaired", payload.Symbol, payload.Interval, saved, repaired)
+	return nil
+}
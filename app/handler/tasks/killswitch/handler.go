@@ -0,0 +1,174 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-trade-bot/app/entities"
+	usecase "go-trade-bot/app/usecase/signal"
+	"go-trade-bot/internal/broker"
+	"go-trade-bot/internal/killswitch"
+	"log"
+	"slices"
+
+	"github.com/hibiken/asynq"
+	"github.com/shopspring/decimal"
+)
+
+type Switch interface {
+	State(ctx context.Context) (killswitch.State, error)
+}
+
+type StrategyRepository interface {
+	GetAll(ctx context.Context) ([]entities.Strategy, error)
+}
+
+type SignalRepository interface {
+	GetAllOpenSignals() ([]entities.Signal, error)
+}
+
+type SignalUseCase interface {
+	GenerateSellSignal(e usecase.ExitSignal) error
+}
+
+type Broker interface {
+	ListTickerPrices(ctx context.Context, symbol string) ([]broker.Ticker, error)
+	CancelOpenOrders(ctx context.Context, symbol string) (int, error)
+}
+
+// FuturesBroker is nil when the exchange has no futures.
+type FuturesBroker interface {
+	CancelOpenOrders(ctx context.Context, symbol string) (int, error)
+}
+
+type KillSwitchProcessor struct {
+	killSwitch    Switch
+	strategies    StrategyRepository
+	signals       SignalRepository
+	signalUseCase SignalUseCase
+	broker        Broker
+	futures       FuturesBroker
+}
+
+func NewKillSwitchProcessor(k Switch, r StrategyRepository, s SignalRepository, uc SignalUseCase, b Broker, f FuturesBroker) *KillSwitchProcessor {
+	return &KillSwitchProcessor{
+		killSwitch:    k,
+		strategies:    r,
+		signals:       s,
+		signalUseCase: uc,
+		broker:        b,
+		futures:       f,
+	}
+}
+
+// HandleKillSwitchTask cancels the spot orders of every symbol traded and
+// the futures orders of the symbols held by futures signals and, when the
+// switch asks for it, sells the open signals at market. Signals still being
+// opened or closed are left to their order and only logged. Failures are
+// retried by asynq, symbols already handled are left as they are.
+func (p *KillSwitchProcessor) HandleKillSwitchTask(ctx context.Context, t *asynq.Task) error {
+	state, err := p.killSwitch.State(ctx)
+	if err != nil {
+		return err
+	}
+	if !state.Engaged {
+		log.Printf("Kill switch released before its task ran")
+		return nil
+	}
+
+	strategies, err := p.strategies.GetAll(ctx)
+	if err != nil {
+		return err
+	}
+	signals, err := p.signals.GetAllOpenSignals()
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, symbol := range symbols(strategies, signals) {
+		cancelled, err := p.broker.CancelOpenOrders(ctx, symbol)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to cancel the orders of %s: %w", symbol, err))
+			continue
+		}
+		if cancelled > 0 {
+			log.Printf("Kill switch cancelled %d orders of %s", cancelled, symbol)
+		}
+	}
+	if p.futures != nil {
+		for _, symbol := range futuresSymbols(signals) {
+			cancelled, err := p.futures.CancelOpenOrders(ctx, symbol)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("failed to cancel the futures orders of %s: %w", symbol, err))
+				continue
+			}
+			if cancelled > 0 {
+				log.Printf("Kill switch cancelled %d futures orders of %s", cancelled, symbol)
+			}
+		}
+	}
+
+	if state.CloseSignals {
+		for _, signal := range signals {
+			if signal.Status != entities.Open {
+				log.Printf("Kill switch left signal %d of %s %s", signal.ID, signal.Symbol, signal.Status)
+				continue
+			}
+			if err := p.close(ctx, signal); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (p *KillSwitchProcessor) close(ctx context.Context, signal entities.Signal) error {
+	tickers, err := p.broker.ListTickerPrices(ctx, signal.Symbol)
+	if err != nil || len(tickers) == 0 {
+		return fmt.Errorf("can't get the price of %s to close signal %d: %v", signal.Symbol, signal.ID, err)
+	}
+	err = p.signalUseCase.GenerateSellSignal(usecase.ExitSignal{
+		Symbol:     signal.Symbol,
+		StrategyID: signal.StrategyID,
+		ExitPrice:  decimal.NewFromFloat(tickers[0].Price),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to close signal %d: %w", signal.ID, err)
+	}
+	log.Printf("Kill switch closed signal %d of %s", signal.ID, signal.Symbol)
+	return nil
+}
+
+// symbols lists once every symbol monitored by a strategy or held by an open
+// signal.
+func symbols(strategies []entities.Strategy, signals []entities.Signal) []string {
+	seen := map[string]bool{}
+	var result []string
+	add := func(symbol string) {
+		if !seen[symbol] {
+			seen[symbol] = true
+			result = append(result, symbol)
+		}
+	}
+	for _, strategy := range strategies {
+		for _, symbol := range strategy.MonitoredSymbols {
+			add(symbol)
+		}
+	}
+	for _, signal := range signals {
+		add(signal.Symbol)
+	}
+	return result
+}
+
+// futuresSymbols lists once every symbol held by a futures signal.
+func futuresSymbols(signals []entities.Signal) []string {
+	var result []string
+	for _, signal := range signals {
+		if signal.Market == entities.Futures && !slices.Contains(result, signal.Symbol) {
+			result = append(result, signal.Symbol)
+		}
+	}
+	return result
+}
@@ -0,0 +1,130 @@
+package handler_test
+
+import (
+	"context"
+	"errors"
+	"go-trade-bot/app/entities"
+	handler "go-trade-bot/app/handler/tasks/killswitch"
+	"go-trade-bot/app/handler/tasks/killswitch/mocks"
+	usecase "go-trade-bot/app/usecase/signal"
+	"go-trade-bot/internal/broker"
+	"go-trade-bot/internal/killswitch"
+	"testing"
+
+	"github.com/hibiken/asynq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type processor struct {
+	*handler.KillSwitchProcessor
+	killSwitch *mocks.Switch
+	strategies *mocks.StrategyRepository
+	signals    *mocks.SignalRepository
+	usecase    *mocks.SignalUseCase
+	broker     *mocks.Broker
+	futures    *mocks.FuturesBroker
+}
+
+func newProcessor() processor {
+	p := processor{
+		killSwitch: new(mocks.Switch),
+		strategies: new(mocks.StrategyRepository),
+		signals:    new(mocks.SignalRepository),
+		usecase:    new(mocks.SignalUseCase),
+		broker:     new(mocks.Broker),
+		futures:    new(mocks.FuturesBroker),
+	}
+	p.KillSwitchProcessor = handler.NewKillSwitchProcessor(p.killSwitch, p.strategies, p.signals, p.usecase, p.broker, p.futures)
+	return p
+}
+
+func TestHandleKillSwitchTask(t *testing.T) {
+	task := asynq.NewTask("killswitch:engage", nil)
+	strategies := []entities.Strategy{
+		{ID: 1, MonitoredSymbols: []string{"BTCUSDT", "ETHUSDT"}},
+		{ID: 2, MonitoredSymbols: []string{"BTCUSDT"}},
+	}
+	signals := []entities.Signal{
+		{ID: 7, Symbol: "BTCUSDT", StrategyID: 2, Status: entities.Open},
+		{ID: 8, Symbol: "SOLUSDT", StrategyID: 1, Status: entities.Open},
+	}
+
+	t.Run("should cancel the orders of every symbol", func(t *testing.T) {
+		p := newProcessor()
+		p.killSwitch.On("State", mock.Anything).Return(killswitch.State{Engaged: true}, nil)
+		p.strategies.On("GetAll", mock.Anything).Return(strategies, nil)
+		p.signals.On("GetAllOpenSignals").Return(signals, nil)
+		for _, symbol := range []string{"BTCUSDT", "ETHUSDT", "SOLUSDT"} {
+			p.broker.On("CancelOpenOrders", mock.Anything, symbol).Return(0, nil).Once()
+		}
+
+		assert.NoError(t, p.HandleKillSwitchTask(context.Background(), task))
+		p.broker.AssertExpectations(t)
+		p.futures.AssertNotCalled(t, "CancelOpenOrders", mock.Anything, mock.Anything)
+		p.usecase.AssertNotCalled(t, "GenerateSellSignal", mock.Anything)
+	})
+
+	t.Run("should cancel the futures orders of futures signals", func(t *testing.T) {
+		p := newProcessor()
+		p.killSwitch.On("State", mock.Anything).Return(killswitch.State{Engaged: true}, nil)
+		p.strategies.On("GetAll", mock.Anything).Return(nil, nil)
+		p.signals.On("GetAllOpenSignals").Return([]entities.Signal{
+			{ID: 9, Symbol: "ETHUSDT", Market: entities.Futures, Status: entities.Pending},
+			{ID: 10, Symbol: "ETHUSDT", Market: entities.Futures, Status: entities.Open},
+		}, nil)
+		p.broker.On("CancelOpenOrders", mock.Anything, "ETHUSDT").Return(0, nil).Once()
+		p.futures.On("CancelOpenOrders", mock.Anything, "ETHUSDT").Return(1, nil).Once()
+
+		assert.NoError(t, p.HandleKillSwitchTask(context.Background(), task))
+		p.futures.AssertExpectations(t)
+	})
+
+	t.Run("should only close open signals", func(t *testing.T) {
+		p := newProcessor()
+		p.killSwitch.On("State", mock.Anything).Return(killswitch.State{Engaged: true, CloseSignals: true}, nil)
+		p.strategies.On("GetAll", mock.Anything).Return(nil, nil)
+		p.signals.On("GetAllOpenSignals").Return([]entities.Signal{
+			{ID: 11, Symbol: "BTCUSDT", StrategyID: 1, Status: entities.Pending},
+			{ID: 12, Symbol: "ETHUSDT", StrategyID: 1, Status: entities.Closing},
+			{ID: 13, Symbol: "SOLUSDT", StrategyID: 2, Status: entities.Open},
+		}, nil)
+		p.broker.On("CancelOpenOrders", mock.Anything, mock.Anything).Return(0, nil)
+		p.broker.On("ListTickerPrices", mock.Anything, "SOLUSDT").Return([]broker.Ticker{{Symbol: "SOLUSDT", Price: 150}}, nil)
+		p.usecase.On("GenerateSellSignal", mock.MatchedBy(func(e usecase.ExitSignal) bool {
+			return e.Symbol == "SOLUSDT" && e.StrategyID == 2
+		})).Return(nil).Once()
+
+		assert.NoError(t, p.HandleKillSwitchTask(context.Background(), task))
+		p.usecase.AssertExpectations(t)
+		p.broker.AssertNotCalled(t, "ListTickerPrices", mock.Anything, "BTCUSDT")
+	})
+
+	t.Run("should close the open signals at market", func(t *testing.T) {
+		p := newProcessor()
+		p.killSwitch.On("State", mock.Anything).Return(killswitch.State{Engaged: true, CloseSignals: true}, nil)
+		p.strategies.On("GetAll", mock.Anything).Return(strategies, nil)
+		p.signals.On("GetAllOpenSignals").Return(signals, nil)
+		p.broker.On("CancelOpenOrders", mock.Anything, mock.Anything).Return(0, nil)
+		p.broker.On("ListTickerPrices", mock.Anything, "BTCUSDT").Return([]broker.Ticker{{Symbol: "BTCUSDT", Price: 50000}}, nil)
+		p.broker.On("ListTickerPrices", mock.Anything, "SOLUSDT").Return([]broker.Ticker{{Symbol: "SOLUSDT", Price: 150}}, nil)
+		p.usecase.On("GenerateSellSignal", mock.MatchedBy(func(e usecase.ExitSignal) bool {
+			return e.Symbol == "BTCUSDT" && e.StrategyID == 2 && e.ExitPrice.IntPart() == 50000
+		})).Return(nil).Once()
+		p.usecase.On("GenerateSellSignal", mock.MatchedBy(func(e usecase.ExitSignal) bool {
+			return e.Symbol == "SOLUSDT" && e.StrategyID == 1
+		})).Return(errors.New("insufficient balance")).Once()
+
+		err := p.HandleKillSwitchTask(context.Background(), task)
+		assert.EqualError(t, err, "failed to close signal 8: insufficient balance")
+		p.usecase.AssertExpectations(t)
+	})
+
+	t.Run("should do nothing once released", func(t *testing.T) {
+		p := newProcessor()
+		p.killSwitch.On("State", mock.Anything).Return(killswitch.State{}, nil)
+
+		assert.NoError(t, p.HandleKillSwitchTask(context.Background(), task))
+		p.strategies.AssertNotCalled(t, "GetAll", mock.Anything)
+	})
+}
@@ -0,0 +1,58 @@
+// Code generated by mockery v2.53.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	entities "go-trade-bot/app/entities"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// SignalRepository is an autogenerated mock type for the SignalRepository type
+type SignalRepository struct {
+	mock.Mock
+}
+
+// GetAllOpenSignals provides a mock function with no fields
+func (_m *SignalRepository) GetAllOpenSignals() ([]entities.Signal, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAllOpenSignals")
+	}
+
+	var r0 []entities.Signal
+	var r1 error
+	if rf, ok := ret.Get(0).(func() ([]entities.Signal, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() []entities.Signal); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entities.Signal)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewSignalRepository creates a new instance of SignalRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewSignalRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *SignalRepository {
+	mock := &SignalRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
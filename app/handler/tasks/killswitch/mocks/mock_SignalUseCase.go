@@ -0,0 +1,46 @@
+// Code generated by mockery v2.53.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	usecase "go-trade-bot/app/usecase/signal"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// SignalUseCase is an autogenerated mock type for the SignalUseCase type
+type SignalUseCase struct {
+	mock.Mock
+}
+
+// GenerateSellSignal provides a mock function with given fields: e
+func (_m *SignalUseCase) GenerateSellSignal(e usecase.ExitSignal) error {
+	ret := _m.Called(e)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GenerateSellSignal")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(usecase.ExitSignal) error); ok {
+		r0 = rf(e)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewSignalUseCase creates a new instance of SignalUseCase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewSignalUseCase(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *SignalUseCase {
+	mock := &SignalUseCase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,58 @@
+// Code generated by mockery v2.53.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	killswitch "go-trade-bot/internal/killswitch"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// Switch is an autogenerated mock type for the Switch type
+type Switch struct {
+	mock.Mock
+}
+
+// State provides a mock function with given fields: ctx
+func (_m *Switch) State(ctx context.Context) (killswitch.State, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for State")
+	}
+
+	var r0 killswitch.State
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (killswitch.State, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) killswitch.State); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(killswitch.State)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewSwitch creates a new instance of Switch. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewSwitch(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Switch {
+	mock := &Switch{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -3,18 +3,20 @@ package handler
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"go-trade-bot/app/entities"
-	"go-trade-bot/app/services/algorithm/bollinger"
-	"go-trade-bot/app/services/algorithm/grid"
-	"go-trade-bot/app/services/algorithm/scalping"
+	"go-trade-bot/app/services/algorithm"
+	risk "go-trade-bot/app/usecase/risk"
 	usecase "go-trade-bot/app/usecase/signal"
 	"go-trade-bot/internal/broker"
+	"go-trade-bot/internal/killswitch"
 	"go-trade-bot/internal/memcache"
 	"go-trade-bot/internal/metrics"
 	"log"
 	"time"
 
 	"github.com/hibiken/asynq"
+	"github.com/shopspring/decimal"
 )
 
 const (
@@ -22,10 +24,6 @@ const (
 	total_strategy_task = "total_strategy_task"
 )
 
-type IStrategyProcessor interface {
-	Execute() error
-}
-
 type StrategyProcessor struct {
 	collector     *metrics.MetricsCollector
 	worker        StrategyWorker
@@ -33,6 +31,22 @@ type StrategyProcessor struct {
 	broker        broker.Broker
 	signalUseCase SignalUseCase
 	cache         memcache.Cache
+	algorithms    Algorithms
+	grids         algorithm.GridStore
+	risk          RiskManager
+	killSwitch    KillSwitch
+}
+
+type Algorithms interface {
+	NewProcessor(strategy entities.Strategy, deps algorithm.Dependencies) (algorithm.Processor, error)
+}
+
+type KillSwitch interface {
+	State(ctx context.Context) (killswitch.State, error)
+}
+
+type RiskManager interface {
+	Evaluate(ctx context.Context) (risk.Status, error)
 }
 
 type StrategyWorker interface {
@@ -49,9 +63,10 @@ type SignalUseCase interface {
 	GenerateBuySignal(e usecase.EntrySignal) error
 	GenerateSellSignal(e usecase.ExitSignal) error
 	GetOpenSignal(symbol string, strategyId uint) (entities.Signal, error)
+	TrackHighWaterMark(signal entities.Signal, price decimal.Decimal) (decimal.Decimal, error)
 }
 
-func NewStrategyProcessor(collector *metrics.MetricsCollector, w StrategyWorker, r StrategyRepository, b broker.Broker, uc SignalUseCase, c memcache.Cache) *StrategyProcessor {
+func NewStrategyProcessor(collector *metrics.MetricsCollector, w StrategyWorker, r StrategyRepository, b broker.Broker, uc SignalUseCase, c memcache.Cache, a Algorithms, g algorithm.GridStore, rm RiskManager, k KillSwitch) *StrategyProcessor {
 	return &StrategyProcessor{
 		collector:     collector,
 		worker:        w,
@@ -59,6 +74,10 @@ func NewStrategyProcessor(collector *metrics.MetricsCollector, w StrategyWorker,
 		broker:        b,
 		signalUseCase: uc,
 		cache:         c,
+		algorithms:    a,
+		grids:         g,
+		risk:          rm,
+		killSwitch:    k,
 	}
 }
 
@@ -105,23 +124,71 @@ func (p *StrategyProcessor) HandleStrategyTask(ctx context.Context, t *asynq.Tas
 }
 
 func (p *StrategyProcessor) processStrategy(ctx context.Context, strategy entities.Strategy) error {
-	var executor IStrategyProcessor
-
-	switch strategy.Algorithm {
-	case entities.Grid:
-		executor = grid.NewGridProcessor(strategy, p.broker, p.signalUseCase, p.cache)
-	case entities.Scalping:
-		executor = scalping.NewScalpingProcessor(strategy, p.broker, p.signalUseCase)
-	case entities.Bollinger:
-		executor = bollinger.NewBollingerProcessor(strategy, p.broker, p.signalUseCase)
-	default:
-		log.Printf("Unknown strategy algorithm: %s", strategy.Algorithm)
+	if p.killSwitch != nil {
+		state, err := p.killSwitch.State(ctx)
+		if err != nil {
+			return err
+		}
+		if state.Engaged {
+			log.Printf("Strategy %s paused by the kill switch: %s", strategy.Name, state.Reason)
+			return fmt.Errorf("paused by the kill switch: %s", state.Reason)
+		}
+	}
+
+	if p.risk != nil {
+		status, err := p.risk.Evaluate(ctx)
+		if err != nil {
+			return err
+		}
+		if status.Flatten {
+			return p.flatten(ctx, strategy, status)
+		}
 	}
 
-	err := executor.Execute()
+	executor, err := p.algorithms.NewProcessor(strategy, algorithm.Dependencies{
+		Broker:  p.broker,
+		Signals: p.signalUseCase,
+		Cache:   p.cache,
+		Grids:   p.grids,
+	})
+	if err != nil {
+		log.Printf("Error creating strategy %s: %v", strategy.Name, err)
+		return err
+	}
+
+	err = executor.Execute()
 	if err != nil {
 		log.Printf("Error executing strategy %s: %v", strategy.Name, err)
 	}
 
 	return err
 }
+
+// flatten closes the open signals of the strategy at the last price, in place
+// of running it, while a tripped risk breaker asks for it.
+func (p *StrategyProcessor) flatten(ctx context.Context, strategy entities.Strategy, status risk.Status) error {
+	for _, symbol := range strategy.MonitoredSymbols {
+		signal, err := p.signalUseCase.GetOpenSignal(symbol, strategy.ID)
+		if err != nil {
+			return err
+		}
+		if signal.ID == 0 {
+			continue
+		}
+
+		tickers, err := p.broker.ListTickerPrices(ctx, symbol)
+		if err != nil || len(tickers) == 0 {
+			return fmt.Errorf("can't get the price of %s to flatten strategy %s: %v", symbol, strategy.Name, err)
+		}
+		err = p.signalUseCase.GenerateSellSignal(usecase.ExitSignal{
+			Symbol:     symbol,
+			StrategyID: strategy.ID,
+			ExitPrice:  decimal.NewFromFloat(tickers[0].Price),
+		})
+		if err != nil {
+			return err
+		}
+		log.Printf("Strategy %s closed %s, the %s breaker tripped: %s", strategy.Name, symbol, status.Rule, status.Reason)
+	}
+	return nil
+}
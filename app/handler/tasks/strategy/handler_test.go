@@ -5,15 +5,27 @@ import (
 	"encoding/json"
 	"go-trade-bot/app/entities"
 	handler "go-trade-bot/app/handler/tasks/strategy"
+	"go-trade-bot/app/handler/tasks/strategy/mocks"
+	"go-trade-bot/app/services/algorithm"
+	riskUseCase "go-trade-bot/app/usecase/risk"
+	signalUseCase "go-trade-bot/app/usecase/signal"
+	brokerTypes "go-trade-bot/internal/broker"
+	"go-trade-bot/internal/killswitch"
+	"go-trade-bot/internal/metrics"
 	"testing"
 
 	"github.com/hibiken/asynq"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestHandleStrategyTask(t *testing.T) {
 	strategy := entities.Strategy{
-		Name: "TestStrategy",
+		ID:        1,
+		Name:      "TestStrategy",
+		Algorithm: "unknown",
+		Status:    entities.Testing,
 	}
 
 	payload, err := json.Marshal(strategy)
@@ -21,10 +33,121 @@ func TestHandleStrategyTask(t *testing.T) {
 		t.Fatalf("Error on marshal strategy: %v ", err)
 	}
 
+	worker := new(mocks.StrategyWorker)
+	repository := new(mocks.StrategyRepository)
+	repository.On("GetByID", mock.Anything, uint(1)).Return(strategy, nil)
+	worker.On("EnqueueStrategyTask", strategy).Return(nil)
+	repository.On("SaveExecution", mock.Anything, mock.MatchedBy(func(e entities.StrategyExecution) bool {
+		return e.Status == entities.Error && e.Message == "Error executing strategy: unknown strategy algorithm: unknown"
+	})).Return(nil)
+
+	task := asynq.NewTask(handler.StrategyTask+strategy.Name, payload)
+	processor := handler.NewStrategyProcessor(metrics.NewMetricsCollector(nil), worker, repository, nil, nil, nil, algorithm.NewRegistry(), nil, nil, nil)
+
+	err = processor.HandleStrategyTask(context.Background(), task)
+
+	assert.Nil(t, err)
+	worker.AssertExpectations(t)
+	repository.AssertExpectations(t)
+}
+
+func TestHandleStrategyTask_Disabled(t *testing.T) {
+	strategy := entities.Strategy{
+		ID:     1,
+		Name:   "TestStrategy",
+		Status: entities.Disabled,
+	}
+
+	payload, err := json.Marshal(strategy)
+	if err != nil {
+		t.Fatalf("Error on marshal strategy: %v ", err)
+	}
+
+	worker := new(mocks.StrategyWorker)
+	repository := new(mocks.StrategyRepository)
+	repository.On("GetByID", mock.Anything, uint(1)).Return(strategy, nil)
+
+	task := asynq.NewTask(handler.StrategyTask+strategy.Name, payload)
+	processor := handler.NewStrategyProcessor(metrics.NewMetricsCollector(nil), worker, repository, nil, nil, nil, algorithm.NewRegistry(), nil, nil, nil)
+
+	err = processor.HandleStrategyTask(context.Background(), task)
+
+	assert.Nil(t, err)
+	worker.AssertNotCalled(t, "EnqueueStrategyTask", mock.Anything)
+	repository.AssertNotCalled(t, "SaveExecution", mock.Anything, mock.Anything)
+}
+
+func TestHandleStrategyTask_Flatten(t *testing.T) {
+	strategy := entities.Strategy{
+		ID:               1,
+		Name:             "TestStrategy",
+		Algorithm:        "unknown",
+		Status:           entities.Testing,
+		MonitoredSymbols: []string{"BTCUSDT", "ETHUSDT"},
+	}
+
+	payload, err := json.Marshal(strategy)
+	if err != nil {
+		t.Fatalf("Error on marshal strategy: %v ", err)
+	}
+
+	worker := new(mocks.StrategyWorker)
+	repository := new(mocks.StrategyRepository)
+	signals := new(mocks.SignalUseCase)
+	broker := new(mocks.Broker)
+	risk := new(mocks.RiskManager)
+	repository.On("GetByID", mock.Anything, uint(1)).Return(strategy, nil)
+	worker.On("EnqueueStrategyTask", strategy).Return(nil)
+	repository.On("SaveExecution", mock.Anything, mock.MatchedBy(func(e entities.StrategyExecution) bool {
+		return e.Status == entities.OK
+	})).Return(nil)
+	risk.On("Evaluate", mock.Anything).Return(riskUseCase.Status{Halted: true, Flatten: true, Rule: entities.DrawdownRule}, nil)
+	signals.On("GetOpenSignal", "BTCUSDT", uint(1)).Return(entities.Signal{ID: 7, Symbol: "BTCUSDT"}, nil)
+	signals.On("GetOpenSignal", "ETHUSDT", uint(1)).Return(entities.Signal{}, nil)
+	broker.On("ListTickerPrices", mock.Anything, "BTCUSDT").Return([]brokerTypes.Ticker{{Symbol: "BTCUSDT", Price: 50000}}, nil)
+	signals.On("GenerateSellSignal", mock.MatchedBy(func(e signalUseCase.ExitSignal) bool {
+		return e.Symbol == "BTCUSDT" && e.StrategyID == 1 && e.ExitPrice.Equal(decimal.NewFromInt(50000))
+	})).Return(nil).Once()
+
+	task := asynq.NewTask(handler.StrategyTask+strategy.Name, payload)
+	processor := handler.NewStrategyProcessor(metrics.NewMetricsCollector(nil), worker, repository, broker, signals, nil, algorithm.NewRegistry(), nil, risk, nil)
+
+	err = processor.HandleStrategyTask(context.Background(), task)
+
+	assert.Nil(t, err)
+	signals.AssertExpectations(t)
+	repository.AssertExpectations(t)
+}
+
+func TestHandleStrategyTask_KillSwitch(t *testing.T) {
+	strategy := entities.Strategy{
+		ID:        1,
+		Name:      "TestStrategy",
+		Algorithm: entities.Bollinger,
+		Status:    entities.Productive,
+	}
+
+	payload, err := json.Marshal(strategy)
+	if err != nil {
+		t.Fatalf("Error on marshal strategy: %v ", err)
+	}
+
+	worker := new(mocks.StrategyWorker)
+	repository := new(mocks.StrategyRepository)
+	killSwitch := new(mocks.KillSwitch)
+	repository.On("GetByID", mock.Anything, uint(1)).Return(strategy, nil)
+	worker.On("EnqueueStrategyTask", strategy).Return(nil)
+	repository.On("SaveExecution", mock.Anything, mock.MatchedBy(func(e entities.StrategyExecution) bool {
+		return e.Status == entities.Error && e.Message == "Error executing strategy: paused by the kill switch: exchange outage"
+	})).Return(nil)
+	killSwitch.On("State", mock.Anything).Return(killswitch.State{Engaged: true, Reason: "exchange outage"}, nil)
+
 	task := asynq.NewTask(handler.StrategyTask+strategy.Name, payload)
-	processor := &handler.StrategyProcessor{}
+	processor := handler.NewStrategyProcessor(metrics.NewMetricsCollector(nil), worker, repository, nil, nil, nil, algorithm.NewRegistry(), nil, nil, killSwitch)
 
 	err = processor.HandleStrategyTask(context.Background(), task)
 
 	assert.Nil(t, err)
+	worker.AssertExpectations(t)
+	repository.AssertExpectations(t)
 }
@@ -0,0 +1,58 @@
+// Code generated by mockery v2.53.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	usecase "go-trade-bot/app/usecase/risk"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// RiskManager is an autogenerated mock type for the RiskManager type
+type RiskManager struct {
+	mock.Mock
+}
+
+// Evaluate provides a mock function with given fields: ctx
+func (_m *RiskManager) Evaluate(ctx context.Context) (usecase.Status, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Evaluate")
+	}
+
+	var r0 usecase.Status
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (usecase.Status, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) usecase.Status); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(usecase.Status)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewRiskManager creates a new instance of RiskManager. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewRiskManager(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *RiskManager {
+	mock := &RiskManager{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
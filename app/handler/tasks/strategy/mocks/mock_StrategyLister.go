@@ -0,0 +1,59 @@
+// Code generated by mockery v2.53.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	entities "go-trade-bot/app/entities"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// StrategyLister is an autogenerated mock type for the StrategyLister type
+type StrategyLister struct {
+	mock.Mock
+}
+
+// GetAll provides a mock function with given fields: ctx
+func (_m *StrategyLister) GetAll(ctx context.Context) ([]entities.Strategy, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAll")
+	}
+
+	var r0 []entities.Strategy
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]entities.Strategy, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []entities.Strategy); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entities.Strategy)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewStrategyLister creates a new instance of StrategyLister. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewStrategyLister(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *StrategyLister {
+	mock := &StrategyLister{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
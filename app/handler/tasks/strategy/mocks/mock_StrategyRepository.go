@@ -0,0 +1,103 @@
+// Code generated by mockery v2.53.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	entities "go-trade-bot/app/entities"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// StrategyRepository is an autogenerated mock type for the StrategyRepository type
+type StrategyRepository struct {
+	mock.Mock
+}
+
+// CountOpenSignals provides a mock function with given fields: ctx, strategy
+func (_m *StrategyRepository) CountOpenSignals(ctx context.Context, strategy entities.Strategy) (int64, error) {
+	ret := _m.Called(ctx, strategy)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountOpenSignals")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, entities.Strategy) (int64, error)); ok {
+		return rf(ctx, strategy)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entities.Strategy) int64); ok {
+		r0 = rf(ctx, strategy)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entities.Strategy) error); ok {
+		r1 = rf(ctx, strategy)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetByID provides a mock function with given fields: ctx, id
+func (_m *StrategyRepository) GetByID(ctx context.Context, id uint) (entities.Strategy, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByID")
+	}
+
+	var r0 entities.Strategy
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) (entities.Strategy, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) entities.Strategy); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(entities.Strategy)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SaveExecution provides a mock function with given fields: ctx, execution
+func (_m *StrategyRepository) SaveExecution(ctx context.Context, execution entities.StrategyExecution) error {
+	ret := _m.Called(ctx, execution)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveExecution")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, entities.StrategyExecution) error); ok {
+		r0 = rf(ctx, execution)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewStrategyRepository creates a new instance of StrategyRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewStrategyRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *StrategyRepository {
+	mock := &StrategyRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
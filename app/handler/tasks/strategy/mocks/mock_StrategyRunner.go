@@ -0,0 +1,48 @@
+// Code generated by mockery v2.53.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	entities "go-trade-bot/app/entities"
+
+	time "time"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// StrategyRunner is an autogenerated mock type for the StrategyRunner type
+type StrategyRunner struct {
+	mock.Mock
+}
+
+// RunStrategyTask provides a mock function with given fields: strategy, closeTime
+func (_m *StrategyRunner) RunStrategyTask(strategy entities.Strategy, closeTime time.Time) error {
+	ret := _m.Called(strategy, closeTime)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RunStrategyTask")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(entities.Strategy, time.Time) error); ok {
+		r0 = rf(strategy, closeTime)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewStrategyRunner creates a new instance of StrategyRunner. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewStrategyRunner(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *StrategyRunner {
+	mock := &StrategyRunner{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-trade-bot/app/entities"
+	"go-trade-bot/internal/broker"
+	"slices"
+	"time"
+)
+
+type StrategyLister interface {
+	GetAll(ctx context.Context) ([]entities.Strategy, error)
+}
+
+type StrategyRunner interface {
+	RunStrategyTask(strategy entities.Strategy, closeTime time.Time) error
+}
+
+// CandleTrigger runs the strategies watching a symbol as soon as the stream
+// closes a candle of their interval, ahead of the time they are scheduled
+// at.
+type CandleTrigger struct {
+	strategies StrategyLister
+	worker     StrategyRunner
+}
+
+func NewCandleTrigger(s StrategyLister, w StrategyRunner) CandleTrigger {
+	return CandleTrigger{
+		strategies: s,
+		worker:     w,
+	}
+}
+
+func (t CandleTrigger) HandleCandleClose(ctx context.Context, symbol string, interval string, candle broker.Candle) error {
+	strategies, err := t.strategies.GetAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	closeTime := candle.CloseTime.Add(time.Millisecond)
+	var errs []error
+	for _, strategy := range strategies {
+		if strategy.Status == entities.Disabled || strategy.GetBrokerInterval() != interval ||
+			!slices.Contains(strategy.MonitoredSymbols, symbol) {
+			continue
+		}
+		if err := t.worker.RunStrategyTask(strategy, closeTime); err != nil {
+			errs = append(errs, fmt.Errorf("failed to run strategy %s on the close of %s: %w", strategy.Name, symbol, err))
+		}
+	}
+	return errors.Join(errs...)
+}
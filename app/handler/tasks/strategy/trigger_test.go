@@ -0,0 +1,38 @@
+package handler_test
+
+import (
+	"context"
+	"go-trade-bot/app/entities"
+	handler "go-trade-bot/app/handler/tasks/strategy"
+	"go-trade-bot/app/handler/tasks/strategy/mocks"
+	brokerTypes "go-trade-bot/internal/broker"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCandleTrigger_HandleCandleClose(t *testing.T) {
+	fiveMinutes := entities.StrategyConfiguration{Cycle: entities.FiveMinutes}
+	watching := entities.Strategy{ID: 1, Name: "watching", Status: entities.Testing, MonitoredSymbols: []string{"ETHUSDT", "BTCUSDT"}, StrategyConfiguration: fiveMinutes}
+	other := entities.Strategy{ID: 2, Name: "other", Status: entities.Testing, MonitoredSymbols: []string{"ETHUSDT"}, StrategyConfiguration: fiveMinutes}
+	hourly := entities.Strategy{ID: 3, Name: "hourly", Status: entities.Testing, MonitoredSymbols: []string{"BTCUSDT"}, StrategyConfiguration: entities.StrategyConfiguration{Cycle: entities.OneHour}}
+	disabled := entities.Strategy{ID: 4, Name: "disabled", Status: entities.Disabled, MonitoredSymbols: []string{"BTCUSDT"}, StrategyConfiguration: fiveMinutes}
+
+	strategies := new(mocks.StrategyLister)
+	strategies.On("GetAll", mock.Anything).Return([]entities.Strategy{watching, other, hourly, disabled}, nil)
+	runner := new(mocks.StrategyRunner)
+	closeTime := time.Date(2024, 1, 1, 10, 5, 0, 0, time.UTC)
+	runner.On("RunStrategyTask", watching, closeTime).Return(nil).Once()
+
+	trigger := handler.NewCandleTrigger(strategies, runner)
+	err := trigger.HandleCandleClose(context.Background(), "BTCUSDT", "5m", brokerTypes.Candle{
+		OpenTime:  closeTime.Add(-5 * time.Minute),
+		CloseTime: closeTime.Add(-time.Millisecond),
+	})
+
+	assert.NoError(t, err)
+	runner.AssertExpectations(t)
+	runner.AssertNumberOfCalls(t, "RunStrategyTask", 1)
+}
@@ -2,18 +2,24 @@ package handler
 
 import (
 	"go-trade-bot/app/entities"
+
+	"github.com/shopspring/decimal"
 )
 
 type AccountDto struct {
-	Amount          float32 `json:"amount"`
-	AvailableOrders int64   `json:"available_orders"`
-	Currency        string  `json:"currency"`
+	Name            string          `json:"name"`
+	Amount          decimal.Decimal `json:"amount"`
+	AvailableOrders int64           `json:"available_orders"`
+	Currency        string          `json:"currency"`
+	FeeModel        string          `json:"fee_model"`
 }
 
 func (s AccountDto) ToModel() entities.Account {
 	return entities.Account{
+		Name:            s.Name,
 		Amount:          s.Amount,
 		AvailableOrders: s.AvailableOrders,
 		Currency:        s.Currency,
+		FeeModel:        s.FeeModel,
 	}
 }
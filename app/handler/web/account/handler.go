@@ -3,13 +3,22 @@ package handler
 import (
 	"encoding/json"
 	"go-trade-bot/app/entities"
+	usecase "go-trade-bot/app/usecase/account"
 	"go-trade-bot/internal/handler"
 	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
 )
 
 type UseCase interface {
-	CreateAccount(account entities.Account) error
-	GetAccount() (entities.Account, error)
+	CreateAccount(account entities.Account) (entities.Account, error)
+	UpdateAccount(id int64, account entities.Account) (entities.Account, error)
+	DeleteAccount(id int64) error
+	GetAccount(id int64) (entities.Account, error)
+	GetAll() ([]entities.Account, error)
+	GetLedger(id int64, from time.Time, to time.Time) (usecase.Ledger, error)
 }
 
 type AccountHandler struct {
@@ -30,9 +39,29 @@ func (h *AccountHandler) Handlers() []handler.Configuration {
 		},
 		{
 			Pattern: "/account",
+			Action:  h.GetAll,
+			Method:  http.MethodGet,
+		},
+		{
+			Pattern: "/account/{id}",
 			Action:  h.Get,
 			Method:  http.MethodGet,
 		},
+		{
+			Pattern: "/account/{id}",
+			Action:  h.Put,
+			Method:  http.MethodPut,
+		},
+		{
+			Pattern: "/account/{id}",
+			Action:  h.Delete,
+			Method:  http.MethodDelete,
+		},
+		{
+			Pattern: "/account/{id}/ledger",
+			Action:  h.Ledger,
+			Method:  http.MethodGet,
+		},
 	}
 }
 func (h *AccountHandler) Post(w http.ResponseWriter, r *http.Request) {
@@ -41,17 +70,34 @@ func (h *AccountHandler) Post(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
-	if err := h.UseCase.CreateAccount(account.ToModel()); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	created, err := h.UseCase.CreateAccount(account.ToModel())
+	if err != nil {
+		handler.WriteError(w, err)
 		return
 	}
+	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(created)
+}
+
+func (h *AccountHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	accounts, err := h.UseCase.GetAll()
+	if err != nil {
+		handler.WriteError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(accounts)
 }
 
 func (h *AccountHandler) Get(w http.ResponseWriter, r *http.Request) {
-	account, err := h.UseCase.GetAccount()
+	id, ok := accountID(w, r)
+	if !ok {
+		return
+	}
+	account, err := h.UseCase.GetAccount(id)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		handler.WriteError(w, err)
 		return
 	}
 	w.Header().Set("Content-Type", "application/json")
@@ -60,3 +106,74 @@ func (h *AccountHandler) Get(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 }
+
+func (h *AccountHandler) Put(w http.ResponseWriter, r *http.Request) {
+	id, ok := accountID(w, r)
+	if !ok {
+		return
+	}
+	var dto AccountDto
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	account, err := h.UseCase.UpdateAccount(id, dto.ToModel())
+	if err != nil {
+		handler.WriteError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(account)
+}
+
+func (h *AccountHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, ok := accountID(w, r)
+	if !ok {
+		return
+	}
+	if err := h.UseCase.DeleteAccount(id); err != nil {
+		handler.WriteError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Ledger lists the cash movements of the account, from and to are optional
+// RFC3339 times bounding when they happened.
+func (h *AccountHandler) Ledger(w http.ResponseWriter, r *http.Request) {
+	id, ok := accountID(w, r)
+	if !ok {
+		return
+	}
+
+	var bounds [2]time.Time
+	for i, name := range []string{"from", "to"} {
+		value := r.URL.Query().Get(name)
+		if value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			http.Error(w, "Invalid "+name+", use a RFC3339 time", http.StatusBadRequest)
+			return
+		}
+		bounds[i] = t
+	}
+
+	ledger, err := h.UseCase.GetLedger(id, bounds[0], bounds[1])
+	if err != nil {
+		handler.WriteError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(ledger)
+}
+
+func accountID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil || id <= 0 {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return 0, false
+	}
+	return id, true
+}
@@ -6,11 +6,15 @@ import (
 	"go-trade-bot/app/entities"
 	handler "go-trade-bot/app/handler/web/account"
 	"go-trade-bot/app/handler/web/account/mocks"
+	usecase "go-trade-bot/app/usecase/account"
+	"go-trade-bot/internal/customerror"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 	"time"
 
+	"github.com/gorilla/mux"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -19,7 +23,8 @@ func TestAccountHandler_Post(t *testing.T) {
 	h := handler.NewAccountHandler(mockUseCase)
 
 	dto := handler.AccountDto{
-		Amount:          1000.0,
+		Name:            "paper",
+		Amount:          d(1000),
 		AvailableOrders: 5,
 		Currency:        "USDT",
 	}
@@ -32,10 +37,16 @@ func TestAccountHandler_Post(t *testing.T) {
 	req.Header.Set("Content-Type", "application/json")
 
 	rec := httptest.NewRecorder()
-	mockUseCase.On("CreateAccount", dto.ToModel()).Return(nil)
+	created := dto.ToModel()
+	created.ID = 2
+	mockUseCase.On("CreateAccount", dto.ToModel()).Return(created, nil)
 
 	h.Post(rec, req)
 	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	var response entities.Account
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, int64(2), response.ID)
 	mockUseCase.AssertExpectations(t)
 }
 func TestAccountHandler_Post_InvalidJSON(t *testing.T) {
@@ -57,17 +68,18 @@ func TestAccountHandler_Get(t *testing.T) {
 	h := handler.NewAccountHandler(mockUseCase)
 
 	r := entities.Account{
-		Amount:          1000.0,
+		Amount:          d(1000),
 		AvailableOrders: 5,
 		Currency:        "USDT",
 		CreatedAt:       time.Date(1, time.January, 1, 0, 0, 0, 0, time.UTC),
 		UpdatedAt:       time.Date(1, time.January, 1, 0, 0, 0, 0, time.UTC),
 	}
 
-	mockUseCase.On("GetAccount").Return(r, nil)
+	mockUseCase.On("GetAccount", int64(1)).Return(r, nil)
 
-	req, err := http.NewRequest(http.MethodGet, "/account", nil)
+	req, err := http.NewRequest(http.MethodGet, "/account/1", nil)
 	assert.NoError(t, err)
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
 
 	rec := httptest.NewRecorder()
 
@@ -80,3 +92,104 @@ func TestAccountHandler_Get(t *testing.T) {
 	assert.Equal(t, r, response)
 	mockUseCase.AssertExpectations(t)
 }
+
+func TestAccountHandler_Get_NotFound(t *testing.T) {
+	mockUseCase := new(mocks.UseCase)
+	h := handler.NewAccountHandler(mockUseCase)
+	mockUseCase.On("GetAccount", int64(9)).Return(entities.Account{}, customerror.New(http.StatusNotFound, "Account not found"))
+
+	req := mux.SetURLVars(httptest.NewRequest(http.MethodGet, "/account/9", nil), map[string]string{"id": "9"})
+	rec := httptest.NewRecorder()
+
+	h.Get(rec, req)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestAccountHandler_GetAll(t *testing.T) {
+	mockUseCase := new(mocks.UseCase)
+	h := handler.NewAccountHandler(mockUseCase)
+	mockUseCase.On("GetAll").Return([]entities.Account{{ID: 1, Name: "paper"}, {ID: 2, Name: "live"}}, nil)
+
+	rec := httptest.NewRecorder()
+	h.GetAll(rec, httptest.NewRequest(http.MethodGet, "/account", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var response []entities.Account
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Len(t, response, 2)
+}
+
+func TestAccountHandler_Put(t *testing.T) {
+	mockUseCase := new(mocks.UseCase)
+	h := handler.NewAccountHandler(mockUseCase)
+
+	dto := handler.AccountDto{Name: "live", Amount: d(500), AvailableOrders: 2, Currency: "USDT"}
+	body, err := json.Marshal(dto)
+	assert.NoError(t, err)
+	updated := dto.ToModel()
+	updated.ID = 2
+	mockUseCase.On("UpdateAccount", int64(2), dto.ToModel()).Return(updated, nil)
+
+	req := mux.SetURLVars(httptest.NewRequest(http.MethodPut, "/account/2", bytes.NewBuffer(body)), map[string]string{"id": "2"})
+	rec := httptest.NewRecorder()
+
+	h.Put(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	mockUseCase.AssertExpectations(t)
+}
+
+func TestAccountHandler_Delete(t *testing.T) {
+	mockUseCase := new(mocks.UseCase)
+	h := handler.NewAccountHandler(mockUseCase)
+	mockUseCase.On("DeleteAccount", int64(2)).Return(customerror.New(http.StatusConflict, "Account has strategies bound to it"))
+
+	req := mux.SetURLVars(httptest.NewRequest(http.MethodDelete, "/account/2", nil), map[string]string{"id": "2"})
+	rec := httptest.NewRecorder()
+
+	h.Delete(rec, req)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+}
+
+func TestAccountHandler_Ledger(t *testing.T) {
+	t.Run("should filter the ledger by date", func(t *testing.T) {
+		mockUseCase := new(mocks.UseCase)
+		h := handler.NewAccountHandler(mockUseCase)
+		from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		ledger := usecase.Ledger{
+			AccountID: 2,
+			Amount:    d(900),
+			Balance:   d(900),
+			Entries:   []entities.LedgerEntry{{ID: 1, AccountID: 2, Type: entities.Deposit, Amount: d(900), Balance: d(900)}},
+		}
+		mockUseCase.On("GetLedger", int64(2), from, time.Time{}).Return(ledger, nil)
+
+		req := mux.SetURLVars(httptest.NewRequest(http.MethodGet, "/account/2/ledger?from=2024-01-01T00:00:00Z", nil), map[string]string{"id": "2"})
+		rec := httptest.NewRecorder()
+
+		h.Ledger(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var response usecase.Ledger
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.Len(t, response.Entries, 1)
+		assert.True(t, d(900).Equal(response.Balance))
+		mockUseCase.AssertExpectations(t)
+	})
+
+	t.Run("should refuse invalid dates", func(t *testing.T) {
+		mockUseCase := new(mocks.UseCase)
+		h := handler.NewAccountHandler(mockUseCase)
+
+		req := mux.SetURLVars(httptest.NewRequest(http.MethodGet, "/account/2/ledger?to=yesterday", nil), map[string]string{"id": "2"})
+		rec := httptest.NewRecorder()
+
+		h.Ledger(rec, req)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		mockUseCase.AssertNotCalled(t, "GetLedger")
+	})
+}
+
+// d builds amounts the way they are decoded from JSON, so mocks match them.
+func d(value int64) decimal.Decimal {
+	return decimal.NewFromInt(value)
+}
@@ -5,6 +5,9 @@ package mocks
 import (
 	entities "go-trade-bot/app/entities"
 
+	usecase "go-trade-bot/app/usecase/account"
+	time "time"
+
 	mock "github.com/stretchr/testify/mock"
 )
 
@@ -14,16 +17,44 @@ type UseCase struct {
 }
 
 // CreateAccount provides a mock function with given fields: account
-func (_m *UseCase) CreateAccount(account entities.Account) error {
+func (_m *UseCase) CreateAccount(account entities.Account) (entities.Account, error) {
 	ret := _m.Called(account)
 
 	if len(ret) == 0 {
 		panic("no return value specified for CreateAccount")
 	}
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(entities.Account) error); ok {
+	var r0 entities.Account
+	var r1 error
+	if rf, ok := ret.Get(0).(func(entities.Account) (entities.Account, error)); ok {
+		return rf(account)
+	}
+	if rf, ok := ret.Get(0).(func(entities.Account) entities.Account); ok {
 		r0 = rf(account)
+	} else {
+		r0 = ret.Get(0).(entities.Account)
+	}
+
+	if rf, ok := ret.Get(1).(func(entities.Account) error); ok {
+		r1 = rf(account)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteAccount provides a mock function with given fields: id
+func (_m *UseCase) DeleteAccount(id int64) error {
+	ret := _m.Called(id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteAccount")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int64) error); ok {
+		r0 = rf(id)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -31,9 +62,9 @@ func (_m *UseCase) CreateAccount(account entities.Account) error {
 	return r0
 }
 
-// GetAccount provides a mock function with no fields
-func (_m *UseCase) GetAccount() (entities.Account, error) {
-	ret := _m.Called()
+// GetAccount provides a mock function with given fields: id
+func (_m *UseCase) GetAccount(id int64) (entities.Account, error) {
+	ret := _m.Called(id)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetAccount")
@@ -41,13 +72,43 @@ func (_m *UseCase) GetAccount() (entities.Account, error) {
 
 	var r0 entities.Account
 	var r1 error
-	if rf, ok := ret.Get(0).(func() (entities.Account, error)); ok {
+	if rf, ok := ret.Get(0).(func(int64) (entities.Account, error)); ok {
+		return rf(id)
+	}
+	if rf, ok := ret.Get(0).(func(int64) entities.Account); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Get(0).(entities.Account)
+	}
+
+	if rf, ok := ret.Get(1).(func(int64) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAll provides a mock function with no fields
+func (_m *UseCase) GetAll() ([]entities.Account, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAll")
+	}
+
+	var r0 []entities.Account
+	var r1 error
+	if rf, ok := ret.Get(0).(func() ([]entities.Account, error)); ok {
 		return rf()
 	}
-	if rf, ok := ret.Get(0).(func() entities.Account); ok {
+	if rf, ok := ret.Get(0).(func() []entities.Account); ok {
 		r0 = rf()
 	} else {
-		r0 = ret.Get(0).(entities.Account)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entities.Account)
+		}
 	}
 
 	if rf, ok := ret.Get(1).(func() error); ok {
@@ -59,6 +120,62 @@ func (_m *UseCase) GetAccount() (entities.Account, error) {
 	return r0, r1
 }
 
+// GetLedger provides a mock function with given fields: id, from, to
+func (_m *UseCase) GetLedger(id int64, from time.Time, to time.Time) (usecase.Ledger, error) {
+	ret := _m.Called(id, from, to)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLedger")
+	}
+
+	var r0 usecase.Ledger
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64, time.Time, time.Time) (usecase.Ledger, error)); ok {
+		return rf(id, from, to)
+	}
+	if rf, ok := ret.Get(0).(func(int64, time.Time, time.Time) usecase.Ledger); ok {
+		r0 = rf(id, from, to)
+	} else {
+		r0 = ret.Get(0).(usecase.Ledger)
+	}
+
+	if rf, ok := ret.Get(1).(func(int64, time.Time, time.Time) error); ok {
+		r1 = rf(id, from, to)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateAccount provides a mock function with given fields: id, account
+func (_m *UseCase) UpdateAccount(id int64, account entities.Account) (entities.Account, error) {
+	ret := _m.Called(id, account)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateAccount")
+	}
+
+	var r0 entities.Account
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64, entities.Account) (entities.Account, error)); ok {
+		return rf(id, account)
+	}
+	if rf, ok := ret.Get(0).(func(int64, entities.Account) entities.Account); ok {
+		r0 = rf(id, account)
+	} else {
+		r0 = ret.Get(0).(entities.Account)
+	}
+
+	if rf, ok := ret.Get(1).(func(int64, entities.Account) error); ok {
+		r1 = rf(id, account)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewUseCase creates a new instance of UseCase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewUseCase(t interface {
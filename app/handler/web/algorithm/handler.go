@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"encoding/json"
+	"go-trade-bot/app/services/algorithm"
+	"go-trade-bot/internal/handler"
+	"net/http"
+)
+
+type Registry interface {
+	List() []algorithm.Definition
+}
+
+type AlgorithmHandler struct {
+	Registry Registry
+}
+
+func NewAlgorithmHandler(r Registry) *AlgorithmHandler {
+	return &AlgorithmHandler{
+		Registry: r,
+	}
+}
+
+func (h *AlgorithmHandler) Handlers() []handler.Configuration {
+	return []handler.Configuration{
+		{
+			Pattern: "/algorithms",
+			Action:  h.List,
+			Method:  http.MethodGet,
+		},
+	}
+}
+
+// List returns the registered algorithms with the documentation of their
+// parameters.
+func (h *AlgorithmHandler) List(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(h.Registry.List()); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
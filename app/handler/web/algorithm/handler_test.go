@@ -0,0 +1,41 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"go-trade-bot/app/entities"
+	handler "go-trade-bot/app/handler/web/algorithm"
+	"go-trade-bot/app/services/algorithm"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAlgorithmHandler_List(t *testing.T) {
+	registry := algorithm.NewRegistry()
+	registry.Register(algorithm.Definition{
+		Name:        entities.Grid,
+		Description: "Grid",
+		Config: struct {
+			GridLevels int `json:"grid_levels" default:"8" doc:"Levels"`
+		}{},
+		New: func(strategy entities.Strategy, deps algorithm.Dependencies) algorithm.Processor { return nil },
+	})
+	h := handler.NewAlgorithmHandler(registry)
+
+	req := httptest.NewRequest(http.MethodGet, "/algorithms", nil)
+	rec := httptest.NewRecorder()
+
+	h.List(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var result []map[string]any
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &result))
+	assert.Len(t, result, 1)
+	assert.Equal(t, "grid", result[0]["name"])
+	parameter := result[0]["parameters"].([]any)[0].(map[string]any)
+	assert.Equal(t, "grid_levels", parameter["name"])
+	assert.Equal(t, "integer", parameter["type"])
+	assert.Equal(t, 8.0, parameter["default"])
+}
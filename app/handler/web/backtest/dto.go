@@ -0,0 +1,21 @@
+package handler
+
+import (
+	usecase "go-trade-bot/app/usecase/backtest"
+
+	"github.com/shopspring/decimal"
+)
+
+type BacktestDto struct {
+	Candles         int             `json:"candles"`
+	InitialAmount   decimal.Decimal `json:"initial_amount"`
+	AvailableOrders int64           `json:"available_orders"`
+}
+
+func (d BacktestDto) ToParameters() usecase.Parameters {
+	return usecase.Parameters{
+		Candles:         d.Candles,
+		InitialAmount:   d.InitialAmount,
+		AvailableOrders: d.AvailableOrders,
+	}
+}
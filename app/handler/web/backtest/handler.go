@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"go-trade-bot/app/services/backtest"
+	usecase "go-trade-bot/app/usecase/backtest"
+	"go-trade-bot/internal/handler"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+type UseCase interface {
+	Run(ctx context.Context, strategyID uint, params usecase.Parameters) (backtest.Result, error)
+}
+
+type BacktestHandler struct {
+	UseCase UseCase
+}
+
+func NewBacktestHandler(u UseCase) *BacktestHandler {
+	return &BacktestHandler{
+		UseCase: u,
+	}
+}
+
+func (h *BacktestHandler) Handlers() []handler.Configuration {
+	return []handler.Configuration{
+		{
+			Pattern: "/strategy/{id}/backtest",
+			Action:  h.Post,
+			Method:  http.MethodPost,
+		},
+	}
+}
+
+func (h *BacktestHandler) Post(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	var dto BacktestDto
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.UseCase.Run(r.Context(), uint(id), dto.ToParameters())
+	if err != nil {
+		handler.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
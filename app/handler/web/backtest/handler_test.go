@@ -0,0 +1,68 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	handler "go-trade-bot/app/handler/web/backtest"
+	"go-trade-bot/app/handler/web/backtest/mocks"
+	"go-trade-bot/app/services/backtest"
+	usecase "go-trade-bot/app/usecase/backtest"
+	"go-trade-bot/internal/customerror"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestBacktestHandler_Post(t *testing.T) {
+	mockUseCase := new(mocks.UseCase)
+	h := handler.NewBacktestHandler(mockUseCase)
+
+	body, err := json.Marshal(handler.BacktestDto{Candles: 500, InitialAmount: decimal.NewFromInt(1000)})
+	assert.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/strategy/1/backtest", bytes.NewBuffer(body))
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+	rec := httptest.NewRecorder()
+
+	mockUseCase.On("Run", mock.Anything, uint(1), usecase.Parameters{Candles: 500, InitialAmount: decimal.NewFromInt(1000)}).
+		Return(backtest.Result{Summary: backtest.Summary{Trades: 2}}, nil)
+
+	h.Post(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var result backtest.Result
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &result))
+	assert.Equal(t, 2, result.Summary.Trades)
+	mockUseCase.AssertExpectations(t)
+}
+
+func TestBacktestHandler_Post_InvalidID(t *testing.T) {
+	h := handler.NewBacktestHandler(new(mocks.UseCase))
+
+	req := httptest.NewRequest(http.MethodPost, "/strategy/abc/backtest", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "abc"})
+	rec := httptest.NewRecorder()
+
+	h.Post(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestBacktestHandler_Post_ValidationError(t *testing.T) {
+	mockUseCase := new(mocks.UseCase)
+	h := handler.NewBacktestHandler(mockUseCase)
+
+	req := httptest.NewRequest(http.MethodPost, "/strategy/1/backtest", bytes.NewBufferString(`{"candles": 0}`))
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+	rec := httptest.NewRecorder()
+
+	mockUseCase.On("Run", mock.Anything, uint(1), mock.Anything).
+		Return(backtest.Result{}, customerror.New(http.StatusBadRequest, "Candles must be between 1 and 10000"))
+
+	h.Post(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
@@ -0,0 +1,59 @@
+// Code generated by mockery v2.53.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	backtest "go-trade-bot/app/services/backtest"
+
+	usecase "go-trade-bot/app/usecase/backtest"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// UseCase is an autogenerated mock type for the UseCase type
+type UseCase struct {
+	mock.Mock
+}
+
+// Run provides a mock function with given fields: ctx, strategyID, params
+func (_m *UseCase) Run(ctx context.Context, strategyID uint, params usecase.Parameters) (backtest.Result, error) {
+	ret := _m.Called(ctx, strategyID, params)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Run")
+	}
+
+	var r0 backtest.Result
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, usecase.Parameters) (backtest.Result, error)); ok {
+		return rf(ctx, strategyID, params)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint, usecase.Parameters) backtest.Result); ok {
+		r0 = rf(ctx, strategyID, params)
+	} else {
+		r0 = ret.Get(0).(backtest.Result)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint, usecase.Parameters) error); ok {
+		r1 = rf(ctx, strategyID, params)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewUseCase creates a new instance of UseCase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewUseCase(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *UseCase {
+	mock := &UseCase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,9 @@
+package handler
+
+import "time"
+
+type BackfillDto struct {
+	Symbol   string    `json:"symbol"`
+	Interval string    `json:"interval"`
+	From     time.Time `json:"from"`
+}
@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	usecase "go-trade-bot/app/usecase/candle"
+	"go-trade-bot/internal/broker"
+	"go-trade-bot/internal/handler"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type UseCase interface {
+	ListKline(ctx context.Context, symbol string, interval string, limit int) ([]broker.Candle, error)
+	DetectGaps(ctx context.Context, symbol string, interval string, start time.Time, end time.Time) ([]usecase.Gap, error)
+	RequestBackfill(ctx context.Context, symbol string, interval string, from time.Time) error
+}
+
+type CandleHandler struct {
+	UseCase UseCase
+}
+
+func NewCandleHandler(u UseCase) *CandleHandler {
+	return &CandleHandler{
+		UseCase: u,
+	}
+}
+
+func (h *CandleHandler) Handlers() []handler.Configuration {
+	return []handler.Configuration{
+		{
+			Pattern: "/candles",
+			Action:  h.List,
+			Method:  http.MethodGet,
+		},
+		{
+			Pattern: "/candles/gaps",
+			Action:  h.Gaps,
+			Method:  http.MethodGet,
+		},
+		{
+			Pattern: "/candles/backfill",
+			Action:  h.Backfill,
+			Method:  http.MethodPost,
+		},
+	}
+}
+
+func (h *CandleHandler) List(w http.ResponseWriter, r *http.Request) {
+	symbol, interval, ok := seriesParams(w, r)
+	if !ok {
+		return
+	}
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+	if err != nil || limit <= 0 {
+		http.Error(w, "Limit must be a positive integer", http.StatusBadRequest)
+		return
+	}
+
+	candles, err := h.UseCase.ListKline(r.Context(), symbol, interval, limit)
+	if err != nil {
+		handler.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(candles)
+}
+
+func (h *CandleHandler) Gaps(w http.ResponseWriter, r *http.Request) {
+	symbol, interval, ok := seriesParams(w, r)
+	if !ok {
+		return
+	}
+	from, err := time.Parse(time.RFC3339, r.URL.Query().Get("from"))
+	if err != nil {
+		http.Error(w, "From must be a RFC3339 time", http.StatusBadRequest)
+		return
+	}
+	to := time.Now()
+	if value := r.URL.Query().Get("to"); value != "" {
+		to, err = time.Parse(time.RFC3339, value)
+		if err != nil {
+			http.Error(w, "To must be a RFC3339 time", http.StatusBadRequest)
+			return
+		}
+	}
+
+	gaps, err := h.UseCase.DetectGaps(r.Context(), symbol, interval, from, to)
+	if err != nil {
+		handler.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(gaps)
+}
+
+func (h *CandleHandler) Backfill(w http.ResponseWriter, r *http.Request) {
+	var dto BackfillDto
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.UseCase.RequestBackfill(r.Context(), dto.Symbol, dto.Interval, dto.From); err != nil {
+		handler.WriteError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func seriesParams(w http.ResponseWriter, r *http.Request) (string, string, bool) {
+	symbol := r.URL.Query().Get("symbol")
+	if symbol == "" {
+		http.Error(w, "Symbol is required", http.StatusBadRequest)
+		return "", "", false
+	}
+	interval := r.URL.Query().Get("interval")
+	if interval == "" {
+		http.Error(w, "Interval is required", http.StatusBadRequest)
+		return "", "", false
+	}
+	return symbol, interval, true
+}
@@ -0,0 +1,79 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	handler "go-trade-bot/app/handler/web/candle"
+	"go-trade-bot/app/handler/web/candle/mocks"
+	"go-trade-bot/internal/broker"
+	"go-trade-bot/internal/customerror"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCandleHandler_List(t *testing.T) {
+	mockUseCase := new(mocks.UseCase)
+	h := handler.NewCandleHandler(mockUseCase)
+
+	req := httptest.NewRequest(http.MethodGet, "/candles?symbol=BTCUSDT&interval=1h&limit=2", nil)
+	rec := httptest.NewRecorder()
+
+	mockUseCase.On("ListKline", mock.Anything, "BTCUSDT", "1h", 2).
+		Return([]broker.Candle{{Close: 1}, {Close: 2}}, nil)
+
+	h.List(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var candles []broker.Candle
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &candles))
+	assert.Len(t, candles, 2)
+	mockUseCase.AssertExpectations(t)
+}
+
+func TestCandleHandler_List_InvalidLimit(t *testing.T) {
+	h := handler.NewCandleHandler(new(mocks.UseCase))
+
+	req := httptest.NewRequest(http.MethodGet, "/candles?symbol=BTCUSDT&interval=1h&limit=abc", nil)
+	rec := httptest.NewRecorder()
+
+	h.List(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestCandleHandler_Backfill(t *testing.T) {
+	mockUseCase := new(mocks.UseCase)
+	h := handler.NewCandleHandler(mockUseCase)
+
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	body, err := json.Marshal(handler.BackfillDto{Symbol: "BTCUSDT", Interval: "1h", From: from})
+	assert.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/candles/backfill", bytes.NewBuffer(body))
+	rec := httptest.NewRecorder()
+
+	mockUseCase.On("RequestBackfill", mock.Anything, "BTCUSDT", "1h", from).Return(nil)
+
+	h.Backfill(rec, req)
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+	mockUseCase.AssertExpectations(t)
+}
+
+func TestCandleHandler_Backfill_ValidationError(t *testing.T) {
+	mockUseCase := new(mocks.UseCase)
+	h := handler.NewCandleHandler(mockUseCase)
+
+	req := httptest.NewRequest(http.MethodPost, "/candles/backfill", bytes.NewBufferString(`{"symbol": "BTCUSDT", "interval": "10m"}`))
+	rec := httptest.NewRecorder()
+
+	mockUseCase.On("RequestBackfill", mock.Anything, "BTCUSDT", "10m", time.Time{}).
+		Return(customerror.New(http.StatusBadRequest, "Unsupported interval 10m"))
+
+	h.Backfill(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "Unsupported interval 10m")
+}
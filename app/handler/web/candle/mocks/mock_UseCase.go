@@ -0,0 +1,110 @@
+// Code generated by mockery v2.53.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	broker "go-trade-bot/internal/broker"
+
+	usecase "go-trade-bot/app/usecase/candle"
+	time "time"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// UseCase is an autogenerated mock type for the UseCase type
+type UseCase struct {
+	mock.Mock
+}
+
+// DetectGaps provides a mock function with given fields: ctx, symbol, interval, start, end
+func (_m *UseCase) DetectGaps(ctx context.Context, symbol string, interval string, start time.Time, end time.Time) ([]usecase.Gap, error) {
+	ret := _m.Called(ctx, symbol, interval, start, end)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DetectGaps")
+	}
+
+	var r0 []usecase.Gap
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Time, time.Time) ([]usecase.Gap, error)); ok {
+		return rf(ctx, symbol, interval, start, end)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Time, time.Time) []usecase.Gap); ok {
+		r0 = rf(ctx, symbol, interval, start, end)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]usecase.Gap)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, time.Time, time.Time) error); ok {
+		r1 = rf(ctx, symbol, interval, start, end)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListKline provides a mock function with given fields: ctx, symbol, interval, limit
+func (_m *UseCase) ListKline(ctx context.Context, symbol string, interval string, limit int) ([]broker.Candle, error) {
+	ret := _m.Called(ctx, symbol, interval, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListKline")
+	}
+
+	var r0 []broker.Candle
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int) ([]broker.Candle, error)); ok {
+		return rf(ctx, symbol, interval, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int) []broker.Candle); ok {
+		r0 = rf(ctx, symbol, interval, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]broker.Candle)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, int) error); ok {
+		r1 = rf(ctx, symbol, interval, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RequestBackfill provides a mock function with given fields: ctx, symbol, interval, from
+func (_m *UseCase) RequestBackfill(ctx context.Context, symbol string, interval string, from time.Time) error {
+	ret := _m.Called(ctx, symbol, interval, from)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RequestBackfill")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Time) error); ok {
+		r0 = rf(ctx, symbol, interval, from)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewUseCase creates a new instance of UseCase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewUseCase(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *UseCase {
+	mock := &UseCase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"go-trade-bot/app/entities"
+	"go-trade-bot/internal/handler"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+type UseCase interface {
+	List(ctx context.Context, strategyID uint) ([]entities.GridState, error)
+	Reset(ctx context.Context, strategyID uint, symbol string) error
+}
+
+type GridHandler struct {
+	UseCase UseCase
+}
+
+func NewGridHandler(u UseCase) *GridHandler {
+	return &GridHandler{
+		UseCase: u,
+	}
+}
+
+func (h *GridHandler) Handlers() []handler.Configuration {
+	return []handler.Configuration{
+		{
+			Pattern: "/strategy/{id}/grid",
+			Action:  h.List,
+			Method:  http.MethodGet,
+		},
+		{
+			Pattern: "/strategy/{id}/grid",
+			Action:  h.Reset,
+			Method:  http.MethodDelete,
+		},
+		{
+			Pattern: "/strategy/{id}/grid/{symbol}",
+			Action:  h.Reset,
+			Method:  http.MethodDelete,
+		},
+	}
+}
+
+func (h *GridHandler) List(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	grids, err := h.UseCase.List(r.Context(), uint(id))
+	if err != nil {
+		handler.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(grids)
+}
+
+// Reset discards the grid of the symbol in the path, or every grid of the
+// strategy without one.
+func (h *GridHandler) Reset(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.UseCase.Reset(r.Context(), uint(id), vars["symbol"]); err != nil {
+		handler.WriteError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
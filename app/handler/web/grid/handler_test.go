@@ -0,0 +1,66 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"go-trade-bot/app/entities"
+	handler "go-trade-bot/app/handler/web/grid"
+	"go-trade-bot/app/handler/web/grid/mocks"
+	"go-trade-bot/internal/customerror"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGridHandler_List(t *testing.T) {
+	mockUseCase := new(mocks.UseCase)
+	h := handler.NewGridHandler(mockUseCase)
+
+	req := httptest.NewRequest(http.MethodGet, "/strategy/1/grid", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+	rec := httptest.NewRecorder()
+
+	mockUseCase.On("List", mock.Anything, uint(1)).Return([]entities.GridState{
+		{StrategyID: 1, Symbol: "BTCUSDT", Levels: []entities.GridLevel{{Type: entities.GridBuy, Price: 100}}},
+	}, nil)
+
+	h.List(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var grids []entities.GridState
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &grids))
+	assert.Len(t, grids, 1)
+	assert.Equal(t, 100.0, grids[0].Levels[0].Price)
+}
+
+func TestGridHandler_Reset(t *testing.T) {
+	mockUseCase := new(mocks.UseCase)
+	h := handler.NewGridHandler(mockUseCase)
+
+	req := httptest.NewRequest(http.MethodDelete, "/strategy/1/grid/BTCUSDT", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "1", "symbol": "BTCUSDT"})
+	rec := httptest.NewRecorder()
+
+	mockUseCase.On("Reset", mock.Anything, uint(1), "BTCUSDT").Return(nil)
+
+	h.Reset(rec, req)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	mockUseCase.AssertExpectations(t)
+}
+
+func TestGridHandler_Reset_NotFound(t *testing.T) {
+	mockUseCase := new(mocks.UseCase)
+	h := handler.NewGridHandler(mockUseCase)
+
+	req := httptest.NewRequest(http.MethodDelete, "/strategy/1/grid/ETHUSDT", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "1", "symbol": "ETHUSDT"})
+	rec := httptest.NewRecorder()
+
+	mockUseCase.On("Reset", mock.Anything, uint(1), "ETHUSDT").Return(customerror.New(http.StatusNotFound, "Grid not found"))
+
+	h.Reset(rec, req)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
@@ -0,0 +1,77 @@
+// Code generated by mockery v2.53.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	entities "go-trade-bot/app/entities"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// UseCase is an autogenerated mock type for the UseCase type
+type UseCase struct {
+	mock.Mock
+}
+
+// List provides a mock function with given fields: ctx, strategyID
+func (_m *UseCase) List(ctx context.Context, strategyID uint) ([]entities.GridState, error) {
+	ret := _m.Called(ctx, strategyID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 []entities.GridState
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) ([]entities.GridState, error)); ok {
+		return rf(ctx, strategyID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) []entities.GridState); ok {
+		r0 = rf(ctx, strategyID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entities.GridState)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, strategyID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Reset provides a mock function with given fields: ctx, strategyID, symbol
+func (_m *UseCase) Reset(ctx context.Context, strategyID uint, symbol string) error {
+	ret := _m.Called(ctx, strategyID, symbol)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Reset")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, string) error); ok {
+		r0 = rf(ctx, strategyID, symbol)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewUseCase creates a new instance of UseCase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewUseCase(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *UseCase {
+	mock := &UseCase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
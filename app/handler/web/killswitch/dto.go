@@ -0,0 +1,6 @@
+package handler
+
+type EngageDto struct {
+	Reason       string `json:"reason"`
+	CloseSignals bool   `json:"close_signals"`
+}
@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"go-trade-bot/internal/handler"
+	"go-trade-bot/internal/killswitch"
+	"io"
+	"net/http"
+)
+
+type UseCase interface {
+	Engage(ctx context.Context, reason string, closeSignals bool) (killswitch.State, error)
+	Release(ctx context.Context) error
+	State(ctx context.Context) (killswitch.State, error)
+}
+
+type KillSwitchHandler struct {
+	UseCase UseCase
+}
+
+func NewKillSwitchHandler(u UseCase) *KillSwitchHandler {
+	return &KillSwitchHandler{
+		UseCase: u,
+	}
+}
+
+func (h *KillSwitchHandler) Handlers() []handler.Configuration {
+	return []handler.Configuration{
+		{
+			Pattern: "/killswitch",
+			Action:  h.Engage,
+			Method:  http.MethodPost,
+		},
+		{
+			Pattern: "/killswitch",
+			Action:  h.Get,
+			Method:  http.MethodGet,
+		},
+		{
+			Pattern: "/killswitch",
+			Action:  h.Release,
+			Method:  http.MethodDelete,
+		},
+	}
+}
+
+// Engage pauses every strategy, the body is optional.
+func (h *KillSwitchHandler) Engage(w http.ResponseWriter, r *http.Request) {
+	var dto EngageDto
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil && !errors.Is(err, io.EOF) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	state, err := h.UseCase.Engage(r.Context(), dto.Reason, dto.CloseSignals)
+	if err != nil {
+		handler.WriteError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(state)
+}
+
+func (h *KillSwitchHandler) Get(w http.ResponseWriter, r *http.Request) {
+	state, err := h.UseCase.State(r.Context())
+	if err != nil {
+		handler.WriteError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(state)
+}
+
+func (h *KillSwitchHandler) Release(w http.ResponseWriter, r *http.Request) {
+	if err := h.UseCase.Release(r.Context()); err != nil {
+		handler.WriteError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
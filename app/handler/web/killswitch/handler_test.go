@@ -0,0 +1,74 @@
+package handler_test
+
+import (
+	"encoding/json"
+	handler "go-trade-bot/app/handler/web/killswitch"
+	"go-trade-bot/app/handler/web/killswitch/mocks"
+	"go-trade-bot/internal/customerror"
+	"go-trade-bot/internal/killswitch"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestKillSwitchHandler_Engage(t *testing.T) {
+	t.Run("should engage with the reason of the body", func(t *testing.T) {
+		mockUseCase := new(mocks.UseCase)
+		h := handler.NewKillSwitchHandler(mockUseCase)
+
+		body := `{"reason": "exchange outage", "close_signals": true}`
+		req := httptest.NewRequest(http.MethodPost, "/killswitch", strings.NewReader(body))
+		rec := httptest.NewRecorder()
+
+		mockUseCase.On("Engage", mock.Anything, "exchange outage", true).Return(killswitch.State{Engaged: true, Reason: "exchange outage", CloseSignals: true}, nil).Once()
+
+		h.Engage(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var state killswitch.State
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &state))
+		assert.True(t, state.Engaged)
+		mockUseCase.AssertExpectations(t)
+	})
+
+	t.Run("should engage without a body", func(t *testing.T) {
+		mockUseCase := new(mocks.UseCase)
+		h := handler.NewKillSwitchHandler(mockUseCase)
+
+		req := httptest.NewRequest(http.MethodPost, "/killswitch", nil)
+		rec := httptest.NewRecorder()
+
+		mockUseCase.On("Engage", mock.Anything, "", false).Return(killswitch.State{Engaged: true}, nil).Once()
+
+		h.Engage(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		mockUseCase.AssertExpectations(t)
+	})
+
+	t.Run("should reject invalid bodies", func(t *testing.T) {
+		h := handler.NewKillSwitchHandler(new(mocks.UseCase))
+
+		req := httptest.NewRequest(http.MethodPost, "/killswitch", strings.NewReader("{"))
+		rec := httptest.NewRecorder()
+
+		h.Engage(rec, req)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
+
+func TestKillSwitchHandler_Release(t *testing.T) {
+	mockUseCase := new(mocks.UseCase)
+	h := handler.NewKillSwitchHandler(mockUseCase)
+
+	req := httptest.NewRequest(http.MethodDelete, "/killswitch", nil)
+	rec := httptest.NewRecorder()
+
+	mockUseCase.On("Release", mock.Anything).Return(customerror.New(http.StatusConflict, "Kill switch is not engaged")).Once()
+
+	h.Release(rec, req)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+}
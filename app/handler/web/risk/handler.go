@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"go-trade-bot/app/entities"
+	usecase "go-trade-bot/app/usecase/risk"
+	"go-trade-bot/internal/handler"
+	"net/http"
+	"strconv"
+)
+
+const defaultEventsLimit = 100
+
+type UseCase interface {
+	Evaluate(ctx context.Context) (usecase.Status, error)
+	Reset(ctx context.Context) (usecase.Status, error)
+	Events(ctx context.Context, limit int) ([]entities.RiskEvent, error)
+}
+
+type RiskHandler struct {
+	UseCase UseCase
+}
+
+func NewRiskHandler(u UseCase) *RiskHandler {
+	return &RiskHandler{
+		UseCase: u,
+	}
+}
+
+func (h *RiskHandler) Handlers() []handler.Configuration {
+	return []handler.Configuration{
+		{
+			Pattern: "/risk",
+			Action:  h.Get,
+			Method:  http.MethodGet,
+		},
+		{
+			Pattern: "/risk/events",
+			Action:  h.Events,
+			Method:  http.MethodGet,
+		},
+		{
+			Pattern: "/risk/reset",
+			Action:  h.Reset,
+			Method:  http.MethodPost,
+		},
+	}
+}
+
+func (h *RiskHandler) Get(w http.ResponseWriter, r *http.Request) {
+	status, err := h.UseCase.Evaluate(r.Context())
+	if err != nil {
+		handler.WriteError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(status)
+}
+
+// Events lists the last trips and resets, limit defaults to 100.
+func (h *RiskHandler) Events(w http.ResponseWriter, r *http.Request) {
+	limit := defaultEventsLimit
+	if value := r.URL.Query().Get("limit"); value != "" {
+		var err error
+		limit, err = strconv.Atoi(value)
+		if err != nil || limit <= 0 {
+			http.Error(w, "Invalid limit", http.StatusBadRequest)
+			return
+		}
+	}
+
+	events, err := h.UseCase.Events(r.Context(), limit)
+	if err != nil {
+		handler.WriteError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(events)
+}
+
+// Reset clears a tripped breaker so entries open again.
+func (h *RiskHandler) Reset(w http.ResponseWriter, r *http.Request) {
+	status, err := h.UseCase.Reset(r.Context())
+	if err != nil {
+		handler.WriteError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(status)
+}
@@ -0,0 +1,101 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"go-trade-bot/app/entities"
+	handler "go-trade-bot/app/handler/web/risk"
+	"go-trade-bot/app/handler/web/risk/mocks"
+	usecase "go-trade-bot/app/usecase/risk"
+	"go-trade-bot/internal/customerror"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRiskHandler_Get(t *testing.T) {
+	mockUseCase := new(mocks.UseCase)
+	h := handler.NewRiskHandler(mockUseCase)
+
+	req := httptest.NewRequest(http.MethodGet, "/risk", nil)
+	rec := httptest.NewRecorder()
+
+	mockUseCase.On("Evaluate", mock.Anything).Return(usecase.Status{
+		Halted: true,
+		Rule:   entities.DrawdownRule,
+		Equity: decimal.NewFromInt(800),
+	}, nil)
+
+	h.Get(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var status usecase.Status
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &status))
+	assert.True(t, status.Halted)
+	assert.Equal(t, entities.DrawdownRule, status.Rule)
+	assert.Equal(t, "800", status.Equity.String())
+}
+
+func TestRiskHandler_Events(t *testing.T) {
+	t.Run("should list the events up to the limit", func(t *testing.T) {
+		mockUseCase := new(mocks.UseCase)
+		h := handler.NewRiskHandler(mockUseCase)
+
+		req := httptest.NewRequest(http.MethodGet, "/risk/events?limit=5", nil)
+		rec := httptest.NewRecorder()
+
+		mockUseCase.On("Events", mock.Anything, 5).Return([]entities.RiskEvent{
+			{ID: 1, Type: entities.RiskTrip, Rule: entities.DailyLossRule},
+		}, nil).Once()
+
+		h.Events(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var events []entities.RiskEvent
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &events))
+		assert.Len(t, events, 1)
+		mockUseCase.AssertExpectations(t)
+	})
+
+	t.Run("should reject invalid limits", func(t *testing.T) {
+		h := handler.NewRiskHandler(new(mocks.UseCase))
+
+		req := httptest.NewRequest(http.MethodGet, "/risk/events?limit=-1", nil)
+		rec := httptest.NewRecorder()
+
+		h.Events(rec, req)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
+
+func TestRiskHandler_Reset(t *testing.T) {
+	t.Run("should reset the breaker", func(t *testing.T) {
+		mockUseCase := new(mocks.UseCase)
+		h := handler.NewRiskHandler(mockUseCase)
+
+		req := httptest.NewRequest(http.MethodPost, "/risk/reset", nil)
+		rec := httptest.NewRecorder()
+
+		mockUseCase.On("Reset", mock.Anything).Return(usecase.Status{}, nil).Once()
+
+		h.Reset(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		mockUseCase.AssertExpectations(t)
+	})
+
+	t.Run("should answer conflict when nothing tripped", func(t *testing.T) {
+		mockUseCase := new(mocks.UseCase)
+		h := handler.NewRiskHandler(mockUseCase)
+
+		req := httptest.NewRequest(http.MethodPost, "/risk/reset", nil)
+		rec := httptest.NewRecorder()
+
+		mockUseCase.On("Reset", mock.Anything).Return(usecase.Status{}, customerror.New(http.StatusConflict, "Risk manager is not halted")).Once()
+
+		h.Reset(rec, req)
+		assert.Equal(t, http.StatusConflict, rec.Code)
+	})
+}
@@ -0,0 +1,27 @@
+package handler
+
+import (
+	usecase "go-trade-bot/app/usecase/signal"
+
+	"github.com/shopspring/decimal"
+)
+
+// ResolveDto is what the exchange reports for the order of a stuck signal,
+// filled false when the order never went through.
+type ResolveDto struct {
+	Filled   bool            `json:"filled"`
+	OrderID  string          `json:"order_id"`
+	Price    decimal.Decimal `json:"price"`
+	Quantity decimal.Decimal `json:"quantity"`
+	Fee      decimal.Decimal `json:"fee"`
+}
+
+func (d ResolveDto) ToResolution() usecase.Resolution {
+	return usecase.Resolution{
+		Filled:   d.Filled,
+		OrderID:  d.OrderID,
+		Price:    d.Price,
+		Quantity: d.Quantity,
+		Fee:      d.Fee,
+	}
+}
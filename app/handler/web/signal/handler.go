@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"go-trade-bot/app/entities"
+	usecase "go-trade-bot/app/usecase/signal"
 	"go-trade-bot/internal/handler"
 	"net/http"
 	"strconv"
@@ -15,6 +16,7 @@ type UseCase interface {
 	Close(ctx context.Context, id uint) error
 	GetAll(ctx context.Context) ([]entities.Signal, error)
 	GetByID(ctx context.Context, id uint) (entities.Signal, error)
+	Resolve(ctx context.Context, id uint, resolution usecase.Resolution) (entities.Signal, error)
 }
 type SignalHandler struct {
 	UseCase UseCase
@@ -33,6 +35,11 @@ func (h *SignalHandler) Handlers() []handler.Configuration {
 			Action:  h.Close,
 			Method:  http.MethodPost,
 		},
+		{
+			Pattern: "/signal/resolve/{id}",
+			Action:  h.Resolve,
+			Method:  http.MethodPost,
+		},
 		{
 			Pattern: "/signal",
 			Action:  h.GetAll,
@@ -64,6 +71,31 @@ func (h *SignalHandler) Close(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusAccepted)
 }
 
+// Resolve settles a signal stuck pending or closing with what the exchange
+// reports for its order.
+func (h *SignalHandler) Resolve(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	var dto ResolveDto
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	signal, err := h.UseCase.Resolve(r.Context(), uint(id), dto.ToResolution())
+	if err != nil {
+		handler.WriteError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(signal)
+}
+
 func (h *SignalHandler) GetAll(w http.ResponseWriter, r *http.Request) {
 	signals, err := h.UseCase.GetAll(r.Context())
 	if err != nil {
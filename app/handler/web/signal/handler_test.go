@@ -0,0 +1,73 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"go-trade-bot/app/entities"
+	handler "go-trade-bot/app/handler/web/signal"
+	"go-trade-bot/app/handler/web/signal/mocks"
+	usecase "go-trade-bot/app/usecase/signal"
+	"go-trade-bot/internal/customerror"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestSignalHandler_Resolve(t *testing.T) {
+	t.Run("should resolve the signal with the fill of the body", func(t *testing.T) {
+		mockUseCase := new(mocks.UseCase)
+		h := handler.NewSignalHandler(mockUseCase)
+
+		body := `{"filled": true, "order_id": "42", "price": "55000", "quantity": "0.02", "fee": "1.1"}`
+		req := httptest.NewRequest(http.MethodPost, "/signal/resolve/3", strings.NewReader(body))
+		req = mux.SetURLVars(req, map[string]string{"id": "3"})
+		rec := httptest.NewRecorder()
+
+		mockUseCase.On("Resolve", mock.Anything, uint(3), mock.MatchedBy(func(r usecase.Resolution) bool {
+			return r.Filled && r.OrderID == "42" && r.Price.Equal(decimal.NewFromInt(55000)) && r.Fee.Equal(decimal.RequireFromString("1.1"))
+		})).Return(entities.Signal{ID: 3, Status: entities.Closed}, nil).Once()
+
+		h.Resolve(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var signal entities.Signal
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &signal))
+		assert.Equal(t, entities.Closed, signal.Status)
+		mockUseCase.AssertExpectations(t)
+	})
+
+	t.Run("should answer the error of the use case", func(t *testing.T) {
+		mockUseCase := new(mocks.UseCase)
+		h := handler.NewSignalHandler(mockUseCase)
+
+		req := httptest.NewRequest(http.MethodPost, "/signal/resolve/3", strings.NewReader(`{"filled": false}`))
+		req = mux.SetURLVars(req, map[string]string{"id": "3"})
+		rec := httptest.NewRecorder()
+
+		mockUseCase.On("Resolve", mock.Anything, uint(3), usecase.Resolution{}).Return(entities.Signal{}, customerror.New(http.StatusConflict, "Signal 3 is open, only pending and closing signals can be resolved")).Once()
+
+		h.Resolve(rec, req)
+		assert.Equal(t, http.StatusConflict, rec.Code)
+	})
+
+	t.Run("should reject invalid requests", func(t *testing.T) {
+		h := handler.NewSignalHandler(new(mocks.UseCase))
+
+		req := httptest.NewRequest(http.MethodPost, "/signal/resolve/abc", strings.NewReader(`{}`))
+		req = mux.SetURLVars(req, map[string]string{"id": "abc"})
+		rec := httptest.NewRecorder()
+		h.Resolve(rec, req)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+		req = httptest.NewRequest(http.MethodPost, "/signal/resolve/3", strings.NewReader("{"))
+		req = mux.SetURLVars(req, map[string]string{"id": "3"})
+		rec = httptest.NewRecorder()
+		h.Resolve(rec, req)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
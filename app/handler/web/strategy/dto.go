@@ -12,8 +12,10 @@ type StrategyDto struct {
 	Description      string          `json:"description"`
 	MonitoredSymbols []string        `json:"monitored_symbols"`
 	Status           string          `json:"status" only:"productive,testing,disabled"`
+	AccountID        int64           `json:"account_id"`
 	Algorithm        string          `json:"algorithm"`
 	Cycle            int             `json:"cycle"`
+	CandleInterval   string          `json:"candle_interval"`
 	Configuration    json.RawMessage `json:"configuration"`
 }
 
@@ -24,9 +26,11 @@ func (s StrategyDto) ToModel() entities.Strategy {
 		MonitoredSymbols: s.MonitoredSymbols,
 		Algorithm:        entities.Algorithm(s.Algorithm),
 		Status:           entities.StrategyStatus(s.Status),
+		AccountID:        s.AccountID,
 		StrategyConfiguration: entities.StrategyConfiguration{
-			Cycle:         entities.Cycle(s.Cycle),
-			Configuration: datatypes.JSON(s.Configuration),
+			Cycle:          entities.Cycle(s.Cycle),
+			CandleInterval: s.CandleInterval,
+			Configuration:  datatypes.JSON(s.Configuration),
 		},
 	}
 }
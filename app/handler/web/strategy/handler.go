@@ -78,7 +78,8 @@ func (h *StrategyHandler) Post(w http.ResponseWriter, r *http.Request) {
 	err = h.UseCase.Save(r.Context(), dto.ToModel())
 
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		handler.WriteError(w, err)
+		return
 	}
 
 	w.WriteHeader(http.StatusCreated)
@@ -129,7 +130,7 @@ func (h *StrategyHandler) Put(w http.ResponseWriter, r *http.Request) {
 
 	err = h.UseCase.Update(r.Context(), strategy)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		handler.WriteError(w, err)
 		return
 	}
 
@@ -6,6 +6,7 @@ import (
 	"go-trade-bot/app/entities"
 	handler "go-trade-bot/app/handler/web/strategy"
 	"go-trade-bot/app/handler/web/strategy/mocks"
+	"go-trade-bot/internal/customerror"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -150,3 +151,40 @@ func TestStrategyHandler_GetAll_EmptyResponse(t *testing.T) {
 	assert.Empty(t, response)
 	mockUseCase.AssertExpectations(t)
 }
+
+func TestStrategyHandler_Post_InvalidConfiguration(t *testing.T) {
+	mockUseCase := new(mocks.UseCase)
+	h := handler.NewStrategyHandler(mockUseCase)
+
+	dto := handler.StrategyDto{
+		Name:             "Test Strategy",
+		Description:      "Test Description",
+		MonitoredSymbols: []string{"BTCUSDT"},
+		Algorithm:        "bollinger",
+		Cycle:            5,
+		Configuration:    json.RawMessage(`{"take_profit":0.5}`),
+	}
+
+	body, err := json.Marshal(dto)
+	assert.NoError(t, err)
+
+	req, err := http.NewRequest(http.MethodPost, "/strategy", bytes.NewBuffer(body))
+	assert.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	mockUseCase.On("Save", mock.Anything, dto.ToModel()).Return(customerror.NewWithFields(http.StatusBadRequest, "Invalid configuration for algorithm bollinger", []customerror.FieldError{
+		{Field: "configuration.take_profit_pct", Message: "is required"},
+		{Field: "configuration.take_profit", Message: "is not a parameter of the algorithm"},
+	}))
+
+	h.Post(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.JSONEq(t, `{
+		"message": "Invalid configuration for algorithm bollinger",
+		"fields": [
+			{"field": "configuration.take_profit_pct", "message": "is required"},
+			{"field": "configuration.take_profit", "message": "is not a parameter of the algorithm"}
+		]
+	}`, rec.Body.String())
+	mockUseCase.AssertExpectations(t)
+}
@@ -1,9 +1,12 @@
 package repository
 
 import (
+	"context"
 	"go-trade-bot/app/entities"
+	"go-trade-bot/internal/db"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type AccountRepository struct {
@@ -16,8 +19,9 @@ func NewAccountRepository(db *gorm.DB) AccountRepository {
 	}
 }
 
-func (r AccountRepository) Create(account entities.Account) error {
-	return r.db.Create(&account).Error
+func (r AccountRepository) Create(ctx context.Context, account entities.Account) (entities.Account, error) {
+	err := db.Conn(ctx, r.db).Create(&account).Error
+	return account, err
 }
 
 func (r AccountRepository) GetAccountByID(id int64) (entities.Account, error) {
@@ -29,6 +33,47 @@ func (r AccountRepository) GetAccountByID(id int64) (entities.Account, error) {
 	return account, nil
 }
 
-func (r AccountRepository) UpdateAccount(account entities.Account) error {
-	return r.db.Save(&account).Error
+// Lock reads the account and locks its row until the transaction of ctx
+// ends, so concurrent reservations on it run one after the other.
+func (r AccountRepository) Lock(ctx context.Context, id int64) (entities.Account, error) {
+	var account entities.Account
+	err := db.Conn(ctx, r.db).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", id).
+		First(&account).Error
+	if err != nil {
+		return entities.Account{}, err
+	}
+	return account, nil
+}
+
+func (r AccountRepository) GetAll() ([]entities.Account, error) {
+	var accounts []entities.Account
+	err := r.db.Order("id asc").Find(&accounts).Error
+	return accounts, err
+}
+
+func (r AccountRepository) UpdateAccount(ctx context.Context, account entities.Account) error {
+	return db.Conn(ctx, r.db).Save(&account).Error
+}
+
+func (r AccountRepository) Delete(id int64) error {
+	return r.db.Delete(&entities.Account{}, id).Error
+}
+
+// CountStrategies returns how many strategies are bound to the account.
+func (r AccountRepository) CountStrategies(id int64) (int64, error) {
+	var count int64
+	err := r.db.Model(&entities.Strategy{}).Where("account_id = ?", id).Count(&count).Error
+	return count, err
+}
+
+// CountActiveSignals returns how many signals of the account hold a
+// reservation on it, pending, open or closing.
+func (r AccountRepository) CountActiveSignals(ctx context.Context, id int64) (int64, error) {
+	var count int64
+	err := db.Conn(ctx, r.db).Model(&entities.Signal{}).
+		Where("account_id = ? AND status IN ?", id, entities.ActiveStatuses).
+		Count(&count).Error
+	return count, err
 }
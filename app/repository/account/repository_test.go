@@ -1,11 +1,13 @@
 package repository_test
 
 import (
+	"context"
 	"go-trade-bot/app/entities"
 	repository "go-trade-bot/app/repository/account"
 	"testing"
 	"time"
 
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/require"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
@@ -22,13 +24,13 @@ func TestAccountRepository_Create(t *testing.T) {
 	}
 	repo := repository.NewAccountRepository(db)
 	account := entities.Account{
-		Amount:          1000.0,
+		Amount:          d(1000),
 		AvailableOrders: 5,
 		Currency:        "USD",
 		CreatedAt:       time.Now(),
 		UpdatedAt:       time.Now(),
 	}
-	err = repo.Create(account)
+	_, err = repo.Create(context.Background(), account)
 	require.NoError(t, err)
 
 	var result entities.Account
@@ -49,13 +51,13 @@ func TestAccountRepository_GetAccountByID(t *testing.T) {
 	repo := repository.NewAccountRepository(db)
 	account := entities.Account{
 		ID:              1,
-		Amount:          1000.0,
+		Amount:          d(1000),
 		AvailableOrders: 5,
 		Currency:        "USD",
 		CreatedAt:       time.Now(),
 		UpdatedAt:       time.Now(),
 	}
-	err = repo.Create(account)
+	_, err = repo.Create(context.Background(), account)
 	require.NoError(t, err)
 
 	result, err := repo.GetAccountByID(1)
@@ -75,20 +77,89 @@ func TestAccountRepository_UpdateAccount(t *testing.T) {
 	repo := repository.NewAccountRepository(db)
 	account := entities.Account{
 		ID:              1,
-		Amount:          1000.0,
+		Amount:          d(1000),
 		AvailableOrders: 5,
 		Currency:        "USD",
 		CreatedAt:       time.Now(),
 		UpdatedAt:       time.Now(),
 	}
-	err = repo.Create(account)
+	_, err = repo.Create(context.Background(), account)
 	require.NoError(t, err)
 
-	account.Amount = 2000.0
-	err = repo.UpdateAccount(account)
+	account.Amount = d(2000)
+	err = repo.UpdateAccount(context.Background(), account)
 	require.NoError(t, err)
 
 	result, err := repo.GetAccountByID(1)
 	require.NoError(t, err)
-	require.Equal(t, float32(2000.0), result.Amount)
+	require.True(t, d(2000).Equal(result.Amount))
+}
+
+func TestAccountRepository_GetAll(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to connect to database: %v", err)
+	}
+	err = db.AutoMigrate(&entities.Account{}, &entities.Strategy{})
+	if err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+	repo := repository.NewAccountRepository(db)
+
+	paper, err := repo.Create(context.Background(), entities.Account{Name: "paper", Amount: d(1000), AvailableOrders: 5, Currency: "USDT"})
+	require.NoError(t, err)
+	live, err := repo.Create(context.Background(), entities.Account{Name: "live", Amount: d(500), AvailableOrders: 2, Currency: "USDT"})
+	require.NoError(t, err)
+	require.NotEqual(t, paper.ID, live.ID)
+
+	require.NoError(t, db.Create(&entities.Strategy{Name: "scalper", AccountID: live.ID}).Error)
+
+	accounts, err := repo.GetAll()
+	require.NoError(t, err)
+	require.Len(t, accounts, 2)
+	require.Equal(t, "paper", accounts[0].Name)
+
+	count, err := repo.CountStrategies(live.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), count)
+
+	require.NoError(t, repo.Delete(paper.ID))
+	_, err = repo.GetAccountByID(paper.ID)
+	require.Error(t, err)
+}
+
+func TestAccountRepository_Lock(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&entities.Account{}))
+
+	repo := repository.NewAccountRepository(db)
+	created, err := repo.Create(context.Background(), entities.Account{Name: "paper", Amount: d(1000), AvailableOrders: 5, Currency: "USDT"})
+	require.NoError(t, err)
+
+	account, err := repo.Lock(context.Background(), created.ID)
+	require.NoError(t, err)
+	require.Equal(t, "paper", account.Name)
+
+	_, err = repo.Lock(context.Background(), created.ID+1)
+	require.Error(t, err)
+}
+
+func d(value float64) decimal.Decimal {
+	return decimal.NewFromFloat(value)
+}
+
+func TestAccountRepository_CountActiveSignals(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&entities.Signal{}))
+	require.NoError(t, db.Create(&[]entities.Signal{
+		{Symbol: "BTCUSDT", AccountID: 1, Status: entities.Pending},
+		{Symbol: "ETHUSDT", AccountID: 1, Status: entities.Closed},
+		{Symbol: "BTCUSDT", AccountID: 2, Status: entities.Open},
+	}).Error)
+
+	count, err := repository.NewAccountRepository(db).CountActiveSignals(context.Background(), 1)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), count)
 }
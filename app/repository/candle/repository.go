@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"go-trade-bot/app/entities"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const saveBatchSize = 500
+
+type CandleRepository struct {
+	db *gorm.DB
+}
+
+func NewCandleRepository(db *gorm.DB) CandleRepository {
+	return CandleRepository{
+		db: db,
+	}
+}
+
+// Save inserts the candles, replacing the ones already stored with the same
+// symbol, interval and open time.
+func (r CandleRepository) Save(ctx context.Context, candles []entities.Candle) error {
+	if len(candles) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{UpdateAll: true}).
+		CreateInBatches(&candles, saveBatchSize).Error
+}
+
+// GetLast returns the last limit candles stored, oldest first. Conditions go
+// through the entity so gorm quotes interval, a keyword in Postgres.
+func (r CandleRepository) GetLast(ctx context.Context, symbol string, interval string, limit int) ([]entities.Candle, error) {
+	var candles []entities.Candle
+	err := r.db.WithContext(ctx).
+		Where(entities.Candle{Symbol: symbol, Interval: interval}).
+		Order("open_time desc").
+		Limit(limit).
+		Find(&candles).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for i, j := 0, len(candles)-1; i < j; i, j = i+1, j-1 {
+		candles[i], candles[j] = candles[j], candles[i]
+	}
+	return candles, nil
+}
+
+// GetRange returns the candles opened between start and end inclusive,
+// oldest first.
+func (r CandleRepository) GetRange(ctx context.Context, symbol string, interval string, start time.Time, end time.Time) ([]entities.Candle, error) {
+	var candles []entities.Candle
+	err := r.db.WithContext(ctx).
+		Where(entities.Candle{Symbol: symbol, Interval: interval}).
+		Where("open_time >= ? AND open_time <= ?", start, end).
+		Order("open_time asc").
+		Find(&candles).Error
+	return candles, err
+}
+
+// GetLatest returns the most recent candle stored, false when there is none.
+func (r CandleRepository) GetLatest(ctx context.Context, symbol string, interval string) (entities.Candle, bool, error) {
+	var candle entities.Candle
+	err := r.db.WithContext(ctx).
+		Where(entities.Candle{Symbol: symbol, Interval: interval}).
+		Order("open_time desc").
+		First(&candle).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return entities.Candle{}, false, nil
+	}
+	if err != nil {
+		return entities.Candle{}, false, err
+	}
+	return candle, true, nil
+}
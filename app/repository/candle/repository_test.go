@@ -0,0 +1,77 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go-trade-bot/app/entities"
+	repository "go-trade-bot/app/repository/candle"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupRepository(t *testing.T) repository.CandleRepository {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&entities.Candle{}))
+	return repository.NewCandleRepository(db)
+}
+
+func candles(symbol string, start time.Time, n int) []entities.Candle {
+	result := make([]entities.Candle, n)
+	for i := range result {
+		open := start.Add(time.Duration(i) * time.Minute)
+		result[i] = entities.Candle{
+			Symbol:    symbol,
+			Interval:  "1m",
+			OpenTime:  open,
+			CloseTime: open.Add(time.Minute - time.Millisecond),
+			Close:     float64(i),
+		}
+	}
+	return result
+}
+
+func TestCandleRepository_SaveUpserts(t *testing.T) {
+	repo := setupRepository(t)
+	ctx := context.Background()
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	require.NoError(t, repo.Save(ctx, candles("BTCUSDT", start, 3)))
+
+	updated := candles("BTCUSDT", start.Add(2*time.Minute), 1)
+	updated[0].Close = 42
+	require.NoError(t, repo.Save(ctx, updated))
+
+	result, err := repo.GetRange(ctx, "BTCUSDT", "1m", start, start.Add(time.Hour))
+	require.NoError(t, err)
+	require.Len(t, result, 3)
+	require.Equal(t, 42.0, result[2].Close)
+}
+
+func TestCandleRepository_GetLast(t *testing.T) {
+	repo := setupRepository(t)
+	ctx := context.Background()
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	require.NoError(t, repo.Save(ctx, candles("BTCUSDT", start, 5)))
+	require.NoError(t, repo.Save(ctx, candles("ETHUSDT", start, 5)))
+
+	result, err := repo.GetLast(ctx, "BTCUSDT", "1m", 2)
+	require.NoError(t, err)
+	require.Len(t, result, 2)
+	require.Equal(t, 3.0, result[0].Close)
+	require.Equal(t, 4.0, result[1].Close)
+
+	latest, ok, err := repo.GetLatest(ctx, "BTCUSDT", "1m")
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, 4.0, latest.Close)
+
+	_, ok, err = repo.GetLatest(ctx, "BTCUSDT", "1h")
+	require.NoError(t, err)
+	require.False(t, ok)
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"go-trade-bot/app/entities"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type GridRepository struct {
+	db *gorm.DB
+}
+
+func NewGridRepository(db *gorm.DB) GridRepository {
+	return GridRepository{
+		db: db,
+	}
+}
+
+// Get returns the grid of the strategy for the symbol, false when none was
+// built yet.
+func (r GridRepository) Get(ctx context.Context, strategyID uint, symbol string) (entities.GridState, bool, error) {
+	var state entities.GridState
+	err := r.db.WithContext(ctx).
+		Where(entities.GridState{StrategyID: strategyID, Symbol: symbol}).
+		First(&state).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return entities.GridState{}, false, nil
+	}
+	if err != nil {
+		return entities.GridState{}, false, err
+	}
+	return state, true, nil
+}
+
+func (r GridRepository) ListByStrategy(ctx context.Context, strategyID uint) ([]entities.GridState, error) {
+	var states []entities.GridState
+	err := r.db.WithContext(ctx).
+		Where(entities.GridState{StrategyID: strategyID}).
+		Order("symbol asc").
+		Find(&states).Error
+	return states, err
+}
+
+// Save replaces the grid of the strategy for the symbol.
+func (r GridRepository) Save(ctx context.Context, state entities.GridState) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "strategy_id"}, {Name: "symbol"}},
+			DoUpdates: clause.AssignmentColumns([]string{"config_hash", "levels", "updated_at"}),
+		}).
+		Create(&state).Error
+}
+
+// Delete removes the grid of the strategy for the symbol, or every grid of
+// the strategy when symbol is empty. It returns the number of grids removed.
+func (r GridRepository) Delete(ctx context.Context, strategyID uint, symbol string) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Where(entities.GridState{StrategyID: strategyID, Symbol: symbol}).
+		Delete(&entities.GridState{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,80 @@
+package repository_test
+
+import (
+	"context"
+	"go-trade-bot/app/entities"
+	repository "go-trade-bot/app/repository/grid"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func newRepository(t *testing.T) repository.GridRepository {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	assert.NoError(t, err)
+	assert.NoError(t, db.AutoMigrate(&entities.GridState{}))
+	return repository.NewGridRepository(db)
+}
+
+func TestGridRepository_Save(t *testing.T) {
+	repo := newRepository(t)
+	ctx := context.Background()
+
+	_, ok, err := repo.Get(ctx, 1, "BTCUSDT")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	err = repo.Save(ctx, entities.GridState{
+		StrategyID: 1,
+		Symbol:     "BTCUSDT",
+		ConfigHash: "a",
+		Levels:     []entities.GridLevel{{Type: entities.GridBuy, Price: 100}},
+	})
+	assert.NoError(t, err)
+
+	err = repo.Save(ctx, entities.GridState{
+		StrategyID: 1,
+		Symbol:     "BTCUSDT",
+		ConfigHash: "b",
+		Levels:     []entities.GridLevel{{Type: entities.GridBuy, Price: 90}, {Type: entities.GridSell, Price: 120}},
+	})
+	assert.NoError(t, err)
+
+	state, ok, err := repo.Get(ctx, 1, "BTCUSDT")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "b", state.ConfigHash)
+	assert.Equal(t, []entities.GridLevel{{Type: entities.GridBuy, Price: 90}, {Type: entities.GridSell, Price: 120}}, []entities.GridLevel(state.Levels))
+}
+
+func TestGridRepository_Delete(t *testing.T) {
+	repo := newRepository(t)
+	ctx := context.Background()
+
+	for _, s := range []entities.GridState{
+		{StrategyID: 1, Symbol: "BTCUSDT", ConfigHash: "a"},
+		{StrategyID: 1, Symbol: "ETHUSDT", ConfigHash: "a"},
+		{StrategyID: 2, Symbol: "BTCUSDT", ConfigHash: "a"},
+	} {
+		assert.NoError(t, repo.Save(ctx, s))
+	}
+
+	deleted, err := repo.Delete(ctx, 1, "ETHUSDT")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+
+	states, err := repo.ListByStrategy(ctx, 1)
+	assert.NoError(t, err)
+	assert.Len(t, states, 1)
+	assert.Equal(t, "BTCUSDT", states[0].Symbol)
+
+	deleted, err = repo.Delete(ctx, 1, "")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+
+	states, err = repo.ListByStrategy(ctx, 2)
+	assert.NoError(t, err)
+	assert.Len(t, states, 1)
+}
@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"context"
+	"go-trade-bot/app/entities"
+	"go-trade-bot/internal/db"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+type LedgerRepository struct {
+	db *gorm.DB
+}
+
+func NewLedgerRepository(db *gorm.DB) LedgerRepository {
+	return LedgerRepository{
+		db: db,
+	}
+}
+
+// Append inserts the entries, the ledger has no update nor delete.
+func (r LedgerRepository) Append(ctx context.Context, entries ...entities.LedgerEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	return db.Conn(ctx, r.db).Create(&entries).Error
+}
+
+// List returns the entries of the account created between from and to,
+// oldest first. Zero times leave that side of the range open.
+func (r LedgerRepository) List(accountID int64, from time.Time, to time.Time) ([]entities.LedgerEntry, error) {
+	query := r.db.Where("account_id = ?", accountID)
+	if !from.IsZero() {
+		query = query.Where("created_at >= ?", from)
+	}
+	if !to.IsZero() {
+		query = query.Where("created_at <= ?", to)
+	}
+
+	var entries []entities.LedgerEntry
+	err := query.Order("created_at asc, id asc").Find(&entries).Error
+	return entries, err
+}
+
+// Balance sums every entry of the account, false when it has none.
+func (r LedgerRepository) Balance(ctx context.Context, accountID int64) (decimal.Decimal, bool, error) {
+	var result struct {
+		Total decimal.Decimal
+		Count int64
+	}
+	err := db.Conn(ctx, r.db).Model(&entities.LedgerEntry{}).
+		Select("COALESCE(SUM(amount), 0) AS total, COUNT(*) AS count").
+		Where("account_id = ?", accountID).
+		Scan(&result).Error
+	return result.Total, result.Count > 0, err
+}
@@ -0,0 +1,59 @@
+package repository_test
+
+import (
+	"context"
+	"go-trade-bot/app/entities"
+	repository "go-trade-bot/app/repository/ledger"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestLedgerRepository(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&entities.LedgerEntry{}))
+
+	repo := repository.NewLedgerRepository(db)
+
+	_, ok, err := repo.Balance(context.Background(), 1)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	day := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	signalID := uint(3)
+	err = repo.Append(context.Background(),
+		entities.LedgerEntry{AccountID: 1, Type: entities.Deposit, Amount: d(1000), Balance: d(1000), CreatedAt: day},
+		entities.LedgerEntry{AccountID: 1, Type: entities.OrderReserve, Amount: d(-100), Balance: d(900), SignalID: &signalID, CreatedAt: day.Add(24 * time.Hour)},
+		entities.LedgerEntry{AccountID: 2, Type: entities.Deposit, Amount: d(50), Balance: d(50), CreatedAt: day},
+	)
+	require.NoError(t, err)
+
+	balance, ok, err := repo.Balance(context.Background(), 1)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.True(t, d(900).Equal(balance))
+
+	entries, err := repo.List(1, time.Time{}, time.Time{})
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	require.Equal(t, entities.Deposit, entries[0].Type)
+
+	entries, err = repo.List(1, day.Add(time.Hour), time.Time{})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, signalID, *entries[0].SignalID)
+
+	entries, err = repo.List(1, time.Time{}, day.Add(time.Hour))
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, entities.Deposit, entries[0].Type)
+}
+
+func d(value float64) decimal.Decimal {
+	return decimal.NewFromFloat(value)
+}
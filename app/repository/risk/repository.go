@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"go-trade-bot/app/entities"
+	"go-trade-bot/internal/db"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// stateID is the id of the single risk state row.
+const stateID = 1
+
+type RiskRepository struct {
+	db *gorm.DB
+}
+
+func NewRiskRepository(db *gorm.DB) RiskRepository {
+	return RiskRepository{
+		db: db,
+	}
+}
+
+// GetState returns the state of the risk manager, a clear one before the
+// first save.
+func (r RiskRepository) GetState(ctx context.Context) (entities.RiskState, error) {
+	var state entities.RiskState
+	err := db.Conn(ctx, r.db).First(&state, stateID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return entities.RiskState{ID: stateID}, nil
+	}
+	return state, err
+}
+
+// SaveState saves the breaker, the equity peak is only moved by
+// RaiseEquityPeak and ResetEquityPeak.
+func (r RiskRepository) SaveState(ctx context.Context, state entities.RiskState) error {
+	state.ID = stateID
+	return db.Conn(ctx, r.db).Omit("EquityPeak").Save(&state).Error
+}
+
+// RaiseEquityPeak raises the equity peak to equity when it is higher, in one
+// conditional update so concurrent evaluations never lower it, and returns
+// the peak.
+func (r RiskRepository) RaiseEquityPeak(ctx context.Context, equity decimal.Decimal) (decimal.Decimal, error) {
+	conn := db.Conn(ctx, r.db)
+	err := conn.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&entities.RiskState{ID: stateID}).Error
+	if err != nil {
+		return decimal.Zero, err
+	}
+	err = conn.Model(&entities.RiskState{}).
+		Where("id = ? AND equity_peak < ?", stateID, equity).
+		Update("equity_peak", equity).Error
+	if err != nil {
+		return decimal.Zero, err
+	}
+	var state entities.RiskState
+	err = conn.First(&state, stateID).Error
+	return state.EquityPeak, err
+}
+
+// ResetEquityPeak sets the equity peak, lower than it was or not.
+func (r RiskRepository) ResetEquityPeak(ctx context.Context, equity decimal.Decimal) error {
+	return db.Conn(ctx, r.db).Model(&entities.RiskState{ID: stateID}).
+		Update("equity_peak", equity).Error
+}
+
+func (r RiskRepository) AddEvent(ctx context.Context, event entities.RiskEvent) error {
+	return db.Conn(ctx, r.db).Create(&event).Error
+}
+
+// ListEvents returns the last events, newest first.
+func (r RiskRepository) ListEvents(ctx context.Context, limit int) ([]entities.RiskEvent, error) {
+	var events []entities.RiskEvent
+	err := db.Conn(ctx, r.db).
+		Order("created_at desc, id desc").
+		Limit(limit).
+		Find(&events).Error
+	return events, err
+}
+
+// LiveAccounts returns the accounts trading on the exchange: those a
+// productive strategy draws from and those still holding live signals.
+func (r RiskRepository) LiveAccounts(ctx context.Context) ([]entities.Account, error) {
+	conn := db.Conn(ctx, r.db)
+	var accounts []entities.Account
+	err := conn.
+		Where("id IN (?)", conn.Model(&entities.Strategy{}).Select("account_id").Where("status = ?", entities.Productive)).
+		Or("id IN (?)", conn.Model(&entities.Signal{}).Select("account_id").Where("mode = ? AND status IN ?", entities.Live, entities.ActiveStatuses)).
+		Order("id").
+		Find(&accounts).Error
+	return accounts, err
+}
+
+// Positions returns the active live signals with their entry orders.
+func (r RiskRepository) Positions(ctx context.Context) ([]entities.Signal, error) {
+	var signals []entities.Signal
+	err := db.Conn(ctx, r.db).
+		Preload("Orders", "NOT is_closing").
+		Where("mode = ? AND status IN ?", entities.Live, entities.ActiveStatuses).
+		Order("id").
+		Find(&signals).Error
+	return signals, err
+}
+
+// RealizedPnL sums the profit of the live signals closed since the given
+// time.
+func (r RiskRepository) RealizedPnL(ctx context.Context, since time.Time) (decimal.Decimal, error) {
+	var result struct {
+		Total decimal.Decimal
+	}
+	err := db.Conn(ctx, r.db).Model(&entities.Order{}).
+		Select("COALESCE(SUM(orders.profit), 0) AS total").
+		Joins("JOIN signals ON signals.id = orders.signal_id").
+		Where("signals.mode = ? AND orders.is_closing AND orders.updated_at >= ?", entities.Live, since).
+		Scan(&result).Error
+	return result.Total, err
+}
+
+// CountOpenSignals counts the active signals of the symbol, of the
+// strategy, or of both when both are set.
+func (r RiskRepository) CountOpenSignals(ctx context.Context, symbol string, strategyID uint) (int64, error) {
+	var count int64
+	err := db.Conn(ctx, r.db).Model(&entities.Signal{}).
+		Where(entities.Signal{Symbol: symbol, StrategyID: strategyID}).
+		Where("status IN ?", entities.ActiveStatuses).
+		Count(&count).Error
+	return count, err
+}
@@ -0,0 +1,148 @@
+package repository_test
+
+import (
+	"context"
+	"go-trade-bot/app/entities"
+	repository "go-trade-bot/app/repository/risk"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func d(value float64) decimal.Decimal {
+	return decimal.NewFromFloat(value)
+}
+
+func newRepository(t *testing.T) (repository.RiskRepository, *gorm.DB) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	assert.NoError(t, err)
+	assert.NoError(t, db.AutoMigrate(&entities.Account{}, &entities.Strategy{}, &entities.Signal{}, &entities.Order{}, &entities.RiskState{}, &entities.RiskEvent{}))
+	return repository.NewRiskRepository(db), db
+}
+
+func TestRiskRepository_State(t *testing.T) {
+	repo, _ := newRepository(t)
+	ctx := context.Background()
+
+	state, err := repo.GetState(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), state.ID)
+	assert.False(t, state.Halted)
+
+	tripped := time.Now()
+	state.Halted = true
+	state.Rule = entities.DrawdownRule
+	state.TrippedAt = &tripped
+	state.EquityPeak = d(1000)
+	assert.NoError(t, repo.SaveState(ctx, state))
+
+	state.Reason = "drawdown of 20.00%"
+	assert.NoError(t, repo.SaveState(ctx, state))
+
+	state, err = repo.GetState(ctx)
+	assert.NoError(t, err)
+	assert.True(t, state.Halted)
+	assert.Equal(t, "drawdown of 20.00%", state.Reason)
+	// The peak is only moved by its own updates.
+	assert.True(t, state.EquityPeak.IsZero(), state.EquityPeak.String())
+}
+
+func TestRiskRepository_EquityPeak(t *testing.T) {
+	repo, _ := newRepository(t)
+	ctx := context.Background()
+
+	peak, err := repo.RaiseEquityPeak(ctx, d(1000))
+	assert.NoError(t, err)
+	assert.True(t, d(1000).Equal(peak), peak.String())
+
+	// A stale evaluation never lowers the peak.
+	peak, err = repo.RaiseEquityPeak(ctx, d(900))
+	assert.NoError(t, err)
+	assert.True(t, d(1000).Equal(peak), peak.String())
+
+	state, err := repo.GetState(ctx)
+	assert.NoError(t, err)
+	state.Halted = true
+	state.EquityPeak = d(500)
+	assert.NoError(t, repo.SaveState(ctx, state))
+
+	state, err = repo.GetState(ctx)
+	assert.NoError(t, err)
+	assert.True(t, state.Halted)
+	assert.True(t, d(1000).Equal(state.EquityPeak), state.EquityPeak.String())
+
+	assert.NoError(t, repo.ResetEquityPeak(ctx, d(800)))
+	state, err = repo.GetState(ctx)
+	assert.NoError(t, err)
+	assert.True(t, d(800).Equal(state.EquityPeak), state.EquityPeak.String())
+}
+
+func TestRiskRepository_Events(t *testing.T) {
+	repo, _ := newRepository(t)
+	ctx := context.Background()
+	now := time.Now()
+
+	assert.NoError(t, repo.AddEvent(ctx, entities.RiskEvent{Type: entities.RiskTrip, Rule: entities.DailyLossRule, Equity: d(900), CreatedAt: now.Add(-time.Hour)}))
+	assert.NoError(t, repo.AddEvent(ctx, entities.RiskEvent{Type: entities.RiskReset, Rule: entities.DailyLossRule, Equity: d(900), CreatedAt: now}))
+
+	events, err := repo.ListEvents(ctx, 10)
+	assert.NoError(t, err)
+	assert.Len(t, events, 2)
+	assert.Equal(t, entities.RiskReset, events[0].Type)
+
+	events, err = repo.ListEvents(ctx, 1)
+	assert.NoError(t, err)
+	assert.Len(t, events, 1)
+}
+
+func TestRiskRepository_Books(t *testing.T) {
+	repo, db := newRepository(t)
+	ctx := context.Background()
+	now := time.Now()
+
+	assert.NoError(t, db.Create(&[]entities.Account{
+		{Amount: d(600), AvailableOrders: 5, Currency: "USDT"},
+		{Amount: d(400), AvailableOrders: 5, Currency: "USDT"},
+		{Amount: d(5000), AvailableOrders: 5, Currency: "USDT"},
+	}).Error)
+	assert.NoError(t, db.Create(&[]entities.Strategy{
+		{Name: "live", Status: entities.Productive, AccountID: 1},
+		{Name: "paper", Status: entities.Testing, AccountID: 3},
+	}).Error)
+	assert.NoError(t, db.Create(&[]entities.Signal{
+		{Symbol: "BTCUSDT", StrategyID: 1, AccountID: 1, Status: entities.Open, Mode: entities.Live, Orders: []entities.Order{{InvestedAmount: d(100)}}},
+		// Account 2 still holds a live signal of a strategy since demoted.
+		{Symbol: "BTCUSDT", StrategyID: 2, AccountID: 2, Status: entities.Closing, Mode: entities.Live, Orders: []entities.Order{{InvestedAmount: d(50)}}},
+		{Symbol: "BTCUSDT", StrategyID: 2, AccountID: 3, Status: entities.Open, Mode: entities.Simulated, Orders: []entities.Order{{InvestedAmount: d(500)}}},
+		{Symbol: "ETHUSDT", StrategyID: 1, AccountID: 1, Status: entities.Closed, Mode: entities.Live, Orders: []entities.Order{{InvestedAmount: d(70), Profit: d(-7), IsClosing: true, UpdatedAt: now}}},
+		{Symbol: "ETHUSDT", StrategyID: 1, AccountID: 1, Status: entities.Closed, Mode: entities.Live, Orders: []entities.Order{{InvestedAmount: d(70), Profit: d(20), IsClosing: true, UpdatedAt: now.Add(-48 * time.Hour)}}},
+		{Symbol: "ETHUSDT", StrategyID: 2, AccountID: 3, Status: entities.Closed, Mode: entities.Simulated, Orders: []entities.Order{{InvestedAmount: d(70), Profit: d(-50), IsClosing: true, UpdatedAt: now}}},
+	}).Error)
+
+	accounts, err := repo.LiveAccounts(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, accounts, 2)
+	assert.Equal(t, int64(1), accounts[0].ID)
+	assert.Equal(t, int64(2), accounts[1].ID)
+
+	positions, err := repo.Positions(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, positions, 2)
+	assert.True(t, d(50).Equal(positions[1].Orders[0].InvestedAmount))
+
+	pnl, err := repo.RealizedPnL(ctx, now.Add(-time.Hour))
+	assert.NoError(t, err)
+	assert.True(t, d(-7).Equal(pnl), pnl.String())
+
+	bySymbol, err := repo.CountOpenSignals(ctx, "BTCUSDT", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), bySymbol)
+
+	byStrategy, err := repo.CountOpenSignals(ctx, "", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), byStrategy)
+}
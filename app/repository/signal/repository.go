@@ -1,8 +1,13 @@
 package repository
 
 import (
+	"context"
 	"go-trade-bot/app/entities"
+	"go-trade-bot/internal/broker"
+	"go-trade-bot/internal/db"
+	"time"
 
+	"github.com/shopspring/decimal"
 	"gorm.io/gorm"
 )
 
@@ -16,21 +21,34 @@ func NewSignalRepository(db *gorm.DB) SignalRepository {
 	}
 }
 
-func (r SignalRepository) Create(signal entities.Signal) error {
-	return r.db.Create(&signal).Error
+// Create inserts the signal with its orders and returns them with their ids.
+func (r SignalRepository) Create(ctx context.Context, signal entities.Signal) (entities.Signal, error) {
+	err := db.Conn(ctx, r.db).Create(&signal).Error
+	return signal, err
 }
 
-func (r SignalRepository) GetOpenSignals(symbol string, strategyId uint) (entities.Signal, error) {
+// GetOpenSignals returns the active signal of the strategy on the symbol,
+// pending, open or closing. Within a transaction it reads through it.
+func (r SignalRepository) GetOpenSignals(ctx context.Context, symbol string, strategyId uint) (entities.Signal, error) {
 	var signals []entities.Signal
-	err := r.db.
+	err := db.Conn(ctx, r.db).
 		Preload("Orders").
-		Where("symbol = ? AND status = ? AND strategy_id = ?", symbol, entities.Open, strategyId).
+		Where("symbol = ? AND status IN ? AND strategy_id = ?", symbol, entities.ActiveStatuses, strategyId).
 		Find(&signals).Error
-
-	if len(signals) == 0 {
-		return entities.Signal{}, nil
+	if err != nil || len(signals) == 0 {
+		return entities.Signal{}, err
 	}
-	return signals[0], err
+	return signals[0], nil
+}
+
+// HasLiveFutures tells whether a live futures signal on the symbol is
+// active, the exchange nets every order of the symbol into its position.
+func (r SignalRepository) HasLiveFutures(ctx context.Context, symbol string) (bool, error) {
+	var count int64
+	err := db.Conn(ctx, r.db).Model(&entities.Signal{}).
+		Where("symbol = ? AND market = ? AND mode = ? AND status IN ?", symbol, entities.Futures, entities.Live, entities.ActiveStatuses).
+		Count(&count).Error
+	return count > 0, err
 }
 
 func (r SignalRepository) GetAllOpenSignals() ([]entities.Signal, error) {
@@ -38,7 +56,7 @@ func (r SignalRepository) GetAllOpenSignals() ([]entities.Signal, error) {
 	err := r.db.
 		Preload("Orders").
 		Preload("Strategy").
-		Where("status = ?", entities.Open).
+		Where("status IN ?", entities.ActiveStatuses).
 		Find(&signals).Error
 
 	if err != nil {
@@ -57,6 +75,148 @@ func (r SignalRepository) Update(signal entities.Signal) error {
 	return r.db.Save(&order).Error
 }
 
+// Transition moves the signal from one status to another. False means it
+// was not in the from status and nothing was written, so only one caller
+// wins a transition.
+func (r SignalRepository) Transition(ctx context.Context, id uint, from entities.SignalStatus, to entities.SignalStatus) (bool, error) {
+	result := db.Conn(ctx, r.db).Model(&entities.Signal{}).
+		Where("id = ? AND status = ?", id, from).
+		Updates(map[string]any{"status": to, "updated_at": time.Now()})
+	return result.RowsAffected > 0, result.Error
+}
+
+// Open marks the pending signal open and saves the fill of its entry order.
+func (r SignalRepository) Open(ctx context.Context, signal entities.Signal) (bool, error) {
+	tx := db.Conn(ctx, r.db)
+	result := tx.Model(&entities.Signal{}).
+		Where("id = ? AND status = ?", signal.ID, entities.Pending).
+		Updates(map[string]any{"status": entities.Open, "high_water_mark": signal.HighWaterMark, "updated_at": time.Now()})
+	if result.Error != nil || result.RowsAffected == 0 {
+		return false, result.Error
+	}
+
+	order := signal.Orders[0]
+	return true, tx.Save(&order).Error
+}
+
+// SaveOrder writes the order alone, e.g. the fill of an exit before it is
+// booked.
+func (r SignalRepository) SaveOrder(ctx context.Context, order entities.Order) error {
+	return db.Conn(ctx, r.db).Save(&order).Error
+}
+
+// Close marks the signal closed and saves its order, only if it is still
+// closing. False means another close got there first and nothing was
+// written.
+func (r SignalRepository) Close(ctx context.Context, signal entities.Signal) (bool, error) {
+	tx := db.Conn(ctx, r.db)
+	result := tx.Model(&entities.Signal{}).
+		Where("id = ? AND status = ?", signal.ID, entities.Closing).
+		Updates(map[string]any{"status": entities.Closed, "updated_at": time.Now()})
+	if result.Error != nil || result.RowsAffected == 0 {
+		return false, result.Error
+	}
+
+	order := signal.Orders[0]
+	return true, tx.Save(&order).Error
+}
+
+// UpdateHighWaterMark only touches the mark, the rest of the signal may be
+// updated at the same time by its close.
+func (r SignalRepository) UpdateHighWaterMark(id uint, price decimal.Decimal) error {
+	return r.db.Model(&entities.Signal{}).Where("id = ?", id).Update("high_water_mark", price).Error
+}
+
+// TradedVolume sums the quote volume bought and sold by the account through
+// orders opened since the given time.
+func (r SignalRepository) TradedVolume(ctx context.Context, accountID int64, since time.Time) (decimal.Decimal, error) {
+	var result struct {
+		Total decimal.Decimal
+	}
+	err := db.Conn(ctx, r.db).Model(&entities.Order{}).
+		Select("COALESCE(SUM(orders.invested_amount + CASE WHEN orders.is_closing THEN orders.exit_price * orders.quantity ELSE 0 END), 0) AS total").
+		Joins("JOIN signals ON signals.id = orders.signal_id").
+		Where("signals.account_id = ? AND orders.created_at >= ?", accountID, since).
+		Scan(&result).Error
+	return result.Total, err
+}
+
+// Exposure sums what the active signals of the account invested.
+func (r SignalRepository) Exposure(ctx context.Context, accountID int64) (decimal.Decimal, error) {
+	var result struct {
+		Total decimal.Decimal
+	}
+	err := db.Conn(ctx, r.db).Model(&entities.Order{}).
+		Select("COALESCE(SUM(orders.invested_amount), 0) AS total").
+		Joins("JOIN signals ON signals.id = orders.signal_id").
+		Where("signals.account_id = ? AND signals.status IN ?", accountID, entities.ActiveStatuses).
+		Scan(&result).Error
+	return result.Total, err
+}
+
+// PaperWallet sums what the paper exchange holds according to the books:
+// the free amount of every account, the reservations of pending spot
+// entries and the quantity of open or closing spot positions.
+func (r SignalRepository) PaperWallet(ctx context.Context) (broker.PaperWallet, error) {
+	wallet := broker.PaperWallet{
+		Cash:      map[string]float64{},
+		Reserved:  map[string]float64{},
+		Positions: map[string]float64{},
+	}
+
+	var cash []struct {
+		Currency string
+		Total    decimal.Decimal
+	}
+	err := db.Conn(ctx, r.db).Model(&entities.Account{}).
+		Select("currency, COALESCE(SUM(amount), 0) AS total").
+		Group("currency").
+		Scan(&cash).Error
+	if err != nil {
+		return broker.PaperWallet{}, err
+	}
+	for _, c := range cash {
+		wallet.Cash[c.Currency] = c.Total.InexactFloat64()
+	}
+
+	var orders []struct {
+		Symbol   string
+		Status   entities.SignalStatus
+		Invested decimal.Decimal
+		Quantity decimal.Decimal
+	}
+	err = db.Conn(ctx, r.db).Model(&entities.Order{}).
+		Select("signals.symbol, signals.status, COALESCE(SUM(orders.invested_amount), 0) AS invested, COALESCE(SUM(orders.quantity), 0) AS quantity").
+		Joins("JOIN signals ON signals.id = orders.signal_id").
+		Where("signals.market = ? AND signals.status IN ?", entities.Spot, entities.ActiveStatuses).
+		Group("signals.symbol, signals.status").
+		Scan(&orders).Error
+	if err != nil {
+		return broker.PaperWallet{}, err
+	}
+	for _, o := range orders {
+		if o.Status == entities.Pending {
+			wallet.Reserved[o.Symbol] += o.Invested.InexactFloat64()
+			continue
+		}
+		wallet.Positions[o.Symbol] += o.Quantity.InexactFloat64()
+	}
+	return wallet, nil
+}
+
+// GetClosedSignals returns the last closed signals of the strategy with
+// their orders, newest first.
+func (r SignalRepository) GetClosedSignals(ctx context.Context, strategyID uint, limit int) ([]entities.Signal, error) {
+	var signals []entities.Signal
+	err := db.Conn(ctx, r.db).
+		Preload("Orders").
+		Where("strategy_id = ? AND status = ?", strategyID, entities.Closed).
+		Order("updated_at desc, id desc").
+		Limit(limit).
+		Find(&signals).Error
+	return signals, err
+}
+
 func (r SignalRepository) GetByID(id uint) (entities.Signal, error) {
 	var signal entities.Signal
 	err := r.db.
@@ -1,11 +1,14 @@
 package repository_test
 
 import (
+	"context"
 	"go-trade-bot/app/entities"
 	repository "go-trade-bot/app/repository/signal"
+	"go-trade-bot/internal/db"
 	"testing"
 	"time"
 
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
@@ -30,15 +33,15 @@ func TestSignalRepository_Create(t *testing.T) {
 			{
 				SignalID:       1,
 				BrokerOrderID:  "12345",
-				EntryPrice:     50000.0,
-				ExitPrice:      51000.0,
-				Quantity:       0.1,
-				InvestedAmount: 5000.0,
+				EntryPrice:     d(50000),
+				ExitPrice:      d(51000),
+				Quantity:       d(0.1),
+				InvestedAmount: d(5000),
 				MarginType:     entities.Isolated,
-				EntryFee:       0.1,
-				ExitFee:        0.1,
+				EntryFee:       d(0.1),
+				ExitFee:        d(0.1),
 				Leverage:       10.0,
-				ExecutedQty:    0.1,
+				ExecutedQty:    d(0.1),
 				IsClosing:      true,
 				CreatedAt:      time.Now(),
 				UpdatedAt:      time.Now(),
@@ -46,7 +49,7 @@ func TestSignalRepository_Create(t *testing.T) {
 		},
 	}
 
-	err = repo.Create(signal)
+	_, err = repo.Create(context.Background(), signal)
 	assert.NoError(t, err)
 
 	var result entities.Signal
@@ -72,15 +75,15 @@ func TestSignalRepository_GetByID(t *testing.T) {
 		Orders: []entities.Order{
 			{
 				BrokerOrderID:  "54321",
-				EntryPrice:     2000.0,
-				ExitPrice:      2100.0,
-				Quantity:       0.2,
-				InvestedAmount: 400.0,
+				EntryPrice:     d(2000),
+				ExitPrice:      d(2100),
+				Quantity:       d(0.2),
+				InvestedAmount: d(400),
 				MarginType:     entities.Cross,
-				EntryFee:       0.05,
-				ExitFee:        0.05,
+				EntryFee:       d(0.05),
+				ExitFee:        d(0.05),
 				Leverage:       5.0,
-				ExecutedQty:    0.2,
+				ExecutedQty:    d(0.2),
 				IsClosing:      false,
 				CreatedAt:      time.Now(),
 				UpdatedAt:      time.Now(),
@@ -88,7 +91,7 @@ func TestSignalRepository_GetByID(t *testing.T) {
 		},
 	}
 
-	err = repo.Create(signal)
+	_, err = repo.Create(context.Background(), signal)
 	assert.NoError(t, err)
 
 	var createdSignal entities.Signal
@@ -136,15 +139,15 @@ func TestSignalRepository_GetAll(t *testing.T) {
 			Orders: []entities.Order{
 				{
 					BrokerOrderID:  "order1",
-					EntryPrice:     10000.0,
-					ExitPrice:      11000.0,
-					Quantity:       0.5,
-					InvestedAmount: 5000.0,
+					EntryPrice:     d(10000),
+					ExitPrice:      d(11000),
+					Quantity:       d(0.5),
+					InvestedAmount: d(5000),
 					MarginType:     entities.Isolated,
-					EntryFee:       0.1,
-					ExitFee:        0.1,
+					EntryFee:       d(0.1),
+					ExitFee:        d(0.1),
 					Leverage:       10.0,
-					ExecutedQty:    0.5,
+					ExecutedQty:    d(0.5),
 					IsClosing:      false,
 					CreatedAt:      time.Now(),
 					UpdatedAt:      time.Now(),
@@ -160,15 +163,15 @@ func TestSignalRepository_GetAll(t *testing.T) {
 			Orders: []entities.Order{
 				{
 					BrokerOrderID:  "order2",
-					EntryPrice:     2000.0,
-					ExitPrice:      2100.0,
-					Quantity:       1.0,
-					InvestedAmount: 2000.0,
+					EntryPrice:     d(2000),
+					ExitPrice:      d(2100),
+					Quantity:       d(1),
+					InvestedAmount: d(2000),
 					MarginType:     entities.Cross,
-					EntryFee:       0.05,
-					ExitFee:        0.05,
+					EntryFee:       d(0.05),
+					ExitFee:        d(0.05),
 					Leverage:       5.0,
-					ExecutedQty:    1.0,
+					ExecutedQty:    d(1),
 					IsClosing:      true,
 					CreatedAt:      time.Now(),
 					UpdatedAt:      time.Now(),
@@ -178,7 +181,8 @@ func TestSignalRepository_GetAll(t *testing.T) {
 	}
 
 	for _, s := range signals {
-		assert.NoError(t, repo.Create(s))
+		_, err := repo.Create(context.Background(), s)
+		assert.NoError(t, err)
 	}
 
 	gotSignals, err := repo.GetAll()
@@ -191,3 +195,244 @@ func TestSignalRepository_GetAll(t *testing.T) {
 		assert.True(t, len(s.Orders) > 0)
 	}
 }
+
+func TestSignalRepository_UpdateHighWaterMark(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	assert.NoError(t, err)
+
+	err = db.AutoMigrate(&entities.Signal{}, &entities.Order{})
+	assert.NoError(t, err)
+
+	repo := repository.NewSignalRepository(db)
+
+	signal := entities.Signal{
+		Symbol:        "BTCUSDT",
+		StrategyID:    1,
+		Status:        entities.Open,
+		HighWaterMark: d(50000),
+	}
+	assert.NoError(t, db.Create(&signal).Error)
+
+	err = repo.UpdateHighWaterMark(signal.ID, d(52000))
+	assert.NoError(t, err)
+
+	got, err := repo.GetByID(signal.ID)
+	assert.NoError(t, err)
+	assert.True(t, d(52000).Equal(got.HighWaterMark))
+	assert.Equal(t, entities.Open, got.Status)
+}
+
+func TestSignalRepository_TradedVolume(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	assert.NoError(t, err)
+	assert.NoError(t, db.AutoMigrate(&entities.Signal{}, &entities.Order{}))
+
+	repo := repository.NewSignalRepository(db)
+	now := time.Now()
+	for _, signal := range []entities.Signal{
+		{Symbol: "BTCUSDT", AccountID: 1, Status: entities.Closed, Orders: []entities.Order{
+			{InvestedAmount: d(100), ExitPrice: d(60), Quantity: d(2), IsClosing: true, CreatedAt: now.Add(-time.Hour)},
+		}},
+		{Symbol: "ETHUSDT", AccountID: 1, Status: entities.Open, Orders: []entities.Order{
+			{InvestedAmount: d(50), ExitPrice: d(0), Quantity: d(1), CreatedAt: now.Add(-time.Hour)},
+		}},
+		{Symbol: "BTCUSDT", AccountID: 1, Status: entities.Closed, Orders: []entities.Order{
+			{InvestedAmount: d(1000), ExitPrice: d(600), Quantity: d(2), IsClosing: true, CreatedAt: now.Add(-40 * 24 * time.Hour)},
+		}},
+		{Symbol: "BTCUSDT", AccountID: 2, Status: entities.Open, Orders: []entities.Order{
+			{InvestedAmount: d(70), ExitPrice: d(0), Quantity: d(1), CreatedAt: now.Add(-time.Hour)},
+		}},
+	} {
+		_, err := repo.Create(context.Background(), signal)
+		assert.NoError(t, err)
+	}
+
+	volume, err := repo.TradedVolume(context.Background(), 1, now.Add(-30*24*time.Hour))
+	assert.NoError(t, err)
+	assert.True(t, d(270).Equal(volume), volume.String())
+}
+
+func TestSignalRepository_Exposure(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	assert.NoError(t, err)
+	assert.NoError(t, db.AutoMigrate(&entities.Signal{}, &entities.Order{}))
+
+	repo := repository.NewSignalRepository(db)
+	now := time.Now()
+	for i, signal := range []entities.Signal{
+		{Symbol: "BTCUSDT", StrategyID: 1, AccountID: 1, Status: entities.Open, Orders: []entities.Order{{InvestedAmount: d(100)}}},
+		{Symbol: "ETHUSDT", StrategyID: 1, AccountID: 1, Status: entities.Closed, Orders: []entities.Order{{InvestedAmount: d(50), Profit: d(5)}}},
+		{Symbol: "BTCUSDT", StrategyID: 1, AccountID: 1, Status: entities.Closed, Orders: []entities.Order{{InvestedAmount: d(70), Profit: d(-7)}}},
+		{Symbol: "BTCUSDT", StrategyID: 2, AccountID: 2, Status: entities.Open, Orders: []entities.Order{{InvestedAmount: d(30)}}},
+	} {
+		signal.UpdatedAt = now.Add(time.Duration(i) * time.Minute)
+		_, err := repo.Create(context.Background(), signal)
+		assert.NoError(t, err)
+	}
+
+	exposure, err := repo.Exposure(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.True(t, d(100).Equal(exposure), exposure.String())
+
+	closed, err := repo.GetClosedSignals(context.Background(), 1, 1)
+	assert.NoError(t, err)
+	assert.Len(t, closed, 1)
+	assert.True(t, d(-7).Equal(closed[0].Orders[0].Profit))
+}
+
+func TestSignalRepository_PaperWallet(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	assert.NoError(t, err)
+	assert.NoError(t, db.AutoMigrate(&entities.Account{}, &entities.Signal{}, &entities.Order{}))
+
+	assert.NoError(t, db.Create(&[]entities.Account{
+		{Name: "paper", Amount: d(900), Currency: "USDT"},
+		{Name: "team", Amount: d(100), Currency: "USDT"},
+	}).Error)
+
+	repo := repository.NewSignalRepository(db)
+	for _, signal := range []entities.Signal{
+		{Symbol: "BTCUSDT", Status: entities.Pending, Market: entities.Spot, Orders: []entities.Order{{InvestedAmount: d(50), Quantity: d(0.5)}}},
+		{Symbol: "BTCUSDT", Status: entities.Open, Market: entities.Spot, Orders: []entities.Order{{InvestedAmount: d(100), Quantity: d(1)}}},
+		{Symbol: "BTCUSDT", Status: entities.Closing, Market: entities.Spot, Orders: []entities.Order{{InvestedAmount: d(200), Quantity: d(2)}}},
+		{Symbol: "BTCUSDT", Status: entities.Closed, Market: entities.Spot, Orders: []entities.Order{{InvestedAmount: d(300), Quantity: d(3)}}},
+		{Symbol: "ETHUSDT", Status: entities.Open, Market: entities.Futures, Orders: []entities.Order{{InvestedAmount: d(10), Quantity: d(4)}}},
+	} {
+		_, err := repo.Create(context.Background(), signal)
+		assert.NoError(t, err)
+	}
+
+	wallet, err := repo.PaperWallet(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]float64{"USDT": 1000}, wallet.Cash)
+	assert.Equal(t, map[string]float64{"BTCUSDT": 50}, wallet.Reserved)
+	assert.Equal(t, map[string]float64{"BTCUSDT": 3}, wallet.Positions)
+}
+
+func TestSignalRepository_GetOpenSignals_Transaction(t *testing.T) {
+	conn, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{})
+	assert.NoError(t, err)
+	assert.NoError(t, conn.AutoMigrate(&entities.Signal{}, &entities.Order{}))
+
+	repo := repository.NewSignalRepository(conn)
+	err = db.NewTransactor(conn).Transaction(context.Background(), func(ctx context.Context) error {
+		created, err := repo.Create(ctx, entities.Signal{Symbol: "BTCUSDT", StrategyID: 1, Status: entities.Pending})
+		assert.NoError(t, err)
+
+		// The pending signal isn't committed yet, only the transaction sees it.
+		pending, err := repo.GetOpenSignals(ctx, "BTCUSDT", 1)
+		assert.NoError(t, err)
+		assert.Equal(t, created.ID, pending.ID)
+		return err
+	})
+	assert.NoError(t, err)
+}
+
+func TestSignalRepository_HasLiveFutures(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	assert.NoError(t, err)
+	assert.NoError(t, db.AutoMigrate(&entities.Signal{}, &entities.Order{}))
+
+	repo := repository.NewSignalRepository(db)
+	for _, signal := range []entities.Signal{
+		{Symbol: "BTCUSDT", Status: entities.Closed, Mode: entities.Live, Market: entities.Futures},
+		{Symbol: "BTCUSDT", Status: entities.Open, Mode: entities.Simulated, Market: entities.Futures},
+		{Symbol: "BTCUSDT", Status: entities.Open, Mode: entities.Live, Market: entities.Spot},
+		{Symbol: "ETHUSDT", Status: entities.Pending, Mode: entities.Live, Market: entities.Futures},
+	} {
+		_, err := repo.Create(context.Background(), signal)
+		assert.NoError(t, err)
+	}
+
+	held, err := repo.HasLiveFutures(context.Background(), "BTCUSDT")
+	assert.NoError(t, err)
+	assert.False(t, held)
+
+	held, err = repo.HasLiveFutures(context.Background(), "ETHUSDT")
+	assert.NoError(t, err)
+	assert.True(t, held)
+}
+
+func TestSignalRepository_Close(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	assert.NoError(t, err)
+	assert.NoError(t, db.AutoMigrate(&entities.Signal{}, &entities.Order{}))
+
+	repo := repository.NewSignalRepository(db)
+	signal, err := repo.Create(context.Background(), entities.Signal{
+		Symbol:     "BTCUSDT",
+		StrategyID: 1,
+		Status:     entities.Closing,
+		Orders:     []entities.Order{{EntryPrice: d(50000), Quantity: d(0.1), InvestedAmount: d(5000)}},
+	})
+	assert.NoError(t, err)
+
+	signal.Status = entities.Closed
+	signal.Orders[0].ExitPrice = d(51000)
+	signal.Orders[0].Profit = d(100)
+
+	closed, err := repo.Close(context.Background(), signal)
+	assert.NoError(t, err)
+	assert.True(t, closed)
+
+	result, err := repo.GetByID(signal.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, entities.Closed, result.Status)
+	assert.True(t, d(100).Equal(result.Orders[0].Profit))
+
+	signal.Orders[0].Profit = d(200)
+	closed, err = repo.Close(context.Background(), signal)
+	assert.NoError(t, err)
+	assert.False(t, closed)
+
+	result, err = repo.GetByID(signal.ID)
+	assert.NoError(t, err)
+	assert.True(t, d(100).Equal(result.Orders[0].Profit))
+}
+
+func TestSignalRepository_Transitions(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	assert.NoError(t, err)
+	assert.NoError(t, db.AutoMigrate(&entities.Signal{}, &entities.Order{}))
+
+	repo := repository.NewSignalRepository(db)
+	ctx := context.Background()
+	signal, err := repo.Create(ctx, entities.Signal{
+		Symbol:     "BTCUSDT",
+		StrategyID: 1,
+		Status:     entities.Pending,
+		Orders:     []entities.Order{{EntryPrice: d(50000), Quantity: d(0.1), InvestedAmount: d(5000)}},
+	})
+	assert.NoError(t, err)
+
+	pending, err := repo.GetOpenSignals(context.Background(), "BTCUSDT", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, signal.ID, pending.ID)
+
+	signal.HighWaterMark = d(49900)
+	signal.Orders[0].EntryPrice = d(49900)
+	signal.Orders[0].BrokerOrderID = "42"
+	opened, err := repo.Open(ctx, signal)
+	assert.NoError(t, err)
+	assert.True(t, opened)
+	opened, err = repo.Open(ctx, signal)
+	assert.NoError(t, err)
+	assert.False(t, opened)
+
+	claimed, err := repo.Transition(ctx, signal.ID, entities.Open, entities.Closing)
+	assert.NoError(t, err)
+	assert.True(t, claimed)
+	claimed, err = repo.Transition(ctx, signal.ID, entities.Open, entities.Closing)
+	assert.NoError(t, err)
+	assert.False(t, claimed)
+
+	result, err := repo.GetByID(signal.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, entities.Closing, result.Status)
+	assert.Equal(t, "42", result.Orders[0].BrokerOrderID)
+	assert.True(t, d(49900).Equal(result.HighWaterMark))
+}
+
+func d(value float64) decimal.Decimal {
+	return decimal.NewFromFloat(value)
+}
@@ -49,7 +49,7 @@ func (r StrategyRepository) CountOpenSignals(ctx context.Context, strategy entit
 	var count int64
 	err := r.db.WithContext(ctx).
 		Model(&entities.Signal{}).
-		Where("strategy_id = ? AND status = ?", strategy.ID, entities.Open).
+		Where("strategy_id = ? AND status IN ?", strategy.ID, entities.ActiveStatuses).
 		Count(&count).Error
 	return count, err
 }
@@ -2,30 +2,36 @@ package bollinger
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"go-trade-bot/app/entities"
+	"go-trade-bot/app/services/algorithm"
 	usecase "go-trade-bot/app/usecase/signal"
 	"go-trade-bot/internal/broker"
 	"log"
-	"strconv"
 
 	talib "github.com/markcheno/go-talib"
+	"github.com/shopspring/decimal"
 )
 
 type BollingerProcessor struct {
 	strategy entities.Strategy
-	broker   broker.Broker
+	broker   Broker
 	usecase  SignalUseCase
 }
 
+type Broker interface {
+	ListTickerPrices(ctx context.Context, symbol string) ([]broker.Ticker, error)
+	ListKline(ctx context.Context, symbol string, interval string, limit int) ([]broker.Candle, error)
+}
+
 type SignalUseCase interface {
 	GenerateBuySignal(e usecase.EntrySignal) error
 	GenerateSellSignal(e usecase.ExitSignal) error
 	GetOpenSignal(symbol string, strategyId uint) (entities.Signal, error)
+	TrackHighWaterMark(signal entities.Signal, price decimal.Decimal) (decimal.Decimal, error)
 }
 
-func NewBollingerProcessor(s entities.Strategy, b broker.Broker, ss SignalUseCase) BollingerProcessor {
+func NewBollingerProcessor(s entities.Strategy, b Broker, ss SignalUseCase) BollingerProcessor {
 	return BollingerProcessor{
 		strategy: s,
 		broker:   b,
@@ -44,27 +50,20 @@ func (p BollingerProcessor) Execute() error {
 }
 
 func (p BollingerProcessor) RunBollingerAlgorithm(ctx context.Context, symbol string) error {
-	klines, err := p.broker.ListKline(ctx, symbol, p.strategy.GetBrokerInterval(), 30)
+	candles, err := p.broker.ListKline(ctx, symbol, p.strategy.GetBrokerInterval(), 30)
 	if err != nil {
 		return err
 	}
-	if len(klines) < 20 {
+	if len(candles) < 20 {
 		return fmt.Errorf("not enough candles to analyze")
 	}
 
-	var config map[string]interface{}
-	err = json.Unmarshal(p.strategy.StrategyConfiguration.Configuration, &config)
-	if err != nil {
+	var config Config
+	if err := algorithm.Decode(p.strategy.StrategyConfiguration.Configuration, &config); err != nil {
 		return err
 	}
 
-	takeProfitPct, _ := config["take_profit_pct"].(float64)
-	stopLossPct, _ := config["stop_loss_pct"].(float64)
-
-	closes := make([]float64, len(klines))
-	for i, k := range klines {
-		closes[i], _ = strconv.ParseFloat(k.Close, 64)
-	}
+	closes := broker.Closes(candles)
 
 	upper, _, lower := talib.BBands(closes, 20, 2.0, 2.0, talib.EMA)
 
@@ -76,37 +75,61 @@ func (p BollingerProcessor) RunBollingerAlgorithm(ctx context.Context, symbol st
 	}
 	// If has a open signal, check if we need to close it
 	if openSignal.ID != 0 {
-		return p.generateSell(ctx, openSignal, takeProfitPct, stopLossPct, upper)
+		return p.generateSell(ctx, openSignal, config, upper, lower)
 	}
 
 	// if we don't have an open signal, check if we need to open one
-	if current < lower[len(lower)-1] {
-		entry := usecase.EntrySignal{
-			Symbol:     symbol,
-			StrategyID: p.strategy.ID,
-			EntryPrice: float32(current),
-			MarginType: entities.MarginType(entities.Isolated),
-		}
-		return p.usecase.GenerateBuySignal(entry)
+	var side entities.PositionSide
+	switch {
+	case current < lower[len(lower)-1] && config.Direction != "short":
+		side = entities.Long
+	case current > upper[len(upper)-1] && (config.Direction == "short" || config.Direction == "both"):
+		side = entities.Short
+	default:
+		return nil
 	}
 
-	return nil
+	entry := config.Open(usecase.EntrySignal{
+		Symbol:     symbol,
+		StrategyID: p.strategy.ID,
+		AccountID:  p.strategy.AccountID,
+		EntryPrice: decimal.NewFromFloat(current),
+		Mode:       p.strategy.ExecutionMode(),
+		OrderType:  config.OrderType,
+	}, side)
+	entry, err = config.Size(ctx, p.broker, p.strategy, entry, config.StopLossPct)
+	if err != nil {
+		return err
+	}
+	return p.usecase.GenerateBuySignal(entry)
 }
 
-func (p BollingerProcessor) generateSell(ctx context.Context, openSignal entities.Signal, takeProfitPct float64, stopLossPct float64, upper []float64) error {
+// generateSell closes longs above the upper band and shorts below the lower
+// band, both on their take profit, stop loss or trailing stop.
+func (p BollingerProcessor) generateSell(ctx context.Context, openSignal entities.Signal, config Config, upper []float64, lower []float64) error {
 	ticker, err := p.broker.ListTickerPrices(ctx, openSignal.Symbol)
-	if err != nil {
+	if err != nil || len(ticker) == 0 {
 		return fmt.Errorf("Can't get current price for symbol %s when closing open order", openSignal.Symbol)
 	}
-	current, _ := strconv.ParseFloat(ticker[0].Price, 32)
+	current := ticker[0].Price
 	entryPrice := openSignal.Orders[0].EntryPrice
-	pnl := (current - float64(entryPrice)) / float64(entryPrice) * 100
+	pnl := (current - entryPrice.InexactFloat64()) / entryPrice.InexactFloat64() * 100
+	crossed := current > upper[len(upper)-1]
+	if openSignal.Side == entities.Short {
+		pnl = -pnl
+		crossed = current < lower[len(lower)-1]
+	}
+
+	trailed, err := config.Hit(ctx, p.broker, p.usecase, p.strategy, openSignal, current)
+	if err != nil {
+		return err
+	}
 
-	if pnl >= takeProfitPct || pnl <= -stopLossPct || current > upper[len(upper)-1] {
+	if pnl >= config.TakeProfitPct || pnl <= -config.StopLossPct || crossed || trailed {
 		exit := usecase.ExitSignal{
 			Symbol:     openSignal.Symbol,
 			StrategyID: p.strategy.ID,
-			ExitPrice:  float32(current),
+			ExitPrice:  decimal.NewFromFloat(current),
 		}
 		return p.usecase.GenerateSellSignal(exit)
 	}
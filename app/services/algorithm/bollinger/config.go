@@ -0,0 +1,26 @@
+package bollinger
+
+import (
+	"go-trade-bot/app/services/algorithm"
+	"go-trade-bot/internal/broker"
+	"go-trade-bot/internal/customerror"
+)
+
+type Config struct {
+	algorithm.Trailing
+	algorithm.Sizing
+	algorithm.Position
+
+	TakeProfitPct float64          `json:"take_profit_pct" required:"true" min:"0.01" max:"100" doc:"Gain in percent that closes the position"`
+	StopLossPct   float64          `json:"stop_loss_pct" required:"true" min:"0.01" max:"100" doc:"Loss in percent that closes the position"`
+	OrderType     broker.OrderType `json:"order_type" default:"market" oneof:"market limit" doc:"Type of the entry order"`
+	Direction     string           `json:"direction" default:"long" oneof:"long short both" doc:"Entries taken: long below the lower band, short above the upper band or both, shorts need the futures market"`
+}
+
+// Check refuses short entries on spot, they need the futures market.
+func (c Config) Check() []customerror.FieldError {
+	if c.Direction != "long" && c.Market != "futures" {
+		return []customerror.FieldError{{Field: "direction", Message: "must be long on the spot market"}}
+	}
+	return nil
+}
@@ -0,0 +1,17 @@
+package bollinger
+
+import (
+	"go-trade-bot/app/entities"
+	"go-trade-bot/app/services/algorithm"
+)
+
+func init() {
+	algorithm.Register(algorithm.Definition{
+		Name:        entities.Bollinger,
+		Description: "Buys when the price closes under the lower Bollinger band and sells on the upper band, take profit or stop loss",
+		Config:      Config{},
+		New: func(strategy entities.Strategy, deps algorithm.Dependencies) algorithm.Processor {
+			return NewBollingerProcessor(strategy, deps.Broker, deps.Signals)
+		},
+	})
+}
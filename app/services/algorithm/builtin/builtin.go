@@ -0,0 +1,9 @@
+// Package builtin registers the algorithms shipped with the bot, import it
+// for its side effects wherever strategies are executed or validated.
+package builtin
+
+import (
+	_ "go-trade-bot/app/services/algorithm/bollinger"
+	_ "go-trade-bot/app/services/algorithm/grid"
+	_ "go-trade-bot/app/services/algorithm/scalping"
+)
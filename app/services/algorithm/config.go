@@ -0,0 +1,288 @@
+package algorithm
+
+import (
+	"encoding/json"
+	"fmt"
+	"go-trade-bot/internal/customerror"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Algorithms declare their configuration as a struct, each field is a
+// parameter described by its tags:
+//
+//	json      name of the parameter
+//	doc       description listed by GET /algorithms
+//	default   value used when the parameter is missing
+//	required  "true" when the parameter has no default and must be set
+//	min, max  inclusive bounds of numbers
+//	oneof     space separated values allowed for strings
+//
+// Embedded structs add their fields as parameters of the algorithm.
+type Parameter struct {
+	Name        string   `json:"name"`
+	Type        string   `json:"type"`
+	Description string   `json:"description"`
+	Required    bool     `json:"required"`
+	Default     any      `json:"default,omitempty"`
+	Min         *float64 `json:"min,omitempty"`
+	Max         *float64 `json:"max,omitempty"`
+	Options     []string `json:"options,omitempty"`
+}
+
+type field struct {
+	index     []int
+	parameter Parameter
+}
+
+const configurationField = "configuration"
+
+// Checker is implemented by configs whose parameters depend on each other.
+// Check runs once every parameter is valid, the fields of its errors are
+// the names of the parameters.
+type Checker interface {
+	Check() []customerror.FieldError
+}
+
+// Validate checks the configuration of a strategy against the config struct
+// of its algorithm and its Check. Unknown parameters are rejected, so a typo
+// does not go unnoticed. It returns the configuration with the defaults
+// filled in.
+func Validate(config any, configuration []byte) ([]byte, []customerror.FieldError) {
+	target := reflect.New(reflect.TypeOf(config))
+	if errs := decode(configuration, target.Elem(), true); len(errs) > 0 {
+		return nil, errs
+	}
+	if checker, ok := target.Interface().(Checker); ok {
+		if errs := checker.Check(); len(errs) > 0 {
+			for i := range errs {
+				errs[i].Field = configurationField + "." + errs[i].Field
+			}
+			return nil, errs
+		}
+	}
+
+	normalized, err := json.Marshal(target.Interface())
+	if err != nil {
+		return nil, []customerror.FieldError{{Field: configurationField, Message: err.Error()}}
+	}
+	return normalized, nil
+}
+
+// Decode fills the config struct pointed by target with the configuration
+// and the defaults. Unknown parameters are ignored so strategies saved with
+// parameters that no longer exist still run.
+func Decode(configuration []byte, target any) error {
+	value := reflect.ValueOf(target)
+	if value.Kind() != reflect.Pointer || value.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("config target must be a pointer to a struct")
+	}
+
+	if errs := decode(configuration, value.Elem(), false); len(errs) > 0 {
+		messages := make([]string, len(errs))
+		for i, e := range errs {
+			messages[i] = e.Field + " " + e.Message
+		}
+		return fmt.Errorf("invalid configuration: %s", strings.Join(messages, ", "))
+	}
+	return nil
+}
+
+// Parameters documents the parameters of a config struct.
+func Parameters(config any) []Parameter {
+	fields, err := fieldsOf(reflect.TypeOf(config))
+	if err != nil {
+		return nil
+	}
+	parameters := make([]Parameter, len(fields))
+	for i, f := range fields {
+		parameters[i] = f.parameter
+	}
+	return parameters
+}
+
+func decode(configuration []byte, target reflect.Value, strict bool) []customerror.FieldError {
+	fields, err := fieldsOf(target.Type())
+	if err != nil {
+		return []customerror.FieldError{{Field: configurationField, Message: err.Error()}}
+	}
+
+	values := map[string]json.RawMessage{}
+	if len(configuration) > 0 && string(configuration) != "null" {
+		if err := json.Unmarshal(configuration, &values); err != nil {
+			return []customerror.FieldError{{Field: configurationField, Message: "must be a JSON object"}}
+		}
+	}
+
+	errs := []customerror.FieldError{}
+	for _, f := range fields {
+		p := f.parameter
+		name := configurationField + "." + p.Name
+		value := target.FieldByIndex(f.index)
+
+		raw, ok := values[p.Name]
+		delete(values, p.Name)
+		switch {
+		case ok && string(raw) != "null":
+			if err := json.Unmarshal(raw, value.Addr().Interface()); err != nil {
+				errs = append(errs, customerror.FieldError{Field: name, Message: "must be " + article(p.Type)})
+				continue
+			}
+		case p.Default != nil:
+			value.Set(reflect.ValueOf(p.Default).Convert(value.Type()))
+		case p.Required:
+			errs = append(errs, customerror.FieldError{Field: name, Message: "is required"})
+			continue
+		default:
+			continue
+		}
+
+		if message := checkBounds(p, value); message != "" {
+			errs = append(errs, customerror.FieldError{Field: name, Message: message})
+		}
+	}
+
+	if strict {
+		unknown := make([]string, 0, len(values))
+		for key := range values {
+			unknown = append(unknown, key)
+		}
+		sort.Strings(unknown)
+		for _, key := range unknown {
+			errs = append(errs, customerror.FieldError{Field: configurationField + "." + key, Message: "is not a parameter of the algorithm"})
+		}
+	}
+	return errs
+}
+
+func checkBounds(p Parameter, value reflect.Value) string {
+	switch value.Kind() {
+	case reflect.Float32, reflect.Float64, reflect.Int, reflect.Int32, reflect.Int64:
+		n := toFloat(value)
+		if p.Min != nil && n < *p.Min {
+			return fmt.Sprintf("must be at least %v", *p.Min)
+		}
+		if p.Max != nil && n > *p.Max {
+			return fmt.Sprintf("must be at most %v", *p.Max)
+		}
+	case reflect.String:
+		if len(p.Options) > 0 && !contains(p.Options, value.String()) {
+			return "must be one of " + strings.Join(p.Options, ", ")
+		}
+	}
+	return ""
+}
+
+func fieldsOf(t reflect.Type) ([]field, error) {
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("config of an algorithm must be a struct, got %s", t)
+	}
+
+	fields := make([]field, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.Anonymous && sf.Type.Kind() == reflect.Struct {
+			// Embedded structs share parameters between algorithms.
+			embedded, err := fieldsOf(sf.Type)
+			if err != nil {
+				return nil, err
+			}
+			for _, f := range embedded {
+				f.index = append([]int{i}, f.index...)
+				fields = append(fields, f)
+			}
+			continue
+		}
+
+		name, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+
+		p := Parameter{
+			Name:        name,
+			Type:        typeName(sf.Type.Kind()),
+			Description: sf.Tag.Get("doc"),
+			Required:    sf.Tag.Get("required") == "true",
+		}
+		if p.Type == "" {
+			return nil, fmt.Errorf("parameter %s has unsupported type %s", name, sf.Type)
+		}
+		if def, ok := sf.Tag.Lookup("default"); ok {
+			value, err := parseTag(def, sf.Type)
+			if err != nil {
+				return nil, fmt.Errorf("invalid default of parameter %s: %w", name, err)
+			}
+			p.Default = value
+		}
+		for tag, bound := range map[string]**float64{"min": &p.Min, "max": &p.Max} {
+			if s, ok := sf.Tag.Lookup(tag); ok {
+				n, err := strconv.ParseFloat(s, 64)
+				if err != nil {
+					return nil, fmt.Errorf("invalid %s of parameter %s: %w", tag, name, err)
+				}
+				*bound = &n
+			}
+		}
+		if oneof, ok := sf.Tag.Lookup("oneof"); ok {
+			p.Options = strings.Fields(oneof)
+		}
+		fields = append(fields, field{index: []int{i}, parameter: p})
+	}
+	return fields, nil
+}
+
+func parseTag(s string, t reflect.Type) (any, error) {
+	switch t.Kind() {
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(s, 64)
+		return reflect.ValueOf(n).Convert(t).Interface(), err
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, 64)
+		return reflect.ValueOf(n).Convert(t).Interface(), err
+	case reflect.Bool:
+		return strconv.ParseBool(s)
+	default:
+		return reflect.ValueOf(s).Convert(t).Interface(), nil
+	}
+}
+
+func typeName(kind reflect.Kind) string {
+	switch kind {
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		return "integer"
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	default:
+		return ""
+	}
+}
+
+func toFloat(value reflect.Value) float64 {
+	if value.CanFloat() {
+		return value.Float()
+	}
+	return float64(value.Int())
+}
+
+func article(typ string) string {
+	if typ == "integer" {
+		return "an integer"
+	}
+	return "a " + typ
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,82 @@
+package algorithm_test
+
+import (
+	"go-trade-bot/app/services/algorithm"
+	"go-trade-bot/internal/customerror"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type config struct {
+	TakeProfitPct float64 `json:"take_profit_pct" required:"true" min:"0.01" max:"100" doc:"Take profit"`
+	Period        int     `json:"period" default:"14" min:"2"`
+	OrderType     string  `json:"order_type" default:"market" oneof:"market limit"`
+}
+
+func TestValidate(t *testing.T) {
+	t.Run("should fill defaults", func(t *testing.T) {
+		normalized, errs := algorithm.Validate(config{}, []byte(`{"take_profit_pct": 0.5}`))
+		assert.Empty(t, errs)
+		assert.JSONEq(t, `{"take_profit_pct": 0.5, "period": 14, "order_type": "market"}`, string(normalized))
+	})
+
+	t.Run("should report a typo as missing and unknown", func(t *testing.T) {
+		_, errs := algorithm.Validate(config{}, []byte(`{"take_profit": 0.5}`))
+		assert.Equal(t, []customerror.FieldError{
+			{Field: "configuration.take_profit_pct", Message: "is required"},
+			{Field: "configuration.take_profit", Message: "is not a parameter of the algorithm"},
+		}, errs)
+	})
+
+	t.Run("should report wrong types and ranges", func(t *testing.T) {
+		_, errs := algorithm.Validate(config{}, []byte(`{"take_profit_pct": 200, "period": 1.5}`))
+		assert.Equal(t, []customerror.FieldError{
+			{Field: "configuration.take_profit_pct", Message: "must be at most 100"},
+			{Field: "configuration.period", Message: "must be an integer"},
+		}, errs)
+	})
+
+	t.Run("should reject configurations that are not objects", func(t *testing.T) {
+		_, errs := algorithm.Validate(config{}, []byte(`[1, 2]`))
+		assert.Equal(t, []customerror.FieldError{{Field: "configuration", Message: "must be a JSON object"}}, errs)
+	})
+}
+
+type checked struct {
+	Side   string `json:"side" default:"long" oneof:"long short"`
+	Market string `json:"market" default:"spot" oneof:"spot futures"`
+}
+
+func (c checked) Check() []customerror.FieldError {
+	if c.Side == "short" && c.Market == "spot" {
+		return []customerror.FieldError{{Field: "side", Message: "must be long on spot"}}
+	}
+	return nil
+}
+
+func TestValidate_Check(t *testing.T) {
+	_, errs := algorithm.Validate(checked{}, []byte(`{"side": "short"}`))
+	assert.Equal(t, []customerror.FieldError{{Field: "configuration.side", Message: "must be long on spot"}}, errs)
+
+	_, errs = algorithm.Validate(checked{}, []byte(`{"side": "short", "market": "futures"}`))
+	assert.Empty(t, errs)
+}
+
+func TestDecode(t *testing.T) {
+	var c config
+	err := algorithm.Decode([]byte(`{"take_profit_pct": 1, "leverage": 0}`), &c)
+	assert.NoError(t, err)
+	assert.Equal(t, config{TakeProfitPct: 1, Period: 14, OrderType: "market"}, c)
+}
+
+func TestParameters(t *testing.T) {
+	params := algorithm.Parameters(config{})
+	assert.Len(t, params, 3)
+	assert.Equal(t, "take_profit_pct", params[0].Name)
+	assert.Equal(t, "number", params[0].Type)
+	assert.True(t, params[0].Required)
+	assert.Equal(t, 100.0, *params[0].Max)
+	assert.Equal(t, 14, params[1].Default)
+	assert.Equal(t, []string{"market", "limit"}, params[2].Options)
+}
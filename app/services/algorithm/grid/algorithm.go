@@ -2,48 +2,54 @@ package grid
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"fmt"
 	"go-trade-bot/app/entities"
+	"go-trade-bot/app/services/algorithm"
 	usecase "go-trade-bot/app/usecase/signal"
 	"go-trade-bot/internal/broker"
 	"log"
-	"strconv"
 
 	"github.com/markcheno/go-talib"
+	"github.com/shopspring/decimal"
 )
 
-var key = "grid-"
+// volumeInterval is the kline read by Get24hVolume.
+const volumeInterval = "1d"
 
 type GridProcessor struct {
 	strategy entities.Strategy
-	broker   broker.Broker
+	broker   Broker
 	usecase  SignalUseCase
-	cache    Cache
+	store    Store
 }
 
-type GridOrder struct {
-	Type  string
-	Price float64
+type Broker interface {
+	ListTickerPrices(ctx context.Context, symbol string) ([]broker.Ticker, error)
+	ListKline(ctx context.Context, symbol string, interval string, limit int) ([]broker.Candle, error)
+	Get24hVolume(ctx context.Context, symbol string) (float64, error)
 }
 
-type Cache interface {
-	Get(key string) (any, bool)
-	Set(key string, value any)
-	Delete(key string)
+type Store interface {
+	Get(ctx context.Context, strategyID uint, symbol string) (entities.GridState, bool, error)
+	Save(ctx context.Context, state entities.GridState) error
 }
 
 type SignalUseCase interface {
 	GenerateBuySignal(e usecase.EntrySignal) error
 	GenerateSellSignal(e usecase.ExitSignal) error
 	GetOpenSignal(symbol string, strategyId uint) (entities.Signal, error)
+	TrackHighWaterMark(signal entities.Signal, price decimal.Decimal) (decimal.Decimal, error)
 }
 
-func NewGridProcessor(s entities.Strategy, b broker.Broker, ss SignalUseCase, c Cache) GridProcessor {
+func NewGridProcessor(s entities.Strategy, b Broker, ss SignalUseCase, st Store) GridProcessor {
 	return GridProcessor{
 		strategy: s,
 		broker:   b,
 		usecase:  ss,
-		cache:    c,
+		store:    st,
 	}
 }
 
@@ -58,40 +64,48 @@ func (p GridProcessor) Execute() error {
 }
 
 func (p GridProcessor) RunGridAlgorithm(ctx context.Context, symbol string) error {
-	v, _ := p.cache.Get(key + symbol)
-
-	existGrid, ok := v.([]GridOrder)
+	var config Config
+	if err := algorithm.Decode(p.strategy.StrategyConfiguration.Configuration, &config); err != nil {
+		return err
+	}
 
-	if !ok {
-		existGrid = []GridOrder{}
+	hash, err := p.configHash(config)
+	if err != nil {
+		return err
 	}
 
-	var config map[string]interface{}
-	err := json.Unmarshal(p.strategy.StrategyConfiguration.Configuration, &config)
+	state, ok, err := p.store.Get(ctx, p.strategy.ID, symbol)
 	if err != nil {
 		return err
 	}
 
-	if len(existGrid) > 0 {
-		p.monitore(ctx, symbol, existGrid, config)
-	} else {
-		p.buildGridForSymbol(ctx, symbol, config)
+	// A grid built with another configuration is discarded and rebuilt.
+	if ok && state.ConfigHash == hash && len(state.Levels) > 0 {
+		return p.monitore(ctx, symbol, state.Levels, config)
 	}
+	return p.buildGridForSymbol(ctx, symbol, config, hash)
+}
 
-	return nil
+// configHash identifies the configuration and interval a grid is built with.
+func (p GridProcessor) configHash(config Config) (string, error) {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(append(data, p.strategy.GetBrokerInterval()...))
+	return hex.EncodeToString(sum[:]), nil
 }
 
-func (p GridProcessor) monitore(ctx context.Context, symbol string, grid []GridOrder, config map[string]interface{}) error {
-	stopLossPct, _ := config["stop_loss_pct"].(float64)
+func (p GridProcessor) monitore(ctx context.Context, symbol string, grid []entities.GridLevel, config Config) error {
 
 	ticker, err := p.broker.ListTickerPrices(ctx, symbol)
 	if err != nil {
 		return err
 	}
-	current, err := strconv.ParseFloat(ticker[0].Price, 64)
-	if err != nil {
-		log.Printf("Erro parsing value for grid symbol %s err %s", symbol, err.Error())
+	if len(ticker) == 0 {
+		return fmt.Errorf("no ticker prices found for symbol %s", symbol)
 	}
+	current := ticker[0].Price
 
 	openSignal, err := p.usecase.GetOpenSignal(symbol, p.strategy.ID)
 	if err != nil {
@@ -100,38 +114,50 @@ func (p GridProcessor) monitore(ctx context.Context, symbol string, grid []GridO
 
 	if openSignal.ID != 0 {
 		entryPrice := openSignal.Orders[0].EntryPrice
-		pnl := (current - float64(entryPrice)) / float64(entryPrice) * 100
+		pnl := (current - entryPrice.InexactFloat64()) / entryPrice.InexactFloat64() * 100
+
+		trailed, err := config.Hit(ctx, p.broker, p.usecase, p.strategy, openSignal, current)
+		if err != nil {
+			return err
+		}
 
-		if pnl <= -stopLossPct {
+		if pnl <= -config.StopLossPct || trailed {
 			return p.usecase.GenerateSellSignal(usecase.ExitSignal{
 				Symbol:     openSignal.Symbol,
 				StrategyID: p.strategy.ID,
-				ExitPrice:  float32(current),
+				ExitPrice:  decimal.NewFromFloat(current),
 			})
 		}
 	}
 
 	for _, order := range grid {
-		if order.Type == "buy" {
+		if order.Type == entities.GridBuy {
 			if current <= order.Price {
 				log.Printf("[GRID] %s triggered %s at %.2f", symbol, order.Type, current)
-				return p.usecase.GenerateBuySignal(usecase.EntrySignal{
+				entry, err := config.Size(ctx, p.broker, p.strategy, usecase.EntrySignal{
 					Symbol:     symbol,
 					StrategyID: p.strategy.ID,
-					EntryPrice: float32(current),
+					AccountID:  p.strategy.AccountID,
+					EntryPrice: decimal.NewFromFloat(current),
 					MarginType: entities.MarginType(entities.Isolated),
-				})
+					Mode:       p.strategy.ExecutionMode(),
+					OrderType:  config.OrderType,
+				}, config.StopLossPct)
+				if err != nil {
+					return err
+				}
+				return p.usecase.GenerateBuySignal(entry)
 			}
 		}
 
-		if order.Type == "sell" {
+		if order.Type == entities.GridSell {
 			if current >= order.Price {
 				if openSignal.ID != 0 {
 					log.Printf("[GRID] %s triggered %s at %.2f", symbol, order.Type, current)
 					return p.usecase.GenerateSellSignal(usecase.ExitSignal{
 						Symbol:     openSignal.Symbol,
 						StrategyID: p.strategy.ID,
-						ExitPrice:  float32(current),
+						ExitPrice:  decimal.NewFromFloat(current),
 					})
 				}
 
@@ -141,51 +167,34 @@ func (p GridProcessor) monitore(ctx context.Context, symbol string, grid []GridO
 	return nil
 }
 
-func (p GridProcessor) buildGridForSymbol(ctx context.Context, symbol string, config map[string]interface{}) error {
-	klines, err := p.broker.ListKline(ctx, symbol, p.strategy.GetBrokerInterval(), 100)
+func (p GridProcessor) buildGridForSymbol(ctx context.Context, symbol string, config Config, hash string) error {
+	candles, err := p.broker.ListKline(ctx, symbol, p.strategy.GetBrokerInterval(), 100)
 	if err != nil {
 		return err
 	}
-	if len(klines) == 0 {
+	if len(candles) == 0 {
 		return nil
 	}
-	gridLevelsFloat, _ := config["grid_levels"].(float64)
-	gridLevels := int(gridLevelsFloat)
-	gridSpacingPct, _ := config["grid_spacing_pct"].(float64)
-	volumeFilter, _ := config["volume_filter"].(float64)
-	takeProfitPct, _ := config["take_profit_pct"].(float64)
-	rsiPeriodFloat, _ := config["rsi_period"].(float64)
-	rsiBuyThreshold, _ := config["rsi_buy_threshold"].(float64)
-	rsiSellThreshold, _ := config["rsi_sell_threshold"].(float64)
-
-	closes := make([]float64, len(klines))
-	for i, k := range klines {
-		close, err := strconv.ParseFloat(k.Close, 64)
-		if err != nil {
-			return err
-		}
-		closes[i] = close
-	}
+	gridLevels := config.GridLevels
+
+	closes := broker.Closes(candles)
 
-	rsi := talib.Rsi(closes, int(rsiPeriodFloat))
+	rsi := talib.Rsi(closes, config.RSIPeriod)
 	currentRSI := rsi[len(rsi)-1]
 
-	latestClose, err := strconv.ParseFloat(klines[len(klines)-1].Close, 64)
-	if err != nil {
-		return err
-	}
-	gridSpacing := latestClose * gridSpacingPct / 100
+	latestClose := closes[len(closes)-1]
+	gridSpacing := latestClose * config.GridSpacingPct / 100
 
-	if volumeFilter > 0 {
+	if config.VolumeFilter > 0 {
 		vol, err := p.broker.Get24hVolume(ctx, symbol)
-		if err == nil && vol < volumeFilter {
-			log.Printf("Volume under minimun (%.2f < %.2f), ignoring symbol %s", vol, volumeFilter, symbol)
+		if err == nil && vol < config.VolumeFilter {
+			log.Printf("Volume under minimun (%.2f < %.2f), ignoring symbol %s", vol, config.VolumeFilter, symbol)
 			return nil
 		}
 	}
 
-	if currentRSI > rsiSellThreshold {
-		log.Printf("RSI above sell threshold (%.2f > %.2f), skipping %s", currentRSI, rsiSellThreshold, symbol)
+	if currentRSI > config.RSISellThreshold {
+		log.Printf("RSI above sell threshold (%.2f > %.2f), skipping %s", currentRSI, config.RSISellThreshold, symbol)
 		return nil
 	}
 
@@ -194,31 +203,35 @@ func (p GridProcessor) buildGridForSymbol(ctx context.Context, symbol string, co
 		gridPrices[i] = latestClose + (float64(i)-float64(gridLevels/2))*gridSpacing
 	}
 
-	gridData := []GridOrder{}
+	gridData := []entities.GridLevel{}
 	hasBuySignal := false
 	for _, price := range gridPrices {
 		if price < latestClose {
-			if currentRSI < rsiBuyThreshold {
+			if currentRSI < config.RSIBuyThreshold {
 				hasBuySignal = true
-				gridData = append(gridData, GridOrder{
-					Type:  "buy",
+				gridData = append(gridData, entities.GridLevel{
+					Type:  entities.GridBuy,
 					Price: price,
 				})
 			}
 		} else {
 			profit := ((price - latestClose) / latestClose) * 100
-			if profit >= takeProfitPct && hasBuySignal {
-				gridData = append(gridData, GridOrder{
-					Type:  "sell",
+			if profit >= config.TakeProfitPct && hasBuySignal {
+				gridData = append(gridData, entities.GridLevel{
+					Type:  entities.GridSell,
 					Price: price,
 				})
 			}
 		}
 	}
 
-	if len(gridData) > 0 {
-		p.cache.Set(key+symbol, gridData)
+	if len(gridData) == 0 {
+		return nil
 	}
-
-	return nil
+	return p.store.Save(ctx, entities.GridState{
+		StrategyID: p.strategy.ID,
+		Symbol:     symbol,
+		ConfigHash: hash,
+		Levels:     gridData,
+	})
 }
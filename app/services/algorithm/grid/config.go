@@ -0,0 +1,21 @@
+package grid
+
+import (
+	"go-trade-bot/app/services/algorithm"
+	"go-trade-bot/internal/broker"
+)
+
+type Config struct {
+	algorithm.Trailing
+	algorithm.Sizing
+
+	GridLevels       int              `json:"grid_levels" default:"8" min:"2" max:"100" doc:"Number of price levels of the grid"`
+	GridSpacingPct   float64          `json:"grid_spacing_pct" required:"true" min:"0.01" max:"50" doc:"Distance between levels in percent of the price"`
+	TakeProfitPct    float64          `json:"take_profit_pct" required:"true" min:"0.01" max:"100" doc:"Minimum gain in percent of a sell level"`
+	StopLossPct      float64          `json:"stop_loss_pct" required:"true" min:"0.01" max:"100" doc:"Loss in percent that closes the position"`
+	VolumeFilter     float64          `json:"volume_filter" default:"0" min:"0" doc:"Minimum 24h volume of the symbol, 0 disables the filter"`
+	RSIPeriod        int              `json:"rsi_period" default:"14" min:"2" max:"100" doc:"Period of the RSI"`
+	RSIBuyThreshold  float64          `json:"rsi_buy_threshold" default:"30" min:"0" max:"100" doc:"RSI under which buy levels are placed"`
+	RSISellThreshold float64          `json:"rsi_sell_threshold" default:"70" min:"0" max:"100" doc:"RSI above which no grid is built"`
+	OrderType        broker.OrderType `json:"order_type" default:"market" oneof:"market limit" doc:"Type of the entry order"`
+}
@@ -0,0 +1,20 @@
+package grid
+
+import (
+	"go-trade-bot/app/entities"
+	"go-trade-bot/app/services/algorithm"
+)
+
+func init() {
+	algorithm.Register(algorithm.Definition{
+		Name:        entities.Grid,
+		Description: "Places a grid of buy levels under the price when RSI is low and sell levels above it, closes on stop loss",
+		Config:      Config{},
+		History: []algorithm.History{
+			{Interval: volumeInterval, Candles: 1},
+		},
+		New: func(strategy entities.Strategy, deps algorithm.Dependencies) algorithm.Processor {
+			return NewGridProcessor(strategy, deps.Broker, deps.Signals, deps.Grids)
+		},
+	})
+}
@@ -0,0 +1,28 @@
+package algorithm
+
+import (
+	"go-trade-bot/app/entities"
+	usecase "go-trade-bot/app/usecase/signal"
+)
+
+// Position is embedded in the config of the algorithms to choose the market
+// their entries trade on. Futures entries open a position of their margin
+// times the leverage, shorts are only possible there. Configurations saved
+// before futures existed carry a leverage of 0, it trades as 1.
+type Position struct {
+	Market     string `json:"market" default:"spot" oneof:"spot futures" doc:"Market of the entries: spot or USD-M futures"`
+	Leverage   int    `json:"leverage" default:"1" min:"0" max:"125" doc:"Leverage of the entries, futures market, 0 is read as 1"`
+	MarginType string `json:"margin_type" default:"isolated" oneof:"isolated cross" doc:"Margin of the entries, isolated or cross, futures market"`
+}
+
+// Open sets the side, market and margin of the entry.
+func (p Position) Open(entry usecase.EntrySignal, side entities.PositionSide) usecase.EntrySignal {
+	entry.Side = side
+	entry.Market = entities.Market(p.Market)
+	entry.Leverage = max(p.Leverage, 1)
+	entry.MarginType = entities.MarginType(p.MarginType)
+	if entry.MarginType == "" {
+		entry.MarginType = entities.Isolated
+	}
+	return entry
+}
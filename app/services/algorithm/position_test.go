@@ -0,0 +1,27 @@
+package algorithm_test
+
+import (
+	"go-trade-bot/app/entities"
+	"go-trade-bot/app/services/algorithm"
+	"go-trade-bot/app/services/algorithm/bollinger"
+	usecase "go-trade-bot/app/usecase/signal"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPosition_LegacyLeverage(t *testing.T) {
+	legacy := []byte(`{"take_profit_pct": 0.5, "stop_loss_pct": 1, "leverage": 0, "order_type": "market"}`)
+
+	var config bollinger.Config
+	require.NoError(t, algorithm.Decode(legacy, &config))
+	assert.Equal(t, 0, config.Leverage)
+
+	_, errs := algorithm.Validate(bollinger.Config{}, legacy)
+	assert.Empty(t, errs)
+
+	entry := config.Open(usecase.EntrySignal{Symbol: "BTCUSDT"}, entities.Long)
+	assert.Equal(t, 1, entry.Leverage)
+	assert.Equal(t, entities.Spot, entry.Market)
+}
@@ -0,0 +1,165 @@
+package algorithm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"go-trade-bot/app/entities"
+	usecase "go-trade-bot/app/usecase/signal"
+	"go-trade-bot/internal/broker"
+	"go-trade-bot/internal/customerror"
+	"go-trade-bot/internal/memcache"
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/shopspring/decimal"
+)
+
+type Processor interface {
+	Execute() error
+}
+
+// MarketData is everything an algorithm may read from the exchange.
+type MarketData interface {
+	ListTickerPrices(ctx context.Context, symbol string) ([]broker.Ticker, error)
+	ListKline(ctx context.Context, symbol string, interval string, limit int) ([]broker.Candle, error)
+	Get24hVolume(ctx context.Context, symbol string) (float64, error)
+}
+
+type SignalUseCase interface {
+	GenerateBuySignal(e usecase.EntrySignal) error
+	GenerateSellSignal(e usecase.ExitSignal) error
+	GetOpenSignal(symbol string, strategyId uint) (entities.Signal, error)
+	TrackHighWaterMark(signal entities.Signal, price decimal.Decimal) (decimal.Decimal, error)
+}
+
+// GridStore persists the grids of the strategies, so they survive restarts
+// and are shared by every worker.
+type GridStore interface {
+	Get(ctx context.Context, strategyID uint, symbol string) (entities.GridState, bool, error)
+	Save(ctx context.Context, state entities.GridState) error
+}
+
+// Dependencies are handed to the constructor of every algorithm, each one
+// takes what it needs.
+type Dependencies struct {
+	Broker  MarketData
+	Signals SignalUseCase
+	Cache   memcache.Cache
+	Grids   GridStore
+}
+
+// History is a kline interval read by an algorithm besides the one of the
+// strategy cycle, with the number of candles read on each evaluation.
+type History struct {
+	Interval string `json:"interval"`
+	Candles  int    `json:"candles"`
+}
+
+type Definition struct {
+	Name        entities.Algorithm
+	Description string
+	// Config is the zero value of the configuration struct of the algorithm.
+	Config  any
+	History []History
+	New     func(strategy entities.Strategy, deps Dependencies) Processor
+}
+
+func (d Definition) Parameters() []Parameter {
+	return Parameters(d.Config)
+}
+
+// Validate checks the configuration of a strategy using the algorithm and
+// returns it with the defaults filled in.
+func (d Definition) Validate(configuration []byte) ([]byte, []customerror.FieldError) {
+	return Validate(d.Config, configuration)
+}
+
+func (d Definition) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Name        entities.Algorithm `json:"name"`
+		Description string             `json:"description"`
+		Parameters  []Parameter        `json:"parameters"`
+		History     []History          `json:"history,omitempty"`
+	}{
+		Name:        d.Name,
+		Description: d.Description,
+		Parameters:  d.Parameters(),
+		History:     d.History,
+	})
+}
+
+type Registry struct {
+	mu          sync.RWMutex
+	definitions map[entities.Algorithm]Definition
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		definitions: make(map[entities.Algorithm]Definition),
+	}
+}
+
+// Default is where the algorithm packages register themselves on init.
+var Default = NewRegistry()
+
+// DefaultRegistry returns the registry with the built-in algorithms.
+func DefaultRegistry() *Registry {
+	return Default
+}
+
+// Register adds an algorithm to the default registry. It panics when the
+// name is taken, like a duplicated database/sql driver.
+func Register(d Definition) {
+	if err := Default.Register(d); err != nil {
+		panic(err)
+	}
+}
+
+func (r *Registry) Register(d Definition) error {
+	if d.Name == "" || d.New == nil || d.Config == nil {
+		return fmt.Errorf("algorithm needs a name, a config and a constructor")
+	}
+	if _, err := fieldsOf(reflect.TypeOf(d.Config)); err != nil {
+		return fmt.Errorf("algorithm %s: %w", d.Name, err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.definitions[d.Name]; ok {
+		return fmt.Errorf("algorithm %s is already registered", d.Name)
+	}
+	r.definitions[d.Name] = d
+	return nil
+}
+
+func (r *Registry) Get(name entities.Algorithm) (Definition, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	d, ok := r.definitions[name]
+	return d, ok
+}
+
+// List returns the registered algorithms sorted by name.
+func (r *Registry) List() []Definition {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	definitions := make([]Definition, 0, len(r.definitions))
+	for _, d := range r.definitions {
+		definitions = append(definitions, d)
+	}
+	sort.Slice(definitions, func(i, j int) bool {
+		return definitions[i].Name < definitions[j].Name
+	})
+	return definitions
+}
+
+func (r *Registry) NewProcessor(strategy entities.Strategy, deps Dependencies) (Processor, error) {
+	d, ok := r.Get(strategy.Algorithm)
+	if !ok {
+		return nil, fmt.Errorf("unknown strategy algorithm: %s", strategy.Algorithm)
+	}
+	return d.New(strategy, deps), nil
+}
@@ -2,31 +2,41 @@ package scalping
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"go-trade-bot/app/entities"
+	"go-trade-bot/app/services/algorithm"
 	usecase "go-trade-bot/app/usecase/signal"
 	"go-trade-bot/internal/broker"
 	"log"
-	"strconv"
 
-	"github.com/adshao/go-binance/v2"
 	"github.com/markcheno/go-talib"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	trendInterval = "15m"
+	trendCandles  = 50
 )
 
 type ScalpingProcessor struct {
 	strategy entities.Strategy
-	broker   broker.Broker
+	broker   Broker
 	usecase  SignalUseCase
 }
 
+type Broker interface {
+	ListTickerPrices(ctx context.Context, symbol string) ([]broker.Ticker, error)
+	ListKline(ctx context.Context, symbol string, interval string, limit int) ([]broker.Candle, error)
+}
+
 type SignalUseCase interface {
 	GenerateBuySignal(e usecase.EntrySignal) error
 	GenerateSellSignal(e usecase.ExitSignal) error
 	GetOpenSignal(symbol string, strategyId uint) (entities.Signal, error)
+	TrackHighWaterMark(signal entities.Signal, price decimal.Decimal) (decimal.Decimal, error)
 }
 
-func NewScalpingProcessor(s entities.Strategy, b broker.Broker, ss SignalUseCase) ScalpingProcessor {
+func NewScalpingProcessor(s entities.Strategy, b Broker, ss SignalUseCase) ScalpingProcessor {
 	return ScalpingProcessor{
 		strategy: s,
 		broker:   b,
@@ -45,49 +55,46 @@ func (p ScalpingProcessor) Execute() error {
 }
 
 func (p ScalpingProcessor) RunScalpingAlgorithm(ctx context.Context, symbol string) error {
-	klines, err := p.broker.ListKline(ctx, symbol, p.strategy.GetBrokerInterval(), 60)
+	candles, err := p.broker.ListKline(ctx, symbol, p.strategy.GetBrokerInterval(), 60)
 	if err != nil {
 		return err
 	}
-	if len(klines) < 10 {
+	if len(candles) < 10 {
 		return fmt.Errorf("not enough candles to analyze")
 	}
 
-	var config map[string]interface{}
-	err = json.Unmarshal(p.strategy.StrategyConfiguration.Configuration, &config)
-	if err != nil {
+	var config Config
+	if err := algorithm.Decode(p.strategy.StrategyConfiguration.Configuration, &config); err != nil {
 		return err
 	}
 
-	takeProfitPct, _ := config["take_profit_pct"].(float64)
-	stopLossPct, _ := config["stop_loss_pct"].(float64)
-
 	openSignal, err := p.usecase.GetOpenSignal(symbol, p.strategy.ID)
 	if err != nil {
 		return err
 	}
 	// has open signal need to close first
 	if openSignal.ID != 0 {
-		return p.generateSell(ctx, symbol, openSignal, takeProfitPct, stopLossPct)
+		return p.generateSell(ctx, symbol, openSignal, config)
 	}
 
 	// Generate a buy signal validating volume and RSI
-	if validateVolume(klines) && validateRSI(klines) && p.isUptrend(ctx, symbol) {
-		latestClose, err := strconv.ParseFloat(klines[len(klines)-1].Close, 64)
-		if err != nil {
-			return fmt.Errorf("failed to parse latest close price: %v", err)
-		}
-		prevClose, err := strconv.ParseFloat(klines[len(klines)-2].Close, 64)
-		if err != nil {
-			return fmt.Errorf("failed to parse previous close price: %v", err)
-		}
+	if validateVolume(candles) && validateRSI(candles) && p.isUptrend(ctx, symbol) {
+		latestClose := candles[len(candles)-1].Close
+		prevClose := candles[len(candles)-2].Close
 
 		if latestClose > prevClose {
 			entry := usecase.EntrySignal{
 				Symbol:     symbol,
 				StrategyID: p.strategy.ID,
-				EntryPrice: float32(latestClose),
+				AccountID:  p.strategy.AccountID,
+				EntryPrice: decimal.NewFromFloat(latestClose),
 				MarginType: entities.MarginType(entities.Isolated),
+				Mode:       p.strategy.ExecutionMode(),
+				OrderType:  config.OrderType,
+			}
+			entry, err = config.Size(ctx, p.broker, p.strategy, entry, config.StopLossPct)
+			if err != nil {
+				return err
 			}
 
 			p.usecase.GenerateBuySignal(entry)
@@ -96,26 +103,22 @@ func (p ScalpingProcessor) RunScalpingAlgorithm(ctx context.Context, symbol stri
 	return nil
 }
 
-func validateVolume(klines []*binance.Kline) bool {
+func validateVolume(candles []broker.Candle) bool {
 	avgVolume := 0.0
-	for _, k := range klines {
-		vol, _ := strconv.ParseFloat(k.Volume, 64)
-		avgVolume += vol
+	for _, c := range candles {
+		avgVolume += c.Volume
 	}
-	avgVolume /= float64(len(klines))
+	avgVolume /= float64(len(candles))
 
-	latestVolume, _ := strconv.ParseFloat(klines[len(klines)-1].Volume, 64)
+	latestVolume := candles[len(candles)-1].Volume
 	if latestVolume < avgVolume {
 		return false
 	}
 	return true
 }
 
-func validateRSI(klines []*binance.Kline) bool {
-	closes := make([]float64, len(klines))
-	for i, k := range klines {
-		closes[i], _ = strconv.ParseFloat(k.Close, 64)
-	}
+func validateRSI(candles []broker.Candle) bool {
+	closes := broker.Closes(candles)
 
 	rsi := talib.Rsi(closes, 14)
 	latestRSI := rsi[len(rsi)-1]
@@ -126,7 +129,7 @@ func validateRSI(klines []*binance.Kline) bool {
 	return true
 }
 
-func (p ScalpingProcessor) generateSell(ctx context.Context, symbol string, openSignal entities.Signal, takeProfitPct float64, stopLossPct float64) error {
+func (p ScalpingProcessor) generateSell(ctx context.Context, symbol string, openSignal entities.Signal, config Config) error {
 	tickerPrices, err := p.broker.ListTickerPrices(ctx, symbol)
 	if err != nil {
 		return fmt.Errorf("failed to list ticker prices for symbol %s: %v", symbol, err)
@@ -134,19 +137,21 @@ func (p ScalpingProcessor) generateSell(ctx context.Context, symbol string, open
 	if len(tickerPrices) == 0 {
 		return fmt.Errorf("no ticker prices found for symbol %s", symbol)
 	}
-	currentPrice, err := strconv.ParseFloat(tickerPrices[0].Price, 64)
-	if err != nil {
-		return fmt.Errorf("failed to parse ticker price for symbol %s: %v", symbol, err)
-	}
+	currentPrice := tickerPrices[0].Price
 	entryPrice := openSignal.Orders[0].EntryPrice
 
-	pnl := (currentPrice - float64(entryPrice)) / float64(entryPrice) * 100
+	pnl := (currentPrice - entryPrice.InexactFloat64()) / entryPrice.InexactFloat64() * 100
 
-	if pnl >= takeProfitPct || pnl <= -stopLossPct {
+	trailed, err := config.Hit(ctx, p.broker, p.usecase, p.strategy, openSignal, currentPrice)
+	if err != nil {
+		return err
+	}
+
+	if pnl >= config.TakeProfitPct || pnl <= -config.StopLossPct || trailed {
 		exit := usecase.ExitSignal{
 			Symbol:     symbol,
 			StrategyID: p.strategy.ID,
-			ExitPrice:  float32(currentPrice),
+			ExitPrice:  decimal.NewFromFloat(currentPrice),
 		}
 
 		err := p.usecase.GenerateSellSignal(exit)
@@ -160,16 +165,13 @@ func (p ScalpingProcessor) generateSell(ctx context.Context, symbol string, open
 }
 
 func (p ScalpingProcessor) isUptrend(ctx context.Context, symbol string) bool {
-	longTermKlines, err := p.broker.ListKline(ctx, symbol, "15m", 50)
-	if err != nil || len(longTermKlines) < 20 {
+	longTermCandles, err := p.broker.ListKline(ctx, symbol, trendInterval, trendCandles)
+	if err != nil || len(longTermCandles) < 20 {
 		log.Printf("Failed to fetch long-term klines for trend analysis: %v", err)
 		return false
 	}
 
-	longCloses := make([]float64, len(longTermKlines))
-	for i, k := range longTermKlines {
-		longCloses[i], _ = strconv.ParseFloat(k.Close, 64)
-	}
+	longCloses := broker.Closes(longTermCandles)
 
 	ema := talib.Ema(longCloses, 20)
 	if len(ema) == 0 {
@@ -0,0 +1,15 @@
+package scalping
+
+import (
+	"go-trade-bot/app/services/algorithm"
+	"go-trade-bot/internal/broker"
+)
+
+type Config struct {
+	algorithm.Trailing
+	algorithm.Sizing
+
+	TakeProfitPct float64          `json:"take_profit_pct" required:"true" min:"0.01" max:"100" doc:"Gain in percent that closes the position"`
+	StopLossPct   float64          `json:"stop_loss_pct" required:"true" min:"0.01" max:"100" doc:"Loss in percent that closes the position"`
+	OrderType     broker.OrderType `json:"order_type" default:"market" oneof:"market limit" doc:"Type of the entry order"`
+}
@@ -0,0 +1,20 @@
+package scalping
+
+import (
+	"go-trade-bot/app/entities"
+	"go-trade-bot/app/services/algorithm"
+)
+
+func init() {
+	algorithm.Register(algorithm.Definition{
+		Name:        entities.Scalping,
+		Description: "Buys rising closes with volume above average, RSI under 70 and a 15 minutes uptrend, sells on take profit or stop loss",
+		Config:      Config{},
+		History: []algorithm.History{
+			{Interval: trendInterval, Candles: trendCandles},
+		},
+		New: func(strategy entities.Strategy, deps algorithm.Dependencies) algorithm.Processor {
+			return NewScalpingProcessor(strategy, deps.Broker, deps.Signals)
+		},
+	})
+}
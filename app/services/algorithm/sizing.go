@@ -0,0 +1,48 @@
+package algorithm
+
+import (
+	"context"
+	"go-trade-bot/app/entities"
+	usecase "go-trade-bot/app/usecase/signal"
+
+	"github.com/shopspring/decimal"
+)
+
+// Sizing is embedded in the config of the algorithms to choose how much of
+// the account each entry spends. The amount itself is worked out by the
+// signal use case when the entry is booked.
+type Sizing struct {
+	SizingMode                string  `json:"sizing_mode" default:"equal" oneof:"equal fixed_notional fixed_fraction risk volatility kelly" doc:"Amount of the entries: equal slice of the balance per available order, fixed_notional, fixed_fraction of the equity, risk per trade, volatility targeted or kelly"`
+	SizingNotional            float64 `json:"sizing_notional" default:"100" min:"0.01" doc:"Quote amount of every entry, fixed_notional mode"`
+	SizingFractionPct         float64 `json:"sizing_fraction_pct" default:"10" min:"0.01" max:"100" doc:"Percent of the equity put in every entry, fixed_fraction mode"`
+	SizingRiskPct             float64 `json:"sizing_risk_pct" default:"1" min:"0.01" max:"100" doc:"Percent of the equity lost when the stop loss is hit, risk mode"`
+	SizingTargetVolatilityPct float64 `json:"sizing_target_volatility_pct" default:"1" min:"0.01" max:"100" doc:"Percent of the equity a move of one ATR is worth, volatility mode"`
+	SizingATRPeriod           int     `json:"sizing_atr_period" default:"14" min:"2" max:"100" doc:"Period of the ATR, volatility mode"`
+	SizingKellyFraction       float64 `json:"sizing_kelly_fraction" default:"0.5" min:"0.01" max:"1" doc:"Fraction of the Kelly criterion staked, kelly mode"`
+	SizingKellyMaxPct         float64 `json:"sizing_kelly_max_pct" default:"20" min:"0.01" max:"100" doc:"Cap of every entry in percent of the equity, kelly mode"`
+}
+
+// Size sets the sizing policy of the entry, with stopLossPct the stop loss
+// of the algorithm. The ATR is only read in volatility mode.
+func (s Sizing) Size(ctx context.Context, klines Klines, strategy entities.Strategy, entry usecase.EntrySignal, stopLossPct float64) (usecase.EntrySignal, error) {
+	entry.Sizing = usecase.Sizing{
+		Mode:                usecase.SizingMode(s.SizingMode),
+		Notional:            decimal.NewFromFloat(s.SizingNotional),
+		FractionPct:         decimal.NewFromFloat(s.SizingFractionPct),
+		RiskPct:             decimal.NewFromFloat(s.SizingRiskPct),
+		StopLossPct:         decimal.NewFromFloat(stopLossPct),
+		TargetVolatilityPct: decimal.NewFromFloat(s.SizingTargetVolatilityPct),
+		KellyFraction:       decimal.NewFromFloat(s.SizingKellyFraction),
+		KellyMaxPct:         decimal.NewFromFloat(s.SizingKellyMaxPct),
+	}
+	if entry.Sizing.Mode != usecase.VolatilitySizing {
+		return entry, nil
+	}
+
+	atr, err := latestATR(ctx, klines, strategy, entry.Symbol, s.SizingATRPeriod)
+	if err != nil {
+		return entry, err
+	}
+	entry.Sizing.ATR = decimal.NewFromFloat(atr)
+	return entry, nil
+}
@@ -0,0 +1,48 @@
+package algorithm_test
+
+import (
+	"context"
+	"go-trade-bot/app/entities"
+	"go-trade-bot/app/services/algorithm"
+	"go-trade-bot/app/services/algorithm/mocks"
+	usecase "go-trade-bot/app/usecase/signal"
+	"go-trade-bot/internal/broker"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestSizing_Size(t *testing.T) {
+	strategy := entities.Strategy{
+		ID:                    1,
+		StrategyConfiguration: entities.StrategyConfiguration{Cycle: entities.FiveMinutes},
+	}
+	entry := usecase.EntrySignal{Symbol: "BTCUSDT", StrategyID: 1, EntryPrice: d(100)}
+
+	t.Run("should hand the risk policy the stop loss of the algorithm", func(t *testing.T) {
+		klines := new(mocks.Klines)
+		sizing := algorithm.Sizing{SizingMode: "risk", SizingRiskPct: 1}
+
+		sized, err := sizing.Size(context.Background(), klines, strategy, entry, 2.5)
+		assert.NoError(t, err)
+		assert.Equal(t, usecase.RiskSizing, sized.Sizing.Mode)
+		assert.True(t, d(2.5).Equal(sized.Sizing.StopLossPct))
+		klines.AssertNotCalled(t, "ListKline", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("should read the ATR in volatility mode", func(t *testing.T) {
+		candles := make([]broker.Candle, 4)
+		for i := range candles {
+			candles[i] = broker.Candle{High: 102, Low: 98, Close: 100}
+		}
+		klines := new(mocks.Klines)
+		klines.On("ListKline", mock.Anything, "BTCUSDT", "5m", 4).Return(candles, nil).Once()
+		sizing := algorithm.Sizing{SizingMode: "volatility", SizingTargetVolatilityPct: 1, SizingATRPeriod: 3}
+
+		sized, err := sizing.Size(context.Background(), klines, strategy, entry, 2)
+		assert.NoError(t, err)
+		assert.True(t, d(4).Equal(sized.Sizing.ATR))
+		klines.AssertExpectations(t)
+	})
+}
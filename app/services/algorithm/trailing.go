@@ -0,0 +1,89 @@
+package algorithm
+
+import (
+	"context"
+	"fmt"
+	"go-trade-bot/app/entities"
+	"go-trade-bot/internal/broker"
+
+	talib "github.com/markcheno/go-talib"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	TrailingOff     = "off"
+	TrailingPercent = "percent"
+	TrailingATR     = "atr"
+)
+
+type Klines interface {
+	ListKline(ctx context.Context, symbol string, interval string, limit int) ([]broker.Candle, error)
+}
+
+// Trailing is embedded in the config of the algorithms to add a trailing
+// stop on top of their own exits. The stop follows the best price seen
+// since the entry, persisted on the signal, at a fixed percent or at a
+// multiple of the ATR of the strategy interval. It trails below longs and
+// above shorts.
+type Trailing struct {
+	TrailingMode          string  `json:"trailing_mode" default:"off" oneof:"off percent atr" doc:"Trailing stop of open positions: off, percent or atr"`
+	TrailingPct           float64 `json:"trailing_pct" default:"1" min:"0.01" max:"100" doc:"Retracement from the highest price in percent that closes the position, percent mode"`
+	TrailingATRPeriod     int     `json:"trailing_atr_period" default:"14" min:"2" max:"100" doc:"Period of the ATR, atr mode"`
+	TrailingATRMultiplier float64 `json:"trailing_atr_multiplier" default:"3" min:"0.1" max:"20" doc:"Distance from the highest price in ATRs that closes the position, atr mode"`
+}
+
+// Hit moves the high-water mark of the open signal with the current price
+// and tells whether the price retraced past the trailing stop.
+func (t Trailing) Hit(ctx context.Context, klines Klines, signals SignalUseCase, strategy entities.Strategy, signal entities.Signal, current float64) (bool, error) {
+	if t.TrailingMode == "" || t.TrailingMode == TrailingOff {
+		return false, nil
+	}
+
+	mark, err := signals.TrackHighWaterMark(signal, decimal.NewFromFloat(current))
+	if err != nil {
+		return false, err
+	}
+
+	distance, err := t.distance(ctx, klines, strategy, signal.Symbol, mark.InexactFloat64())
+	if err != nil {
+		return false, err
+	}
+	if signal.Side == entities.Short {
+		return current >= mark.InexactFloat64()+distance, nil
+	}
+	return current <= mark.InexactFloat64()-distance, nil
+}
+
+// distance returns how far from the mark the stop trails.
+func (t Trailing) distance(ctx context.Context, klines Klines, strategy entities.Strategy, symbol string, mark float64) (float64, error) {
+	if t.TrailingMode != TrailingATR {
+		return mark * t.TrailingPct / 100, nil
+	}
+
+	atr, err := latestATR(ctx, klines, strategy, symbol, t.TrailingATRPeriod)
+	if err != nil {
+		return 0, err
+	}
+	return t.TrailingATRMultiplier * atr, nil
+}
+
+// latestATR returns the ATR of the symbol over the last period candles of
+// the strategy interval.
+func latestATR(ctx context.Context, klines Klines, strategy entities.Strategy, symbol string, period int) (float64, error) {
+	candles, err := klines.ListKline(ctx, symbol, strategy.GetBrokerInterval(), period+1)
+	if err != nil {
+		return 0, err
+	}
+	if len(candles) <= period {
+		return 0, fmt.Errorf("not enough candles to calculate the ATR of %s", symbol)
+	}
+
+	high := make([]float64, len(candles))
+	low := make([]float64, len(candles))
+	for i, c := range candles {
+		high[i] = c.High
+		low[i] = c.Low
+	}
+	atr := talib.Atr(high, low, broker.Closes(candles), period)
+	return atr[len(atr)-1], nil
+}
@@ -0,0 +1,105 @@
+package algorithm_test
+
+import (
+	"context"
+	"go-trade-bot/app/entities"
+	"go-trade-bot/app/services/algorithm"
+	"go-trade-bot/app/services/algorithm/mocks"
+	"go-trade-bot/internal/broker"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestTrailing_Hit(t *testing.T) {
+	strategy := entities.Strategy{
+		ID:                    1,
+		StrategyConfiguration: entities.StrategyConfiguration{Cycle: entities.FiveMinutes},
+	}
+	signal := entities.Signal{ID: 3, Symbol: "BTCUSDT", HighWaterMark: d(110)}
+
+	t.Run("should not track the mark when the trail is off", func(t *testing.T) {
+		signals := new(mocks.SignalUseCase)
+		trailing := algorithm.Trailing{TrailingMode: algorithm.TrailingOff}
+
+		hit, err := trailing.Hit(context.Background(), new(mocks.Klines), signals, strategy, signal, 90)
+		assert.NoError(t, err)
+		assert.False(t, hit)
+		signals.AssertNotCalled(t, "TrackHighWaterMark", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should hit when the price retraces past the percent trail", func(t *testing.T) {
+		signals := new(mocks.SignalUseCase)
+		signals.On("TrackHighWaterMark", signal, d(107)).Return(d(110), nil).Once()
+		trailing := algorithm.Trailing{TrailingMode: algorithm.TrailingPercent, TrailingPct: 2}
+
+		hit, err := trailing.Hit(context.Background(), new(mocks.Klines), signals, strategy, signal, 107)
+		assert.NoError(t, err)
+		assert.True(t, hit)
+		signals.AssertExpectations(t)
+	})
+
+	t.Run("should not hit while the price is above the percent trail", func(t *testing.T) {
+		signals := new(mocks.SignalUseCase)
+		signals.On("TrackHighWaterMark", signal, d(120)).Return(d(120), nil).Once()
+		trailing := algorithm.Trailing{TrailingMode: algorithm.TrailingPercent, TrailingPct: 2}
+
+		hit, err := trailing.Hit(context.Background(), new(mocks.Klines), signals, strategy, signal, 120)
+		assert.NoError(t, err)
+		assert.False(t, hit)
+	})
+
+	t.Run("should trail above shorts", func(t *testing.T) {
+		short := entities.Signal{ID: 4, Symbol: "BTCUSDT", Side: entities.Short, HighWaterMark: d(90)}
+		signals := new(mocks.SignalUseCase)
+		signals.On("TrackHighWaterMark", short, mock.Anything).Return(d(90), nil)
+		trailing := algorithm.Trailing{TrailingMode: algorithm.TrailingPercent, TrailingPct: 2}
+
+		// The stop sits at 90 + 2%.
+		hit, err := trailing.Hit(context.Background(), new(mocks.Klines), signals, strategy, short, 91)
+		assert.NoError(t, err)
+		assert.False(t, hit)
+
+		hit, err = trailing.Hit(context.Background(), new(mocks.Klines), signals, strategy, short, 92)
+		assert.NoError(t, err)
+		assert.True(t, hit)
+	})
+
+	t.Run("should trail at a multiple of the ATR", func(t *testing.T) {
+		candles := make([]broker.Candle, 4)
+		for i := range candles {
+			candles[i] = broker.Candle{High: 102, Low: 98, Close: 100}
+		}
+		klines := new(mocks.Klines)
+		klines.On("ListKline", mock.Anything, "BTCUSDT", "5m", 4).Return(candles, nil)
+		signals := new(mocks.SignalUseCase)
+		signals.On("TrackHighWaterMark", signal, mock.Anything).Return(d(110), nil)
+		trailing := algorithm.Trailing{TrailingMode: algorithm.TrailingATR, TrailingATRPeriod: 3, TrailingATRMultiplier: 2}
+
+		// ATR is 4, the stop sits at 110 - 2 * 4.
+		hit, err := trailing.Hit(context.Background(), klines, signals, strategy, signal, 103)
+		assert.NoError(t, err)
+		assert.False(t, hit)
+
+		hit, err = trailing.Hit(context.Background(), klines, signals, strategy, signal, 102)
+		assert.NoError(t, err)
+		assert.True(t, hit)
+	})
+
+	t.Run("should fail without enough candles for the ATR", func(t *testing.T) {
+		klines := new(mocks.Klines)
+		klines.On("ListKline", mock.Anything, "BTCUSDT", "5m", 15).Return([]broker.Candle{}, nil)
+		signals := new(mocks.SignalUseCase)
+		signals.On("TrackHighWaterMark", signal, d(100)).Return(d(110), nil)
+		trailing := algorithm.Trailing{TrailingMode: algorithm.TrailingATR, TrailingATRPeriod: 14, TrailingATRMultiplier: 3}
+
+		_, err := trailing.Hit(context.Background(), klines, signals, strategy, signal, 100)
+		assert.EqualError(t, err, "not enough candles to calculate the ATR of BTCUSDT")
+	})
+}
+
+func d(value float64) decimal.Decimal {
+	return decimal.NewFromFloat(value)
+}
@@ -0,0 +1,256 @@
+package backtest
+
+import (
+	"context"
+	"fmt"
+	"go-trade-bot/app/entities"
+	"go-trade-bot/app/services/algorithm"
+	accountUseCase "go-trade-bot/app/usecase/account"
+	signalUseCase "go-trade-bot/app/usecase/signal"
+	"go-trade-bot/internal/broker"
+	"go-trade-bot/internal/memcache"
+	"sort"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const defaultWarmup = 100
+
+type Config struct {
+	Strategy        entities.Strategy
+	Series          []broker.RecordedSeries
+	InitialAmount   decimal.Decimal
+	AvailableOrders int64
+	// Warmup is the number of candles of the strategy interval that are only
+	// used as history before the first evaluation.
+	Warmup int
+}
+
+type Trade struct {
+	Symbol      string
+	Side        entities.PositionSide
+	EntryTime   time.Time
+	ExitTime    time.Time
+	EntryPrice  decimal.Decimal
+	ExitPrice   decimal.Decimal
+	Quantity    decimal.Decimal
+	Profit      decimal.Decimal
+	ReturnPct   float64
+	ClosedAtEnd bool
+}
+
+type Summary struct {
+	Trades         int
+	NetPnL         float64
+	WinRate        float64
+	MaxDrawdownPct float64
+	Sharpe         float64
+	ExposurePct    float64
+	FinalEquity    float64
+}
+
+type Result struct {
+	Start   time.Time
+	End     time.Time
+	Trades  []Trade
+	Summary Summary
+}
+
+type Algorithms interface {
+	NewProcessor(strategy entities.Strategy, deps algorithm.Dependencies) (algorithm.Processor, error)
+}
+
+// Engine replays recorded candles through the same processors and signal
+// use case the worker runs, only the clock, the repositories and the fills
+// are simulated.
+type Engine struct {
+	algorithms Algorithms
+	fees       signalUseCase.FeeSchedule
+}
+
+func NewEngine(a Algorithms, f signalUseCase.FeeSchedule) Engine {
+	return Engine{
+		algorithms: a,
+		fees:       f,
+	}
+}
+
+func (e Engine) Run(ctx context.Context, cfg Config) (Result, error) {
+	strategy := cfg.Strategy
+	// Backtests never reach the exchange, whatever the strategy status is.
+	strategy.Status = entities.Testing
+
+	clock := &SimulatedClock{}
+	feed := broker.NewReplayFeed(cfg.Series, clock)
+
+	accounts := &accountRepository{}
+	accounts.Create(context.Background(), entities.Account{
+		ID:              strategy.AccountID,
+		Amount:          cfg.InitialAmount,
+		AvailableOrders: cfg.AvailableOrders,
+	})
+	account := accountUseCase.NewAccountUseCase(accounts, &ledgerRepository{}, transactor{}, e.fees)
+
+	signals := newSignalRepository(clock)
+	signalUC := signalUseCase.NewSignalUseCase(signals, account, nil, transactor{}, e.fees, nil, nil)
+
+	executor, err := e.algorithms.NewProcessor(strategy, algorithm.Dependencies{
+		Broker:  feed,
+		Signals: signalUC,
+		Cache:   memcache.NewInMemoryCache(),
+		Grids:   newGridStore(),
+	})
+	if err != nil {
+		return Result{}, err
+	}
+
+	steps := evaluationTimes(cfg.Series, strategy)
+	warmup := cfg.Warmup
+	if warmup <= 0 {
+		warmup = defaultWarmup
+	}
+	if len(steps) <= warmup {
+		return Result{}, fmt.Errorf("not enough candles to backtest, need more than %d", warmup)
+	}
+	steps = steps[warmup:]
+
+	equity := make([]float64, 0, len(steps))
+	exposed := 0
+	for _, step := range steps {
+		if err := ctx.Err(); err != nil {
+			return Result{}, err
+		}
+		clock.Set(step)
+		executor.Execute()
+
+		value, open, err := e.equity(ctx, accounts, signals, feed)
+		if err != nil {
+			return Result{}, err
+		}
+		equity = append(equity, value)
+		if open {
+			exposed++
+		}
+	}
+
+	// Positions still open are closed at the last price so the PnL is final.
+	closedAtEnd := map[uint]bool{}
+	open, _ := signals.GetAll()
+	for _, s := range open {
+		if s.Status != entities.Open {
+			continue
+		}
+		prices, err := feed.ListTickerPrices(ctx, s.Symbol)
+		if err != nil {
+			return Result{}, err
+		}
+		err = signalUC.GenerateSellSignal(signalUseCase.ExitSignal{
+			Symbol:     s.Symbol,
+			StrategyID: s.StrategyID,
+			ExitPrice:  decimal.NewFromFloat(prices[0].Price),
+		})
+		if err != nil {
+			return Result{}, err
+		}
+		closedAtEnd[s.ID] = true
+	}
+	if len(closedAtEnd) > 0 {
+		final, _, err := e.equity(ctx, accounts, signals, feed)
+		if err != nil {
+			return Result{}, err
+		}
+		equity[len(equity)-1] = final
+	}
+
+	all, _ := signals.GetAll()
+	trades := make([]Trade, 0, len(all))
+	for _, s := range all {
+		order := s.Orders[0]
+		trade := Trade{
+			Symbol:      s.Symbol,
+			Side:        s.Side,
+			EntryTime:   s.CreatedAt,
+			ExitTime:    s.UpdatedAt,
+			EntryPrice:  order.EntryPrice,
+			ExitPrice:   order.ExitPrice,
+			Quantity:    order.Quantity,
+			Profit:      order.Profit,
+			ClosedAtEnd: closedAtEnd[s.ID],
+		}
+		if order.InvestedAmount.IsPositive() {
+			trade.ReturnPct = order.Profit.Div(order.InvestedAmount).InexactFloat64() * 100
+		}
+		trades = append(trades, trade)
+	}
+
+	return Result{
+		Start:   steps[0],
+		End:     steps[len(steps)-1],
+		Trades:  trades,
+		Summary: summarize(trades, equity, exposed, cycleDuration(strategy)),
+	}, nil
+}
+
+// equity is the cash of the account plus open positions marked to market,
+// their margin and their unrealized PnL.
+func (e Engine) equity(ctx context.Context, accounts *accountRepository, signals *signalRepository, feed *broker.ReplayFeed) (float64, bool, error) {
+	account := accounts.current()
+	value := account.Amount.InexactFloat64()
+
+	all, _ := signals.GetAll()
+	open := false
+	for _, s := range all {
+		if s.Status != entities.Open {
+			continue
+		}
+		open = true
+		prices, err := feed.ListTickerPrices(ctx, s.Symbol)
+		if err != nil {
+			return 0, false, err
+		}
+		order := s.Orders[0]
+		pnl := (prices[0].Price - order.EntryPrice.InexactFloat64()) * order.Quantity.InexactFloat64()
+		if s.Side == entities.Short {
+			pnl = -pnl
+		}
+		value += order.InvestedAmount.InexactFloat64() + pnl
+	}
+	return value, open, nil
+}
+
+// evaluationTimes returns the close times of the candles the strategy
+// analyses that end one of its cycles, across all symbols, the moments the
+// worker would run. Cycles shorter than the candles run once per candle,
+// the closed candles don't change in between.
+func evaluationTimes(series []broker.RecordedSeries, strategy entities.Strategy) []time.Time {
+	interval := strategy.GetBrokerInterval()
+	cycle := strategy.StrategyConfiguration.Cycle.Interval()
+	seen := map[time.Time]bool{}
+	times := []time.Time{}
+	for _, s := range series {
+		if s.Interval != interval {
+			continue
+		}
+		for _, c := range s.Candles {
+			end := c.CloseTime.Add(time.Millisecond)
+			if !broker.IntervalStart(end, cycle).Equal(end) {
+				continue
+			}
+			if !seen[c.CloseTime] {
+				seen[c.CloseTime] = true
+				times = append(times, c.CloseTime)
+			}
+		}
+	}
+	sort.Slice(times, func(i, j int) bool {
+		return times[i].Before(times[j])
+	})
+	return times
+}
+
+// cycleDuration is the time between two evaluations of the backtest.
+func cycleDuration(strategy entities.Strategy) time.Duration {
+	interval, _ := broker.IntervalDuration(strategy.GetBrokerInterval())
+	return max(strategy.StrategyConfiguration.Cycle.Duration(), interval)
+}
@@ -0,0 +1,157 @@
+package backtest_test
+
+import (
+	"context"
+	"go-trade-bot/app/entities"
+	"go-trade-bot/app/services/algorithm"
+	_ "go-trade-bot/app/services/algorithm/builtin"
+	"go-trade-bot/app/services/backtest"
+	"go-trade-bot/internal/broker"
+	"go-trade-bot/internal/configuration"
+	"go-trade-bot/internal/fees"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func bollingerStrategy() entities.Strategy {
+	return entities.Strategy{
+		ID:               1,
+		Name:             "Bollinger",
+		Algorithm:        entities.Bollinger,
+		Status:           entities.Productive,
+		MonitoredSymbols: []string{"BTCUSDT"},
+		StrategyConfiguration: entities.StrategyConfiguration{
+			Cycle:         entities.OneMinute,
+			Configuration: []byte(`{"take_profit_pct": 2, "stop_loss_pct": 10}`),
+		},
+	}
+}
+
+// candles builds one minute candles with the given closes.
+func candles(closes []float64) []broker.Candle {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	result := make([]broker.Candle, len(closes))
+	for i, c := range closes {
+		open := start.Add(time.Duration(i) * time.Minute)
+		result[i] = broker.Candle{
+			OpenTime:  open,
+			CloseTime: open.Add(time.Minute - time.Millisecond),
+			Open:      c,
+			High:      c,
+			Low:       c,
+			Close:     c,
+			Volume:    10,
+		}
+	}
+	return result
+}
+
+func TestEngine_Run(t *testing.T) {
+	closes := []float64{}
+	for i := 0; i < 40; i++ {
+		closes = append(closes, 100+float64(i%2))
+	}
+	// a drop below the lower band opens a position, the recovery closes it
+	closes = append(closes, 90, 92, 95, 98, 100)
+
+	result, err := backtest.NewEngine(algorithm.DefaultRegistry(), fees.NewSchedule(&configuration.Configuration{})).Run(context.Background(), backtest.Config{
+		Strategy: bollingerStrategy(),
+		Series: []broker.RecordedSeries{
+			{Symbol: "BTCUSDT", Interval: "1m", Candles: candles(closes)},
+		},
+		InitialAmount:   d(1000),
+		AvailableOrders: 10,
+		Warmup:          30,
+	})
+	require.NoError(t, err)
+
+	require.Len(t, result.Trades, 1)
+	trade := result.Trades[0]
+	assert.True(t, d(90).Equal(trade.EntryPrice))
+	assert.True(t, d(92).Equal(trade.ExitPrice))
+	assert.False(t, trade.ClosedAtEnd)
+	assert.True(t, trade.Profit.IsPositive())
+
+	assert.Equal(t, 1, result.Summary.Trades)
+	assert.Equal(t, 100.0, result.Summary.WinRate)
+	assert.InDelta(t, trade.Profit.InexactFloat64(), result.Summary.NetPnL, 1e-6)
+	assert.InDelta(t, 1000+trade.Profit.InexactFloat64(), result.Summary.FinalEquity, 1e-3)
+	assert.Greater(t, result.Summary.ExposurePct, 0.0)
+}
+
+func TestEngine_Run_Short(t *testing.T) {
+	closes := []float64{}
+	for i := 0; i < 40; i++ {
+		closes = append(closes, 100+float64(i%2))
+	}
+	// a spike above the upper band opens a short, the fall takes its profit
+	closes = append(closes, 110, 108, 105, 102, 100)
+	strategy := bollingerStrategy()
+	strategy.StrategyConfiguration.Configuration = []byte(`{"take_profit_pct": 2, "stop_loss_pct": 10, "direction": "short", "market": "futures", "leverage": 3}`)
+
+	result, err := backtest.NewEngine(algorithm.DefaultRegistry(), fees.NewSchedule(&configuration.Configuration{})).Run(context.Background(), backtest.Config{
+		Strategy: strategy,
+		Series: []broker.RecordedSeries{
+			{Symbol: "BTCUSDT", Interval: "1m", Candles: candles(closes)},
+		},
+		InitialAmount:   d(1000),
+		AvailableOrders: 10,
+		Warmup:          30,
+	})
+	require.NoError(t, err)
+
+	require.Len(t, result.Trades, 1)
+	trade := result.Trades[0]
+	assert.Equal(t, entities.Short, trade.Side)
+	assert.True(t, d(110).Equal(trade.EntryPrice))
+	assert.True(t, d(105).Equal(trade.ExitPrice))
+	assert.True(t, trade.Profit.IsPositive())
+	// The margin of 100 opened a position three times larger.
+	assert.InDelta(t, 300, trade.Quantity.InexactFloat64()*110, 1e-6)
+}
+
+func TestEngine_Run_NotEnoughCandles(t *testing.T) {
+	_, err := backtest.NewEngine(algorithm.DefaultRegistry(), fees.NewSchedule(&configuration.Configuration{})).Run(context.Background(), backtest.Config{
+		Strategy: bollingerStrategy(),
+		Series: []broker.RecordedSeries{
+			{Symbol: "BTCUSDT", Interval: "1m", Candles: candles([]float64{1, 2, 3})},
+		},
+		Warmup: 30,
+	})
+	assert.Error(t, err)
+}
+
+func TestEngine_Run_CycleLongerThanCandles(t *testing.T) {
+	closes := []float64{}
+	for i := 0; i < 60; i++ {
+		closes = append(closes, 100+float64(i%2))
+	}
+	strategy := bollingerStrategy()
+	strategy.StrategyConfiguration.Cycle = entities.FiveMinutes
+	strategy.StrategyConfiguration.CandleInterval = "1m"
+
+	result, err := backtest.NewEngine(algorithm.DefaultRegistry(), fees.NewSchedule(&configuration.Configuration{})).Run(context.Background(), backtest.Config{
+		Strategy: strategy,
+		Series: []broker.RecordedSeries{
+			{Symbol: "BTCUSDT", Interval: "1m", Candles: candles(closes)},
+		},
+		InitialAmount:   d(1000),
+		AvailableOrders: 10,
+		Warmup:          6,
+	})
+	require.NoError(t, err)
+
+	// The 1m candles are evaluated when a 5 minutes cycle ends, the first
+	// six cycles warm up.
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, start.Add(35*time.Minute-time.Millisecond), result.Start)
+	assert.Equal(t, start.Add(time.Hour-time.Millisecond), result.End)
+}
+
+func d(value float64) decimal.Decimal {
+	return decimal.NewFromFloat(value)
+}
@@ -0,0 +1,359 @@
+package backtest
+
+import (
+	"context"
+	"fmt"
+	"go-trade-bot/app/entities"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// SimulatedClock is the time seen by the backtest, it only moves when the
+// engine steps to the next candle.
+type SimulatedClock struct {
+	mu  sync.RWMutex
+	now time.Time
+}
+
+func (c *SimulatedClock) Now() time.Time {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.now
+}
+
+func (c *SimulatedClock) Set(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = t
+}
+
+// signalRepository keeps the signals of a backtest in memory and stamps them
+// with the simulated clock instead of the wall clock.
+type signalRepository struct {
+	mu      sync.Mutex
+	clock   *SimulatedClock
+	signals map[uint]entities.Signal
+	nextID  uint
+}
+
+func newSignalRepository(clock *SimulatedClock) *signalRepository {
+	return &signalRepository{
+		clock:   clock,
+		signals: make(map[uint]entities.Signal),
+	}
+}
+
+func (r *signalRepository) Create(ctx context.Context, signal entities.Signal) (entities.Signal, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.nextID++
+	signal.ID = r.nextID
+	signal.CreatedAt = r.clock.Now()
+	signal.UpdatedAt = r.clock.Now()
+	for i := range signal.Orders {
+		signal.Orders[i].ID = r.nextID
+		signal.Orders[i].SignalID = r.nextID
+		signal.Orders[i].CreatedAt = r.clock.Now()
+		signal.Orders[i].UpdatedAt = r.clock.Now()
+	}
+	r.signals[signal.ID] = signal
+	return copySignal(signal), nil
+}
+
+func (r *signalRepository) GetOpenSignals(ctx context.Context, symbol string, strategyId uint) (entities.Signal, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, s := range r.signals {
+		if s.Symbol == symbol && s.StrategyID == strategyId && slices.Contains(entities.ActiveStatuses, s.Status) {
+			return copySignal(s), nil
+		}
+	}
+	return entities.Signal{}, nil
+}
+
+func (r *signalRepository) HasLiveFutures(ctx context.Context, symbol string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, s := range r.signals {
+		if s.Symbol == symbol && s.Market == entities.Futures && s.Mode == entities.Live && slices.Contains(entities.ActiveStatuses, s.Status) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *signalRepository) Transition(ctx context.Context, id uint, from entities.SignalStatus, to entities.SignalStatus) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	signal, ok := r.signals[id]
+	if !ok || signal.Status != from {
+		return false, nil
+	}
+	signal.Status = to
+	signal.UpdatedAt = r.clock.Now()
+	r.signals[id] = signal
+	return true, nil
+}
+
+func (r *signalRepository) Open(ctx context.Context, signal entities.Signal) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.signals[signal.ID].Status != entities.Pending {
+		return false, nil
+	}
+	signal.UpdatedAt = r.clock.Now()
+	for i := range signal.Orders {
+		signal.Orders[i].UpdatedAt = r.clock.Now()
+	}
+	r.signals[signal.ID] = copySignal(signal)
+	return true, nil
+}
+
+func (r *signalRepository) SaveOrder(ctx context.Context, order entities.Order) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	signal := copySignal(r.signals[order.SignalID])
+	for i := range signal.Orders {
+		if signal.Orders[i].ID == order.ID {
+			order.UpdatedAt = r.clock.Now()
+			signal.Orders[i] = order
+		}
+	}
+	r.signals[signal.ID] = signal
+	return nil
+}
+
+func (r *signalRepository) Close(ctx context.Context, signal entities.Signal) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.signals[signal.ID].Status != entities.Closing {
+		return false, nil
+	}
+	signal.UpdatedAt = r.clock.Now()
+	for i := range signal.Orders {
+		signal.Orders[i].UpdatedAt = r.clock.Now()
+	}
+	r.signals[signal.ID] = signal
+	return true, nil
+}
+
+func (r *signalRepository) UpdateHighWaterMark(id uint, price decimal.Decimal) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	signal := r.signals[id]
+	signal.HighWaterMark = price
+	r.signals[id] = signal
+	return nil
+}
+
+// TradedVolume is always zero, backtests are charged the base tier of the
+// fee model.
+func (r *signalRepository) TradedVolume(ctx context.Context, accountID int64, since time.Time) (decimal.Decimal, error) {
+	return decimal.Zero, nil
+}
+
+func (r *signalRepository) Exposure(ctx context.Context, accountID int64) (decimal.Decimal, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	exposure := decimal.Zero
+	for _, s := range r.signals {
+		if s.AccountID == accountID && slices.Contains(entities.ActiveStatuses, s.Status) {
+			for _, o := range s.Orders {
+				exposure = exposure.Add(o.InvestedAmount)
+			}
+		}
+	}
+	return exposure, nil
+}
+
+func (r *signalRepository) GetClosedSignals(ctx context.Context, strategyID uint, limit int) ([]entities.Signal, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	signals := make([]entities.Signal, 0)
+	for _, s := range r.signals {
+		if s.StrategyID == strategyID && s.Status == entities.Closed {
+			signals = append(signals, copySignal(s))
+		}
+	}
+	sort.Slice(signals, func(i, j int) bool {
+		return signals[i].ID > signals[j].ID
+	})
+	if len(signals) > limit {
+		signals = signals[:limit]
+	}
+	return signals, nil
+}
+
+func (r *signalRepository) GetByID(id uint) (entities.Signal, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return copySignal(r.signals[id]), nil
+}
+
+func (r *signalRepository) GetAll() ([]entities.Signal, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	signals := make([]entities.Signal, 0, len(r.signals))
+	for _, s := range r.signals {
+		signals = append(signals, copySignal(s))
+	}
+	sort.Slice(signals, func(i, j int) bool {
+		return signals[i].ID < signals[j].ID
+	})
+	return signals, nil
+}
+
+func copySignal(s entities.Signal) entities.Signal {
+	s.Orders = append([]entities.Order(nil), s.Orders...)
+	return s
+}
+
+// accountRepository holds the single account the backtest trades with,
+// whatever the account of the strategy is.
+type accountRepository struct {
+	mu      sync.Mutex
+	account entities.Account
+}
+
+func (r *accountRepository) Create(ctx context.Context, account entities.Account) (entities.Account, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.account = account
+	return account, nil
+}
+
+func (r *accountRepository) UpdateAccount(ctx context.Context, account entities.Account) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.account = account
+	return nil
+}
+
+func (r *accountRepository) GetAccountByID(id int64) (entities.Account, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.account, nil
+}
+
+func (r *accountRepository) Lock(ctx context.Context, id int64) (entities.Account, error) {
+	return r.GetAccountByID(id)
+}
+
+func (r *accountRepository) current() entities.Account {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.account
+}
+
+func (r *accountRepository) GetAll() ([]entities.Account, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return []entities.Account{r.account}, nil
+}
+
+func (r *accountRepository) Delete(id int64) error {
+	return nil
+}
+
+func (r *accountRepository) CountStrategies(id int64) (int64, error) {
+	return 1, nil
+}
+
+func (r *accountRepository) CountActiveSignals(ctx context.Context, id int64) (int64, error) {
+	return 0, nil
+}
+
+// ledgerRepository keeps the cash movements of the backtest account.
+type ledgerRepository struct {
+	mu      sync.Mutex
+	entries []entities.LedgerEntry
+}
+
+func (r *ledgerRepository) Append(ctx context.Context, entries ...entities.LedgerEntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = append(r.entries, entries...)
+	return nil
+}
+
+func (r *ledgerRepository) List(accountID int64, from time.Time, to time.Time) ([]entities.LedgerEntry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entries := []entities.LedgerEntry{}
+	for _, e := range r.entries {
+		if e.AccountID != accountID || (!from.IsZero() && e.CreatedAt.Before(from)) || (!to.IsZero() && e.CreatedAt.After(to)) {
+			continue
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+func (r *ledgerRepository) Balance(ctx context.Context, accountID int64) (decimal.Decimal, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	balance := decimal.Zero
+	found := false
+	for _, e := range r.entries {
+		if e.AccountID == accountID {
+			balance = balance.Add(e.Amount)
+			found = true
+		}
+	}
+	return balance, found, nil
+}
+
+// gridStore keeps the grids built during a backtest, they are dropped with it.
+type gridStore struct {
+	mu    sync.Mutex
+	grids map[string]entities.GridState
+}
+
+func newGridStore() *gridStore {
+	return &gridStore{
+		grids: make(map[string]entities.GridState),
+	}
+}
+
+func (s *gridStore) Get(ctx context.Context, strategyID uint, symbol string) (entities.GridState, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state, ok := s.grids[gridKey(strategyID, symbol)]
+	return state, ok, nil
+}
+
+func (s *gridStore) Save(ctx context.Context, state entities.GridState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.grids[gridKey(state.StrategyID, state.Symbol)] = state
+	return nil
+}
+
+func gridKey(strategyID uint, symbol string) string {
+	return fmt.Sprintf("%d-%s", strategyID, symbol)
+}
+
+// transactor runs the functions as they come, the backtest steps through its
+// candles one at a time.
+type transactor struct{}
+
+func (transactor) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
@@ -0,0 +1,82 @@
+package backtest
+
+import (
+	"math"
+	"time"
+)
+
+const year = 365 * 24 * time.Hour
+
+func summarize(trades []Trade, equity []float64, exposed int, cycle time.Duration) Summary {
+	summary := Summary{
+		Trades: len(trades),
+	}
+
+	wins := 0
+	for _, t := range trades {
+		summary.NetPnL += t.Profit.InexactFloat64()
+		if t.Profit.IsPositive() {
+			wins++
+		}
+	}
+	if len(trades) > 0 {
+		summary.WinRate = float64(wins) / float64(len(trades)) * 100
+	}
+	if len(equity) > 0 {
+		summary.FinalEquity = equity[len(equity)-1]
+		summary.ExposurePct = float64(exposed) / float64(len(equity)) * 100
+	}
+	summary.MaxDrawdownPct = maxDrawdown(equity)
+	summary.Sharpe = sharpe(equity, cycle)
+	return summary
+}
+
+// maxDrawdown is the largest drop from an equity peak, in percent.
+func maxDrawdown(equity []float64) float64 {
+	peak := 0.0
+	drawdown := 0.0
+	for _, value := range equity {
+		if value > peak {
+			peak = value
+		}
+		if peak > 0 {
+			drawdown = math.Max(drawdown, (peak-value)/peak*100)
+		}
+	}
+	return drawdown
+}
+
+// sharpe is the annualized Sharpe ratio of the per cycle equity returns,
+// assuming a zero risk free rate.
+func sharpe(equity []float64, cycle time.Duration) float64 {
+	if len(equity) < 2 || cycle <= 0 {
+		return 0
+	}
+
+	returns := make([]float64, 0, len(equity)-1)
+	for i := 1; i < len(equity); i++ {
+		if equity[i-1] == 0 {
+			continue
+		}
+		returns = append(returns, equity[i]/equity[i-1]-1)
+	}
+	if len(returns) < 2 {
+		return 0
+	}
+
+	mean := 0.0
+	for _, r := range returns {
+		mean += r
+	}
+	mean /= float64(len(returns))
+
+	variance := 0.0
+	for _, r := range returns {
+		variance += (r - mean) * (r - mean)
+	}
+	std := math.Sqrt(variance / float64(len(returns)-1))
+	if std == 0 {
+		return 0
+	}
+	return mean / std * math.Sqrt(float64(year)/float64(cycle))
+}
@@ -3,6 +3,7 @@
 package mocks
 
 import (
+	context "context"
 	entities "go-trade-bot/app/entities"
 
 	mock "github.com/stretchr/testify/mock"
@@ -13,17 +14,101 @@ type AccountRepository struct {
 	mock.Mock
 }
 
-// Create provides a mock function with given fields: account
-func (_m *AccountRepository) Create(account entities.Account) error {
-	ret := _m.Called(account)
+// CountActiveSignals provides a mock function with given fields: ctx, id
+func (_m *AccountRepository) CountActiveSignals(ctx context.Context, id int64) (int64, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountActiveSignals")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (int64, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) int64); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CountStrategies provides a mock function with given fields: id
+func (_m *AccountRepository) CountStrategies(id int64) (int64, error) {
+	ret := _m.Called(id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountStrategies")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64) (int64, error)); ok {
+		return rf(id)
+	}
+	if rf, ok := ret.Get(0).(func(int64) int64); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(int64) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Create provides a mock function with given fields: ctx, account
+func (_m *AccountRepository) Create(ctx context.Context, account entities.Account) (entities.Account, error) {
+	ret := _m.Called(ctx, account)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Create")
 	}
 
+	var r0 entities.Account
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, entities.Account) (entities.Account, error)); ok {
+		return rf(ctx, account)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entities.Account) entities.Account); ok {
+		r0 = rf(ctx, account)
+	} else {
+		r0 = ret.Get(0).(entities.Account)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entities.Account) error); ok {
+		r1 = rf(ctx, account)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Delete provides a mock function with given fields: id
+func (_m *AccountRepository) Delete(id int64) error {
+	ret := _m.Called(id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
 	var r0 error
-	if rf, ok := ret.Get(0).(func(entities.Account) error); ok {
-		r0 = rf(account)
+	if rf, ok := ret.Get(0).(func(int64) error); ok {
+		r0 = rf(id)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -59,17 +144,75 @@ func (_m *AccountRepository) GetAccountByID(id int64) (entities.Account, error)
 	return r0, r1
 }
 
-// UpdateAccount provides a mock function with given fields: account
-func (_m *AccountRepository) UpdateAccount(account entities.Account) error {
-	ret := _m.Called(account)
+// GetAll provides a mock function with no fields
+func (_m *AccountRepository) GetAll() ([]entities.Account, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAll")
+	}
+
+	var r0 []entities.Account
+	var r1 error
+	if rf, ok := ret.Get(0).(func() ([]entities.Account, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() []entities.Account); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entities.Account)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Lock provides a mock function with given fields: ctx, id
+func (_m *AccountRepository) Lock(ctx context.Context, id int64) (entities.Account, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Lock")
+	}
+
+	var r0 entities.Account
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (entities.Account, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) entities.Account); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(entities.Account)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateAccount provides a mock function with given fields: ctx, account
+func (_m *AccountRepository) UpdateAccount(ctx context.Context, account entities.Account) error {
+	ret := _m.Called(ctx, account)
 
 	if len(ret) == 0 {
 		panic("no return value specified for UpdateAccount")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(entities.Account) error); ok {
-		r0 = rf(account)
+	if rf, ok := ret.Get(0).(func(context.Context, entities.Account) error); ok {
+		r0 = rf(ctx, account)
 	} else {
 		r0 = ret.Error(0)
 	}
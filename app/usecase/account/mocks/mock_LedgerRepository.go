@@ -0,0 +1,121 @@
+// Code generated by mockery v2.53.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	entities "go-trade-bot/app/entities"
+	time "time"
+
+	decimal "github.com/shopspring/decimal"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// LedgerRepository is an autogenerated mock type for the LedgerRepository type
+type LedgerRepository struct {
+	mock.Mock
+}
+
+// Append provides a mock function with given fields: ctx, entries
+func (_m *LedgerRepository) Append(ctx context.Context, entries ...entities.LedgerEntry) error {
+	_va := make([]interface{}, len(entries))
+	for _i := range entries {
+		_va[_i] = entries[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Append")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, ...entities.LedgerEntry) error); ok {
+		r0 = rf(ctx, entries...)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Balance provides a mock function with given fields: ctx, accountID
+func (_m *LedgerRepository) Balance(ctx context.Context, accountID int64) (decimal.Decimal, bool, error) {
+	ret := _m.Called(ctx, accountID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Balance")
+	}
+
+	var r0 decimal.Decimal
+	var r1 bool
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (decimal.Decimal, bool, error)); ok {
+		return rf(ctx, accountID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) decimal.Decimal); ok {
+		r0 = rf(ctx, accountID)
+	} else {
+		r0 = ret.Get(0).(decimal.Decimal)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) bool); ok {
+		r1 = rf(ctx, accountID)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, int64) error); ok {
+		r2 = rf(ctx, accountID)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// List provides a mock function with given fields: accountID, from, to
+func (_m *LedgerRepository) List(accountID int64, from time.Time, to time.Time) ([]entities.LedgerEntry, error) {
+	ret := _m.Called(accountID, from, to)
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 []entities.LedgerEntry
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64, time.Time, time.Time) ([]entities.LedgerEntry, error)); ok {
+		return rf(accountID, from, to)
+	}
+	if rf, ok := ret.Get(0).(func(int64, time.Time, time.Time) []entities.LedgerEntry); ok {
+		r0 = rf(accountID, from, to)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entities.LedgerEntry)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int64, time.Time, time.Time) error); ok {
+		r1 = rf(accountID, from, to)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewLedgerRepository creates a new instance of LedgerRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewLedgerRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *LedgerRepository {
+	mock := &LedgerRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
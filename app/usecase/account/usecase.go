@@ -1,76 +1,382 @@
 package usecase
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"go-trade-bot/app/entities"
+	"go-trade-bot/internal/customerror"
+	"go-trade-bot/internal/fees"
+	"net/http"
 	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
 )
 
 type AccountRepository interface {
-	Create(account entities.Account) error
-	UpdateAccount(account entities.Account) error
+	Create(ctx context.Context, account entities.Account) (entities.Account, error)
+	UpdateAccount(ctx context.Context, account entities.Account) error
 	GetAccountByID(id int64) (entities.Account, error)
+	Lock(ctx context.Context, id int64) (entities.Account, error)
+	GetAll() ([]entities.Account, error)
+	Delete(id int64) error
+	CountStrategies(id int64) (int64, error)
+	CountActiveSignals(ctx context.Context, id int64) (int64, error)
+}
+
+type LedgerRepository interface {
+	Append(ctx context.Context, entries ...entities.LedgerEntry) error
+	List(accountID int64, from time.Time, to time.Time) ([]entities.LedgerEntry, error)
+	Balance(ctx context.Context, accountID int64) (decimal.Decimal, bool, error)
 }
 
+type FeeSchedule interface {
+	Model(name string) (fees.Model, bool)
+}
+
+type Transactor interface {
+	Transaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// Ledger is the history of an account between two dates. Balance is the sum
+// of every entry of the account, it differs from Amount when the balance was
+// changed outside of the ledger.
+type Ledger struct {
+	AccountID  int64
+	Amount     decimal.Decimal
+	Balance    decimal.Decimal
+	Difference decimal.Decimal
+	Entries    []entities.LedgerEntry
+}
+
+// AccountUseCase changes an account and appends its ledger entries in one
+// transaction, with the account row locked so concurrent orders can't book
+// the same slot or balance twice.
 type AccountUseCase struct {
-	Repository AccountRepository
+	Repository   AccountRepository
+	Ledger       LedgerRepository
+	Transactions Transactor
+	Fees         FeeSchedule
 }
 
-func NewAccountUseCase(r AccountRepository) *AccountUseCase {
+func NewAccountUseCase(r AccountRepository, l LedgerRepository, t Transactor, f FeeSchedule) *AccountUseCase {
 	return &AccountUseCase{
-		Repository: r,
+		Repository:   r,
+		Ledger:       l,
+		Transactions: t,
+		Fees:         f,
 	}
 }
 
-func (a *AccountUseCase) CreateAccount(account entities.Account) error {
+func (a *AccountUseCase) CreateAccount(account entities.Account) (entities.Account, error) {
+	if err := a.validate(account); err != nil {
+		return entities.Account{}, err
+	}
+	account.ID = 0
 	account.CreatedAt = time.Now()
 	account.UpdatedAt = time.Now()
-	return a.Repository.Create(account)
-}
 
-func (a *AccountUseCase) DeductOrder(entryPrice float32) error {
-	account, err := a.Repository.GetAccountByID(1)
+	err := a.Transactions.Transaction(context.Background(), func(ctx context.Context) error {
+		var err error
+		account, err = a.Repository.Create(ctx, account)
+		if err != nil {
+			return err
+		}
+		return a.record(ctx, account.ID, decimal.Zero, adjustment(account.Amount, "Initial balance")...)
+	})
 	if err != nil {
-		return err
+		return entities.Account{}, err
 	}
+	return account, nil
+}
 
-	account.AvailableOrders--
-	account.Amount -= entryPrice
-	account.UpdatedAt = time.Now()
-	return a.Repository.UpdateAccount(account)
+// UpdateAccount replaces the name, balance, order slots, currency and fee
+// model of the account. The balance and slots are the free ones, they can't
+// change while signals hold reservations on them, closing those signals
+// gives the reservations back.
+func (a *AccountUseCase) UpdateAccount(id int64, changes entities.Account) (entities.Account, error) {
+	if err := a.validate(changes); err != nil {
+		return entities.Account{}, err
+	}
+	if _, err := a.GetAccount(id); err != nil {
+		return entities.Account{}, err
+	}
+
+	var account entities.Account
+	err := a.Transactions.Transaction(context.Background(), func(ctx context.Context) error {
+		var err error
+		account, err = a.Repository.Lock(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if !account.Amount.Equal(changes.Amount) || account.AvailableOrders != changes.AvailableOrders {
+			active, err := a.Repository.CountActiveSignals(ctx, id)
+			if err != nil {
+				return err
+			}
+			if active > 0 {
+				return customerror.New(http.StatusConflict, "Account has active signals, its balance and order slots can't change until they close")
+			}
+		}
+
+		before := account.Amount
+		account.Name = changes.Name
+		account.Amount = changes.Amount
+		account.AvailableOrders = changes.AvailableOrders
+		account.Currency = changes.Currency
+		account.FeeModel = changes.FeeModel
+		account.UpdatedAt = time.Now()
+		if err := a.Repository.UpdateAccount(ctx, account); err != nil {
+			return err
+		}
+		return a.record(ctx, account.ID, before, adjustment(account.Amount.Sub(before), "Balance adjusted")...)
+	})
+	if err != nil {
+		return entities.Account{}, err
+	}
+	return account, nil
 }
 
-func (a *AccountUseCase) AddOrder(profit float32) error {
-	account, err := a.Repository.GetAccountByID(1)
+// DeleteAccount removes an account no strategy is bound to.
+func (a *AccountUseCase) DeleteAccount(id int64) error {
+	if _, err := a.GetAccount(id); err != nil {
+		return err
+	}
+
+	strategies, err := a.Repository.CountStrategies(id)
 	if err != nil {
 		return err
 	}
+	if strategies > 0 {
+		return customerror.New(http.StatusConflict, "Account has strategies bound to it")
+	}
+	return a.Repository.Delete(id)
+}
 
-	account.AvailableOrders++
-	account.Amount += profit
-	account.UpdatedAt = time.Now()
-	return a.Repository.UpdateAccount(account)
+// DeductOrder reserves the amount invested by the order of an opened signal,
+// it fails when the account has no order slot or not enough balance left.
+func (a *AccountUseCase) DeductOrder(ctx context.Context, accountID int64, signal entities.Signal) error {
+	return a.Transactions.Transaction(ctx, func(ctx context.Context) error {
+		account, err := a.Repository.Lock(ctx, accountID)
+		if err != nil {
+			return err
+		}
+		order := signal.Orders[0]
+		if account.AvailableOrders <= 0 {
+			return customerror.New(http.StatusConflict, "Account has no available orders")
+		}
+		if account.Amount.LessThan(order.InvestedAmount) {
+			return customerror.New(http.StatusConflict, "Account balance is too low for the order")
+		}
+
+		before := account.Amount
+		account.AvailableOrders--
+		account.Amount = account.Amount.Sub(order.InvestedAmount)
+		account.UpdatedAt = time.Now()
+		if err := a.Repository.UpdateAccount(ctx, account); err != nil {
+			return err
+		}
+		return a.record(ctx, accountID, before, movement(signal, entities.OrderReserve, order.InvestedAmount.Neg(), "Reserved for "+signal.Symbol))
+	})
+}
+
+// AddOrder releases the amount reserved by a closed signal and books its
+// realized PnL, the fees paid on both sides and the funding of futures.
+func (a *AccountUseCase) AddOrder(ctx context.Context, accountID int64, signal entities.Signal) error {
+	return a.Transactions.Transaction(ctx, func(ctx context.Context) error {
+		account, err := a.Repository.Lock(ctx, accountID)
+		if err != nil {
+			return err
+		}
+		order := signal.Orders[0]
+		fees := order.EntryFee.Add(order.ExitFee)
+
+		before := account.Amount
+		account.AvailableOrders++
+		account.Amount = account.Amount.Add(order.InvestedAmount).Add(order.Profit)
+		account.UpdatedAt = time.Now()
+		if err := a.Repository.UpdateAccount(ctx, account); err != nil {
+			return err
+		}
+		entries := []entities.LedgerEntry{
+			movement(signal, entities.OrderRelease, order.InvestedAmount, "Released from "+signal.Symbol),
+			movement(signal, entities.RealizedPnL, order.Profit.Add(fees).Add(order.FundingFee), "Realized on "+signal.Symbol),
+			movement(signal, entities.Fee, fees.Neg(), "Fees on "+signal.Symbol),
+		}
+		if !order.FundingFee.IsZero() {
+			entries = append(entries, movement(signal, entities.Funding, order.FundingFee.Neg(), "Funding on "+signal.Symbol))
+		}
+		return a.record(ctx, accountID, before, entries...)
+	})
 }
 
-func (a *AccountUseCase) GetDisponibleAmout() (float32, error) {
-	account, err := a.Repository.GetAccountByID(1)
+// ReleaseOrder gives back the amount and the slot reserved by a signal
+// whose entry order was never filled.
+func (a *AccountUseCase) ReleaseOrder(ctx context.Context, accountID int64, signal entities.Signal) error {
+	return a.Transactions.Transaction(ctx, func(ctx context.Context) error {
+		account, err := a.Repository.Lock(ctx, accountID)
+		if err != nil {
+			return err
+		}
+		order := signal.Orders[0]
+
+		before := account.Amount
+		account.AvailableOrders++
+		account.Amount = account.Amount.Add(order.InvestedAmount)
+		account.UpdatedAt = time.Now()
+		if err := a.Repository.UpdateAccount(ctx, account); err != nil {
+			return err
+		}
+		return a.record(ctx, accountID, before, movement(signal, entities.OrderRelease, order.InvestedAmount, "Released from "+signal.Symbol+", order not filled"))
+	})
+}
+
+// GetDisponibleAmout returns the amount of one order slot. Within a
+// transaction the account stays locked until it ends.
+func (a *AccountUseCase) GetDisponibleAmout(ctx context.Context, accountID int64) (decimal.Decimal, error) {
+	account, err := a.Repository.Lock(ctx, accountID)
 	if err != nil {
-		return 0, err
+		return decimal.Zero, err
+	}
+	if account.AvailableOrders <= 0 {
+		return decimal.Zero, nil
 	}
-	return account.Amount / float32(account.AvailableOrders), nil
+	return account.Amount.Div(decimal.NewFromInt(account.AvailableOrders)), nil
 }
 
-func (a *AccountUseCase) CanOpenOrder() (bool, error) {
-	account, err := a.Repository.GetAccountByID(1)
+// CanOpenOrder tells whether the account has an order slot left. Within a
+// transaction the account stays locked until it ends.
+func (a *AccountUseCase) CanOpenOrder(ctx context.Context, accountID int64) (bool, error) {
+	account, err := a.Repository.Lock(ctx, accountID)
 	if err != nil {
 		return false, err
 	}
 	return account.AvailableOrders > 0, nil
 }
 
-func (a *AccountUseCase) GetAccount() (entities.Account, error) {
-	account, err := a.Repository.GetAccountByID(1)
+func (a *AccountUseCase) GetAccount(id int64) (entities.Account, error) {
+	account, err := a.Repository.GetAccountByID(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return entities.Account{}, customerror.New(http.StatusNotFound, "Account not found")
+	}
 	if err != nil {
 		return entities.Account{}, err
 	}
 	return account, nil
 }
+
+func (a *AccountUseCase) GetAll() ([]entities.Account, error) {
+	return a.Repository.GetAll()
+}
+
+// GetLedger returns the entries of the account created between from and to,
+// zero times leave that side open, and reconciles the account with them.
+func (a *AccountUseCase) GetLedger(id int64, from time.Time, to time.Time) (Ledger, error) {
+	account, err := a.GetAccount(id)
+	if err != nil {
+		return Ledger{}, err
+	}
+	if !from.IsZero() && !to.IsZero() && to.Before(from) {
+		return Ledger{}, customerror.New(http.StatusBadRequest, "From must be before to")
+	}
+
+	entries, err := a.Ledger.List(id, from, to)
+	if err != nil {
+		return Ledger{}, err
+	}
+	balance, _, err := a.Ledger.Balance(context.Background(), id)
+	if err != nil {
+		return Ledger{}, err
+	}
+
+	return Ledger{
+		AccountID:  id,
+		Amount:     account.Amount,
+		Balance:    balance,
+		Difference: account.Amount.Sub(balance),
+		Entries:    entries,
+	}, nil
+}
+
+// record appends the entries to the ledger of the account, with the balance
+// after each of them. Accounts funded before the ledger existed get their
+// balance booked as an opening deposit first.
+func (a *AccountUseCase) record(ctx context.Context, accountID int64, before decimal.Decimal, entries ...entities.LedgerEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	_, ok, err := a.Ledger.Balance(ctx, accountID)
+	if err != nil {
+		return err
+	}
+	if !ok && !before.IsZero() {
+		entries = append(adjustment(before, "Opening balance"), entries...)
+		before = decimal.Zero
+	}
+
+	now := time.Now()
+	balance := before
+	for i := range entries {
+		balance = balance.Add(entries[i].Amount)
+		entries[i].AccountID = accountID
+		entries[i].Balance = balance
+		entries[i].CreatedAt = now
+	}
+	return a.Ledger.Append(ctx, entries...)
+}
+
+// adjustment books a change of balance made by hand, as a deposit or a
+// withdrawal depending on its sign.
+func adjustment(amount decimal.Decimal, description string) []entities.LedgerEntry {
+	switch {
+	case amount.IsPositive():
+		return []entities.LedgerEntry{{Type: entities.Deposit, Amount: amount, Description: description}}
+	case amount.IsNegative():
+		return []entities.LedgerEntry{{Type: entities.Withdrawal, Amount: amount, Description: description}}
+	default:
+		return nil
+	}
+}
+
+func movement(signal entities.Signal, kind entities.LedgerEntryType, amount decimal.Decimal, description string) entities.LedgerEntry {
+	signalID := signal.ID
+	orderID := signal.Orders[0].ID
+	return entities.LedgerEntry{
+		Type:        kind,
+		Amount:      amount,
+		SignalID:    &signalID,
+		OrderID:     &orderID,
+		Description: description,
+	}
+}
+
+// validate checks the fields of the account and that its fee model is one
+// of the schedule, an empty model charges the default one.
+func (a *AccountUseCase) validate(account entities.Account) error {
+	if err := validateAccount(account); err != nil {
+		return err
+	}
+	if _, ok := a.Fees.Model(account.FeeModel); !ok {
+		return customerror.New(http.StatusBadRequest, fmt.Sprintf("Unknown fee model %s", account.FeeModel))
+	}
+	return nil
+}
+
+func validateAccount(account entities.Account) error {
+	if account.Name == "" {
+		return customerror.New(http.StatusBadRequest, "Account has to have a name")
+	}
+	if account.Currency == "" {
+		return customerror.New(http.StatusBadRequest, "Currency must be filled")
+	}
+	if account.Amount.IsNegative() {
+		return customerror.New(http.StatusBadRequest, "Amount can't be negative")
+	}
+	if account.AvailableOrders < 0 {
+		return customerror.New(http.StatusBadRequest, "Available orders can't be negative")
+	}
+	return nil
+}
@@ -1,108 +1,184 @@
 package usecase_test
 
 import (
+	"context"
+	"errors"
 	"go-trade-bot/app/entities"
 	usecase "go-trade-bot/app/usecase/account"
 	"go-trade-bot/app/usecase/account/mocks"
+	"go-trade-bot/internal/configuration"
+	"go-trade-bot/internal/customerror"
+	"go-trade-bot/internal/fees"
+	"net/http"
 	"testing"
+	"time"
 
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
 )
 
 func TestAccountUseCase_CreateAccount(t *testing.T) {
 	account := entities.Account{
-		ID:              1,
-		Amount:          1000.0,
+		Name:            "paper",
+		Amount:          d(1000),
 		AvailableOrders: 10,
 		Currency:        "USD",
 	}
+	created := account
+	created.ID = 2
 	repo := new(mocks.AccountRepository)
-	repo.On("Create", account).Return(nil)
+	ledger := new(mocks.LedgerRepository)
+	repo.On("Create", mock.Anything, mock.MatchedBy(func(a entities.Account) bool {
+		return a.Name == "paper" && a.Amount.Equal(d(1000)) && !a.CreatedAt.IsZero() && !a.UpdatedAt.IsZero()
+	})).Return(created, nil)
+	ledger.On("Balance", mock.Anything, int64(2)).Return(d(0), false, nil)
+	ledger.On("Append", mock.Anything, mock.MatchedBy(func(e entities.LedgerEntry) bool {
+		return e.AccountID == 2 && e.Type == entities.Deposit && e.Amount.Equal(d(1000)) && e.Balance.Equal(d(1000))
+	})).Return(nil)
 
-	usecase := usecase.NewAccountUseCase(repo)
-	err := usecase.CreateAccount(account)
+	usecase := usecase.NewAccountUseCase(repo, ledger, transactor(), schedule())
+	result, err := usecase.CreateAccount(account)
 
 	assert.NoError(t, err)
+	assert.Equal(t, int64(2), result.ID)
 	repo.AssertExpectations(t)
+	ledger.AssertExpectations(t)
 }
 func TestAccountUseCase_DeductOrder(t *testing.T) {
 	account := entities.Account{
 		ID:              1,
-		Amount:          1000.0,
+		Amount:          d(1000),
 		AvailableOrders: 10,
 		Currency:        "USD",
 	}
 
 	deducted := entities.Account{
 		ID:              1,
-		Amount:          900.0,
+		Amount:          d(900),
 		AvailableOrders: 9,
 		Currency:        "USD",
 	}
 	repo := new(mocks.AccountRepository)
-	repo.On("GetAccountByID", int64(1)).Return(account, nil)
-	repo.On("UpdateAccount", deducted).Return(nil)
+	ledger := new(mocks.LedgerRepository)
+	repo.On("Lock", mock.Anything, int64(1)).Return(account, nil)
+	repo.On("UpdateAccount", mock.Anything, mock.MatchedBy(func(a entities.Account) bool {
+		a.UpdatedAt = deducted.UpdatedAt
+		return a.AvailableOrders == deducted.AvailableOrders && a.Amount.Equal(deducted.Amount)
+	})).Return(nil)
+	ledger.On("Balance", mock.Anything, int64(1)).Return(d(1000), true, nil)
+	ledger.On("Append", mock.Anything, mock.MatchedBy(func(e entities.LedgerEntry) bool {
+		return e.Type == entities.OrderReserve && e.Amount.Equal(d(-100)) && e.Balance.Equal(d(900)) && *e.SignalID == 3 && *e.OrderID == 4
+	})).Return(nil)
+
+	usecase := usecase.NewAccountUseCase(repo, ledger, transactor(), schedule())
+	err := usecase.DeductOrder(context.Background(), 1, signal(100, 0, 0))
+
+	assert.NoError(t, err)
+	repo.AssertExpectations(t)
+	ledger.AssertExpectations(t)
+}
+func TestAccountUseCase_ReleaseOrder(t *testing.T) {
+	account := entities.Account{ID: 1, Amount: d(900), AvailableOrders: 9, Currency: "USD"}
 
-	usecase := usecase.NewAccountUseCase(repo)
-	err := usecase.DeductOrder(100.0)
+	repo := new(mocks.AccountRepository)
+	ledger := new(mocks.LedgerRepository)
+	repo.On("Lock", mock.Anything, int64(1)).Return(account, nil)
+	repo.On("UpdateAccount", mock.Anything, mock.MatchedBy(func(a entities.Account) bool {
+		return a.AvailableOrders == 10 && a.Amount.Equal(d(1000))
+	})).Return(nil)
+	ledger.On("Balance", mock.Anything, int64(1)).Return(d(900), true, nil)
+	ledger.On("Append", mock.Anything, mock.MatchedBy(func(e entities.LedgerEntry) bool {
+		return e.Type == entities.OrderRelease && e.Amount.Equal(d(100)) && e.Balance.Equal(d(1000))
+	})).Return(nil)
+
+	usecase := usecase.NewAccountUseCase(repo, ledger, transactor(), schedule())
+	err := usecase.ReleaseOrder(context.Background(), 1, signal(100, 0, 0))
 
 	assert.NoError(t, err)
 	repo.AssertExpectations(t)
+	ledger.AssertExpectations(t)
 }
 func TestAccountUseCase_AddOrder(t *testing.T) {
 	account := entities.Account{
 		ID:              1,
-		Amount:          900.0,
+		Amount:          d(900),
 		AvailableOrders: 9,
 		Currency:        "USD",
 	}
 
 	added := entities.Account{
 		ID:              1,
-		Amount:          1000.0,
+		Amount:          d(1000),
 		AvailableOrders: 10,
 		Currency:        "USD",
 	}
 
 	repo := new(mocks.AccountRepository)
-	repo.On("GetAccountByID", int64(1)).Return(account, nil)
-	repo.On("UpdateAccount", added).Return(nil)
+	ledger := new(mocks.LedgerRepository)
+	repo.On("Lock", mock.Anything, int64(1)).Return(account, nil)
+	repo.On("UpdateAccount", mock.Anything, mock.MatchedBy(func(a entities.Account) bool {
+		a.UpdatedAt = added.UpdatedAt
+		return a.AvailableOrders == added.AvailableOrders && a.Amount.Equal(added.Amount)
+	})).Return(nil)
+	ledger.On("Balance", mock.Anything, int64(1)).Return(d(900), true, nil)
+	ledger.On("Append", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
 
-	usecase := usecase.NewAccountUseCase(repo)
-	err := usecase.AddOrder(100.0)
+	usecase := usecase.NewAccountUseCase(repo, ledger, transactor(), schedule())
+	err := usecase.AddOrder(context.Background(), 1, signal(100, 0, 0))
 
 	assert.NoError(t, err)
 	repo.AssertExpectations(t)
+	ledger.AssertExpectations(t)
+}
+
+func TestAccountUseCase_AddOrder_Exact(t *testing.T) {
+	repo := new(mocks.AccountRepository)
+	ledger := new(mocks.LedgerRepository)
+	repo.On("Lock", mock.Anything, int64(1)).Return(entities.Account{ID: 1, Amount: d(0.1), AvailableOrders: 9}, nil)
+	repo.On("UpdateAccount", mock.Anything, mock.Anything).Return(nil)
+	ledger.On("Balance", mock.Anything, int64(1)).Return(d(0.1), true, nil)
+	ledger.On("Append", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	usecase := usecase.NewAccountUseCase(repo, ledger, transactor(), schedule())
+	err := usecase.AddOrder(context.Background(), 1, signal(0.2, 0, 0))
+
+	assert.NoError(t, err)
+	updated := repo.Calls[1].Arguments.Get(1).(entities.Account)
+	assert.Equal(t, "0.3", updated.Amount.String())
 }
 func TestAccountUseCase_GetDisponibleAmout(t *testing.T) {
 	account := entities.Account{
 		ID:              1,
-		Amount:          1000.0,
+		Amount:          d(1000),
 		AvailableOrders: 10,
 		Currency:        "USD",
 	}
 	repo := new(mocks.AccountRepository)
-	repo.On("GetAccountByID", int64(1)).Return(account, nil)
+	ledger := new(mocks.LedgerRepository)
+	repo.On("Lock", mock.Anything, int64(1)).Return(account, nil)
 
-	usecase := usecase.NewAccountUseCase(repo)
-	amount, err := usecase.GetDisponibleAmout()
+	usecase := usecase.NewAccountUseCase(repo, ledger, transactor(), schedule())
+	amount, err := usecase.GetDisponibleAmout(context.Background(), 1)
 
 	assert.NoError(t, err)
-	assert.Equal(t, float32(100.0), amount)
+	assert.Equal(t, "100", amount.String())
 	repo.AssertExpectations(t)
 }
 func TestAccountUseCase_CanOpenOrder(t *testing.T) {
 	account := entities.Account{
 		ID:              1,
-		Amount:          1000.0,
+		Amount:          d(1000),
 		AvailableOrders: 10,
 		Currency:        "USD",
 	}
 	repo := new(mocks.AccountRepository)
-	repo.On("GetAccountByID", int64(1)).Return(account, nil)
+	ledger := new(mocks.LedgerRepository)
+	repo.On("Lock", mock.Anything, int64(1)).Return(account, nil)
 
-	usecase := usecase.NewAccountUseCase(repo)
-	canOpen, err := usecase.CanOpenOrder()
+	usecase := usecase.NewAccountUseCase(repo, ledger, transactor(), schedule())
+	canOpen, err := usecase.CanOpenOrder(context.Background(), 1)
 
 	assert.NoError(t, err)
 	assert.True(t, canOpen)
@@ -111,15 +187,16 @@ func TestAccountUseCase_CanOpenOrder(t *testing.T) {
 func TestAccountUseCase_CanOpenOrder_NoAvailableOrders(t *testing.T) {
 	account := entities.Account{
 		ID:              1,
-		Amount:          1000.0,
+		Amount:          d(1000),
 		AvailableOrders: 0,
 		Currency:        "USD",
 	}
 	repo := new(mocks.AccountRepository)
-	repo.On("GetAccountByID", int64(1)).Return(account, nil)
+	ledger := new(mocks.LedgerRepository)
+	repo.On("Lock", mock.Anything, int64(1)).Return(account, nil)
 
-	usecase := usecase.NewAccountUseCase(repo)
-	canOpen, err := usecase.CanOpenOrder()
+	usecase := usecase.NewAccountUseCase(repo, ledger, transactor(), schedule())
+	canOpen, err := usecase.CanOpenOrder(context.Background(), 1)
 
 	assert.NoError(t, err)
 	assert.False(t, canOpen)
@@ -129,17 +206,315 @@ func TestAccountUseCase_CanOpenOrder_NoAvailableOrders(t *testing.T) {
 func TestAccountUseCase_GetAccount(t *testing.T) {
 	account := entities.Account{
 		ID:              1,
-		Amount:          1000.0,
+		Amount:          d(1000),
 		AvailableOrders: 10,
 		Currency:        "USD",
 	}
 	repo := new(mocks.AccountRepository)
+	ledger := new(mocks.LedgerRepository)
 	repo.On("GetAccountByID", int64(1)).Return(account, nil)
 
-	usecase := usecase.NewAccountUseCase(repo)
-	acc, err := usecase.GetAccount()
+	usecase := usecase.NewAccountUseCase(repo, ledger, transactor(), schedule())
+	acc, err := usecase.GetAccount(1)
 
 	assert.NoError(t, err)
 	assert.Equal(t, account, acc)
 	repo.AssertExpectations(t)
 }
+
+func TestAccountUseCase_CreateAccount_Invalid(t *testing.T) {
+	usecase := usecase.NewAccountUseCase(new(mocks.AccountRepository), new(mocks.LedgerRepository), transactor(), schedule())
+
+	_, err := usecase.CreateAccount(entities.Account{Currency: "USD"})
+	assert.Equal(t, customerror.New(http.StatusBadRequest, "Account has to have a name"), err)
+}
+
+func TestAccountUseCase_CreateAccount_UnknownFeeModel(t *testing.T) {
+	repo := new(mocks.AccountRepository)
+	usecase := usecase.NewAccountUseCase(repo, new(mocks.LedgerRepository), transactor(), schedule())
+
+	_, err := usecase.CreateAccount(entities.Account{Name: "paper", Currency: "USD", FeeModel: "kraken"})
+
+	assert.Equal(t, customerror.New(http.StatusBadRequest, "Unknown fee model kraken"), err)
+	repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestAccountUseCase_UpdateAccount_UnknownFeeModel(t *testing.T) {
+	repo := new(mocks.AccountRepository)
+	usecase := usecase.NewAccountUseCase(repo, new(mocks.LedgerRepository), transactor(), schedule())
+
+	_, err := usecase.UpdateAccount(2, entities.Account{Name: "paper", Currency: "USD", FeeModel: "kraken"})
+
+	assert.Equal(t, customerror.New(http.StatusBadRequest, "Unknown fee model kraken"), err)
+	repo.AssertNotCalled(t, "Lock", mock.Anything, mock.Anything)
+}
+
+func TestAccountUseCase_GetAccount_NotFound(t *testing.T) {
+	repo := new(mocks.AccountRepository)
+	ledger := new(mocks.LedgerRepository)
+	repo.On("GetAccountByID", int64(7)).Return(entities.Account{}, gorm.ErrRecordNotFound)
+
+	usecase := usecase.NewAccountUseCase(repo, ledger, transactor(), schedule())
+	_, err := usecase.GetAccount(7)
+
+	assert.Equal(t, customerror.New(http.StatusNotFound, "Account not found"), err)
+}
+
+func TestAccountUseCase_GetAccount_DatabaseError(t *testing.T) {
+	repo := new(mocks.AccountRepository)
+	repo.On("GetAccountByID", int64(7)).Return(entities.Account{}, errors.New("connection refused"))
+
+	usecase := usecase.NewAccountUseCase(repo, new(mocks.LedgerRepository), transactor(), schedule())
+	_, err := usecase.GetAccount(7)
+
+	assert.EqualError(t, err, "connection refused")
+}
+
+func TestAccountUseCase_UpdateAccount(t *testing.T) {
+	account := entities.Account{
+		ID:              2,
+		Name:            "paper",
+		Amount:          d(1000),
+		AvailableOrders: 10,
+		Currency:        "USD",
+	}
+	repo := new(mocks.AccountRepository)
+	ledger := new(mocks.LedgerRepository)
+	repo.On("GetAccountByID", int64(2)).Return(account, nil)
+	repo.On("Lock", mock.Anything, int64(2)).Return(account, nil)
+	repo.On("CountActiveSignals", mock.Anything, int64(2)).Return(int64(0), nil)
+	repo.On("UpdateAccount", mock.Anything, mock.MatchedBy(func(a entities.Account) bool {
+		return a.ID == 2 && a.Name == "live" && a.Amount.Equal(d(500)) && a.AvailableOrders == 5
+	})).Return(nil)
+	ledger.On("Balance", mock.Anything, int64(2)).Return(d(1000), true, nil)
+	ledger.On("Append", mock.Anything, mock.MatchedBy(func(e entities.LedgerEntry) bool {
+		return e.Type == entities.Withdrawal && e.Amount.Equal(d(-500)) && e.Balance.Equal(d(500))
+	})).Return(nil)
+
+	usecase := usecase.NewAccountUseCase(repo, ledger, transactor(), schedule())
+	result, err := usecase.UpdateAccount(2, entities.Account{Name: "live", Amount: d(500), AvailableOrders: 5, Currency: "USD"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "live", result.Name)
+	repo.AssertExpectations(t)
+}
+
+func TestAccountUseCase_UpdateAccount_ActiveSignals(t *testing.T) {
+	account := entities.Account{ID: 2, Name: "paper", Amount: d(900), AvailableOrders: 9, Currency: "USD"}
+
+	t.Run("should refuse balance changes while signals hold reservations", func(t *testing.T) {
+		repo := new(mocks.AccountRepository)
+		repo.On("GetAccountByID", int64(2)).Return(account, nil)
+		repo.On("Lock", mock.Anything, int64(2)).Return(account, nil)
+		repo.On("CountActiveSignals", mock.Anything, int64(2)).Return(int64(1), nil)
+
+		usecase := usecase.NewAccountUseCase(repo, new(mocks.LedgerRepository), transactor(), schedule())
+		_, err := usecase.UpdateAccount(2, entities.Account{Name: "paper", Amount: d(1000), AvailableOrders: 10, Currency: "USD"})
+
+		assert.Equal(t, customerror.New(http.StatusConflict, "Account has active signals, its balance and order slots can't change until they close"), err)
+		repo.AssertNotCalled(t, "UpdateAccount", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should rename the account while signals are active", func(t *testing.T) {
+		repo := new(mocks.AccountRepository)
+		repo.On("GetAccountByID", int64(2)).Return(account, nil)
+		repo.On("Lock", mock.Anything, int64(2)).Return(account, nil)
+		repo.On("UpdateAccount", mock.Anything, mock.MatchedBy(func(a entities.Account) bool {
+			return a.Name == "live" && a.Amount.Equal(d(900)) && a.AvailableOrders == 9
+		})).Return(nil)
+
+		usecase := usecase.NewAccountUseCase(repo, new(mocks.LedgerRepository), transactor(), schedule())
+		_, err := usecase.UpdateAccount(2, entities.Account{Name: "live", Amount: d(900), AvailableOrders: 9, Currency: "USD"})
+
+		assert.NoError(t, err)
+		repo.AssertNotCalled(t, "CountActiveSignals", mock.Anything, mock.Anything)
+		repo.AssertExpectations(t)
+	})
+}
+
+func TestAccountUseCase_DeleteAccount(t *testing.T) {
+	t.Run("should delete accounts without strategies", func(t *testing.T) {
+		repo := new(mocks.AccountRepository)
+		ledger := new(mocks.LedgerRepository)
+		repo.On("GetAccountByID", int64(2)).Return(entities.Account{ID: 2}, nil)
+		repo.On("CountStrategies", int64(2)).Return(int64(0), nil)
+		repo.On("Delete", int64(2)).Return(nil)
+
+		usecase := usecase.NewAccountUseCase(repo, ledger, transactor(), schedule())
+		assert.NoError(t, usecase.DeleteAccount(2))
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("should refuse accounts with strategies bound", func(t *testing.T) {
+		repo := new(mocks.AccountRepository)
+		ledger := new(mocks.LedgerRepository)
+		repo.On("GetAccountByID", int64(2)).Return(entities.Account{ID: 2}, nil)
+		repo.On("CountStrategies", int64(2)).Return(int64(1), nil)
+
+		usecase := usecase.NewAccountUseCase(repo, ledger, transactor(), schedule())
+		err := usecase.DeleteAccount(2)
+
+		assert.Equal(t, customerror.New(http.StatusConflict, "Account has strategies bound to it"), err)
+		repo.AssertNotCalled(t, "Delete", int64(2))
+	})
+}
+
+func TestAccountUseCase_AddOrder_Ledger(t *testing.T) {
+	repo := new(mocks.AccountRepository)
+	ledger := new(mocks.LedgerRepository)
+	repo.On("Lock", mock.Anything, int64(1)).Return(entities.Account{ID: 1, Amount: d(900), AvailableOrders: 9}, nil)
+	repo.On("UpdateAccount", mock.Anything, mock.Anything).Return(nil)
+	ledger.On("Balance", mock.Anything, int64(1)).Return(d(900), true, nil)
+
+	var entries []entities.LedgerEntry
+	ledger.On("Append", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		for _, arg := range args[1:] {
+			entries = append(entries, arg.(entities.LedgerEntry))
+		}
+	}).Return(nil)
+
+	usecase := usecase.NewAccountUseCase(repo, ledger, transactor(), schedule())
+	err := usecase.AddOrder(context.Background(), 1, signal(100, 8, 2))
+
+	assert.NoError(t, err)
+	assert.Len(t, entries, 3)
+	assert.Equal(t, entities.OrderRelease, entries[0].Type)
+	assert.Equal(t, "100", entries[0].Amount.String())
+	assert.Equal(t, entities.RealizedPnL, entries[1].Type)
+	assert.Equal(t, "10", entries[1].Amount.String())
+	assert.Equal(t, entities.Fee, entries[2].Type)
+	assert.Equal(t, "-2", entries[2].Amount.String())
+	assert.Equal(t, "1008", entries[2].Balance.String())
+}
+
+func TestAccountUseCase_AddOrder_Funding(t *testing.T) {
+	repo := new(mocks.AccountRepository)
+	ledger := new(mocks.LedgerRepository)
+	repo.On("Lock", mock.Anything, int64(1)).Return(entities.Account{ID: 1, Amount: d(900), AvailableOrders: 9}, nil)
+	repo.On("UpdateAccount", mock.Anything, mock.Anything).Return(nil)
+	ledger.On("Balance", mock.Anything, int64(1)).Return(d(900), true, nil)
+
+	var entries []entities.LedgerEntry
+	ledger.On("Append", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		for _, arg := range args[1:] {
+			entries = append(entries, arg.(entities.LedgerEntry))
+		}
+	}).Return(nil)
+
+	closed := signal(100, 5, 2)
+	closed.Orders[0].FundingFee = d(3)
+	usecase := usecase.NewAccountUseCase(repo, ledger, transactor(), schedule())
+	err := usecase.AddOrder(context.Background(), 1, closed)
+
+	assert.NoError(t, err)
+	assert.Len(t, entries, 4)
+	assert.Equal(t, entities.RealizedPnL, entries[1].Type)
+	assert.Equal(t, "10", entries[1].Amount.String())
+	assert.Equal(t, entities.Funding, entries[3].Type)
+	assert.Equal(t, "-3", entries[3].Amount.String())
+	assert.Equal(t, "1005", entries[3].Balance.String())
+}
+
+func TestAccountUseCase_Record_OpeningBalance(t *testing.T) {
+	repo := new(mocks.AccountRepository)
+	ledger := new(mocks.LedgerRepository)
+	repo.On("Lock", mock.Anything, int64(1)).Return(entities.Account{ID: 1, Amount: d(1000), AvailableOrders: 10}, nil)
+	repo.On("UpdateAccount", mock.Anything, mock.Anything).Return(nil)
+	ledger.On("Balance", mock.Anything, int64(1)).Return(d(0), false, nil)
+	ledger.On("Append", mock.Anything,
+		mock.MatchedBy(func(e entities.LedgerEntry) bool {
+			return e.Type == entities.Deposit && e.Amount.Equal(d(1000)) && e.Description == "Opening balance"
+		}),
+		mock.MatchedBy(func(e entities.LedgerEntry) bool {
+			return e.Type == entities.OrderReserve && e.Balance.Equal(d(900))
+		}),
+	).Return(nil)
+
+	usecase := usecase.NewAccountUseCase(repo, ledger, transactor(), schedule())
+	assert.NoError(t, usecase.DeductOrder(context.Background(), 1, signal(100, 0, 0)))
+	ledger.AssertExpectations(t)
+}
+
+func TestAccountUseCase_GetLedger(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("should reconcile the account with its ledger", func(t *testing.T) {
+		repo := new(mocks.AccountRepository)
+		ledger := new(mocks.LedgerRepository)
+		entries := []entities.LedgerEntry{{ID: 1, AccountID: 1, Type: entities.Deposit, Amount: d(1000), Balance: d(1000)}}
+		repo.On("GetAccountByID", int64(1)).Return(entities.Account{ID: 1, Amount: d(1010)}, nil)
+		ledger.On("List", int64(1), from, to).Return(entries, nil)
+		ledger.On("Balance", mock.Anything, int64(1)).Return(d(1000), true, nil)
+
+		usecase := usecase.NewAccountUseCase(repo, ledger, transactor(), schedule())
+		result, err := usecase.GetLedger(1, from, to)
+
+		assert.NoError(t, err)
+		assert.Equal(t, entries, result.Entries)
+		assert.Equal(t, "1000", result.Balance.String())
+		assert.Equal(t, "10", result.Difference.String())
+	})
+
+	t.Run("should refuse inverted dates", func(t *testing.T) {
+		repo := new(mocks.AccountRepository)
+		repo.On("GetAccountByID", int64(1)).Return(entities.Account{ID: 1}, nil)
+
+		usecase := usecase.NewAccountUseCase(repo, new(mocks.LedgerRepository), transactor(), schedule())
+		_, err := usecase.GetLedger(1, to, from)
+
+		assert.Equal(t, customerror.New(http.StatusBadRequest, "From must be before to"), err)
+	})
+}
+
+func d(value float64) decimal.Decimal {
+	return decimal.NewFromFloat(value)
+}
+
+func signal(invested float64, profit float64, fees float64) entities.Signal {
+	return entities.Signal{
+		ID:     3,
+		Symbol: "BTCUSDT",
+		Orders: []entities.Order{{ID: 4, InvestedAmount: d(invested), Profit: d(profit), EntryFee: d(fees / 2), ExitFee: d(fees / 2)}},
+	}
+}
+
+func TestAccountUseCase_DeductOrder_Refused(t *testing.T) {
+	t.Run("should refuse accounts without order slots", func(t *testing.T) {
+		repo := new(mocks.AccountRepository)
+		ledger := new(mocks.LedgerRepository)
+		repo.On("Lock", mock.Anything, int64(1)).Return(entities.Account{ID: 1, Amount: d(1000), AvailableOrders: 0}, nil)
+
+		usecase := usecase.NewAccountUseCase(repo, ledger, transactor(), schedule())
+		err := usecase.DeductOrder(context.Background(), 1, signal(100, 0, 0))
+
+		assert.Equal(t, customerror.New(http.StatusConflict, "Account has no available orders"), err)
+		repo.AssertNotCalled(t, "UpdateAccount", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should refuse orders above the balance", func(t *testing.T) {
+		repo := new(mocks.AccountRepository)
+		ledger := new(mocks.LedgerRepository)
+		repo.On("Lock", mock.Anything, int64(1)).Return(entities.Account{ID: 1, Amount: d(50), AvailableOrders: 1}, nil)
+
+		usecase := usecase.NewAccountUseCase(repo, ledger, transactor(), schedule())
+		err := usecase.DeductOrder(context.Background(), 1, signal(100, 0, 0))
+
+		assert.Equal(t, customerror.New(http.StatusConflict, "Account balance is too low for the order"), err)
+		repo.AssertNotCalled(t, "UpdateAccount", mock.Anything, mock.Anything)
+		ledger.AssertNotCalled(t, "Append")
+	})
+}
+
+// transactor runs the functions it is given, as a transaction that commits.
+func transactor() *mocks.Transactor {
+	t := new(mocks.Transactor)
+	t.On("Transaction", mock.Anything, mock.Anything).Return(func(ctx context.Context, fn func(context.Context) error) error {
+		return fn(ctx)
+	})
+	return t
+}
+
+func schedule() fees.Schedule {
+	return fees.NewSchedule(&configuration.Configuration{})
+}
@@ -0,0 +1,57 @@
+// Code generated by mockery v2.53.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	backtest "go-trade-bot/app/services/backtest"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// Engine is an autogenerated mock type for the Engine type
+type Engine struct {
+	mock.Mock
+}
+
+// Run provides a mock function with given fields: ctx, cfg
+func (_m *Engine) Run(ctx context.Context, cfg backtest.Config) (backtest.Result, error) {
+	ret := _m.Called(ctx, cfg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Run")
+	}
+
+	var r0 backtest.Result
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, backtest.Config) (backtest.Result, error)); ok {
+		return rf(ctx, cfg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, backtest.Config) backtest.Result); ok {
+		r0 = rf(ctx, cfg)
+	} else {
+		r0 = ret.Get(0).(backtest.Result)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, backtest.Config) error); ok {
+		r1 = rf(ctx, cfg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewEngine creates a new instance of Engine. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewEngine(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Engine {
+	mock := &Engine{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
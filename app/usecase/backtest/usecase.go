@@ -0,0 +1,136 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"go-trade-bot/app/entities"
+	"go-trade-bot/app/services/algorithm"
+	"go-trade-bot/app/services/backtest"
+	"go-trade-bot/internal/broker"
+	"go-trade-bot/internal/customerror"
+	"net/http"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	maxCandles        = 10000
+	defaultAmount     = 1000
+	defaultOrderSlots = 10
+)
+
+type History interface {
+	ListKline(ctx context.Context, symbol string, interval string, limit int) ([]broker.Candle, error)
+}
+
+type StrategyRepository interface {
+	GetByID(ctx context.Context, id uint) (entities.Strategy, error)
+}
+
+type Algorithms interface {
+	Get(name entities.Algorithm) (algorithm.Definition, bool)
+}
+
+type Engine interface {
+	Run(ctx context.Context, cfg backtest.Config) (backtest.Result, error)
+}
+
+type Parameters struct {
+	Candles         int
+	InitialAmount   decimal.Decimal
+	AvailableOrders int64
+}
+
+type BacktestUseCase struct {
+	Repository StrategyRepository
+	History    History
+	Engine     Engine
+	Algorithms Algorithms
+}
+
+func NewBacktestUseCase(r StrategyRepository, h History, e Engine, a Algorithms) BacktestUseCase {
+	return BacktestUseCase{
+		Repository: r,
+		History:    h,
+		Engine:     e,
+		Algorithms: a,
+	}
+}
+
+func (u BacktestUseCase) Run(ctx context.Context, strategyID uint, params Parameters) (backtest.Result, error) {
+	if params.Candles <= 0 || params.Candles > maxCandles {
+		return backtest.Result{}, customerror.New(http.StatusBadRequest, "Candles must be between 1 and 10000")
+	}
+	if params.InitialAmount.IsZero() {
+		params.InitialAmount = decimal.NewFromInt(defaultAmount)
+	}
+	if params.AvailableOrders == 0 {
+		params.AvailableOrders = defaultOrderSlots
+	}
+
+	strategy, err := u.Repository.GetByID(ctx, strategyID)
+	if err != nil {
+		return backtest.Result{}, customerror.New(http.StatusNotFound, "Strategy not found")
+	}
+
+	definition, ok := u.Algorithms.Get(strategy.Algorithm)
+	if !ok {
+		return backtest.Result{}, customerror.New(http.StatusBadRequest, "Invalid algorithm option")
+	}
+
+	series, err := u.loadHistory(ctx, strategy, definition, params.Candles)
+	if err != nil {
+		return backtest.Result{}, err
+	}
+
+	warmup := params.Candles / 2
+	if warmup > 100 {
+		warmup = 100
+	}
+
+	return u.Engine.Run(ctx, backtest.Config{
+		Strategy:        strategy,
+		Series:          series,
+		InitialAmount:   params.InitialAmount,
+		AvailableOrders: params.AvailableOrders,
+		Warmup:          warmup,
+	})
+}
+
+// loadHistory fetches the candles the algorithm of the strategy reads: its
+// own interval plus the extra intervals declared by the algorithm, long
+// enough to cover the whole backtest.
+func (u BacktestUseCase) loadHistory(ctx context.Context, strategy entities.Strategy, definition algorithm.Definition, candles int) ([]broker.RecordedSeries, error) {
+	interval, ok := broker.IntervalDuration(strategy.GetBrokerInterval())
+	if !ok {
+		return nil, fmt.Errorf("strategy %s reads unsupported interval %s", strategy.Name, strategy.GetBrokerInterval())
+	}
+	span := time.Duration(candles) * interval
+	intervals := map[string]int{
+		strategy.GetBrokerInterval(): candles,
+	}
+	for _, h := range definition.History {
+		step, ok := broker.IntervalDuration(h.Interval)
+		if !ok {
+			return nil, fmt.Errorf("algorithm %s reads unsupported interval %s", definition.Name, h.Interval)
+		}
+		intervals[h.Interval] = min(int(span/step)+h.Candles+1, maxCandles)
+	}
+
+	series := []broker.RecordedSeries{}
+	for _, symbol := range strategy.MonitoredSymbols {
+		for interval, limit := range intervals {
+			klines, err := u.History.ListKline(ctx, symbol, interval, limit)
+			if err != nil {
+				return nil, err
+			}
+			series = append(series, broker.RecordedSeries{
+				Symbol:   symbol,
+				Interval: interval,
+				Candles:  klines,
+			})
+		}
+	}
+	return series, nil
+}
@@ -0,0 +1,90 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"go-trade-bot/app/entities"
+	"go-trade-bot/app/services/algorithm"
+	"go-trade-bot/app/services/backtest"
+	usecase "go-trade-bot/app/usecase/backtest"
+	"go-trade-bot/app/usecase/backtest/mocks"
+	"go-trade-bot/internal/broker"
+	"go-trade-bot/internal/customerror"
+	"net/http"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newRegistry() *algorithm.Registry {
+	registry := algorithm.NewRegistry()
+	registry.Register(algorithm.Definition{
+		Name:    entities.Scalping,
+		Config:  struct{}{},
+		History: []algorithm.History{{Interval: "15m", Candles: 50}},
+		New:     func(strategy entities.Strategy, deps algorithm.Dependencies) algorithm.Processor { return nil },
+	})
+	return registry
+}
+
+func TestBacktestUseCase_Run(t *testing.T) {
+	strategy := entities.Strategy{
+		ID:               1,
+		Algorithm:        entities.Scalping,
+		MonitoredSymbols: []string{"BTCUSDT"},
+		StrategyConfiguration: entities.StrategyConfiguration{
+			Cycle: entities.FiveMinutes,
+		},
+	}
+
+	t.Run("should load history for every interval the algorithm reads", func(t *testing.T) {
+		repo := new(mocks.StrategyRepository)
+		history := new(mocks.History)
+		engine := new(mocks.Engine)
+		uc := usecase.NewBacktestUseCase(repo, history, engine, newRegistry())
+
+		repo.On("GetByID", mock.Anything, uint(1)).Return(strategy, nil).Once()
+		history.On("ListKline", mock.Anything, "BTCUSDT", "5m", 300).Return([]broker.Candle{}, nil).Once()
+		history.On("ListKline", mock.Anything, "BTCUSDT", "15m", 151).Return([]broker.Candle{}, nil).Once()
+		engine.On("Run", mock.Anything, mock.MatchedBy(func(cfg backtest.Config) bool {
+			return len(cfg.Series) == 2 && cfg.InitialAmount.Equal(decimal.NewFromInt(1000)) && cfg.AvailableOrders == 10 && cfg.Warmup == 100
+		})).Return(backtest.Result{Summary: backtest.Summary{Trades: 3}}, nil).Once()
+
+		result, err := uc.Run(context.Background(), 1, usecase.Parameters{Candles: 300})
+		assert.NoError(t, err)
+		assert.Equal(t, 3, result.Summary.Trades)
+
+		repo.AssertExpectations(t)
+		history.AssertExpectations(t)
+		engine.AssertExpectations(t)
+	})
+
+	t.Run("should reject invalid candle counts", func(t *testing.T) {
+		uc := usecase.NewBacktestUseCase(new(mocks.StrategyRepository), new(mocks.History), new(mocks.Engine), newRegistry())
+
+		_, err := uc.Run(context.Background(), 1, usecase.Parameters{Candles: 50000})
+		assert.Equal(t, customerror.New(http.StatusBadRequest, "Candles must be between 1 and 10000"), err)
+	})
+
+	t.Run("should return not found for unknown strategies", func(t *testing.T) {
+		repo := new(mocks.StrategyRepository)
+		uc := usecase.NewBacktestUseCase(repo, new(mocks.History), new(mocks.Engine), newRegistry())
+		repo.On("GetByID", mock.Anything, uint(2)).Return(entities.Strategy{}, errors.New("record not found")).Once()
+
+		_, err := uc.Run(context.Background(), 2, usecase.Parameters{Candles: 100})
+		assert.Equal(t, customerror.New(http.StatusNotFound, "Strategy not found"), err)
+	})
+
+	t.Run("should return history errors", func(t *testing.T) {
+		repo := new(mocks.StrategyRepository)
+		history := new(mocks.History)
+		uc := usecase.NewBacktestUseCase(repo, history, new(mocks.Engine), newRegistry())
+		repo.On("GetByID", mock.Anything, uint(1)).Return(strategy, nil).Once()
+		history.On("ListKline", mock.Anything, "BTCUSDT", mock.Anything, mock.Anything).Return(nil, errors.New("broker error"))
+
+		_, err := uc.Run(context.Background(), 1, usecase.Parameters{Candles: 100})
+		assert.EqualError(t, err, "broker error")
+	})
+}
@@ -0,0 +1,25 @@
+package usecase
+
+import (
+	"context"
+	"go-trade-bot/internal/broker"
+)
+
+// StoreBroker is the exchange broker with klines served from the candle
+// store, what processors use so every cycle does not download the whole
+// history again.
+type StoreBroker struct {
+	broker.Broker
+	Candles CandleUseCase
+}
+
+func NewStoreBroker(b broker.Broker, c CandleUseCase) StoreBroker {
+	return StoreBroker{
+		Broker:  b,
+		Candles: c,
+	}
+}
+
+func (s StoreBroker) ListKline(ctx context.Context, symbol string, interval string, limit int) ([]broker.Candle, error) {
+	return s.Candles.ListKline(ctx, symbol, interval, limit)
+}
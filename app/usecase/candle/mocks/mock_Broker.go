@@ -0,0 +1,90 @@
+// Code generated by mockery v2.53.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	broker "go-trade-bot/internal/broker"
+	time "time"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// Broker is an autogenerated mock type for the Broker type
+type Broker struct {
+	mock.Mock
+}
+
+// ListKline provides a mock function with given fields: ctx, symbol, interval, limit
+func (_m *Broker) ListKline(ctx context.Context, symbol string, interval string, limit int) ([]broker.Candle, error) {
+	ret := _m.Called(ctx, symbol, interval, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListKline")
+	}
+
+	var r0 []broker.Candle
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int) ([]broker.Candle, error)); ok {
+		return rf(ctx, symbol, interval, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int) []broker.Candle); ok {
+		r0 = rf(ctx, symbol, interval, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]broker.Candle)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, int) error); ok {
+		r1 = rf(ctx, symbol, interval, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListKlineRange provides a mock function with given fields: ctx, symbol, interval, start, limit
+func (_m *Broker) ListKlineRange(ctx context.Context, symbol string, interval string, start time.Time, limit int) ([]broker.Candle, error) {
+	ret := _m.Called(ctx, symbol, interval, start, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListKlineRange")
+	}
+
+	var r0 []broker.Candle
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Time, int) ([]broker.Candle, error)); ok {
+		return rf(ctx, symbol, interval, start, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Time, int) []broker.Candle); ok {
+		r0 = rf(ctx, symbol, interval, start, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]broker.Candle)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, time.Time, int) error); ok {
+		r1 = rf(ctx, symbol, interval, start, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewBroker creates a new instance of Broker. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewBroker(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Broker {
+	mock := &Broker{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,143 @@
+// Code generated by mockery v2.53.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	entities "go-trade-bot/app/entities"
+	time "time"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// CandleRepository is an autogenerated mock type for the CandleRepository type
+type CandleRepository struct {
+	mock.Mock
+}
+
+// GetLast provides a mock function with given fields: ctx, symbol, interval, limit
+func (_m *CandleRepository) GetLast(ctx context.Context, symbol string, interval string, limit int) ([]entities.Candle, error) {
+	ret := _m.Called(ctx, symbol, interval, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLast")
+	}
+
+	var r0 []entities.Candle
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int) ([]entities.Candle, error)); ok {
+		return rf(ctx, symbol, interval, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int) []entities.Candle); ok {
+		r0 = rf(ctx, symbol, interval, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entities.Candle)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, int) error); ok {
+		r1 = rf(ctx, symbol, interval, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetLatest provides a mock function with given fields: ctx, symbol, interval
+func (_m *CandleRepository) GetLatest(ctx context.Context, symbol string, interval string) (entities.Candle, bool, error) {
+	ret := _m.Called(ctx, symbol, interval)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLatest")
+	}
+
+	var r0 entities.Candle
+	var r1 bool
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (entities.Candle, bool, error)); ok {
+		return rf(ctx, symbol, interval)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) entities.Candle); ok {
+		r0 = rf(ctx, symbol, interval)
+	} else {
+		r0 = ret.Get(0).(entities.Candle)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) bool); ok {
+		r1 = rf(ctx, symbol, interval)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, string) error); ok {
+		r2 = rf(ctx, symbol, interval)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetRange provides a mock function with given fields: ctx, symbol, interval, start, end
+func (_m *CandleRepository) GetRange(ctx context.Context, symbol string, interval string, start time.Time, end time.Time) ([]entities.Candle, error) {
+	ret := _m.Called(ctx, symbol, interval, start, end)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRange")
+	}
+
+	var r0 []entities.Candle
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Time, time.Time) ([]entities.Candle, error)); ok {
+		return rf(ctx, symbol, interval, start, end)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Time, time.Time) []entities.Candle); ok {
+		r0 = rf(ctx, symbol, interval, start, end)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entities.Candle)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, time.Time, time.Time) error); ok {
+		r1 = rf(ctx, symbol, interval, start, end)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Save provides a mock function with given fields: ctx, candles
+func (_m *CandleRepository) Save(ctx context.Context, candles []entities.Candle) error {
+	ret := _m.Called(ctx, candles)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Save")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []entities.Candle) error); ok {
+		r0 = rf(ctx, candles)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewCandleRepository creates a new instance of CandleRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewCandleRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *CandleRepository {
+	mock := &CandleRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,46 @@
+// Code generated by mockery v2.53.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	time "time"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// CandleWorker is an autogenerated mock type for the CandleWorker type
+type CandleWorker struct {
+	mock.Mock
+}
+
+// EnqueueBackfill provides a mock function with given fields: symbol, interval, from
+func (_m *CandleWorker) EnqueueBackfill(symbol string, interval string, from time.Time) error {
+	ret := _m.Called(symbol, interval, from)
+
+	if len(ret) == 0 {
+		panic("no return value specified for EnqueueBackfill")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, time.Time) error); ok {
+		r0 = rf(symbol, interval, from)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewCandleWorker creates a new instance of CandleWorker. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewCandleWorker(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *CandleWorker {
+	mock := &CandleWorker{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,278 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"go-trade-bot/app/entities"
+	"go-trade-bot/internal/broker"
+	"go-trade-bot/internal/customerror"
+	"net/http"
+	"time"
+)
+
+// pageSize is the most klines the exchange returns in a single request.
+const pageSize = 1000
+
+type CandleRepository interface {
+	Save(ctx context.Context, candles []entities.Candle) error
+	GetLast(ctx context.Context, symbol string, interval string, limit int) ([]entities.Candle, error)
+	GetRange(ctx context.Context, symbol string, interval string, start time.Time, end time.Time) ([]entities.Candle, error)
+	GetLatest(ctx context.Context, symbol string, interval string) (entities.Candle, bool, error)
+}
+
+type Broker interface {
+	ListKline(ctx context.Context, symbol string, interval string, limit int) ([]broker.Candle, error)
+	ListKlineRange(ctx context.Context, symbol string, interval string, start time.Time, limit int) ([]broker.Candle, error)
+}
+
+type CandleWorker interface {
+	EnqueueBackfill(symbol string, interval string, from time.Time) error
+}
+
+// Gap is a run of missing candles, Start and End are the open times of the
+// first and the last candle missing.
+type Gap struct {
+	Start time.Time
+	End   time.Time
+}
+
+type CandleUseCase struct {
+	Repository CandleRepository
+	Broker     Broker
+	Worker     CandleWorker
+	Now        func() time.Time
+}
+
+func NewCandleUseCase(r CandleRepository, b Broker, w CandleWorker) CandleUseCase {
+	return CandleUseCase{
+		Repository: r,
+		Broker:     b,
+		Worker:     w,
+		Now:        time.Now,
+	}
+}
+
+// ListKline returns the last limit candles of the symbol. Closed candles come
+// from the store, only the ones missing since the last stored candle and the
+// one still open are requested from the exchange, and the older ones when
+// the store holds fewer than limit.
+func (u CandleUseCase) ListKline(ctx context.Context, symbol string, interval string, limit int) ([]broker.Candle, error) {
+	step, err := intervalDuration(interval)
+	if err != nil {
+		return nil, err
+	}
+	now := u.Now()
+
+	latest, ok, err := u.Repository.GetLatest(ctx, symbol, interval)
+	if err != nil {
+		return nil, err
+	}
+
+	var fresh []broker.Candle
+	start := latest.OpenTime.Add(step)
+	whole := !ok || now.Sub(start) > time.Duration(limit)*step
+	if whole {
+		// Nothing useful stored, the last limit candles are all new.
+		fresh, err = u.Broker.ListKline(ctx, symbol, interval, min(limit, pageSize))
+	} else {
+		fresh, err = u.Broker.ListKlineRange(ctx, symbol, interval, start, pageSize)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	closed, open := splitClosed(fresh, now)
+	if err := u.Repository.Save(ctx, toEntities(symbol, interval, closed)); err != nil {
+		return nil, err
+	}
+
+	stored, err := u.Repository.GetLast(ctx, symbol, interval, limit)
+	if err != nil {
+		return nil, err
+	}
+	if len(stored)+len(open) < limit && (!whole || limit > pageSize) {
+		// The store only holds the tail of the window, e.g. after a shorter
+		// read, the candles before it are downloaded once.
+		to := broker.IntervalStart(now, interval)
+		if _, err := u.fetch(ctx, symbol, interval, to.Add(-time.Duration(limit)*step), to); err != nil {
+			return nil, err
+		}
+		if stored, err = u.Repository.GetLast(ctx, symbol, interval, limit); err != nil {
+			return nil, err
+		}
+	}
+
+	candles := append(toCandles(stored), open...)
+	if len(candles) > limit {
+		candles = candles[len(candles)-limit:]
+	}
+	return candles, nil
+}
+
+// RequestBackfill schedules the download of the history of the symbol from
+// the given time, the work is done by the backfill task of the worker.
+func (u CandleUseCase) RequestBackfill(ctx context.Context, symbol string, interval string, from time.Time) error {
+	if symbol == "" {
+		return customerror.New(http.StatusBadRequest, "Symbol is required")
+	}
+	if _, err := intervalDuration(interval); err != nil {
+		return err
+	}
+	if from.IsZero() || !from.Before(u.Now()) {
+		return customerror.New(http.StatusBadRequest, "From must be a time in the past")
+	}
+	return u.Worker.EnqueueBackfill(symbol, interval, from)
+}
+
+// Backfill stores every closed candle of the symbol from the given time up to
+// now, paging through the exchange history. It returns how many were saved.
+func (u CandleUseCase) Backfill(ctx context.Context, symbol string, interval string, from time.Time) (int, error) {
+	if _, err := intervalDuration(interval); err != nil {
+		return 0, err
+	}
+	return u.fetch(ctx, symbol, interval, from, u.Now())
+}
+
+// DetectGaps compares the candles stored between start and end with the ones
+// the interval should produce and returns the missing runs.
+func (u CandleUseCase) DetectGaps(ctx context.Context, symbol string, interval string, start time.Time, end time.Time) ([]Gap, error) {
+	step, err := intervalDuration(interval)
+	if err != nil {
+		return nil, err
+	}
+	if now := u.Now(); end.After(now) {
+		end = now
+	}
+
+	stored, err := u.Repository.GetRange(ctx, symbol, interval, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	gaps := []Gap{}
+	expected := broker.IntervalStart(start, interval)
+	if expected.Before(start) {
+		expected = expected.Add(step)
+	}
+	for _, c := range stored {
+		if c.OpenTime.After(expected) {
+			gaps = append(gaps, Gap{Start: expected, End: c.OpenTime.Add(-step)})
+		}
+		expected = c.OpenTime.Add(step)
+	}
+
+	// The candle holding end may still be open, it is not missing yet.
+	lastClosed := broker.IntervalStart(end, interval).Add(-step)
+	if !expected.After(lastClosed) {
+		gaps = append(gaps, Gap{Start: expected, End: lastClosed})
+	}
+	return gaps, nil
+}
+
+// RepairGaps fetches the candles missing between start and end from the
+// exchange. It returns how many were saved.
+func (u CandleUseCase) RepairGaps(ctx context.Context, symbol string, interval string, start time.Time, end time.Time) (int, error) {
+	gaps, err := u.DetectGaps(ctx, symbol, interval, start, end)
+	if err != nil {
+		return 0, err
+	}
+
+	step, _ := intervalDuration(interval)
+	saved := 0
+	for _, gap := range gaps {
+		n, err := u.fetch(ctx, symbol, interval, gap.Start, gap.End.Add(step))
+		saved += n
+		if err != nil {
+			return saved, err
+		}
+	}
+	return saved, nil
+}
+
+// fetch pages through the closed candles opened in [from, to).
+func (u CandleUseCase) fetch(ctx context.Context, symbol string, interval string, from time.Time, to time.Time) (int, error) {
+	now := u.Now()
+	saved := 0
+	start := from
+	for start.Before(to) {
+		if err := ctx.Err(); err != nil {
+			return saved, err
+		}
+
+		page, err := u.Broker.ListKlineRange(ctx, symbol, interval, start, pageSize)
+		if err != nil {
+			return saved, err
+		}
+
+		closed, _ := splitClosed(page, now)
+		inRange := make([]broker.Candle, 0, len(closed))
+		for _, c := range closed {
+			if c.OpenTime.Before(to) {
+				inRange = append(inRange, c)
+			}
+		}
+		if err := u.Repository.Save(ctx, toEntities(symbol, interval, inRange)); err != nil {
+			return saved, err
+		}
+		saved += len(inRange)
+
+		if len(page) < pageSize {
+			break
+		}
+		start = page[len(page)-1].OpenTime.Add(time.Millisecond)
+	}
+	return saved, nil
+}
+
+func intervalDuration(interval string) (time.Duration, error) {
+	step, ok := broker.IntervalDuration(interval)
+	if !ok {
+		return 0, customerror.New(http.StatusBadRequest, fmt.Sprintf("Unsupported interval %s", interval))
+	}
+	return step, nil
+}
+
+// splitClosed separates the candles already closed at now from the one still
+// being formed.
+func splitClosed(candles []broker.Candle, now time.Time) ([]broker.Candle, []broker.Candle) {
+	for i, c := range candles {
+		if !c.CloseTime.Before(now) {
+			return candles[:i], candles[i:]
+		}
+	}
+	return candles, nil
+}
+
+func toEntities(symbol string, interval string, candles []broker.Candle) []entities.Candle {
+	result := make([]entities.Candle, len(candles))
+	for i, c := range candles {
+		result[i] = entities.Candle{
+			Symbol:    symbol,
+			Interval:  interval,
+			OpenTime:  c.OpenTime,
+			CloseTime: c.CloseTime,
+			Open:      c.Open,
+			High:      c.High,
+			Low:       c.Low,
+			Close:     c.Close,
+			Volume:    c.Volume,
+		}
+	}
+	return result
+}
+
+func toCandles(candles []entities.Candle) []broker.Candle {
+	result := make([]broker.Candle, len(candles))
+	for i, c := range candles {
+		result[i] = broker.Candle{
+			OpenTime:  c.OpenTime,
+			CloseTime: c.CloseTime,
+			Open:      c.Open,
+			High:      c.High,
+			Low:       c.Low,
+			Close:     c.Close,
+			Volume:    c.Volume,
+		}
+	}
+	return result
+}
@@ -0,0 +1,197 @@
+package usecase_test
+
+import (
+	"context"
+	"go-trade-bot/app/entities"
+	usecase "go-trade-bot/app/usecase/candle"
+	"go-trade-bot/app/usecase/candle/mocks"
+	"go-trade-bot/internal/broker"
+	"go-trade-bot/internal/customerror"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var now = time.Date(2024, 1, 1, 10, 0, 30, 0, time.UTC)
+
+func minuteCandle(open time.Time) broker.Candle {
+	return broker.Candle{OpenTime: open, CloseTime: open.Add(time.Minute - time.Millisecond), Close: float64(open.Minute())}
+}
+
+func minuteEntity(open time.Time) entities.Candle {
+	c := minuteCandle(open)
+	return entities.Candle{Symbol: "BTCUSDT", Interval: "1m", OpenTime: c.OpenTime, CloseTime: c.CloseTime, Close: c.Close}
+}
+
+func newUseCase(repo *mocks.CandleRepository, b *mocks.Broker, w *mocks.CandleWorker) usecase.CandleUseCase {
+	uc := usecase.NewCandleUseCase(repo, b, w)
+	uc.Now = func() time.Time { return now }
+	return uc
+}
+
+func TestCandleUseCase_ListKline(t *testing.T) {
+	t.Run("should only download the candles missing since the last stored", func(t *testing.T) {
+		repo := new(mocks.CandleRepository)
+		b := new(mocks.Broker)
+		uc := newUseCase(repo, b, new(mocks.CandleWorker))
+
+		latest := time.Date(2024, 1, 1, 9, 58, 0, 0, time.UTC)
+		repo.On("GetLatest", mock.Anything, "BTCUSDT", "1m").Return(minuteEntity(latest), true, nil)
+		b.On("ListKlineRange", mock.Anything, "BTCUSDT", "1m", latest.Add(time.Minute), 1000).Return([]broker.Candle{
+			minuteCandle(latest.Add(time.Minute)),
+			minuteCandle(latest.Add(2 * time.Minute)),
+		}, nil)
+		repo.On("Save", mock.Anything, []entities.Candle{minuteEntity(latest.Add(time.Minute))}).Return(nil)
+		repo.On("GetLast", mock.Anything, "BTCUSDT", "1m", 2).Return([]entities.Candle{
+			minuteEntity(latest),
+			minuteEntity(latest.Add(time.Minute)),
+		}, nil)
+
+		candles, err := uc.ListKline(context.Background(), "BTCUSDT", "1m", 2)
+		assert.NoError(t, err)
+		assert.Equal(t, []broker.Candle{
+			minuteCandle(latest.Add(time.Minute)),
+			minuteCandle(latest.Add(2 * time.Minute)),
+		}, candles)
+		repo.AssertExpectations(t)
+		b.AssertExpectations(t)
+	})
+
+	t.Run("should download the last candles when nothing is stored", func(t *testing.T) {
+		repo := new(mocks.CandleRepository)
+		b := new(mocks.Broker)
+		uc := newUseCase(repo, b, new(mocks.CandleWorker))
+
+		first := time.Date(2024, 1, 1, 9, 59, 0, 0, time.UTC)
+		repo.On("GetLatest", mock.Anything, "BTCUSDT", "1m").Return(entities.Candle{}, false, nil)
+		b.On("ListKline", mock.Anything, "BTCUSDT", "1m", 2).Return([]broker.Candle{
+			minuteCandle(first),
+			minuteCandle(first.Add(time.Minute)),
+		}, nil)
+		repo.On("Save", mock.Anything, []entities.Candle{minuteEntity(first)}).Return(nil)
+		repo.On("GetLast", mock.Anything, "BTCUSDT", "1m", 2).Return([]entities.Candle{minuteEntity(first)}, nil)
+
+		candles, err := uc.ListKline(context.Background(), "BTCUSDT", "1m", 2)
+		assert.NoError(t, err)
+		assert.Len(t, candles, 2)
+		repo.AssertExpectations(t)
+		b.AssertExpectations(t)
+	})
+
+	t.Run("should download the whole window when the store holds fewer candles", func(t *testing.T) {
+		repo := new(mocks.CandleRepository)
+		b := new(mocks.Broker)
+		uc := newUseCase(repo, b, new(mocks.CandleWorker))
+
+		latest := time.Date(2024, 1, 1, 9, 59, 0, 0, time.UTC)
+		from := time.Date(2024, 1, 1, 9, 57, 0, 0, time.UTC)
+		repo.On("GetLatest", mock.Anything, "BTCUSDT", "1m").Return(minuteEntity(latest), true, nil)
+		b.On("ListKlineRange", mock.Anything, "BTCUSDT", "1m", latest.Add(time.Minute), 1000).Return([]broker.Candle{
+			minuteCandle(latest.Add(time.Minute)),
+		}, nil).Once()
+		repo.On("Save", mock.Anything, []entities.Candle{}).Return(nil).Once()
+		repo.On("GetLast", mock.Anything, "BTCUSDT", "1m", 3).Return([]entities.Candle{minuteEntity(latest)}, nil).Once()
+		b.On("ListKlineRange", mock.Anything, "BTCUSDT", "1m", from, 1000).Return([]broker.Candle{
+			minuteCandle(from),
+			minuteCandle(from.Add(time.Minute)),
+			minuteCandle(latest),
+			minuteCandle(latest.Add(time.Minute)),
+		}, nil).Once()
+		repo.On("Save", mock.Anything, []entities.Candle{
+			minuteEntity(from),
+			minuteEntity(from.Add(time.Minute)),
+			minuteEntity(latest),
+		}).Return(nil).Once()
+		repo.On("GetLast", mock.Anything, "BTCUSDT", "1m", 3).Return([]entities.Candle{
+			minuteEntity(from),
+			minuteEntity(from.Add(time.Minute)),
+			minuteEntity(latest),
+		}, nil).Once()
+
+		candles, err := uc.ListKline(context.Background(), "BTCUSDT", "1m", 3)
+		assert.NoError(t, err)
+		assert.Equal(t, []broker.Candle{
+			minuteCandle(from.Add(time.Minute)),
+			minuteCandle(latest),
+			minuteCandle(latest.Add(time.Minute)),
+		}, candles)
+		repo.AssertExpectations(t)
+		b.AssertExpectations(t)
+	})
+
+	t.Run("should reject unsupported intervals", func(t *testing.T) {
+		uc := newUseCase(new(mocks.CandleRepository), new(mocks.Broker), new(mocks.CandleWorker))
+
+		_, err := uc.ListKline(context.Background(), "BTCUSDT", "10m", 2)
+		assert.Equal(t, customerror.New(http.StatusBadRequest, "Unsupported interval 10m"), err)
+	})
+}
+
+func TestCandleUseCase_DetectGaps(t *testing.T) {
+	repo := new(mocks.CandleRepository)
+	uc := newUseCase(repo, new(mocks.Broker), new(mocks.CandleWorker))
+
+	start := time.Date(2024, 1, 1, 9, 50, 0, 0, time.UTC)
+	repo.On("GetRange", mock.Anything, "BTCUSDT", "1m", start, now).Return([]entities.Candle{
+		minuteEntity(start),
+		minuteEntity(start.Add(time.Minute)),
+		minuteEntity(start.Add(4 * time.Minute)),
+		minuteEntity(start.Add(5 * time.Minute)),
+	}, nil)
+
+	gaps, err := uc.DetectGaps(context.Background(), "BTCUSDT", "1m", start, now.Add(time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, []usecase.Gap{
+		{Start: start.Add(2 * time.Minute), End: start.Add(3 * time.Minute)},
+		{Start: start.Add(6 * time.Minute), End: start.Add(9 * time.Minute)},
+	}, gaps)
+}
+
+func TestCandleUseCase_Backfill(t *testing.T) {
+	repo := new(mocks.CandleRepository)
+	b := new(mocks.Broker)
+	uc := newUseCase(repo, b, new(mocks.CandleWorker))
+
+	from := now.Add(-1500 * time.Minute).Truncate(time.Minute)
+	page := make([]broker.Candle, 1000)
+	for i := range page {
+		page[i] = minuteCandle(from.Add(time.Duration(i) * time.Minute))
+	}
+	rest := make([]broker.Candle, 501)
+	for i := range rest {
+		rest[i] = minuteCandle(from.Add(time.Duration(1000+i) * time.Minute))
+	}
+
+	b.On("ListKlineRange", mock.Anything, "BTCUSDT", "1m", from, 1000).Return(page, nil)
+	b.On("ListKlineRange", mock.Anything, "BTCUSDT", "1m", page[999].OpenTime.Add(time.Millisecond), 1000).Return(rest, nil)
+	repo.On("Save", mock.Anything, mock.Anything).Return(nil)
+
+	saved, err := uc.Backfill(context.Background(), "BTCUSDT", "1m", from)
+	assert.NoError(t, err)
+	// The last candle is still open and is not stored.
+	assert.Equal(t, 1500, saved)
+	b.AssertExpectations(t)
+}
+
+func TestCandleUseCase_RequestBackfill(t *testing.T) {
+	t.Run("should enqueue the backfill", func(t *testing.T) {
+		w := new(mocks.CandleWorker)
+		uc := newUseCase(new(mocks.CandleRepository), new(mocks.Broker), w)
+
+		from := now.Add(-24 * time.Hour)
+		w.On("EnqueueBackfill", "BTCUSDT", "1h", from).Return(nil)
+
+		assert.NoError(t, uc.RequestBackfill(context.Background(), "BTCUSDT", "1h", from))
+		w.AssertExpectations(t)
+	})
+
+	t.Run("should reject times in the future", func(t *testing.T) {
+		uc := newUseCase(new(mocks.CandleRepository), new(mocks.Broker), new(mocks.CandleWorker))
+
+		err := uc.RequestBackfill(context.Background(), "BTCUSDT", "1h", now.Add(time.Hour))
+		assert.Equal(t, customerror.New(http.StatusBadRequest, "From must be a time in the past"), err)
+	})
+}
@@ -0,0 +1,65 @@
+package usecase
+
+import (
+	"context"
+	"go-trade-bot/app/entities"
+	"go-trade-bot/internal/customerror"
+	"net/http"
+)
+
+type GridRepository interface {
+	ListByStrategy(ctx context.Context, strategyID uint) ([]entities.GridState, error)
+	Delete(ctx context.Context, strategyID uint, symbol string) (int64, error)
+}
+
+type StrategyRepository interface {
+	GetByID(ctx context.Context, id uint) (entities.Strategy, error)
+}
+
+type GridUseCase struct {
+	Repository GridRepository
+	Strategies StrategyRepository
+}
+
+func NewGridUseCase(r GridRepository, s StrategyRepository) GridUseCase {
+	return GridUseCase{
+		Repository: r,
+		Strategies: s,
+	}
+}
+
+// List returns the grids built by the strategy, one per symbol.
+func (u GridUseCase) List(ctx context.Context, strategyID uint) ([]entities.GridState, error) {
+	if err := u.checkStrategy(ctx, strategyID); err != nil {
+		return nil, err
+	}
+	return u.Repository.ListByStrategy(ctx, strategyID)
+}
+
+// Reset discards the grid of the symbol, or every grid of the strategy when
+// symbol is empty. The next execution of the strategy builds them again.
+func (u GridUseCase) Reset(ctx context.Context, strategyID uint, symbol string) error {
+	if err := u.checkStrategy(ctx, strategyID); err != nil {
+		return err
+	}
+
+	deleted, err := u.Repository.Delete(ctx, strategyID, symbol)
+	if err != nil {
+		return err
+	}
+	if deleted == 0 && symbol != "" {
+		return customerror.New(http.StatusNotFound, "Grid not found")
+	}
+	return nil
+}
+
+func (u GridUseCase) checkStrategy(ctx context.Context, strategyID uint) error {
+	strategy, err := u.Strategies.GetByID(ctx, strategyID)
+	if err != nil {
+		return customerror.New(http.StatusNotFound, "Strategy not found")
+	}
+	if strategy.Algorithm != entities.Grid {
+		return customerror.New(http.StatusBadRequest, "Strategy does not use the grid algorithm")
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"go-trade-bot/app/entities"
+	usecase "go-trade-bot/app/usecase/grid"
+	"go-trade-bot/app/usecase/grid/mocks"
+	"go-trade-bot/internal/customerror"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGridUseCase_List(t *testing.T) {
+	t.Run("should list the grids of the strategy", func(t *testing.T) {
+		repo := new(mocks.GridRepository)
+		strategies := new(mocks.StrategyRepository)
+		uc := usecase.NewGridUseCase(repo, strategies)
+
+		grids := []entities.GridState{{StrategyID: 1, Symbol: "BTCUSDT"}}
+		strategies.On("GetByID", mock.Anything, uint(1)).Return(entities.Strategy{ID: 1, Algorithm: entities.Grid}, nil).Once()
+		repo.On("ListByStrategy", mock.Anything, uint(1)).Return(grids, nil).Once()
+
+		result, err := uc.List(context.Background(), 1)
+		assert.NoError(t, err)
+		assert.Equal(t, grids, result)
+	})
+
+	t.Run("should return not found for unknown strategies", func(t *testing.T) {
+		strategies := new(mocks.StrategyRepository)
+		uc := usecase.NewGridUseCase(new(mocks.GridRepository), strategies)
+		strategies.On("GetByID", mock.Anything, uint(2)).Return(entities.Strategy{}, errors.New("record not found")).Once()
+
+		_, err := uc.List(context.Background(), 2)
+		assert.Equal(t, customerror.New(http.StatusNotFound, "Strategy not found"), err)
+	})
+
+	t.Run("should reject strategies of other algorithms", func(t *testing.T) {
+		strategies := new(mocks.StrategyRepository)
+		uc := usecase.NewGridUseCase(new(mocks.GridRepository), strategies)
+		strategies.On("GetByID", mock.Anything, uint(3)).Return(entities.Strategy{ID: 3, Algorithm: entities.Scalping}, nil).Once()
+
+		_, err := uc.List(context.Background(), 3)
+		assert.Equal(t, customerror.New(http.StatusBadRequest, "Strategy does not use the grid algorithm"), err)
+	})
+}
+
+func TestGridUseCase_Reset(t *testing.T) {
+	strategy := entities.Strategy{ID: 1, Algorithm: entities.Grid}
+
+	t.Run("should reset the grid of a symbol", func(t *testing.T) {
+		repo := new(mocks.GridRepository)
+		strategies := new(mocks.StrategyRepository)
+		uc := usecase.NewGridUseCase(repo, strategies)
+		strategies.On("GetByID", mock.Anything, uint(1)).Return(strategy, nil).Once()
+		repo.On("Delete", mock.Anything, uint(1), "BTCUSDT").Return(int64(1), nil).Once()
+
+		assert.NoError(t, uc.Reset(context.Background(), 1, "BTCUSDT"))
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("should return not found when the symbol has no grid", func(t *testing.T) {
+		repo := new(mocks.GridRepository)
+		strategies := new(mocks.StrategyRepository)
+		uc := usecase.NewGridUseCase(repo, strategies)
+		strategies.On("GetByID", mock.Anything, uint(1)).Return(strategy, nil).Once()
+		repo.On("Delete", mock.Anything, uint(1), "ETHUSDT").Return(int64(0), nil).Once()
+
+		err := uc.Reset(context.Background(), 1, "ETHUSDT")
+		assert.Equal(t, customerror.New(http.StatusNotFound, "Grid not found"), err)
+	})
+
+	t.Run("should reset every grid of the strategy", func(t *testing.T) {
+		repo := new(mocks.GridRepository)
+		strategies := new(mocks.StrategyRepository)
+		uc := usecase.NewGridUseCase(repo, strategies)
+		strategies.On("GetByID", mock.Anything, uint(1)).Return(strategy, nil).Once()
+		repo.On("Delete", mock.Anything, uint(1), "").Return(int64(0), nil).Once()
+
+		assert.NoError(t, uc.Reset(context.Background(), 1, ""))
+	})
+}
@@ -0,0 +1,103 @@
+// Code generated by mockery v2.53.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	killswitch "go-trade-bot/internal/killswitch"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// Switch is an autogenerated mock type for the Switch type
+type Switch struct {
+	mock.Mock
+}
+
+// Engage provides a mock function with given fields: ctx, reason, closeSignals
+func (_m *Switch) Engage(ctx context.Context, reason string, closeSignals bool) (killswitch.State, error) {
+	ret := _m.Called(ctx, reason, closeSignals)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Engage")
+	}
+
+	var r0 killswitch.State
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, bool) (killswitch.State, error)); ok {
+		return rf(ctx, reason, closeSignals)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, bool) killswitch.State); ok {
+		r0 = rf(ctx, reason, closeSignals)
+	} else {
+		r0 = ret.Get(0).(killswitch.State)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, bool) error); ok {
+		r1 = rf(ctx, reason, closeSignals)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Release provides a mock function with given fields: ctx
+func (_m *Switch) Release(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Release")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// State provides a mock function with given fields: ctx
+func (_m *Switch) State(ctx context.Context) (killswitch.State, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for State")
+	}
+
+	var r0 killswitch.State
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (killswitch.State, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) killswitch.State); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(killswitch.State)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewSwitch creates a new instance of Switch. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewSwitch(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Switch {
+	mock := &Switch{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,62 @@
+package usecase
+
+import (
+	"context"
+	"go-trade-bot/internal/customerror"
+	"go-trade-bot/internal/killswitch"
+	"net/http"
+)
+
+type Switch interface {
+	Engage(ctx context.Context, reason string, closeSignals bool) (killswitch.State, error)
+	Release(ctx context.Context) error
+	State(ctx context.Context) (killswitch.State, error)
+}
+
+type Worker interface {
+	EnqueueKillSwitchTask() error
+}
+
+// KillSwitchUseCase pauses every strategy at once. The flag stops the
+// strategies on their next execution, the task lets a worker cancel the
+// open orders and close the signals without waiting for them.
+type KillSwitchUseCase struct {
+	Switch Switch
+	Worker Worker
+}
+
+func NewKillSwitchUseCase(s Switch, w Worker) KillSwitchUseCase {
+	return KillSwitchUseCase{
+		Switch: s,
+		Worker: w,
+	}
+}
+
+// Engage pauses the strategies and, with closeSignals, sells every open
+// signal at market.
+func (u KillSwitchUseCase) Engage(ctx context.Context, reason string, closeSignals bool) (killswitch.State, error) {
+	if reason == "" {
+		reason = "Engaged manually"
+	}
+	state, err := u.Switch.Engage(ctx, reason, closeSignals)
+	if err != nil {
+		return killswitch.State{}, err
+	}
+	return state, u.Worker.EnqueueKillSwitchTask()
+}
+
+// Release lets the strategies run again from their next execution.
+func (u KillSwitchUseCase) Release(ctx context.Context) error {
+	state, err := u.Switch.State(ctx)
+	if err != nil {
+		return err
+	}
+	if !state.Engaged {
+		return customerror.New(http.StatusConflict, "Kill switch is not engaged")
+	}
+	return u.Switch.Release(ctx)
+}
+
+func (u KillSwitchUseCase) State(ctx context.Context) (killswitch.State, error) {
+	return u.Switch.State(ctx)
+}
@@ -0,0 +1,80 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	usecase "go-trade-bot/app/usecase/killswitch"
+	"go-trade-bot/app/usecase/killswitch/mocks"
+	"go-trade-bot/internal/customerror"
+	"go-trade-bot/internal/killswitch"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestKillSwitchUseCase_Engage(t *testing.T) {
+	t.Run("should raise the flag and enqueue the task", func(t *testing.T) {
+		s := new(mocks.Switch)
+		w := new(mocks.Worker)
+		uc := usecase.NewKillSwitchUseCase(s, w)
+
+		s.On("Engage", mock.Anything, "exchange outage", true).Return(killswitch.State{Engaged: true, Reason: "exchange outage", CloseSignals: true}, nil).Once()
+		w.On("EnqueueKillSwitchTask").Return(nil).Once()
+
+		state, err := uc.Engage(context.Background(), "exchange outage", true)
+		assert.NoError(t, err)
+		assert.True(t, state.Engaged)
+		s.AssertExpectations(t)
+		w.AssertExpectations(t)
+	})
+
+	t.Run("should default the reason", func(t *testing.T) {
+		s := new(mocks.Switch)
+		w := new(mocks.Worker)
+		uc := usecase.NewKillSwitchUseCase(s, w)
+
+		s.On("Engage", mock.Anything, "Engaged manually", false).Return(killswitch.State{Engaged: true}, nil).Once()
+		w.On("EnqueueKillSwitchTask").Return(nil).Once()
+
+		_, err := uc.Engage(context.Background(), "", false)
+		assert.NoError(t, err)
+		s.AssertExpectations(t)
+	})
+
+	t.Run("should not enqueue when the flag can't be raised", func(t *testing.T) {
+		s := new(mocks.Switch)
+		w := new(mocks.Worker)
+		uc := usecase.NewKillSwitchUseCase(s, w)
+
+		s.On("Engage", mock.Anything, mock.Anything, mock.Anything).Return(killswitch.State{}, errors.New("connection refused")).Once()
+
+		_, err := uc.Engage(context.Background(), "", false)
+		assert.EqualError(t, err, "connection refused")
+		w.AssertNotCalled(t, "EnqueueKillSwitchTask")
+	})
+}
+
+func TestKillSwitchUseCase_Release(t *testing.T) {
+	t.Run("should lower the flag", func(t *testing.T) {
+		s := new(mocks.Switch)
+		uc := usecase.NewKillSwitchUseCase(s, new(mocks.Worker))
+
+		s.On("State", mock.Anything).Return(killswitch.State{Engaged: true}, nil).Once()
+		s.On("Release", mock.Anything).Return(nil).Once()
+
+		assert.NoError(t, uc.Release(context.Background()))
+		s.AssertExpectations(t)
+	})
+
+	t.Run("should answer conflict when not engaged", func(t *testing.T) {
+		s := new(mocks.Switch)
+		uc := usecase.NewKillSwitchUseCase(s, new(mocks.Worker))
+
+		s.On("State", mock.Anything).Return(killswitch.State{}, nil).Once()
+
+		err := uc.Release(context.Background())
+		assert.Equal(t, customerror.New(http.StatusConflict, "Kill switch is not engaged"), err)
+	})
+}
@@ -0,0 +1,34 @@
+// Code generated by mockery v2.53.2. DO NOT EDIT.
+
+package mocks
+
+import mock "github.com/stretchr/testify/mock"
+
+// Metrics is an autogenerated mock type for the Metrics type
+type Metrics struct {
+	mock.Mock
+}
+
+// IncrementCounter provides a mock function with given fields: name, labels
+func (_m *Metrics) IncrementCounter(name string, labels map[string]string) {
+	_m.Called(name, labels)
+}
+
+// SetGauge provides a mock function with given fields: name, labels, value
+func (_m *Metrics) SetGauge(name string, labels map[string]string, value float64) {
+	_m.Called(name, labels, value)
+}
+
+// NewMetrics creates a new instance of Metrics. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMetrics(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Metrics {
+	mock := &Metrics{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,287 @@
+// Code generated by mockery v2.53.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	entities "go-trade-bot/app/entities"
+	time "time"
+
+	decimal "github.com/shopspring/decimal"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// RiskRepository is an autogenerated mock type for the RiskRepository type
+type RiskRepository struct {
+	mock.Mock
+}
+
+// AddEvent provides a mock function with given fields: ctx, event
+func (_m *RiskRepository) AddEvent(ctx context.Context, event entities.RiskEvent) error {
+	ret := _m.Called(ctx, event)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddEvent")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, entities.RiskEvent) error); ok {
+		r0 = rf(ctx, event)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CountOpenSignals provides a mock function with given fields: ctx, symbol, strategyID
+func (_m *RiskRepository) CountOpenSignals(ctx context.Context, symbol string, strategyID uint) (int64, error) {
+	ret := _m.Called(ctx, symbol, strategyID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountOpenSignals")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, uint) (int64, error)); ok {
+		return rf(ctx, symbol, strategyID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, uint) int64); ok {
+		r0 = rf(ctx, symbol, strategyID)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, uint) error); ok {
+		r1 = rf(ctx, symbol, strategyID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetState provides a mock function with given fields: ctx
+func (_m *RiskRepository) GetState(ctx context.Context) (entities.RiskState, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetState")
+	}
+
+	var r0 entities.RiskState
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (entities.RiskState, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) entities.RiskState); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(entities.RiskState)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListEvents provides a mock function with given fields: ctx, limit
+func (_m *RiskRepository) ListEvents(ctx context.Context, limit int) ([]entities.RiskEvent, error) {
+	ret := _m.Called(ctx, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListEvents")
+	}
+
+	var r0 []entities.RiskEvent
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]entities.RiskEvent, error)); ok {
+		return rf(ctx, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []entities.RiskEvent); ok {
+		r0 = rf(ctx, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entities.RiskEvent)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// LiveAccounts provides a mock function with given fields: ctx
+func (_m *RiskRepository) LiveAccounts(ctx context.Context) ([]entities.Account, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LiveAccounts")
+	}
+
+	var r0 []entities.Account
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]entities.Account, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []entities.Account); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entities.Account)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Positions provides a mock function with given fields: ctx
+func (_m *RiskRepository) Positions(ctx context.Context) ([]entities.Signal, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Positions")
+	}
+
+	var r0 []entities.Signal
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]entities.Signal, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []entities.Signal); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entities.Signal)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RaiseEquityPeak provides a mock function with given fields: ctx, equity
+func (_m *RiskRepository) RaiseEquityPeak(ctx context.Context, equity decimal.Decimal) (decimal.Decimal, error) {
+	ret := _m.Called(ctx, equity)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RaiseEquityPeak")
+	}
+
+	var r0 decimal.Decimal
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, decimal.Decimal) (decimal.Decimal, error)); ok {
+		return rf(ctx, equity)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, decimal.Decimal) decimal.Decimal); ok {
+		r0 = rf(ctx, equity)
+	} else {
+		r0 = ret.Get(0).(decimal.Decimal)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, decimal.Decimal) error); ok {
+		r1 = rf(ctx, equity)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RealizedPnL provides a mock function with given fields: ctx, since
+func (_m *RiskRepository) RealizedPnL(ctx context.Context, since time.Time) (decimal.Decimal, error) {
+	ret := _m.Called(ctx, since)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RealizedPnL")
+	}
+
+	var r0 decimal.Decimal
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) (decimal.Decimal, error)); ok {
+		return rf(ctx, since)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) decimal.Decimal); ok {
+		r0 = rf(ctx, since)
+	} else {
+		r0 = ret.Get(0).(decimal.Decimal)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, since)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ResetEquityPeak provides a mock function with given fields: ctx, equity
+func (_m *RiskRepository) ResetEquityPeak(ctx context.Context, equity decimal.Decimal) error {
+	ret := _m.Called(ctx, equity)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResetEquityPeak")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, decimal.Decimal) error); ok {
+		r0 = rf(ctx, equity)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SaveState provides a mock function with given fields: ctx, state
+func (_m *RiskRepository) SaveState(ctx context.Context, state entities.RiskState) error {
+	ret := _m.Called(ctx, state)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveState")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, entities.RiskState) error); ok {
+		r0 = rf(ctx, state)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewRiskRepository creates a new instance of RiskRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewRiskRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *RiskRepository {
+	mock := &RiskRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,399 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"go-trade-bot/app/entities"
+	"go-trade-bot/internal/broker"
+	"go-trade-bot/internal/configuration"
+	"go-trade-bot/internal/customerror"
+	"log"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	risk_halted        = "risk_halted"
+	risk_equity        = "risk_equity"
+	risk_drawdown_pct  = "risk_drawdown_pct"
+	risk_daily_pnl_pct = "risk_daily_pnl_pct"
+	risk_exposure_pct  = "risk_exposure_pct"
+	risk_trips_total   = "risk_trips_total"
+)
+
+var hundred = decimal.NewFromInt(100)
+
+type RiskRepository interface {
+	GetState(ctx context.Context) (entities.RiskState, error)
+	SaveState(ctx context.Context, state entities.RiskState) error
+	AddEvent(ctx context.Context, event entities.RiskEvent) error
+	ListEvents(ctx context.Context, limit int) ([]entities.RiskEvent, error)
+	RaiseEquityPeak(ctx context.Context, equity decimal.Decimal) (decimal.Decimal, error)
+	ResetEquityPeak(ctx context.Context, equity decimal.Decimal) error
+	LiveAccounts(ctx context.Context) ([]entities.Account, error)
+	Positions(ctx context.Context) ([]entities.Signal, error)
+	RealizedPnL(ctx context.Context, since time.Time) (decimal.Decimal, error)
+	CountOpenSignals(ctx context.Context, symbol string, strategyID uint) (int64, error)
+}
+
+// Broker prices the open positions.
+type Broker interface {
+	ListTickerPrices(ctx context.Context, symbol string) ([]broker.Ticker, error)
+}
+
+type Metrics interface {
+	SetGauge(name string, labels map[string]string, value float64)
+	IncrementCounter(name string, labels map[string]string)
+}
+
+// Limits are the limits of RISK, zero disables a limit. Percentages are of
+// the equity.
+type Limits struct {
+	MaxDailyLossPct         decimal.Decimal
+	MaxDrawdownPct          decimal.Decimal
+	MaxPositionsPerSymbol   int64
+	MaxPositionsPerStrategy int64
+	MaxExposurePct          decimal.Decimal
+	FlattenOnTrip           bool
+}
+
+// Status is the state of the breaker with the figures it was decided on.
+// Equity is the balance of the live accounts plus what their open signals
+// invested and their unrealized PnL at the last price, DailyPnL the profit
+// realized since midnight UTC, or since the last reset. Exposure is what the
+// open signals invested.
+type Status struct {
+	Halted        bool
+	Rule          entities.RiskRule
+	Reason        string
+	TrippedAt     *time.Time
+	Flatten       bool
+	Currency      string
+	Equity        decimal.Decimal
+	EquityPeak    decimal.Decimal
+	DrawdownPct   decimal.Decimal
+	UnrealizedPnL decimal.Decimal
+	DailyPnL      decimal.Decimal
+	DailyPnLPct   decimal.Decimal
+	Exposure      decimal.Decimal
+	ExposurePct   decimal.Decimal
+	Limits        Limits
+}
+
+// RiskUseCase is consulted before every entry. Its breakers halt new entries
+// when the daily loss or the drawdown from the equity peak reach their
+// limit: a daily loss halt clears on the next UTC day, a drawdown halt
+// stays until it is reset.
+type RiskUseCase struct {
+	Repository RiskRepository
+	Broker     Broker
+	Metrics    Metrics
+	Limits     Limits
+	Now        func() time.Time
+}
+
+func NewRiskUseCase(cfg *configuration.Configuration, r RiskRepository, b Broker, m Metrics) *RiskUseCase {
+	return &RiskUseCase{
+		Repository: r,
+		Broker:     b,
+		Metrics:    m,
+		Limits: Limits{
+			MaxDailyLossPct:         decimal.NewFromFloat(cfg.Risk.MaxDailyLossPct),
+			MaxDrawdownPct:          decimal.NewFromFloat(cfg.Risk.MaxDrawdownPct),
+			MaxPositionsPerSymbol:   int64(cfg.Risk.MaxPositionsPerSymbol),
+			MaxPositionsPerStrategy: int64(cfg.Risk.MaxPositionsPerStrategy),
+			MaxExposurePct:          decimal.NewFromFloat(cfg.Risk.MaxExposurePct),
+			FlattenOnTrip:           cfg.Risk.FlattenOnTrip,
+		},
+		Now: time.Now,
+	}
+}
+
+// Allow refuses an entry of amount on the symbol while the breaker is
+// tripped or when it would exceed the position or exposure limits.
+func (u *RiskUseCase) Allow(ctx context.Context, symbol string, strategyID uint, amount decimal.Decimal) error {
+	status, err := u.Evaluate(ctx)
+	if err != nil {
+		return err
+	}
+	if status.Halted {
+		return fmt.Errorf("entries are halted by the %s breaker: %s", status.Rule, status.Reason)
+	}
+
+	if u.Limits.MaxPositionsPerSymbol > 0 {
+		open, err := u.Repository.CountOpenSignals(ctx, symbol, 0)
+		if err != nil {
+			return err
+		}
+		if open >= u.Limits.MaxPositionsPerSymbol {
+			return fmt.Errorf("%s already has %d open positions, the limit is %d", symbol, open, u.Limits.MaxPositionsPerSymbol)
+		}
+	}
+
+	if u.Limits.MaxPositionsPerStrategy > 0 {
+		open, err := u.Repository.CountOpenSignals(ctx, "", strategyID)
+		if err != nil {
+			return err
+		}
+		if open >= u.Limits.MaxPositionsPerStrategy {
+			return fmt.Errorf("strategy %d already has %d open positions, the limit is %d", strategyID, open, u.Limits.MaxPositionsPerStrategy)
+		}
+	}
+
+	if u.Limits.MaxExposurePct.IsPositive() {
+		exposure := status.Exposure.Add(amount)
+		limit := status.Equity.Mul(u.Limits.MaxExposurePct).Div(hundred)
+		if exposure.GreaterThan(limit) {
+			return fmt.Errorf("entry of %s on %s would raise the exposure to %s, above the limit of %s",
+				amount.StringFixed(2), symbol, exposure.StringFixed(2), limit.StringFixed(2))
+		}
+	}
+	return nil
+}
+
+// Evaluate measures the equity of the live accounts, raises its peak and
+// trips a breaker whose limit is reached. The figures are published as
+// metrics. Live accounts in several currencies can't be summed and are
+// refused with a conflict.
+func (u *RiskUseCase) Evaluate(ctx context.Context) (Status, error) {
+	state, err := u.Repository.GetState(ctx)
+	if err != nil {
+		return Status{}, err
+	}
+	accounts, err := u.Repository.LiveAccounts(ctx)
+	if err != nil {
+		return Status{}, err
+	}
+	currency, balance, err := sum(accounts)
+	if err != nil {
+		return Status{}, err
+	}
+	positions, err := u.Repository.Positions(ctx)
+	if err != nil {
+		return Status{}, err
+	}
+	exposure, unrealized, err := u.mark(ctx, positions)
+	if err != nil {
+		return Status{}, err
+	}
+	day := u.Now().UTC().Truncate(24 * time.Hour)
+	since := day
+	if state.ResetAt != nil && state.ResetAt.After(day) {
+		since = *state.ResetAt
+	}
+	pnl, err := u.Repository.RealizedPnL(ctx, since)
+	if err != nil {
+		return Status{}, err
+	}
+	equity := balance.Add(exposure).Add(unrealized)
+
+	changed := false
+	if state.Halted && state.Rule == entities.DailyLossRule && state.TrippedAt != nil && state.TrippedAt.Before(day) {
+		if err := u.clear(ctx, &state, equity, "New trading day"); err != nil {
+			return Status{}, err
+		}
+		changed = true
+	}
+	if equity.GreaterThan(state.EquityPeak) {
+		peak, err := u.Repository.RaiseEquityPeak(ctx, equity)
+		if err != nil {
+			return Status{}, err
+		}
+		state.EquityPeak = peak
+	}
+
+	status := u.status(state, equity, exposure, pnl)
+	if !state.Halted {
+		if rule, reason := u.breach(status); rule != "" {
+			if err := u.trip(ctx, &state, rule, reason, equity); err != nil {
+				return Status{}, err
+			}
+			status = u.status(state, equity, exposure, pnl)
+			changed = true
+		}
+	}
+	status.Currency = currency
+	status.UnrealizedPnL = unrealized
+
+	if changed {
+		if err := u.Repository.SaveState(ctx, state); err != nil {
+			return Status{}, err
+		}
+	}
+	u.publish(status)
+	return status, nil
+}
+
+// Reset clears a tripped breaker, drawdowns are measured from the current
+// equity and the daily loss from now on.
+func (u *RiskUseCase) Reset(ctx context.Context) (Status, error) {
+	status, err := u.Evaluate(ctx)
+	if err != nil {
+		return Status{}, err
+	}
+	if !status.Halted {
+		return Status{}, customerror.New(http.StatusConflict, "Risk manager is not halted")
+	}
+
+	state, err := u.Repository.GetState(ctx)
+	if err != nil {
+		return Status{}, err
+	}
+	if err := u.clear(ctx, &state, status.Equity, "Reset manually"); err != nil {
+		return Status{}, err
+	}
+	now := u.Now()
+	state.ResetAt = &now
+	if err := u.Repository.SaveState(ctx, state); err != nil {
+		return Status{}, err
+	}
+	if err := u.Repository.ResetEquityPeak(ctx, status.Equity); err != nil {
+		return Status{}, err
+	}
+	return u.Evaluate(ctx)
+}
+
+// Events returns the last trips and resets, newest first.
+func (u *RiskUseCase) Events(ctx context.Context, limit int) ([]entities.RiskEvent, error) {
+	return u.Repository.ListEvents(ctx, limit)
+}
+
+// sum adds up the balance of the accounts, which must share their currency.
+func sum(accounts []entities.Account) (string, decimal.Decimal, error) {
+	var currencies []string
+	balance := decimal.Zero
+	for _, account := range accounts {
+		if !slices.Contains(currencies, account.Currency) {
+			currencies = append(currencies, account.Currency)
+		}
+		balance = balance.Add(account.Amount)
+	}
+	if len(currencies) > 1 {
+		return "", decimal.Zero, customerror.New(http.StatusConflict,
+			fmt.Sprintf("Live accounts are in %s, the risk manager needs a single currency", strings.Join(currencies, ", ")))
+	}
+	if len(currencies) == 0 {
+		return "", balance, nil
+	}
+	return currencies[0], balance, nil
+}
+
+// mark returns what the positions invested and their unrealized PnL at the
+// last price. Pending entries have no fill yet and count at their cost.
+func (u *RiskUseCase) mark(ctx context.Context, positions []entities.Signal) (decimal.Decimal, decimal.Decimal, error) {
+	prices := map[string]decimal.Decimal{}
+	exposure, unrealized := decimal.Zero, decimal.Zero
+	for _, signal := range positions {
+		for _, order := range signal.Orders {
+			exposure = exposure.Add(order.InvestedAmount)
+			if signal.Status == entities.Pending {
+				continue
+			}
+			price, ok := prices[signal.Symbol]
+			if !ok {
+				tickers, err := u.Broker.ListTickerPrices(ctx, signal.Symbol)
+				if err != nil || len(tickers) == 0 {
+					return decimal.Zero, decimal.Zero, fmt.Errorf("can't get the price of %s to mark signal %d: %v", signal.Symbol, signal.ID, err)
+				}
+				price = decimal.NewFromFloat(tickers[0].Price)
+				prices[signal.Symbol] = price
+			}
+			pnl := price.Sub(order.EntryPrice).Mul(order.Quantity)
+			if signal.Side == entities.Short {
+				pnl = pnl.Neg()
+			}
+			unrealized = unrealized.Add(pnl)
+		}
+	}
+	return exposure, unrealized, nil
+}
+
+func (u *RiskUseCase) breach(status Status) (entities.RiskRule, string) {
+	if u.Limits.MaxDrawdownPct.IsPositive() && status.DrawdownPct.GreaterThanOrEqual(u.Limits.MaxDrawdownPct) {
+		return entities.DrawdownRule, fmt.Sprintf("drawdown of %s%% from the equity peak of %s reached the limit of %s%%",
+			status.DrawdownPct.StringFixed(2), status.EquityPeak.StringFixed(2), u.Limits.MaxDrawdownPct)
+	}
+	loss := status.DailyPnLPct.Neg()
+	if u.Limits.MaxDailyLossPct.IsPositive() && loss.GreaterThanOrEqual(u.Limits.MaxDailyLossPct) {
+		return entities.DailyLossRule, fmt.Sprintf("daily loss of %s%% reached the limit of %s%%",
+			loss.StringFixed(2), u.Limits.MaxDailyLossPct)
+	}
+	return "", ""
+}
+
+func (u *RiskUseCase) trip(ctx context.Context, state *entities.RiskState, rule entities.RiskRule, reason string, equity decimal.Decimal) error {
+	now := u.Now()
+	state.Halted = true
+	state.Rule = rule
+	state.Reason = reason
+	state.TrippedAt = &now
+
+	log.Printf("Risk breaker %s tripped: %s", rule, reason)
+	u.Metrics.IncrementCounter(risk_trips_total, map[string]string{"rule": string(rule)})
+	return u.Repository.AddEvent(ctx, entities.RiskEvent{
+		Type:      entities.RiskTrip,
+		Rule:      rule,
+		Reason:    reason,
+		Equity:    equity,
+		CreatedAt: now,
+	})
+}
+
+func (u *RiskUseCase) clear(ctx context.Context, state *entities.RiskState, equity decimal.Decimal, reason string) error {
+	rule := state.Rule
+	state.Halted = false
+	state.Rule = ""
+	state.Reason = ""
+	state.TrippedAt = nil
+
+	log.Printf("Risk breaker %s cleared: %s", rule, reason)
+	return u.Repository.AddEvent(ctx, entities.RiskEvent{
+		Type:      entities.RiskReset,
+		Rule:      rule,
+		Reason:    reason,
+		Equity:    equity,
+		CreatedAt: u.Now(),
+	})
+}
+
+func (u *RiskUseCase) status(state entities.RiskState, equity, exposure, pnl decimal.Decimal) Status {
+	status := Status{
+		Halted:     state.Halted,
+		Rule:       state.Rule,
+		Reason:     state.Reason,
+		TrippedAt:  state.TrippedAt,
+		Flatten:    state.Halted && u.Limits.FlattenOnTrip,
+		Equity:     equity,
+		EquityPeak: state.EquityPeak,
+		DailyPnL:   pnl,
+		Exposure:   exposure,
+		Limits:     u.Limits,
+	}
+	if state.EquityPeak.IsPositive() {
+		status.DrawdownPct = state.EquityPeak.Sub(equity).Div(state.EquityPeak).Mul(hundred)
+	}
+	// The daily PnL is measured against the equity at the start of the day.
+	if start := equity.Sub(pnl); start.IsPositive() {
+		status.DailyPnLPct = pnl.Div(start).Mul(hundred)
+	}
+	if equity.IsPositive() {
+		status.ExposurePct = exposure.Div(equity).Mul(hundred)
+	}
+	return status
+}
+
+func (u *RiskUseCase) publish(status Status) {
+	halted := 0.0
+	if status.Halted {
+		halted = 1
+	}
+	u.Metrics.SetGauge(risk_halted, nil, halted)
+	u.Metrics.SetGauge(risk_equity, nil, status.Equity.InexactFloat64())
+	u.Metrics.SetGauge(risk_drawdown_pct, nil, status.DrawdownPct.InexactFloat64())
+	u.Metrics.SetGauge(risk_daily_pnl_pct, nil, status.DailyPnLPct.InexactFloat64())
+	u.Metrics.SetGauge(risk_exposure_pct, nil, status.ExposurePct.InexactFloat64())
+}
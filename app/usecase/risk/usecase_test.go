@@ -0,0 +1,281 @@
+package usecase_test
+
+import (
+	"context"
+	"go-trade-bot/app/entities"
+	usecase "go-trade-bot/app/usecase/risk"
+	"go-trade-bot/app/usecase/risk/mocks"
+	"go-trade-bot/internal/broker"
+	"go-trade-bot/internal/configuration"
+	"go-trade-bot/internal/customerror"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var now = time.Date(2024, 3, 10, 15, 0, 0, 0, time.UTC)
+
+func d(value float64) decimal.Decimal {
+	return decimal.NewFromFloat(value)
+}
+
+func newUseCase(limits configuration.Risk) (*usecase.RiskUseCase, *mocks.RiskRepository) {
+	repo := new(mocks.RiskRepository)
+	metrics := new(mocks.Metrics)
+	metrics.On("SetGauge", mock.Anything, mock.Anything, mock.Anything).Maybe()
+	metrics.On("IncrementCounter", mock.Anything, mock.Anything).Maybe()
+
+	prices := new(mocks.Broker)
+	prices.On("ListTickerPrices", mock.Anything, "BTCUSDT").Return([]broker.Ticker{{Symbol: "BTCUSDT", Price: 100}}, nil).Maybe()
+
+	uc := usecase.NewRiskUseCase(&configuration.Configuration{Risk: limits}, repo, prices, metrics)
+	uc.Now = func() time.Time { return now }
+	return uc, repo
+}
+
+// books sets the figures the repository reports: the balance of the live
+// accounts, what the open signals invested at the last price and the PnL
+// realized today.
+func books(repo *mocks.RiskRepository, balance, exposure, pnl float64) {
+	repo.On("LiveAccounts", mock.Anything).Return([]entities.Account{{ID: 1, Amount: d(balance), Currency: "USDT"}}, nil)
+	var positions []entities.Signal
+	if exposure > 0 {
+		positions = append(positions, entities.Signal{ID: 1, Symbol: "BTCUSDT", Status: entities.Open, Orders: []entities.Order{
+			{EntryPrice: d(100), Quantity: d(exposure / 100), InvestedAmount: d(exposure)},
+		}})
+	}
+	repo.On("Positions", mock.Anything).Return(positions, nil)
+	repo.On("RealizedPnL", mock.Anything, mock.Anything).Return(d(pnl), nil)
+}
+
+func TestRiskUseCase_Evaluate(t *testing.T) {
+	t.Run("should raise the equity peak", func(t *testing.T) {
+		uc, repo := newUseCase(configuration.Risk{MaxDrawdownPct: 20})
+		books(repo, 900, 300, 0)
+		repo.On("GetState", mock.Anything).Return(entities.RiskState{ID: 1, EquityPeak: d(1000)}, nil)
+		repo.On("RaiseEquityPeak", mock.Anything, mock.MatchedBy(func(equity decimal.Decimal) bool {
+			return equity.Equal(d(1200))
+		})).Return(d(1200), nil).Once()
+
+		status, err := uc.Evaluate(context.Background())
+		require.NoError(t, err)
+		assert.False(t, status.Halted)
+		assert.Equal(t, "USDT", status.Currency)
+		assert.Equal(t, "1200", status.Equity.String())
+		assert.Equal(t, "1200", status.EquityPeak.String())
+		assert.Equal(t, "25", status.ExposurePct.String())
+		repo.AssertExpectations(t)
+		repo.AssertNotCalled(t, "SaveState", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should mark open positions to the last price", func(t *testing.T) {
+		uc, repo := newUseCase(configuration.Risk{})
+		prices := new(mocks.Broker)
+		uc.Broker = prices
+		repo.On("GetState", mock.Anything).Return(entities.RiskState{ID: 1, EquityPeak: d(2000)}, nil)
+		repo.On("LiveAccounts", mock.Anything).Return([]entities.Account{
+			{ID: 1, Amount: d(600), Currency: "USDT"},
+			{ID: 2, Amount: d(400), Currency: "USDT"},
+		}, nil)
+		repo.On("Positions", mock.Anything).Return([]entities.Signal{
+			{ID: 1, Symbol: "BTCUSDT", Status: entities.Open, Side: entities.Long, Orders: []entities.Order{
+				{EntryPrice: d(100), Quantity: d(2), InvestedAmount: d(200)},
+			}},
+			{ID: 2, Symbol: "ETHUSDT", Status: entities.Closing, Side: entities.Short, Market: entities.Futures, Orders: []entities.Order{
+				{EntryPrice: d(50), Quantity: d(4), InvestedAmount: d(100), Leverage: 2},
+			}},
+			{ID: 3, Symbol: "SOLUSDT", Status: entities.Pending, Orders: []entities.Order{
+				{EntryPrice: d(150), InvestedAmount: d(30)},
+			}},
+		}, nil)
+		repo.On("RealizedPnL", mock.Anything, mock.Anything).Return(d(0), nil)
+		prices.On("ListTickerPrices", mock.Anything, "BTCUSDT").Return([]broker.Ticker{{Symbol: "BTCUSDT", Price: 110}}, nil).Once()
+		prices.On("ListTickerPrices", mock.Anything, "ETHUSDT").Return([]broker.Ticker{{Symbol: "ETHUSDT", Price: 45}}, nil).Once()
+
+		status, err := uc.Evaluate(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, "40", status.UnrealizedPnL.String())
+		assert.Equal(t, "330", status.Exposure.String())
+		assert.Equal(t, "1370", status.Equity.String())
+		prices.AssertExpectations(t)
+		prices.AssertNotCalled(t, "ListTickerPrices", mock.Anything, "SOLUSDT")
+	})
+
+	t.Run("should refuse live accounts in several currencies", func(t *testing.T) {
+		uc, repo := newUseCase(configuration.Risk{})
+		repo.On("GetState", mock.Anything).Return(entities.RiskState{ID: 1}, nil)
+		repo.On("LiveAccounts", mock.Anything).Return([]entities.Account{
+			{ID: 1, Amount: d(1000), Currency: "USDT"},
+			{ID: 2, Amount: d(1), Currency: "BTC"},
+		}, nil)
+
+		_, err := uc.Evaluate(context.Background())
+		assert.Equal(t, customerror.New(http.StatusConflict, "Live accounts are in USDT, BTC, the risk manager needs a single currency"), err)
+	})
+
+	t.Run("should trip on the drawdown from the equity peak", func(t *testing.T) {
+		uc, repo := newUseCase(configuration.Risk{MaxDrawdownPct: 20})
+		books(repo, 800, 0, 0)
+		repo.On("GetState", mock.Anything).Return(entities.RiskState{ID: 1, EquityPeak: d(1000)}, nil)
+		repo.On("AddEvent", mock.Anything, mock.MatchedBy(func(e entities.RiskEvent) bool {
+			return e.Type == entities.RiskTrip && e.Rule == entities.DrawdownRule && e.Equity.Equal(d(800))
+		})).Return(nil).Once()
+		repo.On("SaveState", mock.Anything, mock.MatchedBy(func(s entities.RiskState) bool {
+			return s.Halted && s.Rule == entities.DrawdownRule
+		})).Return(nil).Once()
+
+		status, err := uc.Evaluate(context.Background())
+		require.NoError(t, err)
+		assert.True(t, status.Halted)
+		assert.Equal(t, "drawdown of 20.00% from the equity peak of 1000.00 reached the limit of 20%", status.Reason)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("should trip on the daily loss", func(t *testing.T) {
+		uc, repo := newUseCase(configuration.Risk{MaxDailyLossPct: 5, FlattenOnTrip: true})
+		books(repo, 940, 0, -60)
+		repo.On("GetState", mock.Anything).Return(entities.RiskState{ID: 1, EquityPeak: d(1000)}, nil)
+		repo.On("AddEvent", mock.Anything, mock.Anything).Return(nil).Once()
+		repo.On("SaveState", mock.Anything, mock.Anything).Return(nil).Once()
+
+		status, err := uc.Evaluate(context.Background())
+		require.NoError(t, err)
+		assert.True(t, status.Halted)
+		assert.True(t, status.Flatten)
+		assert.Equal(t, entities.DailyLossRule, status.Rule)
+		assert.Equal(t, "daily loss of 6.00% reached the limit of 5%", status.Reason)
+	})
+
+	t.Run("should clear a daily loss halt on the next day", func(t *testing.T) {
+		uc, repo := newUseCase(configuration.Risk{MaxDailyLossPct: 5})
+		books(repo, 940, 0, 0)
+		yesterday := now.Add(-24 * time.Hour)
+		repo.On("GetState", mock.Anything).Return(entities.RiskState{
+			ID: 1, Halted: true, Rule: entities.DailyLossRule, TrippedAt: &yesterday, EquityPeak: d(1000),
+		}, nil)
+		repo.On("AddEvent", mock.Anything, mock.MatchedBy(func(e entities.RiskEvent) bool {
+			return e.Type == entities.RiskReset && e.Rule == entities.DailyLossRule
+		})).Return(nil).Once()
+		repo.On("SaveState", mock.Anything, mock.MatchedBy(func(s entities.RiskState) bool {
+			return !s.Halted && s.TrippedAt == nil
+		})).Return(nil).Once()
+
+		status, err := uc.Evaluate(context.Background())
+		require.NoError(t, err)
+		assert.False(t, status.Halted)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("should keep a drawdown halt on the next day", func(t *testing.T) {
+		uc, repo := newUseCase(configuration.Risk{MaxDrawdownPct: 20})
+		books(repo, 800, 0, 0)
+		yesterday := now.Add(-24 * time.Hour)
+		repo.On("GetState", mock.Anything).Return(entities.RiskState{
+			ID: 1, Halted: true, Rule: entities.DrawdownRule, TrippedAt: &yesterday, EquityPeak: d(1000),
+		}, nil)
+
+		status, err := uc.Evaluate(context.Background())
+		require.NoError(t, err)
+		assert.True(t, status.Halted)
+		repo.AssertNotCalled(t, "AddEvent", mock.Anything, mock.Anything)
+	})
+}
+
+func TestRiskUseCase_Allow(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should refuse entries while halted", func(t *testing.T) {
+		uc, repo := newUseCase(configuration.Risk{MaxDrawdownPct: 20})
+		books(repo, 800, 0, 0)
+		repo.On("GetState", mock.Anything).Return(entities.RiskState{
+			ID: 1, Halted: true, Rule: entities.DrawdownRule, Reason: "drawdown of 20.00%", EquityPeak: d(1000),
+		}, nil)
+
+		err := uc.Allow(ctx, "BTCUSDT", 1, d(100))
+		assert.EqualError(t, err, "entries are halted by the drawdown breaker: drawdown of 20.00%")
+	})
+
+	t.Run("should refuse entries over the positions of the symbol", func(t *testing.T) {
+		uc, repo := newUseCase(configuration.Risk{MaxPositionsPerSymbol: 2, MaxPositionsPerStrategy: 5})
+		books(repo, 1000, 0, 0)
+		repo.On("GetState", mock.Anything).Return(entities.RiskState{ID: 1, EquityPeak: d(1000)}, nil)
+		repo.On("CountOpenSignals", mock.Anything, "BTCUSDT", uint(0)).Return(int64(2), nil).Once()
+
+		err := uc.Allow(ctx, "BTCUSDT", 1, d(100))
+		assert.EqualError(t, err, "BTCUSDT already has 2 open positions, the limit is 2")
+	})
+
+	t.Run("should refuse entries over the positions of the strategy", func(t *testing.T) {
+		uc, repo := newUseCase(configuration.Risk{MaxPositionsPerSymbol: 2, MaxPositionsPerStrategy: 5})
+		books(repo, 1000, 0, 0)
+		repo.On("GetState", mock.Anything).Return(entities.RiskState{ID: 1, EquityPeak: d(1000)}, nil)
+		repo.On("CountOpenSignals", mock.Anything, "BTCUSDT", uint(0)).Return(int64(1), nil).Once()
+		repo.On("CountOpenSignals", mock.Anything, "", uint(1)).Return(int64(5), nil).Once()
+
+		err := uc.Allow(ctx, "BTCUSDT", 1, d(100))
+		assert.EqualError(t, err, "strategy 1 already has 5 open positions, the limit is 5")
+	})
+
+	t.Run("should refuse entries over the exposure limit", func(t *testing.T) {
+		uc, repo := newUseCase(configuration.Risk{MaxExposurePct: 50})
+		books(repo, 600, 400, 0)
+		repo.On("GetState", mock.Anything).Return(entities.RiskState{ID: 1, EquityPeak: d(1000)}, nil)
+
+		assert.NoError(t, uc.Allow(ctx, "BTCUSDT", 1, d(100)))
+
+		err := uc.Allow(ctx, "BTCUSDT", 1, d(150))
+		assert.EqualError(t, err, "entry of 150.00 on BTCUSDT would raise the exposure to 550.00, above the limit of 500.00")
+	})
+
+	t.Run("should allow everything without limits", func(t *testing.T) {
+		uc, repo := newUseCase(configuration.Risk{})
+		books(repo, 100, 900, -500)
+		repo.On("GetState", mock.Anything).Return(entities.RiskState{ID: 1, EquityPeak: d(5000)}, nil)
+
+		assert.NoError(t, uc.Allow(ctx, "BTCUSDT", 1, d(100)))
+		repo.AssertNotCalled(t, "CountOpenSignals", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestRiskUseCase_Reset(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should clear the breaker and rebase the peak", func(t *testing.T) {
+		uc, repo := newUseCase(configuration.Risk{MaxDrawdownPct: 20})
+		books(repo, 800, 0, 0)
+		tripped := now.Add(-time.Hour)
+		repo.On("GetState", mock.Anything).Return(entities.RiskState{
+			ID: 1, Halted: true, Rule: entities.DrawdownRule, TrippedAt: &tripped, EquityPeak: d(1000),
+		}, nil).Twice()
+		repo.On("AddEvent", mock.Anything, mock.MatchedBy(func(e entities.RiskEvent) bool {
+			return e.Type == entities.RiskReset && e.Rule == entities.DrawdownRule && e.Reason == "Reset manually"
+		})).Return(nil).Once()
+		repo.On("SaveState", mock.Anything, mock.MatchedBy(func(s entities.RiskState) bool {
+			return !s.Halted && s.ResetAt.Equal(now)
+		})).Return(nil).Once()
+		repo.On("ResetEquityPeak", mock.Anything, mock.MatchedBy(func(equity decimal.Decimal) bool {
+			return equity.Equal(d(800))
+		})).Return(nil).Once()
+		repo.On("GetState", mock.Anything).Return(entities.RiskState{ID: 1, EquityPeak: d(800), ResetAt: &now}, nil).Once()
+
+		status, err := uc.Reset(ctx)
+		require.NoError(t, err)
+		assert.False(t, status.Halted)
+		assert.True(t, status.DrawdownPct.IsZero())
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("should answer conflict when nothing tripped", func(t *testing.T) {
+		uc, repo := newUseCase(configuration.Risk{MaxDrawdownPct: 20})
+		books(repo, 1000, 0, 0)
+		repo.On("GetState", mock.Anything).Return(entities.RiskState{ID: 1, EquityPeak: d(1000)}, nil)
+
+		_, err := uc.Reset(ctx)
+		assert.Equal(t, customerror.New(http.StatusConflict, "Risk manager is not halted"), err)
+	})
+}
@@ -0,0 +1,183 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"go-trade-bot/internal/broker"
+	"go-trade-bot/internal/fees"
+	"log"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// Execution is the outcome of an entry or exit, either simulated or
+// reported by the exchange. Amount is the quote amount spent or received
+// and Fee is always expressed in the quote asset. Only futures entries have
+// a LiquidationPrice.
+type Execution struct {
+	BrokerOrderID    string
+	Price            decimal.Decimal
+	Quantity         decimal.Decimal
+	ExecutedQty      decimal.Decimal
+	Amount           decimal.Decimal
+	Fee              decimal.Decimal
+	LiquidationPrice decimal.Decimal
+}
+
+type OrderExecutor interface {
+	Buy(ctx context.Context, symbol string, orderType broker.OrderType, price decimal.Decimal, amount decimal.Decimal, rates fees.Rates) (Execution, error)
+	Sell(ctx context.Context, symbol string, price decimal.Decimal, quantity decimal.Decimal, rates fees.Rates) (Execution, error)
+}
+
+type OrderBroker interface {
+	PlaceOrder(ctx context.Context, order broker.OrderRequest) (broker.OrderResult, error)
+}
+
+// simulatedExecutor fills every order at the requested price and charges
+// the fee model, limit orders as makers and market orders as takers.
+type simulatedExecutor struct{}
+
+func NewSimulatedExecutor() OrderExecutor {
+	return simulatedExecutor{}
+}
+
+func (e simulatedExecutor) Buy(ctx context.Context, symbol string, orderType broker.OrderType, price decimal.Decimal, amount decimal.Decimal, rates fees.Rates) (Execution, error) {
+	liquidity := fees.Taker
+	if orderType == broker.Limit {
+		liquidity = fees.Maker
+	}
+	quantity := amount.Div(price)
+	return Execution{
+		Price:       price,
+		Quantity:    quantity,
+		ExecutedQty: quantity,
+		Amount:      amount,
+		Fee:         rates.Fee(amount, liquidity),
+	}, nil
+}
+
+func (e simulatedExecutor) Sell(ctx context.Context, symbol string, price decimal.Decimal, quantity decimal.Decimal, rates fees.Rates) (Execution, error) {
+	amount := price.Mul(quantity)
+	return Execution{
+		Price:       price,
+		Quantity:    quantity,
+		ExecutedQty: quantity,
+		Amount:      amount,
+		Fee:         rates.Fee(amount, fees.Taker),
+	}, nil
+}
+
+// brokerExecutor sends orders to the exchange and reports the real fills.
+// Limit orders are immediate-or-cancel, so every order takes liquidity.
+type brokerExecutor struct {
+	broker OrderBroker
+}
+
+func NewBrokerExecutor(b OrderBroker) OrderExecutor {
+	return brokerExecutor{broker: b}
+}
+
+func (e brokerExecutor) Buy(ctx context.Context, symbol string, orderType broker.OrderType, price decimal.Decimal, amount decimal.Decimal, rates fees.Rates) (Execution, error) {
+	request := broker.OrderRequest{
+		Symbol:  symbol,
+		Side:    broker.Buy,
+		Type:    broker.Market,
+		FeeRate: rates.Taker.InexactFloat64(),
+	}
+	if orderType == broker.Limit {
+		request.Type = broker.Limit
+		request.Price = price.InexactFloat64()
+		request.Quantity = amount.Div(price).InexactFloat64()
+	} else {
+		request.QuoteQuantity = amount.InexactFloat64()
+	}
+
+	result, err := e.broker.PlaceOrder(ctx, request)
+	if err != nil {
+		return Execution{}, fmt.Errorf("failed to place buy order for %s: %w", symbol, err)
+	}
+	if result.ExecutedQty == 0 {
+		return Execution{}, fmt.Errorf("buy order %s for %s was not filled", result.OrderID, symbol)
+	}
+
+	execution := toExecution(symbol, result, rates)
+	// Commission charged in the base asset reduces what we actually hold.
+	execution.Quantity = execution.Quantity.Sub(baseCommission(symbol, result.Fills))
+	return execution, nil
+}
+
+// Sell always closes with a market order so a position is never left half
+// closed by an unfilled limit, the signal price is only informative.
+func (e brokerExecutor) Sell(ctx context.Context, symbol string, price decimal.Decimal, quantity decimal.Decimal, rates fees.Rates) (Execution, error) {
+	result, err := e.broker.PlaceOrder(ctx, broker.OrderRequest{
+		Symbol:   symbol,
+		Side:     broker.Sell,
+		Type:     broker.Market,
+		Quantity: quantity.InexactFloat64(),
+		FeeRate:  rates.Taker.InexactFloat64(),
+	})
+	if err != nil {
+		return Execution{}, fmt.Errorf("failed to place sell order for %s: %w", symbol, err)
+	}
+	if result.ExecutedQty == 0 {
+		return Execution{}, fmt.Errorf("sell order %s for %s was not filled", result.OrderID, symbol)
+	}
+	return toExecution(symbol, result, rates), nil
+}
+
+func toExecution(symbol string, result broker.OrderResult, rates fees.Rates) Execution {
+	amount := decimal.NewFromFloat(result.QuoteQuantity)
+	return Execution{
+		BrokerOrderID: result.OrderID,
+		Price:         decimal.NewFromFloat(result.AveragePrice()),
+		Quantity:      decimal.NewFromFloat(result.ExecutedQty),
+		ExecutedQty:   decimal.NewFromFloat(result.ExecutedQty),
+		Amount:        amount,
+		Fee:           reconcileFee(symbol, result, rates.Fee(amount, fees.Taker), rates),
+	}
+}
+
+// feeTolerance is how far, as a fraction, reported fees may stray from the
+// fee model before it is logged.
+var feeTolerance = decimal.New(1, -1)
+
+// reconcileFee returns the fee reported by the exchange in the quote asset.
+// Commissions charged in a third asset (e.g. BNB) can't be priced here, the
+// fee model estimates them instead. Fees off the expected ones are logged so
+// the configured model can be corrected.
+func reconcileFee(symbol string, result broker.OrderResult, expected decimal.Decimal, rates fees.Rates) decimal.Decimal {
+	fee := decimal.Zero
+	for _, f := range result.Fills {
+		commission := decimal.NewFromFloat(f.Commission)
+		price := decimal.NewFromFloat(f.Price)
+		switch {
+		case f.CommissionAsset == "" || f.Commission == 0:
+			continue
+		case strings.HasSuffix(symbol, f.CommissionAsset):
+			fee = fee.Add(commission)
+		case strings.HasPrefix(symbol, f.CommissionAsset):
+			fee = fee.Add(commission.Mul(price))
+		default:
+			fee = fee.Add(rates.Fee(price.Mul(decimal.NewFromFloat(f.Quantity)), fees.Taker))
+			if f.CommissionAsset != rates.DiscountAsset {
+				log.Printf("Estimating commission of %f %s for %s with the fee model", f.Commission, f.CommissionAsset, symbol)
+			}
+		}
+	}
+
+	if fee.Sub(expected).Abs().GreaterThan(expected.Mul(feeTolerance)) {
+		log.Printf("Fee of %s for order %s of %s differs from the %s expected by the fee model", fee, result.OrderID, symbol, expected)
+	}
+	return fee
+}
+
+func baseCommission(symbol string, fills []broker.Fill) decimal.Decimal {
+	commission := decimal.Zero
+	for _, f := range fills {
+		if f.CommissionAsset != "" && strings.HasPrefix(symbol, f.CommissionAsset) {
+			commission = commission.Add(decimal.NewFromFloat(f.Commission))
+		}
+	}
+	return commission
+}
@@ -0,0 +1,266 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"go-trade-bot/app/entities"
+	"go-trade-bot/internal/broker"
+	"go-trade-bot/internal/fees"
+	"log"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// FuturesOrder opens or closes a futures position of Quantity contracts.
+// Leverage and MarginType are only applied when opening, LiquidationPrice
+// is the one of the position being closed.
+type FuturesOrder struct {
+	Symbol           string
+	Side             entities.PositionSide
+	OrderType        broker.OrderType
+	Price            decimal.Decimal
+	Quantity         decimal.Decimal
+	Leverage         int
+	MarginType       entities.MarginType
+	LiquidationPrice decimal.Decimal
+}
+
+// FuturesExecutor fills futures positions. Executions report the notional
+// of the position as Amount, the margin is left to the caller.
+type FuturesExecutor interface {
+	Open(ctx context.Context, order FuturesOrder, rates fees.Rates) (Execution, error)
+	Close(ctx context.Context, order FuturesOrder, rates fees.Rates) (Execution, error)
+	// Funding returns what the position paid since it opened, negative when
+	// it received funding.
+	Funding(ctx context.Context, signal entities.Signal) (decimal.Decimal, error)
+}
+
+type FuturesBroker interface {
+	PlaceOrder(ctx context.Context, order broker.OrderRequest) (broker.OrderResult, error)
+	GetSymbolFilters(ctx context.Context, symbol string) (broker.SymbolFilters, error)
+	SetLeverage(ctx context.Context, symbol string, leverage int) error
+	SetMarginType(ctx context.Context, symbol string, marginType broker.MarginType) error
+	GetPosition(ctx context.Context, symbol string) (broker.Position, error)
+	ListFundingRates(ctx context.Context, symbol string, start time.Time) ([]broker.FundingRate, error)
+	FundingFees(ctx context.Context, symbol string, start time.Time) (float64, error)
+}
+
+type FundingRates interface {
+	ListFundingRates(ctx context.Context, symbol string, start time.Time) ([]broker.FundingRate, error)
+}
+
+// maintenanceMarginRate is the maintenance margin of the lowest bracket of
+// Binance USD-M futures, used to estimate liquidation prices.
+var maintenanceMarginRate = decimal.New(4, -3)
+
+// simulatedFuturesExecutor fills positions at the requested price like the
+// spot simulator and charges the funding settled by the exchange while the
+// position was open, none without a source of funding rates.
+type simulatedFuturesExecutor struct {
+	rates FundingRates
+}
+
+func NewSimulatedFuturesExecutor(r FundingRates) FuturesExecutor {
+	return simulatedFuturesExecutor{rates: r}
+}
+
+func (e simulatedFuturesExecutor) Open(ctx context.Context, order FuturesOrder, rates fees.Rates) (Execution, error) {
+	liquidity := fees.Taker
+	if order.OrderType == broker.Limit {
+		liquidity = fees.Maker
+	}
+	amount := order.Price.Mul(order.Quantity)
+	return Execution{
+		Price:            order.Price,
+		Quantity:         order.Quantity,
+		ExecutedQty:      order.Quantity,
+		Amount:           amount,
+		Fee:              rates.Fee(amount, liquidity),
+		LiquidationPrice: liquidationPrice(order),
+	}, nil
+}
+
+func (e simulatedFuturesExecutor) Close(ctx context.Context, order FuturesOrder, rates fees.Rates) (Execution, error) {
+	amount := order.Price.Mul(order.Quantity)
+	return Execution{
+		Price:       order.Price,
+		Quantity:    order.Quantity,
+		ExecutedQty: order.Quantity,
+		Amount:      amount,
+		Fee:         rates.Fee(amount, fees.Taker),
+	}, nil
+}
+
+// Funding charges the notional at entry with every funding rate settled
+// since the signal opened. Longs pay positive rates and shorts receive them.
+func (e simulatedFuturesExecutor) Funding(ctx context.Context, signal entities.Signal) (decimal.Decimal, error) {
+	if e.rates == nil {
+		return decimal.Zero, nil
+	}
+	fundings, err := e.rates.ListFundingRates(ctx, signal.Symbol, signal.CreatedAt)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("failed to get the funding rates of %s: %w", signal.Symbol, err)
+	}
+
+	order := signal.Orders[0]
+	notional := order.EntryPrice.Mul(order.Quantity)
+	paid := decimal.Zero
+	for _, f := range fundings {
+		paid = paid.Add(notional.Mul(decimal.NewFromFloat(f.Rate)))
+	}
+	return paid.Mul(direction(signal.Side)), nil
+}
+
+// liquidationPrice estimates where an isolated position loses its margin
+// down to the maintenance margin. Cross positions are backed by the whole
+// account, they have no price of their own.
+func liquidationPrice(order FuturesOrder) decimal.Decimal {
+	if order.MarginType == entities.Cross || order.Leverage <= 0 {
+		return decimal.Zero
+	}
+	move := decimal.NewFromInt(1).Div(decimal.NewFromInt(int64(order.Leverage))).Sub(maintenanceMarginRate)
+	return order.Price.Mul(decimal.NewFromInt(1).Sub(move.Mul(direction(order.Side))))
+}
+
+// futuresBrokerExecutor trades on the futures exchange. Fills of futures
+// orders carry no commission, fees are charged with the fee model.
+type futuresBrokerExecutor struct {
+	broker FuturesBroker
+}
+
+func NewFuturesBrokerExecutor(b FuturesBroker) FuturesExecutor {
+	return futuresBrokerExecutor{broker: b}
+}
+
+// Open sets the leverage and margin type of the symbol before placing the
+// entry, then reads the liquidation price back from the position.
+func (e futuresBrokerExecutor) Open(ctx context.Context, order FuturesOrder, rates fees.Rates) (Execution, error) {
+	if err := e.broker.SetMarginType(ctx, order.Symbol, toBrokerMarginType(order.MarginType)); err != nil {
+		return Execution{}, fmt.Errorf("failed to set the margin type of %s: %w", order.Symbol, err)
+	}
+	if err := e.broker.SetLeverage(ctx, order.Symbol, order.Leverage); err != nil {
+		return Execution{}, fmt.Errorf("failed to set the leverage of %s: %w", order.Symbol, err)
+	}
+
+	request := broker.OrderRequest{
+		Symbol:   order.Symbol,
+		Side:     entrySide(order.Side),
+		Type:     broker.Market,
+		Quantity: order.Quantity.InexactFloat64(),
+	}
+	if order.OrderType == broker.Limit {
+		request.Type = broker.Limit
+		request.Price = order.Price.InexactFloat64()
+	}
+
+	result, err := e.broker.PlaceOrder(ctx, request)
+	if err != nil {
+		return Execution{}, fmt.Errorf("failed to place %s entry for %s: %w", order.Side, order.Symbol, err)
+	}
+	if result.ExecutedQty == 0 {
+		return Execution{}, fmt.Errorf("%s entry %s for %s was not filled", order.Side, result.OrderID, order.Symbol)
+	}
+
+	execution := futuresExecution(result, rates)
+	position, err := e.broker.GetPosition(ctx, order.Symbol)
+	if err != nil {
+		log.Printf("Failed to get the position of %s after its entry: %v", order.Symbol, err)
+		execution.LiquidationPrice = liquidationPrice(order)
+		return execution, nil
+	}
+	execution.LiquidationPrice = decimal.NewFromFloat(position.LiquidationPrice)
+	return execution, nil
+}
+
+// Close exits with a reduce-only market order, so it can never flip the
+// position to the other side. A position the exchange already liquidated
+// has nothing left to reduce, it is booked at its liquidation price.
+func (e futuresBrokerExecutor) Close(ctx context.Context, order FuturesOrder, rates fees.Rates) (Execution, error) {
+	position, err := e.broker.GetPosition(ctx, order.Symbol)
+	if err != nil {
+		return Execution{}, fmt.Errorf("failed to get the position of %s before its exit: %w", order.Symbol, err)
+	}
+	if position.Quantity == 0 {
+		if !order.LiquidationPrice.IsPositive() {
+			return Execution{}, fmt.Errorf("%s position of %s is flat and has no liquidation price to book", order.Side, order.Symbol)
+		}
+		amount := order.LiquidationPrice.Mul(order.Quantity)
+		return Execution{
+			BrokerOrderID: "liquidation",
+			Price:         order.LiquidationPrice,
+			Quantity:      order.Quantity,
+			ExecutedQty:   order.Quantity,
+			Amount:        amount,
+			Fee:           rates.Fee(amount, fees.Taker),
+		}, nil
+	}
+
+	result, err := e.broker.PlaceOrder(ctx, broker.OrderRequest{
+		Symbol:     order.Symbol,
+		Side:       exitSide(order.Side),
+		Type:       broker.Market,
+		Quantity:   order.Quantity.InexactFloat64(),
+		ReduceOnly: true,
+	})
+	if err != nil {
+		return Execution{}, fmt.Errorf("failed to place %s exit for %s: %w", order.Side, order.Symbol, err)
+	}
+	if result.ExecutedQty == 0 {
+		return Execution{}, fmt.Errorf("%s exit %s for %s was not filled", order.Side, result.OrderID, order.Symbol)
+	}
+	return futuresExecution(result, rates), nil
+}
+
+// Funding returns the funding fees booked by the exchange on the symbol
+// since the signal opened, a live signal holds the whole position of its
+// symbol.
+func (e futuresBrokerExecutor) Funding(ctx context.Context, signal entities.Signal) (decimal.Decimal, error) {
+	paid, err := e.broker.FundingFees(ctx, signal.Symbol, signal.CreatedAt)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("failed to get the funding fees of %s: %w", signal.Symbol, err)
+	}
+	return decimal.NewFromFloat(paid), nil
+}
+
+func futuresExecution(result broker.OrderResult, rates fees.Rates) Execution {
+	amount := decimal.NewFromFloat(result.QuoteQuantity)
+	return Execution{
+		BrokerOrderID: result.OrderID,
+		Price:         decimal.NewFromFloat(result.AveragePrice()),
+		Quantity:      decimal.NewFromFloat(result.ExecutedQty),
+		ExecutedQty:   decimal.NewFromFloat(result.ExecutedQty),
+		Amount:        amount,
+		Fee:           rates.Fee(amount, fees.Taker),
+	}
+}
+
+// direction is 1 for longs and -1 for shorts, the sign of the PnL of a
+// rising price.
+func direction(side entities.PositionSide) decimal.Decimal {
+	if side == entities.Short {
+		return decimal.NewFromInt(-1)
+	}
+	return decimal.NewFromInt(1)
+}
+
+func entrySide(side entities.PositionSide) broker.OrderSide {
+	if side == entities.Short {
+		return broker.Sell
+	}
+	return broker.Buy
+}
+
+func exitSide(side entities.PositionSide) broker.OrderSide {
+	if side == entities.Short {
+		return broker.Buy
+	}
+	return broker.Sell
+}
+
+func toBrokerMarginType(marginType entities.MarginType) broker.MarginType {
+	if marginType == entities.Cross {
+		return broker.CrossMargin
+	}
+	return broker.IsolatedMargin
+}
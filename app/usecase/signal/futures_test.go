@@ -0,0 +1,338 @@
+package usecase_test
+
+import (
+	"context"
+	"go-trade-bot/app/entities"
+	usecase "go-trade-bot/app/usecase/signal"
+	"go-trade-bot/app/usecase/signal/mocks"
+	"go-trade-bot/internal/broker"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestSignalUseCase_Futures_Entry(t *testing.T) {
+	t.Run("should refuse shorts on spot", func(t *testing.T) {
+		repo := newRepository()
+		signalUC := usecase.NewSignalUseCase(repo, newAccounts(), newBroker(), transactor(), schedule(), newRisk(), nil)
+
+		err := signalUC.GenerateBuySignal(usecase.EntrySignal{Symbol: "BTCUSDT", StrategyID: 1, AccountID: 2, EntryPrice: d(100), Side: entities.Short})
+
+		assert.EqualError(t, err, "short entries of BTCUSDT need the futures market")
+		repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should open a leveraged short reserving its margin", func(t *testing.T) {
+		repo := newRepository()
+		accounts := newAccounts()
+		signalUC := usecase.NewSignalUseCase(repo, accounts, newBroker(), transactor(), schedule(), newRisk(), nil)
+		accounts.On("CanOpenOrder", mock.Anything, int64(2)).Return(true, nil).Once()
+		accounts.On("GetDisponibleAmout", mock.Anything, int64(2)).Return(d(1000), nil).Once()
+		accounts.On("DeductOrder", mock.Anything, int64(2), mock.Anything).Return(nil).Once()
+		repo.On("GetOpenSignals", mock.Anything, "BTCUSDT", uint(1)).Return(entities.Signal{}, nil).Once()
+		repo.On("Create", mock.Anything, mock.MatchedBy(func(s entities.Signal) bool {
+			order := s.Orders[0]
+			return s.Side == entities.Short && s.Market == entities.Futures &&
+				order.InvestedAmount.Equal(d(1000)) && order.Leverage == 5
+		})).Return(created, nil).Once()
+		repo.On("Open", mock.Anything, mock.MatchedBy(func(s entities.Signal) bool {
+			order := s.Orders[0]
+			return order.Quantity.Equal(d(50)) && order.EntryFee.Equal(d(5)) &&
+				order.LiquidationPrice.Equal(d(119.6))
+		})).Return(true, nil).Once()
+
+		err := signalUC.GenerateBuySignal(usecase.EntrySignal{
+			Symbol:     "BTCUSDT",
+			StrategyID: 1,
+			AccountID:  2,
+			EntryPrice: d(100),
+			Side:       entities.Short,
+			Market:     entities.Futures,
+			Leverage:   5,
+			MarginType: entities.Isolated,
+		})
+
+		assert.NoError(t, err)
+		repo.AssertExpectations(t)
+		accounts.AssertExpectations(t)
+	})
+
+	t.Run("should size notional policies before the leverage", func(t *testing.T) {
+		repo := newRepository()
+		accounts := new(mocks.AccountUseCase)
+		signalUC := usecase.NewSignalUseCase(repo, accounts, newBroker(), transactor(), schedule(), newRisk(), nil)
+		accounts.On("GetAccount", int64(2)).Return(entities.Account{ID: 2, Amount: d(8000)}, nil)
+		accounts.On("CanOpenOrder", mock.Anything, int64(2)).Return(true, nil).Once()
+		accounts.On("DeductOrder", mock.Anything, int64(2), worth(20)).Return(nil).Once()
+		repo.On("Exposure", mock.Anything, int64(2)).Return(decimal.Zero, nil).Once()
+		repo.On("GetOpenSignals", mock.Anything, "BTCUSDT", uint(1)).Return(entities.Signal{}, nil).Once()
+		repo.On("Create", mock.Anything, mock.Anything).Return(created, nil).Once()
+		repo.On("Open", mock.Anything, mock.Anything).Return(true, nil).Once()
+
+		err := signalUC.GenerateBuySignal(usecase.EntrySignal{
+			Symbol:     "BTCUSDT",
+			StrategyID: 1,
+			AccountID:  2,
+			EntryPrice: d(100),
+			Market:     entities.Futures,
+			Leverage:   5,
+			Sizing:     usecase.Sizing{Mode: usecase.FixedNotionalSizing, Notional: d(100)},
+		})
+
+		assert.NoError(t, err)
+		accounts.AssertExpectations(t)
+	})
+}
+
+func TestSignalUseCase_Futures_Exit(t *testing.T) {
+	short := func() entities.Signal {
+		return entities.Signal{
+			ID:         1,
+			Symbol:     "BTCUSDT",
+			Status:     entities.Open,
+			StrategyID: 1,
+			AccountID:  2,
+			Side:       entities.Short,
+			Market:     entities.Futures,
+			CreatedAt:  time.Now().Add(-24 * time.Hour),
+			Orders: []entities.Order{{
+				EntryPrice:     d(100),
+				Quantity:       d(50),
+				InvestedAmount: d(1000),
+				MarginType:     entities.Isolated,
+				EntryFee:       d(5),
+				Leverage:       5,
+			}},
+		}
+	}
+
+	t.Run("should gain when a short closes below its entry and receive funding", func(t *testing.T) {
+		repo := newRepository()
+		accounts := newAccounts()
+		futures := newFuturesBroker()
+		signalUC := usecase.NewSignalUseCase(repo, accounts, newBroker(), transactor(), schedule(), newRisk(), futures)
+		futures.On("ListFundingRates", mock.Anything, "BTCUSDT", mock.Anything).Return([]broker.FundingRate{
+			{Symbol: "BTCUSDT", Rate: 0.0001},
+			{Symbol: "BTCUSDT", Rate: 0.0001},
+		}, nil).Once()
+		repo.On("GetOpenSignals", mock.Anything, "BTCUSDT", uint(1)).Return(short(), nil).Once()
+		repo.On("Transition", mock.Anything, uint(1), entities.Open, entities.Closing).Return(true, nil).Once()
+		repo.On("SaveOrder", mock.Anything, mock.Anything).Return(nil).Once()
+		repo.On("Close", mock.Anything, mock.Anything).Return(true, nil).Once()
+		accounts.On("AddOrder", mock.Anything, int64(2), mock.MatchedBy(func(s entities.Signal) bool {
+			order := s.Orders[0]
+			return order.Profit.Equal(d(491.5)) && order.FundingFee.Equal(d(-1))
+		})).Return(nil).Once()
+
+		err := signalUC.GenerateSellSignal(usecase.ExitSignal{Symbol: "BTCUSDT", StrategyID: 1, ExitPrice: d(90)})
+
+		assert.NoError(t, err)
+		accounts.AssertExpectations(t)
+		futures.AssertNotCalled(t, "PlaceOrder", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should lose no more than the margin of isolated positions", func(t *testing.T) {
+		repo := newRepository()
+		accounts := newAccounts()
+		signalUC := usecase.NewSignalUseCase(repo, accounts, newBroker(), transactor(), schedule(), newRisk(), nil)
+		long := short()
+		long.Side = entities.Long
+		long.Orders[0].Quantity = d(100)
+		long.Orders[0].EntryFee = d(10)
+		long.Orders[0].Leverage = 10
+		repo.On("GetOpenSignals", mock.Anything, "BTCUSDT", uint(1)).Return(long, nil).Once()
+		repo.On("Transition", mock.Anything, uint(1), entities.Open, entities.Closing).Return(true, nil).Once()
+		repo.On("SaveOrder", mock.Anything, mock.Anything).Return(nil).Once()
+		repo.On("Close", mock.Anything, mock.Anything).Return(true, nil).Once()
+		accounts.On("AddOrder", mock.Anything, int64(2), worth(-18)).Return(nil).Once()
+
+		err := signalUC.GenerateSellSignal(usecase.ExitSignal{Symbol: "BTCUSDT", StrategyID: 1, ExitPrice: d(80)})
+
+		assert.NoError(t, err)
+		accounts.AssertExpectations(t)
+	})
+}
+
+func TestSignalUseCase_Futures_Live(t *testing.T) {
+	repo := newRepository()
+	accounts := newAccounts()
+	futures := newFuturesBroker()
+	signalUC := usecase.NewSignalUseCase(repo, accounts, newBroker(), transactor(), schedule(), newRisk(), futures)
+
+	futures.On("SetMarginType", mock.Anything, "BTCUSDT", broker.IsolatedMargin).Return(nil).Once()
+	futures.On("SetLeverage", mock.Anything, "BTCUSDT", 5).Return(nil).Once()
+	futures.On("PlaceOrder", mock.Anything, mock.MatchedBy(func(o broker.OrderRequest) bool {
+		return o.Side == broker.Sell && o.Quantity == 50 && !o.ReduceOnly
+	})).Return(broker.OrderResult{OrderID: "7", ExecutedQty: 50, QuoteQuantity: 5000}, nil).Once()
+	futures.On("GetPosition", mock.Anything, "BTCUSDT").Return(broker.Position{Symbol: "BTCUSDT", Quantity: -50, LiquidationPrice: 119.5}, nil).Twice()
+	futures.On("PlaceOrder", mock.Anything, mock.MatchedBy(func(o broker.OrderRequest) bool {
+		return o.Side == broker.Buy && o.Quantity == 50 && o.ReduceOnly
+	})).Return(broker.OrderResult{OrderID: "8", ExecutedQty: 50, QuoteQuantity: 4500}, nil).Once()
+	futures.On("FundingFees", mock.Anything, "BTCUSDT", mock.Anything).Return(2.5, nil).Once()
+
+	var opened entities.Signal
+	accounts.On("CanOpenOrder", mock.Anything, int64(2)).Return(true, nil).Once()
+	accounts.On("GetDisponibleAmout", mock.Anything, int64(2)).Return(d(1000), nil).Once()
+	accounts.On("DeductOrder", mock.Anything, int64(2), mock.Anything).Return(nil).Once()
+	repo.On("GetOpenSignals", mock.Anything, "BTCUSDT", uint(1)).Return(entities.Signal{}, nil).Once()
+	repo.On("HasLiveFutures", mock.Anything, "BTCUSDT").Return(false, nil).Once()
+	repo.On("Create", mock.Anything, mock.Anything).Return(created, nil).Once()
+	repo.On("Open", mock.Anything, mock.Anything).Return(func(ctx context.Context, signal entities.Signal) (bool, error) {
+		opened = signal
+		return true, nil
+	}).Once()
+
+	err := signalUC.GenerateBuySignal(usecase.EntrySignal{
+		Symbol:     "BTCUSDT",
+		StrategyID: 1,
+		AccountID:  2,
+		EntryPrice: d(100),
+		Side:       entities.Short,
+		Market:     entities.Futures,
+		Leverage:   5,
+		MarginType: entities.Isolated,
+		Mode:       entities.Live,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "7", opened.Orders[0].BrokerOrderID)
+	assert.True(t, opened.Orders[0].LiquidationPrice.Equal(d(119.5)))
+
+	repo.On("GetOpenSignals", mock.Anything, "BTCUSDT", uint(1)).Return(opened, nil).Once()
+	repo.On("Transition", mock.Anything, uint(1), entities.Open, entities.Closing).Return(true, nil).Once()
+	repo.On("SaveOrder", mock.Anything, mock.Anything).Return(nil).Once()
+	repo.On("Close", mock.Anything, mock.Anything).Return(true, nil).Once()
+	accounts.On("AddOrder", mock.Anything, int64(2), mock.MatchedBy(func(s entities.Signal) bool {
+		order := s.Orders[0]
+		return order.ExitOrderID == "8" && order.Profit.Equal(d(488)) && order.FundingFee.Equal(d(2.5))
+	})).Return(nil).Once()
+
+	err = signalUC.GenerateSellSignal(usecase.ExitSignal{Symbol: "BTCUSDT", StrategyID: 1, ExitPrice: d(90)})
+	assert.NoError(t, err)
+	futures.AssertExpectations(t)
+	accounts.AssertExpectations(t)
+}
+
+func TestSignalUseCase_Futures_Live_Position(t *testing.T) {
+	t.Run("should refuse a second live signal on the position of the symbol", func(t *testing.T) {
+		repo := newRepository()
+		accounts := newAccounts()
+		futures := newFuturesBroker()
+		signalUC := usecase.NewSignalUseCase(repo, accounts, newBroker(), transactor(), schedule(), newRisk(), futures)
+		accounts.On("CanOpenOrder", mock.Anything, int64(2)).Return(true, nil).Once()
+		repo.On("GetOpenSignals", mock.Anything, "BTCUSDT", uint(3)).Return(entities.Signal{}, nil).Once()
+		repo.On("HasLiveFutures", mock.Anything, "BTCUSDT").Return(true, nil).Once()
+
+		err := signalUC.GenerateBuySignal(usecase.EntrySignal{
+			Symbol:     "BTCUSDT",
+			StrategyID: 3,
+			AccountID:  2,
+			EntryPrice: d(100),
+			Market:     entities.Futures,
+			Leverage:   5,
+			Mode:       entities.Live,
+		})
+
+		assert.EqualError(t, err, "a live futures signal already holds the position of BTCUSDT")
+		repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		futures.AssertNotCalled(t, "PlaceOrder", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should place reduce-only exits below the minimum notional", func(t *testing.T) {
+		repo := newRepository()
+		accounts := newAccounts()
+		futures := new(mocks.FuturesBroker)
+		signalUC := usecase.NewSignalUseCase(repo, accounts, newBroker(), transactor(), schedule(), newRisk(), futures)
+		futures.On("GetSymbolFilters", mock.Anything, "BTCUSDT").Return(broker.SymbolFilters{
+			Symbol:      "BTCUSDT",
+			StepSize:    decimal.RequireFromString("0.00001"),
+			MinNotional: decimal.RequireFromString("5"),
+		}, nil).Once()
+		futures.On("GetPosition", mock.Anything, "BTCUSDT").Return(broker.Position{Symbol: "BTCUSDT", Quantity: 0.00007}, nil).Once()
+		futures.On("PlaceOrder", mock.Anything, mock.MatchedBy(func(o broker.OrderRequest) bool {
+			return o.Side == broker.Sell && o.Quantity == 0.00007 && o.ReduceOnly
+		})).Return(broker.OrderResult{OrderID: "9", ExecutedQty: 0.00007, QuoteQuantity: 2.8}, nil).Once()
+		futures.On("FundingFees", mock.Anything, "BTCUSDT", mock.Anything).Return(0.0, nil).Once()
+		repo.On("GetOpenSignals", mock.Anything, "BTCUSDT", uint(1)).Return(entities.Signal{
+			ID:         1,
+			Symbol:     "BTCUSDT",
+			Status:     entities.Open,
+			StrategyID: 1,
+			AccountID:  2,
+			Mode:       entities.Live,
+			Side:       entities.Long,
+			Market:     entities.Futures,
+			Orders: []entities.Order{{
+				EntryPrice:     d(50000),
+				Quantity:       d(0.00007),
+				InvestedAmount: d(1.75),
+				MarginType:     entities.Isolated,
+				Leverage:       2,
+			}},
+		}, nil).Once()
+		repo.On("Transition", mock.Anything, uint(1), entities.Open, entities.Closing).Return(true, nil).Once()
+		repo.On("SaveOrder", mock.Anything, mock.Anything).Return(nil).Once()
+		repo.On("Close", mock.Anything, mock.MatchedBy(func(s entities.Signal) bool {
+			return s.Orders[0].ExitOrderID == "9"
+		})).Return(true, nil).Once()
+		accounts.On("AddOrder", mock.Anything, int64(2), mock.Anything).Return(nil).Once()
+
+		err := signalUC.GenerateSellSignal(usecase.ExitSignal{Symbol: "BTCUSDT", StrategyID: 1, ExitPrice: d(40000)})
+
+		assert.NoError(t, err)
+		futures.AssertExpectations(t)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("should book a liquidated position at its liquidation price", func(t *testing.T) {
+		repo := newRepository()
+		accounts := newAccounts()
+		futures := newFuturesBroker()
+		signalUC := usecase.NewSignalUseCase(repo, accounts, newBroker(), transactor(), schedule(), newRisk(), futures)
+		futures.On("GetPosition", mock.Anything, "BTCUSDT").Return(broker.Position{Symbol: "BTCUSDT"}, nil).Once()
+		futures.On("FundingFees", mock.Anything, "BTCUSDT", mock.Anything).Return(2.5, nil).Once()
+		repo.On("GetOpenSignals", mock.Anything, "BTCUSDT", uint(1)).Return(entities.Signal{
+			ID:         1,
+			Symbol:     "BTCUSDT",
+			Status:     entities.Open,
+			StrategyID: 1,
+			AccountID:  2,
+			Mode:       entities.Live,
+			Side:       entities.Short,
+			Market:     entities.Futures,
+			Orders: []entities.Order{{
+				EntryPrice:       d(100),
+				Quantity:         d(50),
+				InvestedAmount:   d(1000),
+				MarginType:       entities.Isolated,
+				EntryFee:         d(5),
+				Leverage:         5,
+				LiquidationPrice: d(119.5),
+			}},
+		}, nil).Once()
+		repo.On("Transition", mock.Anything, uint(1), entities.Open, entities.Closing).Return(true, nil).Once()
+		repo.On("SaveOrder", mock.Anything, mock.Anything).Return(nil).Once()
+		repo.On("Close", mock.Anything, mock.Anything).Return(true, nil).Once()
+		accounts.On("AddOrder", mock.Anything, int64(2), mock.MatchedBy(func(s entities.Signal) bool {
+			order := s.Orders[0]
+			return order.ExitOrderID == "liquidation" && order.ExitPrice.Equal(d(119.5)) && order.Profit.Equal(d(-988.475))
+		})).Return(nil).Once()
+
+		err := signalUC.GenerateSellSignal(usecase.ExitSignal{Symbol: "BTCUSDT", StrategyID: 1, ExitPrice: d(125)})
+
+		assert.NoError(t, err)
+		accounts.AssertExpectations(t)
+		futures.AssertNotCalled(t, "PlaceOrder", mock.Anything, mock.Anything)
+	})
+}
+
+// newFuturesBroker returns a futures exchange whose symbols have no filters.
+func newFuturesBroker() *mocks.FuturesBroker {
+	b := new(mocks.FuturesBroker)
+	b.On("GetSymbolFilters", mock.Anything, mock.Anything).Return(func(ctx context.Context, symbol string) (broker.SymbolFilters, error) {
+		return broker.SymbolFilters{Symbol: symbol}, nil
+	}).Maybe()
+	return b
+}
@@ -1,23 +1,31 @@
+// Code generated by mockery v2.53.2. DO NOT EDIT.
+
 package mocks
 
-import mock "github.com/stretchr/testify/mock"
+import (
+	context "context"
+	entities "go-trade-bot/app/entities"
+
+	decimal "github.com/shopspring/decimal"
+	mock "github.com/stretchr/testify/mock"
+)
 
 // AccountUseCase is an autogenerated mock type for the AccountUseCase type
 type AccountUseCase struct {
 	mock.Mock
 }
 
-// AddOrder provides a mock function with given fields: exitPrice
-func (_m *AccountUseCase) AddOrder(exitPrice float32) error {
-	ret := _m.Called(exitPrice)
+// AddOrder provides a mock function with given fields: ctx, accountID, signal
+func (_m *AccountUseCase) AddOrder(ctx context.Context, accountID int64, signal entities.Signal) error {
+	ret := _m.Called(ctx, accountID, signal)
 
 	if len(ret) == 0 {
 		panic("no return value specified for AddOrder")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(float32) error); ok {
-		r0 = rf(exitPrice)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, entities.Signal) error); ok {
+		r0 = rf(ctx, accountID, signal)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -25,9 +33,9 @@ func (_m *AccountUseCase) AddOrder(exitPrice float32) error {
 	return r0
 }
 
-// CanOpenOrder provides a mock function with no fields
-func (_m *AccountUseCase) CanOpenOrder() (bool, error) {
-	ret := _m.Called()
+// CanOpenOrder provides a mock function with given fields: ctx, accountID
+func (_m *AccountUseCase) CanOpenOrder(ctx context.Context, accountID int64) (bool, error) {
+	ret := _m.Called(ctx, accountID)
 
 	if len(ret) == 0 {
 		panic("no return value specified for CanOpenOrder")
@@ -35,17 +43,17 @@ func (_m *AccountUseCase) CanOpenOrder() (bool, error) {
 
 	var r0 bool
 	var r1 error
-	if rf, ok := ret.Get(0).(func() (bool, error)); ok {
-		return rf()
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (bool, error)); ok {
+		return rf(ctx, accountID)
 	}
-	if rf, ok := ret.Get(0).(func() bool); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(context.Context, int64) bool); ok {
+		r0 = rf(ctx, accountID)
 	} else {
 		r0 = ret.Get(0).(bool)
 	}
 
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, accountID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -53,17 +61,17 @@ func (_m *AccountUseCase) CanOpenOrder() (bool, error) {
 	return r0, r1
 }
 
-// DeductOrder provides a mock function with given fields: entryPrice
-func (_m *AccountUseCase) DeductOrder(entryPrice float32) error {
-	ret := _m.Called(entryPrice)
+// DeductOrder provides a mock function with given fields: ctx, accountID, signal
+func (_m *AccountUseCase) DeductOrder(ctx context.Context, accountID int64, signal entities.Signal) error {
+	ret := _m.Called(ctx, accountID, signal)
 
 	if len(ret) == 0 {
 		panic("no return value specified for DeductOrder")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(float32) error); ok {
-		r0 = rf(entryPrice)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, entities.Signal) error); ok {
+		r0 = rf(ctx, accountID, signal)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -71,27 +79,55 @@ func (_m *AccountUseCase) DeductOrder(entryPrice float32) error {
 	return r0
 }
 
-// GetDisponibleAmout provides a mock function with no fields
-func (_m *AccountUseCase) GetDisponibleAmout() (float32, error) {
-	ret := _m.Called()
+// GetAccount provides a mock function with given fields: id
+func (_m *AccountUseCase) GetAccount(id int64) (entities.Account, error) {
+	ret := _m.Called(id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAccount")
+	}
+
+	var r0 entities.Account
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64) (entities.Account, error)); ok {
+		return rf(id)
+	}
+	if rf, ok := ret.Get(0).(func(int64) entities.Account); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Get(0).(entities.Account)
+	}
+
+	if rf, ok := ret.Get(1).(func(int64) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetDisponibleAmout provides a mock function with given fields: ctx, accountID
+func (_m *AccountUseCase) GetDisponibleAmout(ctx context.Context, accountID int64) (decimal.Decimal, error) {
+	ret := _m.Called(ctx, accountID)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetDisponibleAmout")
 	}
 
-	var r0 float32
+	var r0 decimal.Decimal
 	var r1 error
-	if rf, ok := ret.Get(0).(func() (float32, error)); ok {
-		return rf()
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (decimal.Decimal, error)); ok {
+		return rf(ctx, accountID)
 	}
-	if rf, ok := ret.Get(0).(func() float32); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(context.Context, int64) decimal.Decimal); ok {
+		r0 = rf(ctx, accountID)
 	} else {
-		r0 = ret.Get(0).(float32)
+		r0 = ret.Get(0).(decimal.Decimal)
 	}
 
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, accountID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -99,6 +135,24 @@ func (_m *AccountUseCase) GetDisponibleAmout() (float32, error) {
 	return r0, r1
 }
 
+// ReleaseOrder provides a mock function with given fields: ctx, accountID, signal
+func (_m *AccountUseCase) ReleaseOrder(ctx context.Context, accountID int64, signal entities.Signal) error {
+	ret := _m.Called(ctx, accountID, signal)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReleaseOrder")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, entities.Signal) error); ok {
+		r0 = rf(ctx, accountID, signal)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewAccountUseCase creates a new instance of AccountUseCase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewAccountUseCase(t interface {
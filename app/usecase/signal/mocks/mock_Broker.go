@@ -4,8 +4,7 @@ package mocks
 
 import (
 	context "context"
-
-	binance "github.com/adshao/go-binance/v2"
+	broker "go-trade-bot/internal/broker"
 
 	mock "github.com/stretchr/testify/mock"
 )
@@ -15,24 +14,52 @@ type Broker struct {
 	mock.Mock
 }
 
+// GetSymbolFilters provides a mock function with given fields: ctx, symbol
+func (_m *Broker) GetSymbolFilters(ctx context.Context, symbol string) (broker.SymbolFilters, error) {
+	ret := _m.Called(ctx, symbol)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSymbolFilters")
+	}
+
+	var r0 broker.SymbolFilters
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (broker.SymbolFilters, error)); ok {
+		return rf(ctx, symbol)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) broker.SymbolFilters); ok {
+		r0 = rf(ctx, symbol)
+	} else {
+		r0 = ret.Get(0).(broker.SymbolFilters)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, symbol)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ListTickerPrices provides a mock function with given fields: ctx, symbol
-func (_m *Broker) ListTickerPrices(ctx context.Context, symbol string) ([]*binance.SymbolPrice, error) {
+func (_m *Broker) ListTickerPrices(ctx context.Context, symbol string) ([]broker.Ticker, error) {
 	ret := _m.Called(ctx, symbol)
 
 	if len(ret) == 0 {
 		panic("no return value specified for ListTickerPrices")
 	}
 
-	var r0 []*binance.SymbolPrice
+	var r0 []broker.Ticker
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) ([]*binance.SymbolPrice, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]broker.Ticker, error)); ok {
 		return rf(ctx, symbol)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) []*binance.SymbolPrice); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, string) []broker.Ticker); ok {
 		r0 = rf(ctx, symbol)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*binance.SymbolPrice)
+			r0 = ret.Get(0).([]broker.Ticker)
 		}
 	}
 
@@ -45,6 +72,34 @@ func (_m *Broker) ListTickerPrices(ctx context.Context, symbol string) ([]*binan
 	return r0, r1
 }
 
+// PlaceOrder provides a mock function with given fields: ctx, order
+func (_m *Broker) PlaceOrder(ctx context.Context, order broker.OrderRequest) (broker.OrderResult, error) {
+	ret := _m.Called(ctx, order)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PlaceOrder")
+	}
+
+	var r0 broker.OrderResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, broker.OrderRequest) (broker.OrderResult, error)); ok {
+		return rf(ctx, order)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, broker.OrderRequest) broker.OrderResult); ok {
+		r0 = rf(ctx, order)
+	} else {
+		r0 = ret.Get(0).(broker.OrderResult)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, broker.OrderRequest) error); ok {
+		r1 = rf(ctx, order)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewBroker creates a new instance of Broker. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewBroker(t interface {
@@ -0,0 +1,208 @@
+// Code generated by mockery v2.53.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	broker "go-trade-bot/internal/broker"
+	time "time"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// FuturesBroker is an autogenerated mock type for the FuturesBroker type
+type FuturesBroker struct {
+	mock.Mock
+}
+
+// FundingFees provides a mock function with given fields: ctx, symbol, start
+func (_m *FuturesBroker) FundingFees(ctx context.Context, symbol string, start time.Time) (float64, error) {
+	ret := _m.Called(ctx, symbol, start)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FundingFees")
+	}
+
+	var r0 float64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) (float64, error)); ok {
+		return rf(ctx, symbol, start)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) float64); ok {
+		r0 = rf(ctx, symbol, start)
+	} else {
+		r0 = ret.Get(0).(float64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time) error); ok {
+		r1 = rf(ctx, symbol, start)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPosition provides a mock function with given fields: ctx, symbol
+func (_m *FuturesBroker) GetPosition(ctx context.Context, symbol string) (broker.Position, error) {
+	ret := _m.Called(ctx, symbol)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPosition")
+	}
+
+	var r0 broker.Position
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (broker.Position, error)); ok {
+		return rf(ctx, symbol)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) broker.Position); ok {
+		r0 = rf(ctx, symbol)
+	} else {
+		r0 = ret.Get(0).(broker.Position)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, symbol)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSymbolFilters provides a mock function with given fields: ctx, symbol
+func (_m *FuturesBroker) GetSymbolFilters(ctx context.Context, symbol string) (broker.SymbolFilters, error) {
+	ret := _m.Called(ctx, symbol)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSymbolFilters")
+	}
+
+	var r0 broker.SymbolFilters
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (broker.SymbolFilters, error)); ok {
+		return rf(ctx, symbol)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) broker.SymbolFilters); ok {
+		r0 = rf(ctx, symbol)
+	} else {
+		r0 = ret.Get(0).(broker.SymbolFilters)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, symbol)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListFundingRates provides a mock function with given fields: ctx, symbol, start
+func (_m *FuturesBroker) ListFundingRates(ctx context.Context, symbol string, start time.Time) ([]broker.FundingRate, error) {
+	ret := _m.Called(ctx, symbol, start)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListFundingRates")
+	}
+
+	var r0 []broker.FundingRate
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) ([]broker.FundingRate, error)); ok {
+		return rf(ctx, symbol, start)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) []broker.FundingRate); ok {
+		r0 = rf(ctx, symbol, start)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]broker.FundingRate)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time) error); ok {
+		r1 = rf(ctx, symbol, start)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PlaceOrder provides a mock function with given fields: ctx, order
+func (_m *FuturesBroker) PlaceOrder(ctx context.Context, order broker.OrderRequest) (broker.OrderResult, error) {
+	ret := _m.Called(ctx, order)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PlaceOrder")
+	}
+
+	var r0 broker.OrderResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, broker.OrderRequest) (broker.OrderResult, error)); ok {
+		return rf(ctx, order)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, broker.OrderRequest) broker.OrderResult); ok {
+		r0 = rf(ctx, order)
+	} else {
+		r0 = ret.Get(0).(broker.OrderResult)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, broker.OrderRequest) error); ok {
+		r1 = rf(ctx, order)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetLeverage provides a mock function with given fields: ctx, symbol, leverage
+func (_m *FuturesBroker) SetLeverage(ctx context.Context, symbol string, leverage int) error {
+	ret := _m.Called(ctx, symbol, leverage)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetLeverage")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) error); ok {
+		r0 = rf(ctx, symbol, leverage)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetMarginType provides a mock function with given fields: ctx, symbol, marginType
+func (_m *FuturesBroker) SetMarginType(ctx context.Context, symbol string, marginType broker.MarginType) error {
+	ret := _m.Called(ctx, symbol, marginType)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetMarginType")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, broker.MarginType) error); ok {
+		r0 = rf(ctx, symbol, marginType)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewFuturesBroker creates a new instance of FuturesBroker. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewFuturesBroker(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *FuturesBroker {
+	mock := &FuturesBroker{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,47 @@
+// Code generated by mockery v2.53.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	decimal "github.com/shopspring/decimal"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// RiskManager is an autogenerated mock type for the RiskManager type
+type RiskManager struct {
+	mock.Mock
+}
+
+// Allow provides a mock function with given fields: ctx, symbol, strategyID, amount
+func (_m *RiskManager) Allow(ctx context.Context, symbol string, strategyID uint, amount decimal.Decimal) error {
+	ret := _m.Called(ctx, symbol, strategyID, amount)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Allow")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, uint, decimal.Decimal) error); ok {
+		r0 = rf(ctx, symbol, strategyID, amount)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewRiskManager creates a new instance of RiskManager. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewRiskManager(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *RiskManager {
+	mock := &RiskManager{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
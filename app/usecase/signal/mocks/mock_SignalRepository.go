@@ -3,8 +3,11 @@
 package mocks
 
 import (
+	context "context"
 	entities "go-trade-bot/app/entities"
+	time "time"
 
+	decimal "github.com/shopspring/decimal"
 	mock "github.com/stretchr/testify/mock"
 )
 
@@ -13,22 +16,88 @@ type SignalRepository struct {
 	mock.Mock
 }
 
-// Create provides a mock function with given fields: signal
-func (_m *SignalRepository) Create(signal entities.Signal) error {
-	ret := _m.Called(signal)
+// Close provides a mock function with given fields: ctx, signal
+func (_m *SignalRepository) Close(ctx context.Context, signal entities.Signal) (bool, error) {
+	ret := _m.Called(ctx, signal)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Close")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, entities.Signal) (bool, error)); ok {
+		return rf(ctx, signal)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entities.Signal) bool); ok {
+		r0 = rf(ctx, signal)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entities.Signal) error); ok {
+		r1 = rf(ctx, signal)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Create provides a mock function with given fields: ctx, signal
+func (_m *SignalRepository) Create(ctx context.Context, signal entities.Signal) (entities.Signal, error) {
+	ret := _m.Called(ctx, signal)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Create")
 	}
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(entities.Signal) error); ok {
-		r0 = rf(signal)
+	var r0 entities.Signal
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, entities.Signal) (entities.Signal, error)); ok {
+		return rf(ctx, signal)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entities.Signal) entities.Signal); ok {
+		r0 = rf(ctx, signal)
 	} else {
-		r0 = ret.Error(0)
+		r0 = ret.Get(0).(entities.Signal)
 	}
 
-	return r0
+	if rf, ok := ret.Get(1).(func(context.Context, entities.Signal) error); ok {
+		r1 = rf(ctx, signal)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Exposure provides a mock function with given fields: ctx, accountID
+func (_m *SignalRepository) Exposure(ctx context.Context, accountID int64) (decimal.Decimal, error) {
+	ret := _m.Called(ctx, accountID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Exposure")
+	}
+
+	var r0 decimal.Decimal
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (decimal.Decimal, error)); ok {
+		return rf(ctx, accountID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) decimal.Decimal); ok {
+		r0 = rf(ctx, accountID)
+	} else {
+		r0 = ret.Get(0).(decimal.Decimal)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, accountID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
 // GetAll provides a mock function with no fields
@@ -89,9 +158,39 @@ func (_m *SignalRepository) GetByID(id uint) (entities.Signal, error) {
 	return r0, r1
 }
 
-// GetOpenSignals provides a mock function with given fields: symbol, strategyId
-func (_m *SignalRepository) GetOpenSignals(symbol string, strategyId uint) (entities.Signal, error) {
-	ret := _m.Called(symbol, strategyId)
+// GetClosedSignals provides a mock function with given fields: ctx, strategyID, limit
+func (_m *SignalRepository) GetClosedSignals(ctx context.Context, strategyID uint, limit int) ([]entities.Signal, error) {
+	ret := _m.Called(ctx, strategyID, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetClosedSignals")
+	}
+
+	var r0 []entities.Signal
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, int) ([]entities.Signal, error)); ok {
+		return rf(ctx, strategyID, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint, int) []entities.Signal); ok {
+		r0 = rf(ctx, strategyID, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entities.Signal)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint, int) error); ok {
+		r1 = rf(ctx, strategyID, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetOpenSignals provides a mock function with given fields: ctx, symbol, strategyId
+func (_m *SignalRepository) GetOpenSignals(ctx context.Context, symbol string, strategyId uint) (entities.Signal, error) {
+	ret := _m.Called(ctx, symbol, strategyId)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetOpenSignals")
@@ -99,17 +198,147 @@ func (_m *SignalRepository) GetOpenSignals(symbol string, strategyId uint) (enti
 
 	var r0 entities.Signal
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string, uint) (entities.Signal, error)); ok {
-		return rf(symbol, strategyId)
+	if rf, ok := ret.Get(0).(func(context.Context, string, uint) (entities.Signal, error)); ok {
+		return rf(ctx, symbol, strategyId)
 	}
-	if rf, ok := ret.Get(0).(func(string, uint) entities.Signal); ok {
-		r0 = rf(symbol, strategyId)
+	if rf, ok := ret.Get(0).(func(context.Context, string, uint) entities.Signal); ok {
+		r0 = rf(ctx, symbol, strategyId)
 	} else {
 		r0 = ret.Get(0).(entities.Signal)
 	}
 
-	if rf, ok := ret.Get(1).(func(string, uint) error); ok {
-		r1 = rf(symbol, strategyId)
+	if rf, ok := ret.Get(1).(func(context.Context, string, uint) error); ok {
+		r1 = rf(ctx, symbol, strategyId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// HasLiveFutures provides a mock function with given fields: ctx, symbol
+func (_m *SignalRepository) HasLiveFutures(ctx context.Context, symbol string) (bool, error) {
+	ret := _m.Called(ctx, symbol)
+
+	if len(ret) == 0 {
+		panic("no return value specified for HasLiveFutures")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (bool, error)); ok {
+		return rf(ctx, symbol)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) bool); ok {
+		r0 = rf(ctx, symbol)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, symbol)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Open provides a mock function with given fields: ctx, signal
+func (_m *SignalRepository) Open(ctx context.Context, signal entities.Signal) (bool, error) {
+	ret := _m.Called(ctx, signal)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Open")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, entities.Signal) (bool, error)); ok {
+		return rf(ctx, signal)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entities.Signal) bool); ok {
+		r0 = rf(ctx, signal)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entities.Signal) error); ok {
+		r1 = rf(ctx, signal)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SaveOrder provides a mock function with given fields: ctx, order
+func (_m *SignalRepository) SaveOrder(ctx context.Context, order entities.Order) error {
+	ret := _m.Called(ctx, order)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveOrder")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, entities.Order) error); ok {
+		r0 = rf(ctx, order)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// TradedVolume provides a mock function with given fields: ctx, accountID, since
+func (_m *SignalRepository) TradedVolume(ctx context.Context, accountID int64, since time.Time) (decimal.Decimal, error) {
+	ret := _m.Called(ctx, accountID, since)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TradedVolume")
+	}
+
+	var r0 decimal.Decimal
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, time.Time) (decimal.Decimal, error)); ok {
+		return rf(ctx, accountID, since)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, time.Time) decimal.Decimal); ok {
+		r0 = rf(ctx, accountID, since)
+	} else {
+		r0 = ret.Get(0).(decimal.Decimal)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, time.Time) error); ok {
+		r1 = rf(ctx, accountID, since)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Transition provides a mock function with given fields: ctx, id, from, to
+func (_m *SignalRepository) Transition(ctx context.Context, id uint, from entities.SignalStatus, to entities.SignalStatus) (bool, error) {
+	ret := _m.Called(ctx, id, from, to)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Transition")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, entities.SignalStatus, entities.SignalStatus) (bool, error)); ok {
+		return rf(ctx, id, from, to)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint, entities.SignalStatus, entities.SignalStatus) bool); ok {
+		r0 = rf(ctx, id, from, to)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint, entities.SignalStatus, entities.SignalStatus) error); ok {
+		r1 = rf(ctx, id, from, to)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -117,17 +346,17 @@ func (_m *SignalRepository) GetOpenSignals(symbol string, strategyId uint) (enti
 	return r0, r1
 }
 
-// Update provides a mock function with given fields: signal
-func (_m *SignalRepository) Update(signal entities.Signal) error {
-	ret := _m.Called(signal)
+// UpdateHighWaterMark provides a mock function with given fields: id, price
+func (_m *SignalRepository) UpdateHighWaterMark(id uint, price decimal.Decimal) error {
+	ret := _m.Called(id, price)
 
 	if len(ret) == 0 {
-		panic("no return value specified for Update")
+		panic("no return value specified for UpdateHighWaterMark")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(entities.Signal) error); ok {
-		r0 = rf(signal)
+	if rf, ok := ret.Get(0).(func(uint, decimal.Decimal) error); ok {
+		r0 = rf(id, price)
 	} else {
 		r0 = ret.Error(0)
 	}
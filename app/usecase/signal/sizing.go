@@ -0,0 +1,152 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+type SizingMode string
+
+const (
+	EqualSizing         SizingMode = "equal"
+	FixedNotionalSizing SizingMode = "fixed_notional"
+	FixedFractionSizing SizingMode = "fixed_fraction"
+	RiskSizing          SizingMode = "risk"
+	VolatilitySizing    SizingMode = "volatility"
+	KellySizing         SizingMode = "kelly"
+)
+
+const (
+	// kellyTrades is how many closed signals of the strategy the Kelly
+	// criterion is estimated from.
+	kellyTrades = 50
+	// kellyMinTrades is the history needed before trusting the estimate.
+	kellyMinTrades = 10
+)
+
+var hundred = decimal.NewFromInt(100)
+
+// Sizing is the policy deciding how much of the account an entry spends.
+// Percentages are of the equity of the account, its balance plus what its
+// open signals hold. Entries without a mode get an equal slice of the
+// balance per available order.
+type Sizing struct {
+	Mode        SizingMode
+	Notional    decimal.Decimal
+	FractionPct decimal.Decimal
+	// RiskPct is the equity lost if the stop loss, StopLossPct below the
+	// entry, is hit.
+	RiskPct     decimal.Decimal
+	StopLossPct decimal.Decimal
+	// TargetVolatilityPct is the equity an ATR move of the price is worth.
+	TargetVolatilityPct decimal.Decimal
+	ATR                 decimal.Decimal
+	KellyFraction       decimal.Decimal
+	KellyMaxPct         decimal.Decimal
+}
+
+// orderAmount sizes the entry with its policy, never above the balance of
+// the account. Zero means the policy doesn't open the entry. The amount is
+// what the entry reserves, so modes sizing the position itself get the
+// notional divided by the leverage.
+func (s SignalUseCase) orderAmount(ctx context.Context, e EntrySignal) (decimal.Decimal, error) {
+	sizing := e.Sizing
+	if sizing.Mode == "" || sizing.Mode == EqualSizing {
+		return s.AccountUseCase.GetDisponibleAmout(ctx, e.AccountID)
+	}
+
+	account, err := s.AccountUseCase.GetAccount(e.AccountID)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	exposure, err := s.Repository.Exposure(ctx, e.AccountID)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	equity := account.Amount.Add(exposure)
+
+	var amount decimal.Decimal
+	switch sizing.Mode {
+	case FixedNotionalSizing:
+		amount = sizing.Notional
+	case FixedFractionSizing:
+		amount = equity.Mul(sizing.FractionPct).Div(hundred)
+	case RiskSizing:
+		if !sizing.StopLossPct.IsPositive() {
+			return decimal.Zero, fmt.Errorf("risk sizing of %s needs the stop loss of the entry", e.Symbol)
+		}
+		amount = equity.Mul(sizing.RiskPct).Div(sizing.StopLossPct)
+	case VolatilitySizing:
+		if !sizing.ATR.IsPositive() {
+			return decimal.Zero, fmt.Errorf("volatility sizing of %s needs the ATR of the symbol", e.Symbol)
+		}
+		amount = equity.Mul(sizing.TargetVolatilityPct).Div(hundred).Mul(e.EntryPrice).Div(sizing.ATR)
+	case KellySizing:
+		amount, err = s.kellyAmount(ctx, e, equity)
+		if err != nil {
+			return decimal.Zero, err
+		}
+	default:
+		return decimal.Zero, fmt.Errorf("unknown sizing mode %s", sizing.Mode)
+	}
+	switch sizing.Mode {
+	case FixedNotionalSizing, RiskSizing, VolatilitySizing:
+		amount = margin(amount, e.Leverage)
+	}
+
+	if amount.GreaterThan(account.Amount) {
+		amount = account.Amount
+	}
+	return decimal.Max(amount, decimal.Zero), nil
+}
+
+// kellyAmount stakes a fraction of the Kelly criterion estimated from the
+// last closed signals of the strategy, capped at KellyMaxPct of the equity.
+// Until the strategy has kellyMinTrades closed signals it gets an equal
+// slice of the balance under the same cap.
+func (s SignalUseCase) kellyAmount(ctx context.Context, e EntrySignal, equity decimal.Decimal) (decimal.Decimal, error) {
+	limit := equity.Mul(e.Sizing.KellyMaxPct).Div(hundred)
+
+	signals, err := s.Repository.GetClosedSignals(ctx, e.StrategyID, kellyTrades)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	if len(signals) < kellyMinTrades {
+		amount, err := s.AccountUseCase.GetDisponibleAmout(ctx, e.AccountID)
+		return decimal.Min(amount, limit), err
+	}
+
+	wins, losses := 0, 0
+	won, lost := decimal.Zero, decimal.Zero
+	for _, signal := range signals {
+		order := signal.Orders[0]
+		if !order.InvestedAmount.IsPositive() {
+			continue
+		}
+		ret := order.Profit.Div(order.InvestedAmount)
+		if ret.IsPositive() {
+			wins++
+			won = won.Add(ret)
+		} else {
+			losses++
+			lost = lost.Sub(ret)
+		}
+	}
+	if wins == 0 {
+		return decimal.Zero, nil
+	}
+	if losses == 0 || lost.IsZero() {
+		return limit, nil
+	}
+
+	// f = W - (1 - W) / R, W the win rate and R the average win over the
+	// average loss.
+	total := decimal.NewFromInt(int64(wins + losses))
+	winRate := decimal.NewFromInt(int64(wins)).Div(total)
+	payoff := won.Div(decimal.NewFromInt(int64(wins))).Div(lost.Div(decimal.NewFromInt(int64(losses))))
+	kelly := winRate.Sub(decimal.NewFromInt(1).Sub(winRate).Div(payoff))
+
+	return decimal.Min(equity.Mul(kelly).Mul(e.Sizing.KellyFraction), limit), nil
+}
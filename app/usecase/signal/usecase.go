@@ -2,155 +2,598 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"go-trade-bot/app/entities"
-	"strconv"
+	"go-trade-bot/internal/broker"
+	"go-trade-bot/internal/customerror"
+	"go-trade-bot/internal/fees"
+	"log"
+	"net/http"
 	"time"
 
-	"github.com/adshao/go-binance/v2"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
 )
 
+// EntrySignal opens a long on spot unless Side and Market say otherwise.
+// Shorts need the futures market, Leverage only applies there.
 type EntrySignal struct {
 	Symbol     string
 	StrategyID uint
-	EntryPrice float32
+	AccountID  int64
+	EntryPrice decimal.Decimal
+	Side       entities.PositionSide
+	Market     entities.Market
+	Leverage   int
 	MarginType entities.MarginType
+	Mode       entities.ExecutionMode
+	OrderType  broker.OrderType
+	Sizing     Sizing
+}
+
+// normalized fills the defaults of the entry and checks that its side can
+// be traded on its market.
+func (e EntrySignal) normalized() (EntrySignal, error) {
+	if e.Side == "" {
+		e.Side = entities.Long
+	}
+	if e.Market == "" {
+		e.Market = entities.Spot
+	}
+	if e.Side == entities.Short && e.Market != entities.Futures {
+		return e, fmt.Errorf("short entries of %s need the futures market", e.Symbol)
+	}
+	if e.Market == entities.Spot || e.Leverage < 1 {
+		e.Leverage = 1
+	}
+	return e, nil
 }
 
 type ExitSignal struct {
 	Symbol     string
 	StrategyID uint
-	ExitPrice  float32
+	ExitPrice  decimal.Decimal
 }
 
 type SignalRepository interface {
-	Create(signal entities.Signal) error
-	GetOpenSignals(symbol string, strategyId uint) (entities.Signal, error)
-	Update(signal entities.Signal) error
+	Create(ctx context.Context, signal entities.Signal) (entities.Signal, error)
+	GetOpenSignals(ctx context.Context, symbol string, strategyId uint) (entities.Signal, error)
+	HasLiveFutures(ctx context.Context, symbol string) (bool, error)
+	Transition(ctx context.Context, id uint, from entities.SignalStatus, to entities.SignalStatus) (bool, error)
+	Open(ctx context.Context, signal entities.Signal) (bool, error)
+	SaveOrder(ctx context.Context, order entities.Order) error
+	Close(ctx context.Context, signal entities.Signal) (bool, error)
 	GetByID(id uint) (entities.Signal, error)
 	GetAll() ([]entities.Signal, error)
+	UpdateHighWaterMark(id uint, price decimal.Decimal) error
+	TradedVolume(ctx context.Context, accountID int64, since time.Time) (decimal.Decimal, error)
+	Exposure(ctx context.Context, accountID int64) (decimal.Decimal, error)
+	GetClosedSignals(ctx context.Context, strategyID uint, limit int) ([]entities.Signal, error)
 }
 type AccountUseCase interface {
-	DeductOrder(entryPrice float32) error
-	AddOrder(exitPrice float32) error
-	GetDisponibleAmout() (float32, error)
-	CanOpenOrder() (bool, error)
+	DeductOrder(ctx context.Context, accountID int64, signal entities.Signal) error
+	ReleaseOrder(ctx context.Context, accountID int64, signal entities.Signal) error
+	AddOrder(ctx context.Context, accountID int64, signal entities.Signal) error
+	GetDisponibleAmout(ctx context.Context, accountID int64) (decimal.Decimal, error)
+	CanOpenOrder(ctx context.Context, accountID int64) (bool, error)
+	GetAccount(id int64) (entities.Account, error)
+}
+
+type Transactor interface {
+	Transaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type FeeSchedule interface {
+	Model(name string) (fees.Model, bool)
+}
+
+type RiskManager interface {
+	Allow(ctx context.Context, symbol string, strategyID uint, amount decimal.Decimal) error
 }
 
 type Broker interface {
-	ListTickerPrices(ctx context.Context, symbol string) ([]*binance.SymbolPrice, error)
+	ListTickerPrices(ctx context.Context, symbol string) ([]broker.Ticker, error)
+	PlaceOrder(ctx context.Context, order broker.OrderRequest) (broker.OrderResult, error)
+	GetSymbolFilters(ctx context.Context, symbol string) (broker.SymbolFilters, error)
 }
 
+// SignalUseCase opens and closes signals with the booking on their account.
+// Orders are placed outside of database transactions, between the steps
+// that claim the signal and the ones that book its fill.
 type SignalUseCase struct {
-	Repository     SignalRepository
-	AccountUseCase AccountUseCase
-	Broker         Broker
+	Repository       SignalRepository
+	AccountUseCase   AccountUseCase
+	Broker           Broker
+	Simulator        OrderExecutor
+	Exchange         OrderExecutor
+	FuturesBroker    FuturesBroker
+	FuturesSimulator FuturesExecutor
+	FuturesExchange  FuturesExecutor
+	Transactions     Transactor
+	Fees             FeeSchedule
+	Risk             RiskManager
 }
 
-func NewSignalUseCase(repository SignalRepository, ac AccountUseCase, b Broker) SignalUseCase {
-	return SignalUseCase{
-		Repository:     repository,
-		AccountUseCase: ac,
-		Broker:         b,
+// NewSignalUseCase trades futures signals on fb. Without a futures exchange,
+// on the paper exchange or in backtests, every futures signal is simulated.
+func NewSignalUseCase(repository SignalRepository, ac AccountUseCase, b Broker, t Transactor, f FeeSchedule, r RiskManager, fb FuturesBroker) SignalUseCase {
+	s := SignalUseCase{
+		Repository:       repository,
+		AccountUseCase:   ac,
+		Broker:           b,
+		Simulator:        NewSimulatedExecutor(),
+		Exchange:         NewBrokerExecutor(b),
+		FuturesBroker:    fb,
+		FuturesSimulator: NewSimulatedFuturesExecutor(nil),
+		FuturesExchange:  NewSimulatedFuturesExecutor(nil),
+		Transactions:     t,
+		Fees:             f,
+		Risk:             r,
+	}
+	if fb != nil {
+		s.FuturesSimulator = NewSimulatedFuturesExecutor(fb)
+		s.FuturesExchange = NewFuturesBrokerExecutor(fb)
 	}
+	return s
 }
 
+// NewPaperSignalUseCase fills signals of testing strategies on the paper
+// exchange, so they follow the same order path as live ones.
+func NewPaperSignalUseCase(repository SignalRepository, ac AccountUseCase, b Broker, t Transactor, f FeeSchedule, r RiskManager, fb FuturesBroker, paper OrderBroker) SignalUseCase {
+	return NewSignalUseCase(repository, ac, b, t, f, r, fb).WithSimulator(NewBrokerExecutor(paper))
+}
+
+// WithSimulator replaces how signals of testing strategies are filled, e.g.
+// by routing them through the paper exchange.
+func (s SignalUseCase) WithSimulator(e OrderExecutor) SignalUseCase {
+	s.Simulator = e
+	return s
+}
+
+// executor returns where orders of the given mode are filled, only live
+// signals reach the exchange.
+func (s SignalUseCase) executor(mode entities.ExecutionMode) OrderExecutor {
+	if mode == entities.Live {
+		return s.Exchange
+	}
+	return s.Simulator
+}
+
+func (s SignalUseCase) futuresExecutor(mode entities.ExecutionMode) FuturesExecutor {
+	if mode == entities.Live {
+		return s.FuturesExchange
+	}
+	return s.FuturesSimulator
+}
+
+// GenerateBuySignal keeps the account locked from the slot check until the
+// pending signal and its reservation are written, so concurrent strategies
+// can't open on the same slot. The order is placed once they are committed
+// and its fill opens the signal, an order refused by the exchange releases
+// the reservation. Entries refused by the risk manager fail with its
+// reason, backtests run without one. Futures entries reserve their margin,
+// the sized amount, and open a position of the margin times the leverage.
 func (s SignalUseCase) GenerateBuySignal(e EntrySignal) error {
-	canOpen, err := s.AccountUseCase.CanOpenOrder()
+	e, err := e.normalized()
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+	// Loaded before the account is locked, they may come from the exchange.
+	filters, err := s.symbolFilters(ctx, e.Market, e.Symbol)
 	if err != nil {
-		return fmt.Errorf("failed to check if order can be opened: %w", err)
+		return err
 	}
 
-	if !canOpen {
+	var pending entities.Signal
+	var price, notional decimal.Decimal
+	var rates fees.Rates
+	err = s.Transactions.Transaction(ctx, func(ctx context.Context) error {
+		canOpen, err := s.AccountUseCase.CanOpenOrder(ctx, e.AccountID)
+		if err != nil {
+			return fmt.Errorf("failed to check if order can be opened: %w", err)
+		}
+
+		if !canOpen {
+			return nil
+		}
+
+		openSignal, err := s.Repository.GetOpenSignals(ctx, e.Symbol, e.StrategyID)
+		if err != nil {
+			return err
+		}
+
+		if openSignal.ID != 0 {
+			return nil
+		}
+
+		mode := e.Mode
+		if mode == "" {
+			mode = entities.Simulated
+		}
+		if e.Market == entities.Futures && mode == entities.Live {
+			// Funding and liquidations are reported for the position of the
+			// symbol, they can only be booked on the signal holding it.
+			held, err := s.Repository.HasLiveFutures(ctx, e.Symbol)
+			if err != nil {
+				return err
+			}
+			if held {
+				return fmt.Errorf("a live futures signal already holds the position of %s", e.Symbol)
+			}
+		}
+
+		investedAmount, err := s.orderAmount(ctx, e)
+		if err != nil {
+			return err
+		}
+		if !investedAmount.IsPositive() {
+			log.Printf("Sizing of strategy %d leaves no amount for %s", e.StrategyID, e.Symbol)
+			return nil
+		}
+
+		leverage := decimal.NewFromInt(int64(e.Leverage))
+		price, notional, err = size(filters, e.EntryPrice, investedAmount.Mul(leverage))
+		if err != nil {
+			return err
+		}
+		amount := margin(notional, e.Leverage)
+
+		if s.Risk != nil {
+			if err := s.Risk.Allow(ctx, e.Symbol, e.StrategyID, amount); err != nil {
+				return err
+			}
+		}
+
+		rates, err = s.feeRates(ctx, e.AccountID)
+		if err != nil {
+			return err
+		}
+
+		var orderLeverage float32
+		if e.Market == entities.Futures {
+			orderLeverage = float32(e.Leverage)
+		}
+
+		signal, err := s.Repository.Create(ctx, entities.Signal{
+			Symbol:        e.Symbol,
+			Status:        entities.Pending,
+			StrategyID:    e.StrategyID,
+			AccountID:     e.AccountID,
+			Mode:          mode,
+			Side:          e.Side,
+			Market:        e.Market,
+			HighWaterMark: price,
+			CreatedAt:     time.Now(),
+			UpdatedAt:     time.Now(),
+			Orders: []entities.Order{
+				{
+					EntryPrice:     price,
+					ExitPrice:      decimal.Zero,
+					Quantity:       notional.Div(price),
+					InvestedAmount: amount,
+					MarginType:     e.MarginType,
+					EntryFee:       decimal.Zero,
+					ExitFee:        decimal.Zero,
+					Leverage:       orderLeverage,
+					ExecutedQty:    decimal.Zero,
+					IsClosing:      false,
+					CreatedAt:      time.Now(),
+					UpdatedAt:      time.Now(),
+				},
+			},
+		})
+		if err != nil {
+			return err
+		}
+
+		if err := s.AccountUseCase.DeductOrder(ctx, e.AccountID, signal); err != nil {
+			return err
+		}
+		pending = signal
 		return nil
+	})
+	if err != nil || pending.ID == 0 {
+		return err
+	}
+
+	execution, err := s.open(ctx, pending.Mode, e, price, notional, rates)
+	if err != nil {
+		return errors.Join(err, s.cancel(ctx, pending))
 	}
 
-	openSignal, err := s.Repository.GetOpenSignals(e.Symbol, e.StrategyID)
+	// The order keeps the amount reserved before it was placed, what the
+	// fill cost is booked with the profit on close.
+	pending.Status = entities.Open
+	pending.HighWaterMark = execution.Price
+	order := &pending.Orders[0]
+	order.BrokerOrderID = execution.BrokerOrderID
+	order.EntryPrice = execution.Price
+	order.Quantity = execution.Quantity
+	order.EntryFee = execution.Fee
+	order.ExecutedQty = execution.ExecutedQty
+	order.LiquidationPrice = execution.LiquidationPrice
+	order.UpdatedAt = time.Now()
+
+	opened, err := s.Repository.Open(ctx, pending)
+	if err != nil {
+		return fmt.Errorf("entry order %s of signal %d was filled but not saved, the signal stays pending: %w", execution.BrokerOrderID, pending.ID, err)
+	}
+	if !opened {
+		return fmt.Errorf("signal %d is no longer pending, entry order %s was not saved", pending.ID, execution.BrokerOrderID)
+	}
+	return nil
+}
+
+// cancel fails the pending signal whose entry order was not placed and
+// releases its reservation.
+func (s SignalUseCase) cancel(ctx context.Context, signal entities.Signal) error {
+	return s.Transactions.Transaction(ctx, func(ctx context.Context) error {
+		failed, err := s.Repository.Transition(ctx, signal.ID, entities.Pending, entities.Failed)
+		if err != nil || !failed {
+			return err
+		}
+		return s.AccountUseCase.ReleaseOrder(ctx, signal.AccountID, signal)
+	})
+}
+
+// dustOrderID is the exit order of spot signals too small to be sold.
+const dustOrderID = "dust"
+
+// GenerateSellSignal closes the open signal, long or short, and credits its
+// account. The signal is claimed closing before the exit is placed, so a
+// concurrent close fails instead of selling it twice, and the fill is saved
+// before it is booked. A fill whose booking failed is booked again on the
+// next exit without placing another order.
+func (s SignalUseCase) GenerateSellSignal(e ExitSignal) error {
+	ctx := context.Background()
+	openSignal, err := s.Repository.GetOpenSignals(ctx, e.Symbol, e.StrategyID)
 	if err != nil {
 		return err
 	}
 
-	if openSignal.ID != 0 {
-		return nil
+	if openSignal.ID == 0 {
+		return fmt.Errorf("signal not found for symbol %s and strategy ID %d", e.Symbol, e.StrategyID)
 	}
 
-	investedAmount, _ := s.AccountUseCase.GetDisponibleAmout()
+	switch {
+	case openSignal.Status == entities.Closing && openSignal.Orders[0].IsClosing:
+		return s.book(ctx, openSignal)
+	case openSignal.Status == entities.Closing:
+		return fmt.Errorf("signal %d is already being closed", openSignal.ID)
+	case openSignal.Status != entities.Open:
+		return fmt.Errorf("signal %d is %s, its entry order was not saved yet", openSignal.ID, openSignal.Status)
+	}
 
-	signal := entities.Signal{
-		Symbol:     e.Symbol,
-		Status:     entities.Open,
-		StrategyID: e.StrategyID,
-		CreatedAt:  time.Now(),
-		UpdatedAt:  time.Now(),
-		Orders: []entities.Order{
-			{
-				EntryPrice:     e.EntryPrice,
-				ExitPrice:      0,
-				Quantity:       investedAmount / e.EntryPrice,
-				InvestedAmount: investedAmount,
-				MarginType:     e.MarginType,
-				EntryFee:       calculateEntryFee(investedAmount),
-				ExitFee:        0,
-				Leverage:       0,
-				ExecutedQty:    0,
-				IsClosing:      false,
-				CreatedAt:      time.Now(),
-				UpdatedAt:      time.Now(),
-			},
-		},
+	order := openSignal.Orders[0]
+	filters, err := s.symbolFilters(ctx, openSignal.Market, e.Symbol)
+	if err != nil {
+		return err
+	}
+	// Minimums only guard entries. A spot exit below them would be refused
+	// on every cycle, the coins stay on the exchange as dust and the signal
+	// is written off without an order. Reduce-only futures exits are not
+	// held to the minimum notional, they are always placed.
+	quantity := filters.RoundQuantity(order.Quantity)
+	var dust error
+	if openSignal.Market != entities.Futures {
+		dust = filters.Check(quantity, e.ExitPrice)
 	}
 
-	err = s.Repository.Create(signal)
+	rates, err := s.feeRates(ctx, openSignal.AccountID)
 	if err != nil {
 		return err
 	}
 
-	s.AccountUseCase.DeductOrder(investedAmount)
+	claimed, err := s.Repository.Transition(ctx, openSignal.ID, entities.Open, entities.Closing)
+	if err != nil {
+		return err
+	}
+	if !claimed {
+		return fmt.Errorf("signal %d is already being closed", openSignal.ID)
+	}
 
-	return nil
+	var execution Execution
+	var funding decimal.Decimal
+	if dust != nil {
+		log.Printf("Writing off signal %d without an exit order: %v", openSignal.ID, dust)
+		execution = Execution{
+			BrokerOrderID: dustOrderID,
+			Price:         decimal.Zero,
+			Quantity:      order.Quantity,
+			Amount:        decimal.Zero,
+		}
+	} else {
+		execution, funding, err = s.close(ctx, openSignal, e.ExitPrice, quantity, rates)
+		if err != nil {
+			_, reopenErr := s.Repository.Transition(ctx, openSignal.ID, entities.Closing, entities.Open)
+			return errors.Join(err, reopenErr)
+		}
+	}
+
+	openSignal.Status = entities.SignalStatus(entities.Closed)
+	openSignal.Orders[0].ExitOrderID = execution.BrokerOrderID
+	openSignal.Orders[0].ExitPrice = execution.Price
+	openSignal.Orders[0].ExitFee = execution.Fee
+	openSignal.Orders[0].UpdatedAt = time.Now()
+	openSignal.Orders[0].IsClosing = true
+	openSignal.Orders[0].FundingFee = funding
+	openSignal.Orders[0].Profit = profit(openSignal, execution, funding)
+
+	if err := s.Repository.SaveOrder(ctx, openSignal.Orders[0]); err != nil {
+		return fmt.Errorf("exit order %s of signal %d was filled but not saved, the signal stays closing: %w", execution.BrokerOrderID, openSignal.ID, err)
+	}
+	return s.book(ctx, openSignal)
 }
 
-func (s SignalUseCase) GenerateSellSignal(e ExitSignal) error {
-	openSignal, err := s.Repository.GetOpenSignals(e.Symbol, e.StrategyID)
+// book closes the signal whose exit was filled and credits its account, a
+// signal closed concurrently in between is not credited twice.
+func (s SignalUseCase) book(ctx context.Context, signal entities.Signal) error {
+	signal.Status = entities.Closed
+	return s.Transactions.Transaction(ctx, func(ctx context.Context) error {
+		closed, err := s.Repository.Close(ctx, signal)
+		if err != nil {
+			return err
+		}
+		if !closed {
+			return fmt.Errorf("signal %d is already closed", signal.ID)
+		}
+		return s.AccountUseCase.AddOrder(ctx, signal.AccountID, signal)
+	})
+}
+
+// open fills the entry on its market.
+func (s SignalUseCase) open(ctx context.Context, mode entities.ExecutionMode, e EntrySignal, price decimal.Decimal, notional decimal.Decimal, rates fees.Rates) (Execution, error) {
+	if e.Market != entities.Futures {
+		return s.executor(mode).Buy(ctx, e.Symbol, e.OrderType, price, notional, rates)
+	}
+	return s.futuresExecutor(mode).Open(ctx, FuturesOrder{
+		Symbol:     e.Symbol,
+		Side:       e.Side,
+		OrderType:  e.OrderType,
+		Price:      price,
+		Quantity:   notional.Div(price),
+		Leverage:   e.Leverage,
+		MarginType: e.MarginType,
+	}, rates)
+}
+
+// close fills the exit of the signal on its market and returns the funding
+// paid by futures positions.
+func (s SignalUseCase) close(ctx context.Context, signal entities.Signal, price decimal.Decimal, quantity decimal.Decimal, rates fees.Rates) (Execution, decimal.Decimal, error) {
+	if signal.Market != entities.Futures {
+		execution, err := s.executor(signal.Mode).Sell(ctx, signal.Symbol, price, quantity, rates)
+		return execution, decimal.Zero, err
+	}
+
+	executor := s.futuresExecutor(signal.Mode)
+	execution, err := executor.Close(ctx, FuturesOrder{
+		Symbol:           signal.Symbol,
+		Side:             signal.Side,
+		OrderType:        broker.Market,
+		Price:            price,
+		Quantity:         quantity,
+		LiquidationPrice: signal.Orders[0].LiquidationPrice,
+	}, rates)
 	if err != nil {
-		return err
+		return Execution{}, decimal.Zero, err
+	}
+	funding, err := executor.Funding(ctx, signal)
+	if err != nil {
+		// The position is already closed, funding is not worth failing it.
+		log.Printf("Booking signal %d without funding: %v", signal.ID, err)
+		funding = decimal.Zero
+	}
+	return execution, funding, nil
+}
+
+// profit returns the PnL of the signal closed by execution, net of fees and
+// funding. Shorts gain when the price falls and isolated futures positions
+// can't lose more than their margin.
+func profit(signal entities.Signal, execution Execution, funding decimal.Decimal) decimal.Decimal {
+	order := signal.Orders[0]
+	gross := execution.Price.Sub(order.EntryPrice).Mul(execution.Quantity)
+	if signal.Side == entities.Short {
+		gross = gross.Neg()
 	}
+	if signal.Market == entities.Futures && order.MarginType != entities.Cross && gross.LessThan(order.InvestedAmount.Neg()) {
+		gross = order.InvestedAmount.Neg()
+	}
+	return gross.Sub(execution.Fee.Add(order.EntryFee)).Sub(funding)
+}
 
-	if openSignal.ID != 0 {
-		openSignal.Status = entities.SignalStatus(entities.Closed)
-		openSignal.Orders[0].ExitPrice = e.ExitPrice
-		openSignal.Orders[0].ExitFee = calculateExitFee(openSignal.Orders[0], e.ExitPrice)
-		openSignal.Orders[0].UpdatedAt = time.Now()
-		openSignal.Orders[0].IsClosing = true
-		profit := (e.ExitPrice - openSignal.Orders[0].EntryPrice) * float32(openSignal.Orders[0].Quantity)
-		profit = profit - (openSignal.Orders[0].ExitFee + openSignal.Orders[0].EntryFee)
-		openSignal.Orders[0].Profit = profit
+// margin returns what a position of notional reserves at leverage.
+func margin(notional decimal.Decimal, leverage int) decimal.Decimal {
+	if leverage <= 1 {
+		return notional
+	}
+	return notional.Div(decimal.NewFromInt(int64(leverage)))
+}
 
-		err = s.Repository.Update(openSignal)
+// symbolFilters returns the trading rules of the symbol on the market.
+// Backtests run without a broker, their orders are not filtered.
+func (s SignalUseCase) symbolFilters(ctx context.Context, market entities.Market, symbol string) (broker.SymbolFilters, error) {
+	if market == entities.Futures && s.FuturesBroker != nil {
+		filters, err := s.FuturesBroker.GetSymbolFilters(ctx, symbol)
 		if err != nil {
-			return err
+			return broker.SymbolFilters{}, fmt.Errorf("failed to get futures filters for symbol %s: %w", symbol, err)
 		}
-		return s.AccountUseCase.AddOrder(openSignal.Orders[0].InvestedAmount + profit)
-	} else {
-		return fmt.Errorf("signal not found for symbol %s and strategy ID %d", e.Symbol, e.StrategyID)
+		return filters, nil
 	}
+	if s.Broker == nil {
+		return broker.SymbolFilters{Symbol: symbol}, nil
+	}
+	filters, err := s.Broker.GetSymbolFilters(ctx, symbol)
+	if err != nil {
+		return broker.SymbolFilters{}, fmt.Errorf("failed to get filters for symbol %s: %w", symbol, err)
+	}
+	return filters, nil
 }
 
-func calculateEntryFee(InvestedAmount float32) float32 {
-	feePct := 0.1
-	fee := float32(float64(InvestedAmount) * feePct / 100)
-	return fee
+// size rounds an order of amount at price to the filters of the symbol and
+// returns the price and the quote amount of the rounded quantity.
+func size(filters broker.SymbolFilters, price decimal.Decimal, amount decimal.Decimal) (decimal.Decimal, decimal.Decimal, error) {
+	price = filters.RoundPrice(price)
+	if !price.IsPositive() {
+		return decimal.Zero, decimal.Zero, fmt.Errorf("price of %s is below the tick size of %s", filters.Symbol, filters.TickSize)
+	}
+	quantity := amount.Div(price)
+	rounded := filters.RoundQuantity(quantity)
+	if err := filters.Check(rounded, price); err != nil {
+		return decimal.Zero, decimal.Zero, err
+	}
+	if rounded.Equal(quantity) {
+		return price, amount, nil
+	}
+	return price, rounded.Mul(price), nil
 }
 
-func calculateExitFee(order entities.Order, sellPrice float32) float32 {
-	feePct := 0.1
-	total := float64(order.Quantity) * float64(sellPrice)
-	fee := float32(total * feePct / 100)
-	return fee
+// TrackHighWaterMark moves the high-water mark of an open signal when the
+// price goes past it, above for longs and below for shorts, and returns the
+// mark.
+func (s SignalUseCase) TrackHighWaterMark(signal entities.Signal, price decimal.Decimal) (decimal.Decimal, error) {
+	mark := signal.HighWaterMark
+	if mark.IsZero() && len(signal.Orders) > 0 {
+		// Signals opened before marks were tracked start from the entry.
+		mark = signal.Orders[0].EntryPrice
+	}
+	better := price.GreaterThan(mark)
+	if signal.Side == entities.Short {
+		better = price.LessThan(mark)
+	}
+	if !better && !signal.HighWaterMark.IsZero() {
+		return mark, nil
+	}
+	if better {
+		mark = price
+	}
+	return mark, s.Repository.UpdateHighWaterMark(signal.ID, mark)
+}
+
+// feeVolumeWindow is the period the volume of fee tiers is summed over.
+const feeVolumeWindow = 30 * 24 * time.Hour
+
+// feeRates returns the rates of the fee model of the account, at the tier
+// reached by its volume.
+func (s SignalUseCase) feeRates(ctx context.Context, accountID int64) (fees.Rates, error) {
+	account, err := s.AccountUseCase.GetAccount(accountID)
+	if err != nil {
+		return fees.Rates{}, err
+	}
+	model, ok := s.Fees.Model(account.FeeModel)
+	if !ok {
+		return fees.Rates{}, fmt.Errorf("unknown fee model %s of account %d", account.FeeModel, accountID)
+	}
+	volume, err := s.Repository.TradedVolume(ctx, accountID, time.Now().Add(-feeVolumeWindow))
+	if err != nil {
+		return fees.Rates{}, err
+	}
+	return model.Rates(volume), nil
 }
 
 func (s SignalUseCase) GetOpenSignal(symbol string, strategyId uint) (entities.Signal, error) {
-	openSignal, err := s.Repository.GetOpenSignals(symbol, strategyId)
+	openSignal, err := s.Repository.GetOpenSignals(context.Background(), symbol, strategyId)
 	if err != nil {
 		return entities.Signal{}, err
 	}
@@ -185,16 +628,156 @@ func (s SignalUseCase) Close(ctx context.Context, id uint) error {
 	if err != nil {
 		return err
 	}
-
-	price, err := strconv.ParseFloat(ticker[0].Price, 32)
-	if err != nil {
-		return fmt.Errorf("failed to parse ticker price: %w", err)
+	if len(ticker) == 0 {
+		return fmt.Errorf("no ticker prices found for symbol %s", signal.Symbol)
 	}
 
 	return s.GenerateSellSignal(ExitSignal{
 		Symbol:     signal.Symbol,
 		StrategyID: signal.StrategyID,
-		ExitPrice:  float32(price),
+		ExitPrice:  decimal.NewFromFloat(ticker[0].Price),
 	})
 
 }
+
+// staleAfter is how long a signal stays pending or closing before it can
+// be resolved by hand, an order placed right now is still on its way.
+const staleAfter = time.Minute
+
+// Resolution is what the exchange reports for the order of a signal stuck
+// pending or closing. Without a fill the order never went through.
+type Resolution struct {
+	Filled   bool
+	OrderID  string
+	Price    decimal.Decimal
+	Quantity decimal.Decimal
+	Fee      decimal.Decimal
+}
+
+// Resolve settles a signal left pending or closing, e.g. by a crash between
+// the fill of its order and its booking, with what the exchange reports for
+// that order. A pending signal opens with the fill of its entry, or fails
+// and releases its reservation without one. A closing signal is booked with
+// the fill of its exit, or opens again without one. An exit already saved
+// is booked as it is.
+func (s SignalUseCase) Resolve(ctx context.Context, id uint, resolution Resolution) (entities.Signal, error) {
+	if resolution.Filled && (!resolution.Price.IsPositive() || !resolution.Quantity.IsPositive() || resolution.Fee.IsNegative()) {
+		return entities.Signal{}, customerror.New(http.StatusBadRequest, "A fill needs a positive price and quantity and a fee of at least 0")
+	}
+
+	signal, err := s.Repository.GetByID(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return entities.Signal{}, customerror.New(http.StatusNotFound, "Signal not found")
+	}
+	if err != nil {
+		return entities.Signal{}, err
+	}
+	if signal.Status != entities.Pending && signal.Status != entities.Closing {
+		return entities.Signal{}, customerror.New(http.StatusConflict, fmt.Sprintf("Signal %d is %s, only pending and closing signals can be resolved", id, signal.Status))
+	}
+	if time.Since(signal.UpdatedAt) < staleAfter {
+		return entities.Signal{}, customerror.New(http.StatusConflict, fmt.Sprintf("Signal %d changed less than %s ago, its order may still be on its way", id, staleAfter))
+	}
+
+	if signal.Status == entities.Pending {
+		err = s.resolveEntry(ctx, &signal, resolution)
+	} else {
+		err = s.resolveExit(ctx, &signal, resolution)
+	}
+	if err != nil {
+		return entities.Signal{}, err
+	}
+	log.Printf("Signal %d resolved %s", id, signal.Status)
+	return signal, nil
+}
+
+func (s SignalUseCase) resolveEntry(ctx context.Context, signal *entities.Signal, resolution Resolution) error {
+	if !resolution.Filled {
+		signal.Status = entities.Failed
+		return s.Transactions.Transaction(ctx, func(ctx context.Context) error {
+			failed, err := s.Repository.Transition(ctx, signal.ID, entities.Pending, entities.Failed)
+			if err != nil {
+				return err
+			}
+			if !failed {
+				return customerror.New(http.StatusConflict, fmt.Sprintf("Signal %d is no longer pending", signal.ID))
+			}
+			return s.AccountUseCase.ReleaseOrder(ctx, signal.AccountID, *signal)
+		})
+	}
+
+	signal.Status = entities.Open
+	signal.HighWaterMark = resolution.Price
+	order := &signal.Orders[0]
+	order.BrokerOrderID = resolution.OrderID
+	order.EntryPrice = resolution.Price
+	order.Quantity = resolution.Quantity
+	order.EntryFee = resolution.Fee
+	order.ExecutedQty = resolution.Quantity
+	if signal.Market == entities.Futures {
+		order.LiquidationPrice = liquidationPrice(FuturesOrder{
+			Side:       signal.Side,
+			Price:      resolution.Price,
+			Leverage:   int(order.Leverage),
+			MarginType: order.MarginType,
+		})
+	}
+	order.UpdatedAt = time.Now()
+
+	opened, err := s.Repository.Open(ctx, *signal)
+	if err != nil {
+		return err
+	}
+	if !opened {
+		return customerror.New(http.StatusConflict, fmt.Sprintf("Signal %d is no longer pending", signal.ID))
+	}
+	return nil
+}
+
+func (s SignalUseCase) resolveExit(ctx context.Context, signal *entities.Signal, resolution Resolution) error {
+	order := &signal.Orders[0]
+	if !order.IsClosing && !resolution.Filled {
+		reopened, err := s.Repository.Transition(ctx, signal.ID, entities.Closing, entities.Open)
+		if err != nil {
+			return err
+		}
+		if !reopened {
+			return customerror.New(http.StatusConflict, fmt.Sprintf("Signal %d is no longer closing", signal.ID))
+		}
+		signal.Status = entities.Open
+		return nil
+	}
+
+	if !order.IsClosing {
+		funding := decimal.Zero
+		if signal.Market == entities.Futures {
+			paid, err := s.futuresExecutor(signal.Mode).Funding(ctx, *signal)
+			if err != nil {
+				log.Printf("Booking signal %d without funding: %v", signal.ID, err)
+			} else {
+				funding = paid
+			}
+		}
+		execution := Execution{
+			BrokerOrderID: resolution.OrderID,
+			Price:         resolution.Price,
+			Quantity:      resolution.Quantity,
+			Fee:           resolution.Fee,
+		}
+		order.ExitOrderID = execution.BrokerOrderID
+		order.ExitPrice = execution.Price
+		order.ExitFee = execution.Fee
+		order.UpdatedAt = time.Now()
+		order.IsClosing = true
+		order.FundingFee = funding
+		order.Profit = profit(*signal, execution, funding)
+		if err := s.Repository.SaveOrder(ctx, *order); err != nil {
+			return err
+		}
+	}
+	if err := s.book(ctx, *signal); err != nil {
+		return err
+	}
+	signal.Status = entities.Closed
+	return nil
+}
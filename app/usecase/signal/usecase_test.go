@@ -9,30 +9,40 @@ import (
 
 	"go-trade-bot/app/entities"
 	"go-trade-bot/app/usecase/signal/mocks"
+	"go-trade-bot/internal/broker"
+	"go-trade-bot/internal/configuration"
+	"go-trade-bot/internal/customerror"
+	"go-trade-bot/internal/fees"
+	"net/http"
 
-	"github.com/adshao/go-binance/v2"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
 )
 
 func TestSignalUseCase_GenerateBuySignal(t *testing.T) {
-	mockRepo := new(mocks.SignalRepository)
-	mockAccountUseCase := new(mocks.AccountUseCase)
-	mockBroker := new(mocks.Broker)
-	signalUC := usecase.NewSignalUseCase(mockRepo, mockAccountUseCase, mockBroker)
+	mockRepo := newRepository()
+	mockAccountUseCase := newAccounts()
+	mockBroker := newBroker()
+	signalUC := usecase.NewSignalUseCase(mockRepo, mockAccountUseCase, mockBroker, transactor(), schedule(), newRisk(), nil)
 
 	t.Run("should create a buy signal", func(t *testing.T) {
 		entrySignal := usecase.EntrySignal{
 			Symbol:     "BTCUSDT",
 			StrategyID: 1,
-			EntryPrice: 50000,
+			AccountID:  2,
+			EntryPrice: d(50000),
 			MarginType: entities.Isolated,
 		}
-		mockAccountUseCase.On("CanOpenOrder").Return(true, nil).Once()
-		mockAccountUseCase.On("GetDisponibleAmout").Return(float32(1000), nil).Once()
-		mockAccountUseCase.On("DeductOrder", float32(1000)).Return(nil).Once()
-		mockRepo.On("GetOpenSignals", entrySignal.Symbol, entrySignal.StrategyID).Return(entities.Signal{}, nil).Once()
-		mockRepo.On("Create", mock.Anything).Return(nil).Once()
+		mockAccountUseCase.On("CanOpenOrder", mock.Anything, int64(2)).Return(true, nil).Once()
+		mockAccountUseCase.On("GetDisponibleAmout", mock.Anything, int64(2)).Return(d(1000), nil).Once()
+		mockAccountUseCase.On("DeductOrder", mock.Anything, int64(2), worth(1000)).Return(nil).Once()
+		mockRepo.On("GetOpenSignals", mock.Anything, entrySignal.Symbol, entrySignal.StrategyID).Return(entities.Signal{}, nil).Once()
+		mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(s entities.Signal) bool {
+			return s.AccountID == 2 && s.Status == entities.Pending
+		})).Return(created, nil).Once()
+		mockRepo.On("Open", mock.Anything, mock.Anything).Return(true, nil).Once()
 
 		err := signalUC.GenerateBuySignal(entrySignal)
 		assert.NoError(t, err)
@@ -44,7 +54,8 @@ func TestSignalUseCase_GenerateBuySignal(t *testing.T) {
 		entrySignal := usecase.EntrySignal{
 			Symbol:     "BTCUSDT",
 			StrategyID: 1,
-			EntryPrice: 50000,
+			AccountID:  2,
+			EntryPrice: d(50000),
 			MarginType: entities.Isolated,
 		}
 
@@ -56,8 +67,8 @@ func TestSignalUseCase_GenerateBuySignal(t *testing.T) {
 			CreatedAt:  time.Now(),
 			UpdatedAt:  time.Now(),
 		}
-		mockAccountUseCase.On("CanOpenOrder").Return(true, nil).Once()
-		mockRepo.On("GetOpenSignals", entrySignal.Symbol, entrySignal.StrategyID).Return(existingSignal, nil).Once()
+		mockAccountUseCase.On("CanOpenOrder", mock.Anything, int64(2)).Return(true, nil).Once()
+		mockRepo.On("GetOpenSignals", mock.Anything, entrySignal.Symbol, entrySignal.StrategyID).Return(existingSignal, nil).Once()
 
 		err := signalUC.GenerateBuySignal(entrySignal)
 		assert.NoError(t, err)
@@ -69,11 +80,12 @@ func TestSignalUseCase_GenerateBuySignal(t *testing.T) {
 		entrySignal := usecase.EntrySignal{
 			Symbol:     "BTCUSDT",
 			StrategyID: 1,
-			EntryPrice: 50000,
+			AccountID:  2,
+			EntryPrice: d(50000),
 			MarginType: entities.Isolated,
 		}
-		mockAccountUseCase.On("CanOpenOrder").Return(true, nil).Once()
-		mockRepo.On("GetOpenSignals", entrySignal.Symbol, entrySignal.StrategyID).Return(entities.Signal{}, errors.New("database error")).Once()
+		mockAccountUseCase.On("CanOpenOrder", mock.Anything, int64(2)).Return(true, nil).Once()
+		mockRepo.On("GetOpenSignals", mock.Anything, entrySignal.Symbol, entrySignal.StrategyID).Return(entities.Signal{}, errors.New("database error")).Once()
 		err := signalUC.GenerateBuySignal(entrySignal)
 		assert.Error(t, err)
 		assert.Equal(t, "database error", err.Error())
@@ -83,13 +95,14 @@ func TestSignalUseCase_GenerateBuySignal(t *testing.T) {
 		entrySignal := usecase.EntrySignal{
 			Symbol:     "BTCUSDT",
 			StrategyID: 1,
-			EntryPrice: 50000,
+			AccountID:  2,
+			EntryPrice: d(50000),
 			MarginType: entities.Isolated,
 		}
-		mockAccountUseCase.On("CanOpenOrder").Return(true, nil).Once()
-		mockAccountUseCase.On("GetDisponibleAmout").Return(float32(1000), nil).Once()
-		mockRepo.On("GetOpenSignals", entrySignal.Symbol, entrySignal.StrategyID).Return(entities.Signal{}, nil).Once()
-		mockRepo.On("Create", mock.Anything).Return(errors.New("database error")).Once()
+		mockAccountUseCase.On("CanOpenOrder", mock.Anything, int64(2)).Return(true, nil).Once()
+		mockAccountUseCase.On("GetDisponibleAmout", mock.Anything, int64(2)).Return(d(1000), nil).Once()
+		mockRepo.On("GetOpenSignals", mock.Anything, entrySignal.Symbol, entrySignal.StrategyID).Return(entities.Signal{}, nil).Once()
+		mockRepo.On("Create", mock.Anything, mock.Anything).Return(entities.Signal{}, errors.New("database error")).Once()
 
 		err := signalUC.GenerateBuySignal(entrySignal)
 		assert.Error(t, err)
@@ -101,14 +114,16 @@ func TestSignalUseCase_GenerateBuySignal(t *testing.T) {
 		entrySignal := usecase.EntrySignal{
 			Symbol:     "BTCUSDT",
 			StrategyID: 1,
-			EntryPrice: 50000,
+			AccountID:  2,
+			EntryPrice: d(50000),
 			MarginType: entities.Isolated,
 		}
-		mockAccountUseCase.On("CanOpenOrder").Return(true, nil).Once()
-		mockAccountUseCase.On("GetDisponibleAmout").Return(float32(1000), nil).Once()
-		mockAccountUseCase.On("DeductOrder", float32(1000)).Return(nil).Once()
-		mockRepo.On("GetOpenSignals", entrySignal.Symbol, entrySignal.StrategyID).Return(entities.Signal{}, nil).Once()
-		mockRepo.On("Create", mock.Anything).Return(nil).Once()
+		mockAccountUseCase.On("CanOpenOrder", mock.Anything, int64(2)).Return(true, nil).Once()
+		mockAccountUseCase.On("GetDisponibleAmout", mock.Anything, int64(2)).Return(d(1000), nil).Once()
+		mockAccountUseCase.On("DeductOrder", mock.Anything, int64(2), worth(1000)).Return(nil).Once()
+		mockRepo.On("GetOpenSignals", mock.Anything, entrySignal.Symbol, entrySignal.StrategyID).Return(entities.Signal{}, nil).Once()
+		mockRepo.On("Create", mock.Anything, mock.Anything).Return(created, nil).Once()
+		mockRepo.On("Open", mock.Anything, mock.Anything).Return(true, nil).Once()
 
 		err := signalUC.GenerateBuySignal(entrySignal)
 		assert.NoError(t, err)
@@ -119,32 +134,137 @@ func TestSignalUseCase_GenerateBuySignal(t *testing.T) {
 		entrySignal := usecase.EntrySignal{
 			Symbol:     "BTCUSDT",
 			StrategyID: 1,
-			EntryPrice: 50000,
+			AccountID:  2,
+			EntryPrice: d(50000),
 		}
-		mockAccountUseCase.On("CanOpenOrder").Return(true, nil).Once()
-		mockAccountUseCase.On("GetDisponibleAmout").Return(float32(1000), nil).Once()
-		mockAccountUseCase.On("DeductOrder", float32(1000)).Return(nil).Once()
-		mockRepo.On("GetOpenSignals", entrySignal.Symbol, entrySignal.StrategyID).Return(entities.Signal{}, nil).Once()
-		mockRepo.On("Create", mock.Anything).Return(nil).Once()
+		mockAccountUseCase.On("CanOpenOrder", mock.Anything, int64(2)).Return(true, nil).Once()
+		mockAccountUseCase.On("GetDisponibleAmout", mock.Anything, int64(2)).Return(d(1000), nil).Once()
+		mockAccountUseCase.On("DeductOrder", mock.Anything, int64(2), worth(1000)).Return(nil).Once()
+		mockRepo.On("GetOpenSignals", mock.Anything, entrySignal.Symbol, entrySignal.StrategyID).Return(entities.Signal{}, nil).Once()
+		mockRepo.On("Create", mock.Anything, mock.Anything).Return(created, nil).Once()
+		mockRepo.On("Open", mock.Anything, mock.Anything).Return(true, nil).Once()
 
 		err := signalUC.GenerateBuySignal(entrySignal)
 		assert.NoError(t, err)
 
 		mockRepo.AssertExpectations(t)
 	})
+	t.Run("should place a market order on the broker for live signals", func(t *testing.T) {
+		entrySignal := usecase.EntrySignal{
+			Symbol:     "BTCUSDT",
+			StrategyID: 1,
+			AccountID:  2,
+			EntryPrice: d(50000),
+			MarginType: entities.Isolated,
+			Mode:       entities.Live,
+		}
+		mockAccountUseCase.On("CanOpenOrder", mock.Anything, int64(2)).Return(true, nil).Once()
+		mockAccountUseCase.On("GetDisponibleAmout", mock.Anything, int64(2)).Return(d(1000), nil).Once()
+		mockAccountUseCase.On("DeductOrder", mock.Anything, int64(2), worth(1000)).Return(nil).Once()
+		mockRepo.On("GetOpenSignals", mock.Anything, entrySignal.Symbol, entrySignal.StrategyID).Return(entities.Signal{}, nil).Once()
+		mockBroker.On("PlaceOrder", mock.Anything, broker.OrderRequest{
+			Symbol:        "BTCUSDT",
+			Side:          broker.Buy,
+			Type:          broker.Market,
+			QuoteQuantity: 1000,
+			FeeRate:       0.001,
+		}).Return(broker.OrderResult{
+			OrderID:       "42",
+			Symbol:        "BTCUSDT",
+			ExecutedQty:   0.02,
+			QuoteQuantity: 999,
+			Fills: []broker.Fill{
+				{Price: 49950, Quantity: 0.02, Commission: 0.999, CommissionAsset: "USDT"},
+			},
+		}, nil).Once()
+		mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(s entities.Signal) bool {
+			return s.Mode == entities.Live && s.Status == entities.Pending && s.Orders[0].BrokerOrderID == ""
+		})).Return(created, nil).Once()
+		mockRepo.On("Open", mock.Anything, mock.MatchedBy(func(s entities.Signal) bool {
+			order := s.Orders[0]
+			return s.ID == 1 && s.Status == entities.Open &&
+				order.BrokerOrderID == "42" &&
+				order.EntryPrice.Equal(d(49950)) &&
+				order.ExecutedQty.Equal(d(0.02)) &&
+				order.EntryFee.Equal(d(0.999))
+		})).Return(true, nil).Once()
+
+		err := signalUC.GenerateBuySignal(entrySignal)
+		assert.NoError(t, err)
+
+		mockRepo.AssertExpectations(t)
+		mockBroker.AssertExpectations(t)
+		mockAccountUseCase.AssertExpectations(t)
+	})
+	t.Run("should fail the signal and release its reservation if the live order fails", func(t *testing.T) {
+		mockRepo := newRepository()
+		mockAccountUseCase := newAccounts()
+		mockBroker := newBroker()
+		signalUC := usecase.NewSignalUseCase(mockRepo, mockAccountUseCase, mockBroker, transactor(), schedule(), newRisk(), nil)
+		entrySignal := usecase.EntrySignal{
+			Symbol:     "BTCUSDT",
+			StrategyID: 1,
+			AccountID:  2,
+			EntryPrice: d(50000),
+			Mode:       entities.Live,
+		}
+		mockAccountUseCase.On("CanOpenOrder", mock.Anything, int64(2)).Return(true, nil).Once()
+		mockAccountUseCase.On("GetDisponibleAmout", mock.Anything, int64(2)).Return(d(1000), nil).Once()
+		mockAccountUseCase.On("DeductOrder", mock.Anything, int64(2), worth(1000)).Return(nil).Once()
+		mockAccountUseCase.On("ReleaseOrder", mock.Anything, int64(2), worth(1000)).Return(nil).Once()
+		mockRepo.On("GetOpenSignals", mock.Anything, entrySignal.Symbol, entrySignal.StrategyID).Return(entities.Signal{}, nil).Once()
+		mockRepo.On("Create", mock.Anything, mock.Anything).Return(created, nil).Once()
+		mockRepo.On("Transition", mock.Anything, uint(1), entities.Pending, entities.Failed).Return(true, nil).Once()
+		mockBroker.On("PlaceOrder", mock.Anything, mock.Anything).Return(broker.OrderResult{}, errors.New("insufficient balance")).Once()
+
+		err := signalUC.GenerateBuySignal(entrySignal)
+		assert.EqualError(t, err, "failed to place buy order for BTCUSDT: insufficient balance")
+
+		mockRepo.AssertExpectations(t)
+		mockBroker.AssertExpectations(t)
+		mockAccountUseCase.AssertExpectations(t)
+		mockRepo.AssertNotCalled(t, "Open", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should keep the signal pending when its fill can't be saved", func(t *testing.T) {
+		mockRepo := newRepository()
+		mockAccountUseCase := newAccounts()
+		mockBroker := newBroker()
+		signalUC := usecase.NewSignalUseCase(mockRepo, mockAccountUseCase, mockBroker, transactor(), schedule(), newRisk(), nil)
+		entrySignal := usecase.EntrySignal{
+			Symbol:     "BTCUSDT",
+			StrategyID: 1,
+			AccountID:  2,
+			EntryPrice: d(50000),
+			Mode:       entities.Live,
+		}
+		mockAccountUseCase.On("CanOpenOrder", mock.Anything, int64(2)).Return(true, nil).Once()
+		mockAccountUseCase.On("GetDisponibleAmout", mock.Anything, int64(2)).Return(d(1000), nil).Once()
+		mockAccountUseCase.On("DeductOrder", mock.Anything, int64(2), mock.Anything).Return(nil).Once()
+		mockRepo.On("GetOpenSignals", mock.Anything, entrySignal.Symbol, entrySignal.StrategyID).Return(entities.Signal{}, nil).Once()
+		mockRepo.On("Create", mock.Anything, mock.Anything).Return(created, nil).Once()
+		mockBroker.On("PlaceOrder", mock.Anything, mock.Anything).Return(broker.OrderResult{OrderID: "44", ExecutedQty: 0.02, QuoteQuantity: 1000}, nil).Once()
+		mockRepo.On("Open", mock.Anything, mock.Anything).Return(false, errors.New("connection lost")).Once()
+
+		err := signalUC.GenerateBuySignal(entrySignal)
+		assert.EqualError(t, err, "entry order 44 of signal 1 was filled but not saved, the signal stays pending: connection lost")
+
+		mockRepo.AssertNotCalled(t, "Transition", mock.Anything, uint(1), entities.Pending, entities.Failed)
+		mockAccountUseCase.AssertNotCalled(t, "ReleaseOrder", mock.Anything, mock.Anything, mock.Anything)
+	})
 }
 
 func TestSignalUseCase_GenerateSellSignal(t *testing.T) {
-	mockRepo := new(mocks.SignalRepository)
-	mockAccountUseCase := new(mocks.AccountUseCase)
-	mockBroker := new(mocks.Broker)
-	signalUC := usecase.NewSignalUseCase(mockRepo, mockAccountUseCase, mockBroker)
+	mockRepo := newRepository()
+	mockAccountUseCase := newAccounts()
+	mockBroker := newBroker()
+	signalUC := usecase.NewSignalUseCase(mockRepo, mockAccountUseCase, mockBroker, transactor(), schedule(), newRisk(), nil)
 
 	t.Run("should create a sell signal", func(t *testing.T) {
 		exitSignal := usecase.ExitSignal{
 			Symbol:     "BTCUSDT",
 			StrategyID: 1,
-			ExitPrice:  60000,
+			ExitPrice:  d(60000),
 		}
 
 		openSignal := entities.Signal{
@@ -152,27 +272,30 @@ func TestSignalUseCase_GenerateSellSignal(t *testing.T) {
 			Symbol:     exitSignal.Symbol,
 			Status:     entities.Open,
 			StrategyID: exitSignal.StrategyID,
+			AccountID:  2,
 			CreatedAt:  time.Now(),
 			UpdatedAt:  time.Now(),
 			Orders: []entities.Order{
 				{
-					EntryPrice:  50000,
-					ExitPrice:   50001,
-					Quantity:    0.02,
+					EntryPrice:  d(50000),
+					ExitPrice:   d(50001),
+					Quantity:    d(0.02),
 					MarginType:  entities.Isolated,
-					EntryFee:    0.1,
-					ExitFee:     0,
+					EntryFee:    d(0.1),
+					ExitFee:     d(0),
 					Leverage:    1,
-					ExecutedQty: 0,
+					ExecutedQty: d(0),
 					IsClosing:   false,
 					CreatedAt:   time.Now(),
 					UpdatedAt:   time.Now(),
 				},
 			},
 		}
-		mockAccountUseCase.On("AddOrder", float32(198.7)).Return(nil).Once()
-		mockRepo.On("GetOpenSignals", exitSignal.Symbol, exitSignal.StrategyID).Return(openSignal, nil).Once()
-		mockRepo.On("Update", mock.Anything).Return(nil).Once()
+		mockAccountUseCase.On("AddOrder", mock.Anything, int64(2), worth(198.7)).Return(nil).Once()
+		mockRepo.On("GetOpenSignals", mock.Anything, exitSignal.Symbol, exitSignal.StrategyID).Return(openSignal, nil).Once()
+		mockRepo.On("Transition", mock.Anything, uint(1), entities.Open, entities.Closing).Return(true, nil).Once()
+		mockRepo.On("SaveOrder", mock.Anything, mock.Anything).Return(nil).Once()
+		mockRepo.On("Close", mock.Anything, mock.Anything).Return(true, nil).Once()
 
 		err := signalUC.GenerateSellSignal(exitSignal)
 		assert.NoError(t, err)
@@ -180,14 +303,67 @@ func TestSignalUseCase_GenerateSellSignal(t *testing.T) {
 		mockRepo.AssertExpectations(t)
 	})
 
+	t.Run("should sell on the broker when the signal is live", func(t *testing.T) {
+		exitSignal := usecase.ExitSignal{
+			Symbol:     "BTCUSDT",
+			StrategyID: 1,
+			ExitPrice:  d(60000),
+		}
+
+		openSignal := entities.Signal{
+			ID:         1,
+			Symbol:     exitSignal.Symbol,
+			Status:     entities.Open,
+			Mode:       entities.Live,
+			StrategyID: exitSignal.StrategyID,
+			AccountID:  2,
+			Orders: []entities.Order{
+				{
+					BrokerOrderID:  "42",
+					EntryPrice:     d(50000),
+					Quantity:       d(0.02),
+					InvestedAmount: d(1000),
+					EntryFee:       d(1),
+				},
+			},
+		}
+		mockRepo.On("GetOpenSignals", mock.Anything, exitSignal.Symbol, exitSignal.StrategyID).Return(openSignal, nil).Once()
+		mockBroker.On("PlaceOrder", mock.Anything, broker.OrderRequest{
+			Symbol:   "BTCUSDT",
+			Side:     broker.Sell,
+			Type:     broker.Market,
+			Quantity: 0.02,
+			FeeRate:  0.001,
+		}).Return(broker.OrderResult{
+			OrderID:       "43",
+			ExecutedQty:   0.02,
+			QuoteQuantity: 1100,
+			Fills: []broker.Fill{
+				{Price: 55000, Quantity: 0.02, Commission: 1, CommissionAsset: "USDT"},
+			},
+		}, nil).Once()
+		mockRepo.On("Transition", mock.Anything, uint(1), entities.Open, entities.Closing).Return(true, nil).Once()
+		mockRepo.On("SaveOrder", mock.Anything, mock.Anything).Return(nil).Once()
+		mockRepo.On("Close", mock.Anything, mock.MatchedBy(func(s entities.Signal) bool {
+			return s.Orders[0].ExitOrderID == "43" && s.Orders[0].ExitFee.Equal(d(1))
+		})).Return(true, nil).Once()
+		mockAccountUseCase.On("AddOrder", mock.Anything, int64(2), mock.Anything).Return(nil).Once()
+
+		err := signalUC.GenerateSellSignal(exitSignal)
+		assert.NoError(t, err)
+
+		mockRepo.AssertExpectations(t)
+		mockBroker.AssertExpectations(t)
+	})
+
 	t.Run("should return error if GetOpenSignals fails", func(t *testing.T) {
 		exitSignal := usecase.ExitSignal{
 			Symbol:     "BTCUSDT",
 			StrategyID: 1,
-			ExitPrice:  60000,
+			ExitPrice:  d(60000),
 		}
 
-		mockRepo.On("GetOpenSignals", exitSignal.Symbol, exitSignal.StrategyID).Return(entities.Signal{}, errors.New("database error")).Once()
+		mockRepo.On("GetOpenSignals", mock.Anything, exitSignal.Symbol, exitSignal.StrategyID).Return(entities.Signal{}, errors.New("database error")).Once()
 
 		err := signalUC.GenerateSellSignal(exitSignal)
 		assert.Error(t, err)
@@ -200,7 +376,7 @@ func TestSignalUseCase_GenerateSellSignal(t *testing.T) {
 		exitSignal := usecase.ExitSignal{
 			Symbol:     "BTCUSDT",
 			StrategyID: 1,
-			ExitPrice:  60000,
+			ExitPrice:  d(60000),
 		}
 
 		openSignal := entities.Signal{
@@ -208,19 +384,20 @@ func TestSignalUseCase_GenerateSellSignal(t *testing.T) {
 			Symbol:     exitSignal.Symbol,
 			Status:     entities.Open,
 			StrategyID: exitSignal.StrategyID,
+			AccountID:  2,
 			CreatedAt:  time.Now(),
 			UpdatedAt:  time.Now(),
 			Orders: []entities.Order{
 				{
-					EntryPrice:     50000,
-					ExitPrice:      0,
-					Quantity:       0.02,
-					InvestedAmount: 1000,
+					EntryPrice:     d(50000),
+					ExitPrice:      d(0),
+					Quantity:       d(0.02),
+					InvestedAmount: d(1000),
 					MarginType:     entities.Isolated,
-					EntryFee:       0.1,
-					ExitFee:        0,
+					EntryFee:       d(0.1),
+					ExitFee:        d(0),
 					Leverage:       1,
-					ExecutedQty:    0,
+					ExecutedQty:    d(0),
 					IsClosing:      false,
 					CreatedAt:      time.Now(),
 					UpdatedAt:      time.Now(),
@@ -228,9 +405,11 @@ func TestSignalUseCase_GenerateSellSignal(t *testing.T) {
 			},
 		}
 
-		mockRepo.On("GetOpenSignals", exitSignal.Symbol, exitSignal.StrategyID).Return(openSignal, nil).Once()
-		mockRepo.On("Update", mock.Anything).
-			Return(errors.New("database error")).Once()
+		mockRepo.On("GetOpenSignals", mock.Anything, exitSignal.Symbol, exitSignal.StrategyID).Return(openSignal, nil).Once()
+		mockRepo.On("Transition", mock.Anything, uint(1), entities.Open, entities.Closing).Return(true, nil).Once()
+		mockRepo.On("SaveOrder", mock.Anything, mock.Anything).Return(nil).Once()
+		mockRepo.On("Close", mock.Anything, mock.Anything).
+			Return(false, errors.New("database error")).Once()
 		err := signalUC.GenerateSellSignal(exitSignal)
 		assert.Error(t, err)
 		assert.Equal(t, "database error", err.Error())
@@ -240,10 +419,10 @@ func TestSignalUseCase_GenerateSellSignal(t *testing.T) {
 		exitSignal := usecase.ExitSignal{
 			Symbol:     "BTCUSDT",
 			StrategyID: 1,
-			ExitPrice:  60000,
+			ExitPrice:  d(60000),
 		}
 
-		mockRepo.On("GetOpenSignals", exitSignal.Symbol, exitSignal.StrategyID).Return(entities.Signal{}, nil).Once()
+		mockRepo.On("GetOpenSignals", mock.Anything, exitSignal.Symbol, exitSignal.StrategyID).Return(entities.Signal{}, nil).Once()
 
 		err := signalUC.GenerateSellSignal(exitSignal)
 		assert.Error(t, err)
@@ -252,31 +431,362 @@ func TestSignalUseCase_GenerateSellSignal(t *testing.T) {
 		mockRepo.AssertExpectations(t)
 	})
 }
+func TestSignalUseCase_Atomicity(t *testing.T) {
+	t.Run("should fail the buy when the account refuses the reservation", func(t *testing.T) {
+		mockRepo := newRepository()
+		mockAccountUseCase := newAccounts()
+		tx := transactor()
+		signalUC := usecase.NewSignalUseCase(mockRepo, mockAccountUseCase, newBroker(), tx, schedule(), newRisk(), nil)
+
+		entrySignal := usecase.EntrySignal{Symbol: "BTCUSDT", StrategyID: 1, AccountID: 2, EntryPrice: d(50000)}
+		mockAccountUseCase.On("CanOpenOrder", mock.Anything, int64(2)).Return(true, nil).Once()
+		mockAccountUseCase.On("GetDisponibleAmout", mock.Anything, int64(2)).Return(d(1000), nil).Once()
+		mockAccountUseCase.On("DeductOrder", mock.Anything, int64(2), mock.Anything).Return(errors.New("Account has no available orders")).Once()
+		mockRepo.On("GetOpenSignals", mock.Anything, entrySignal.Symbol, entrySignal.StrategyID).Return(entities.Signal{}, nil).Once()
+		mockRepo.On("Create", mock.Anything, mock.Anything).Return(created, nil).Once()
+
+		err := signalUC.GenerateBuySignal(entrySignal)
+		assert.EqualError(t, err, "Account has no available orders")
+		tx.AssertNumberOfCalls(t, "Transaction", 1)
+	})
+
+	t.Run("should not credit a signal closed concurrently", func(t *testing.T) {
+		mockRepo := newRepository()
+		mockAccountUseCase := newAccounts()
+		signalUC := usecase.NewSignalUseCase(mockRepo, mockAccountUseCase, newBroker(), transactor(), schedule(), newRisk(), nil)
+
+		openSignal := entities.Signal{
+			ID:         7,
+			Symbol:     "BTCUSDT",
+			Status:     entities.Open,
+			StrategyID: 1,
+			AccountID:  2,
+			Orders:     []entities.Order{{EntryPrice: d(50000), Quantity: d(0.02), InvestedAmount: d(1000)}},
+		}
+		mockRepo.On("GetOpenSignals", mock.Anything, "BTCUSDT", uint(1)).Return(openSignal, nil).Once()
+		mockRepo.On("Transition", mock.Anything, uint(7), entities.Open, entities.Closing).Return(false, nil).Once()
+
+		err := signalUC.GenerateSellSignal(usecase.ExitSignal{Symbol: "BTCUSDT", StrategyID: 1, ExitPrice: d(51000)})
+		assert.EqualError(t, err, "signal 7 is already being closed")
+		mockAccountUseCase.AssertNotCalled(t, "AddOrder", mock.Anything, mock.Anything, mock.Anything)
+		mockRepo.AssertNotCalled(t, "Close", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should reopen the signal when the exit order fails", func(t *testing.T) {
+		mockRepo := newRepository()
+		mockBroker := newBroker()
+		signalUC := usecase.NewSignalUseCase(mockRepo, newAccounts(), mockBroker, transactor(), schedule(), newRisk(), nil)
+
+		openSignal := entities.Signal{
+			ID:         7,
+			Symbol:     "BTCUSDT",
+			Status:     entities.Open,
+			Mode:       entities.Live,
+			StrategyID: 1,
+			AccountID:  2,
+			Orders:     []entities.Order{{EntryPrice: d(50000), Quantity: d(0.02), InvestedAmount: d(1000)}},
+		}
+		mockRepo.On("GetOpenSignals", mock.Anything, "BTCUSDT", uint(1)).Return(openSignal, nil).Once()
+		mockRepo.On("Transition", mock.Anything, uint(7), entities.Open, entities.Closing).Return(true, nil).Once()
+		mockBroker.On("PlaceOrder", mock.Anything, mock.Anything).Return(broker.OrderResult{}, errors.New("service unavailable")).Once()
+		mockRepo.On("Transition", mock.Anything, uint(7), entities.Closing, entities.Open).Return(true, nil).Once()
+
+		err := signalUC.GenerateSellSignal(usecase.ExitSignal{Symbol: "BTCUSDT", StrategyID: 1, ExitPrice: d(51000)})
+		assert.EqualError(t, err, "failed to place sell order for BTCUSDT: service unavailable")
+		mockRepo.AssertExpectations(t)
+		mockRepo.AssertNotCalled(t, "Close", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should book a saved exit without selling again", func(t *testing.T) {
+		mockRepo := newRepository()
+		mockAccountUseCase := newAccounts()
+		mockBroker := newBroker()
+		signalUC := usecase.NewSignalUseCase(mockRepo, mockAccountUseCase, mockBroker, transactor(), schedule(), newRisk(), nil)
+
+		closing := entities.Signal{
+			ID:         7,
+			Symbol:     "BTCUSDT",
+			Status:     entities.Closing,
+			Mode:       entities.Live,
+			StrategyID: 1,
+			AccountID:  2,
+			Orders: []entities.Order{{
+				EntryPrice:     d(50000),
+				ExitPrice:      d(51000),
+				Quantity:       d(0.02),
+				InvestedAmount: d(1000),
+				IsClosing:      true,
+				ExitOrderID:    "43",
+				Profit:         d(20),
+			}},
+		}
+		mockRepo.On("GetOpenSignals", mock.Anything, "BTCUSDT", uint(1)).Return(closing, nil).Once()
+		mockRepo.On("Close", mock.Anything, mock.MatchedBy(func(s entities.Signal) bool {
+			return s.Status == entities.Closed && s.Orders[0].ExitOrderID == "43"
+		})).Return(true, nil).Once()
+		mockAccountUseCase.On("AddOrder", mock.Anything, int64(2), worth(1020)).Return(nil).Once()
+
+		err := signalUC.GenerateSellSignal(usecase.ExitSignal{Symbol: "BTCUSDT", StrategyID: 1, ExitPrice: d(52000)})
+		assert.NoError(t, err)
+		mockBroker.AssertNotCalled(t, "PlaceOrder", mock.Anything, mock.Anything)
+		mockRepo.AssertExpectations(t)
+		mockAccountUseCase.AssertExpectations(t)
+	})
+}
+
+func TestSignalUseCase_SymbolFilters(t *testing.T) {
+	filters := broker.SymbolFilters{
+		Symbol:      "BTCUSDT",
+		TickSize:    decimal.RequireFromString("0.01"),
+		StepSize:    decimal.RequireFromString("0.00001"),
+		MinNotional: decimal.RequireFromString("5"),
+	}
+	entrySignal := usecase.EntrySignal{
+		Symbol:     "BTCUSDT",
+		StrategyID: 1,
+		AccountID:  2,
+		EntryPrice: d(50000.123),
+		Mode:       entities.Live,
+		OrderType:  broker.Limit,
+	}
+
+	t.Run("should round limit orders to the filters of the symbol", func(t *testing.T) {
+		mockRepo := newRepository()
+		mockAccountUseCase := newAccounts()
+		mockBroker := new(mocks.Broker)
+		signalUC := usecase.NewSignalUseCase(mockRepo, mockAccountUseCase, mockBroker, transactor(), schedule(), newRisk(), nil)
+
+		mockAccountUseCase.On("CanOpenOrder", mock.Anything, int64(2)).Return(true, nil).Once()
+		mockAccountUseCase.On("GetDisponibleAmout", mock.Anything, int64(2)).Return(d(1000), nil).Once()
+		mockAccountUseCase.On("DeductOrder", mock.Anything, int64(2), mock.Anything).Return(nil).Once()
+		mockRepo.On("GetOpenSignals", mock.Anything, "BTCUSDT", uint(1)).Return(entities.Signal{}, nil).Once()
+		mockRepo.On("Create", mock.Anything, mock.Anything).Return(created, nil).Once()
+		mockRepo.On("Open", mock.Anything, mock.Anything).Return(true, nil).Once()
+		mockBroker.On("GetSymbolFilters", mock.Anything, "BTCUSDT").Return(filters, nil).Once()
+		mockBroker.On("PlaceOrder", mock.Anything, broker.OrderRequest{
+			Symbol:   "BTCUSDT",
+			Side:     broker.Buy,
+			Type:     broker.Limit,
+			Price:    50000.12,
+			Quantity: 0.01999,
+			FeeRate:  0.001,
+		}).Return(broker.OrderResult{OrderID: "42", ExecutedQty: 0.01999, QuoteQuantity: 999.5}, nil).Once()
+
+		err := signalUC.GenerateBuySignal(entrySignal)
+		assert.NoError(t, err)
+
+		mockBroker.AssertExpectations(t)
+	})
+
+	t.Run("should refuse orders below the minimum notional", func(t *testing.T) {
+		mockRepo := newRepository()
+		mockAccountUseCase := newAccounts()
+		mockBroker := new(mocks.Broker)
+		signalUC := usecase.NewSignalUseCase(mockRepo, mockAccountUseCase, mockBroker, transactor(), schedule(), newRisk(), nil)
+
+		mockAccountUseCase.On("CanOpenOrder", mock.Anything, int64(2)).Return(true, nil).Once()
+		mockAccountUseCase.On("GetDisponibleAmout", mock.Anything, int64(2)).Return(d(4), nil).Once()
+		mockRepo.On("GetOpenSignals", mock.Anything, "BTCUSDT", uint(1)).Return(entities.Signal{}, nil).Once()
+		mockBroker.On("GetSymbolFilters", mock.Anything, "BTCUSDT").Return(filters, nil).Once()
+
+		err := signalUC.GenerateBuySignal(entrySignal)
+
+		assert.EqualError(t, err, "order of 0.00007 BTCUSDT worth 3.50 is below the minimum notional of 5")
+		mockBroker.AssertNotCalled(t, "PlaceOrder", mock.Anything, mock.Anything)
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should write dust positions off without an exit order", func(t *testing.T) {
+		mockRepo := newRepository()
+		mockAccountUseCase := newAccounts()
+		mockBroker := new(mocks.Broker)
+		signalUC := usecase.NewSignalUseCase(mockRepo, mockAccountUseCase, mockBroker, transactor(), schedule(), newRisk(), nil)
+
+		mockBroker.On("GetSymbolFilters", mock.Anything, "BTCUSDT").Return(filters, nil).Once()
+		mockRepo.On("GetOpenSignals", mock.Anything, "BTCUSDT", uint(1)).Return(entities.Signal{
+			ID:         1,
+			Symbol:     "BTCUSDT",
+			Status:     entities.Open,
+			StrategyID: 1,
+			AccountID:  2,
+			Mode:       entities.Live,
+			Orders:     []entities.Order{{EntryPrice: d(50000), Quantity: d(0.00007), InvestedAmount: d(3.5)}},
+		}, nil).Once()
+		mockRepo.On("Transition", mock.Anything, uint(1), entities.Open, entities.Closing).Return(true, nil).Once()
+		mockRepo.On("SaveOrder", mock.Anything, mock.Anything).Return(nil).Once()
+		mockRepo.On("Close", mock.Anything, mock.MatchedBy(func(s entities.Signal) bool {
+			return s.Orders[0].ExitOrderID == "dust" && s.Orders[0].ExitPrice.IsZero() && s.Orders[0].ExitFee.IsZero()
+		})).Return(true, nil).Once()
+		mockAccountUseCase.On("AddOrder", mock.Anything, int64(2), worth(0)).Return(nil).Once()
+
+		err := signalUC.GenerateSellSignal(usecase.ExitSignal{Symbol: "BTCUSDT", StrategyID: 1, ExitPrice: d(40000)})
+
+		assert.NoError(t, err)
+		mockBroker.AssertNotCalled(t, "PlaceOrder", mock.Anything, mock.Anything)
+		mockRepo.AssertExpectations(t)
+		mockAccountUseCase.AssertExpectations(t)
+	})
+}
+
+func TestSignalUseCase_Risk(t *testing.T) {
+	entrySignal := usecase.EntrySignal{
+		Symbol:     "BTCUSDT",
+		StrategyID: 1,
+		AccountID:  2,
+		EntryPrice: d(50000),
+	}
+
+	t.Run("should refuse entries refused by the risk manager", func(t *testing.T) {
+		mockRepo := newRepository()
+		mockAccountUseCase := newAccounts()
+		risk := new(mocks.RiskManager)
+		signalUC := usecase.NewSignalUseCase(mockRepo, mockAccountUseCase, newBroker(), transactor(), schedule(), risk, nil)
+
+		mockAccountUseCase.On("CanOpenOrder", mock.Anything, int64(2)).Return(true, nil).Once()
+		mockAccountUseCase.On("GetDisponibleAmout", mock.Anything, int64(2)).Return(d(1000), nil).Once()
+		mockRepo.On("GetOpenSignals", mock.Anything, "BTCUSDT", uint(1)).Return(entities.Signal{}, nil).Once()
+		risk.On("Allow", mock.Anything, "BTCUSDT", uint(1), d(1000)).Return(errors.New("entries are halted by the drawdown breaker")).Once()
+
+		err := signalUC.GenerateBuySignal(entrySignal)
+		assert.EqualError(t, err, "entries are halted by the drawdown breaker")
+
+		risk.AssertExpectations(t)
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		mockAccountUseCase.AssertNotCalled(t, "DeductOrder", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("should open entries without a risk manager", func(t *testing.T) {
+		mockRepo := newRepository()
+		mockAccountUseCase := newAccounts()
+		signalUC := usecase.NewSignalUseCase(mockRepo, mockAccountUseCase, newBroker(), transactor(), schedule(), nil, nil)
+
+		mockAccountUseCase.On("CanOpenOrder", mock.Anything, int64(2)).Return(true, nil).Once()
+		mockAccountUseCase.On("GetDisponibleAmout", mock.Anything, int64(2)).Return(d(1000), nil).Once()
+		mockAccountUseCase.On("DeductOrder", mock.Anything, int64(2), mock.Anything).Return(nil).Once()
+		mockRepo.On("GetOpenSignals", mock.Anything, "BTCUSDT", uint(1)).Return(entities.Signal{}, nil).Once()
+		mockRepo.On("Create", mock.Anything, mock.Anything).Return(created, nil).Once()
+		mockRepo.On("Open", mock.Anything, mock.Anything).Return(true, nil).Once()
+
+		assert.NoError(t, signalUC.GenerateBuySignal(entrySignal))
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestSignalUseCase_Fees(t *testing.T) {
+	binance := fees.NewSchedule(&configuration.Configuration{
+		Fees: configuration.Fees{
+			Models: map[string]configuration.FeeModel{
+				"binance": {MakerPct: 0.08, TakerPct: 0.1, DiscountAsset: "BNB", DiscountPct: 25},
+			},
+		},
+	})
+	entrySignal := usecase.EntrySignal{
+		Symbol:     "BTCUSDT",
+		StrategyID: 1,
+		AccountID:  2,
+		EntryPrice: d(50000),
+		OrderType:  broker.Limit,
+	}
+
+	t.Run("should charge simulated limit orders the maker rate of the account", func(t *testing.T) {
+		mockRepo := newRepository()
+		mockAccountUseCase := new(mocks.AccountUseCase)
+		signalUC := usecase.NewSignalUseCase(mockRepo, mockAccountUseCase, newBroker(), transactor(), binance, newRisk(), nil)
+
+		mockAccountUseCase.On("CanOpenOrder", mock.Anything, int64(2)).Return(true, nil).Once()
+		mockAccountUseCase.On("GetDisponibleAmout", mock.Anything, int64(2)).Return(d(1000), nil).Once()
+		mockAccountUseCase.On("GetAccount", int64(2)).Return(entities.Account{ID: 2, FeeModel: "binance"}, nil).Once()
+		mockAccountUseCase.On("DeductOrder", mock.Anything, int64(2), mock.Anything).Return(nil).Once()
+		mockRepo.On("GetOpenSignals", mock.Anything, "BTCUSDT", uint(1)).Return(entities.Signal{}, nil).Once()
+		mockRepo.On("Create", mock.Anything, mock.Anything).Return(created, nil).Once()
+		mockRepo.On("Open", mock.Anything, mock.MatchedBy(func(s entities.Signal) bool {
+			return s.Orders[0].EntryFee.Equal(d(0.6))
+		})).Return(true, nil).Once()
+
+		err := signalUC.GenerateBuySignal(entrySignal)
+		assert.NoError(t, err)
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should estimate commissions paid in the discount asset", func(t *testing.T) {
+		mockRepo := newRepository()
+		mockAccountUseCase := new(mocks.AccountUseCase)
+		mockBroker := newBroker()
+		signalUC := usecase.NewSignalUseCase(mockRepo, mockAccountUseCase, mockBroker, transactor(), binance, newRisk(), nil)
+
+		live := entrySignal
+		live.Mode = entities.Live
+		mockAccountUseCase.On("CanOpenOrder", mock.Anything, int64(2)).Return(true, nil).Once()
+		mockAccountUseCase.On("GetDisponibleAmout", mock.Anything, int64(2)).Return(d(1000), nil).Once()
+		mockAccountUseCase.On("GetAccount", int64(2)).Return(entities.Account{ID: 2, FeeModel: "binance"}, nil).Once()
+		mockAccountUseCase.On("DeductOrder", mock.Anything, int64(2), mock.Anything).Return(nil).Once()
+		mockRepo.On("GetOpenSignals", mock.Anything, "BTCUSDT", uint(1)).Return(entities.Signal{}, nil).Once()
+		mockBroker.On("PlaceOrder", mock.Anything, mock.Anything).Return(broker.OrderResult{
+			OrderID:       "42",
+			ExecutedQty:   0.02,
+			QuoteQuantity: 1000,
+			Fills: []broker.Fill{
+				{Price: 50000, Quantity: 0.02, Commission: 0.0012, CommissionAsset: "BNB"},
+			},
+		}, nil).Once()
+		mockRepo.On("Create", mock.Anything, mock.Anything).Return(created, nil).Once()
+		mockRepo.On("Open", mock.Anything, mock.MatchedBy(func(s entities.Signal) bool {
+			order := s.Orders[0]
+			return order.EntryFee.Equal(d(0.75)) && order.Quantity.Equal(d(0.02))
+		})).Return(true, nil).Once()
+
+		err := signalUC.GenerateBuySignal(live)
+		assert.NoError(t, err)
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should refuse accounts with an unknown fee model", func(t *testing.T) {
+		mockRepo := newRepository()
+		mockAccountUseCase := new(mocks.AccountUseCase)
+		signalUC := usecase.NewSignalUseCase(mockRepo, mockAccountUseCase, newBroker(), transactor(), binance, newRisk(), nil)
+
+		mockAccountUseCase.On("CanOpenOrder", mock.Anything, int64(2)).Return(true, nil).Once()
+		mockAccountUseCase.On("GetDisponibleAmout", mock.Anything, int64(2)).Return(d(1000), nil).Once()
+		mockAccountUseCase.On("GetAccount", int64(2)).Return(entities.Account{ID: 2, FeeModel: "kraken"}, nil).Once()
+		mockRepo.On("GetOpenSignals", mock.Anything, "BTCUSDT", uint(1)).Return(entities.Signal{}, nil).Once()
+
+		err := signalUC.GenerateBuySignal(entrySignal)
+
+		assert.EqualError(t, err, "unknown fee model kraken of account 2")
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+}
+
 func TestSignalUseCase_Close(t *testing.T) {
-	mockRepo := new(mocks.SignalRepository)
-	mockAccountUseCase := new(mocks.AccountUseCase)
-	mockBroker := new(mocks.Broker)
-	signalUC := usecase.NewSignalUseCase(mockRepo, mockAccountUseCase, mockBroker)
+	mockRepo := newRepository()
+	mockAccountUseCase := newAccounts()
+	mockBroker := newBroker()
+	signalUC := usecase.NewSignalUseCase(mockRepo, mockAccountUseCase, mockBroker, transactor(), schedule(), newRisk(), nil)
 
 	t.Run("should close a signal successfully", func(t *testing.T) {
 		signalID := uint(1)
 		openSignal := entities.Signal{
-			ID:     signalID,
-			Status: entities.Open,
-			Symbol: "BTCUSDT",
+			ID:        signalID,
+			Status:    entities.Open,
+			Symbol:    "BTCUSDT",
+			AccountID: 2,
 			Orders: []entities.Order{
 				{
-					InvestedAmount: 1000,
+					InvestedAmount: d(1000),
 				},
 			},
 		}
 		mockRepo.On("GetByID", signalID).Return(openSignal, nil).Once()
-		mockBroker.On("ListTickerPrices", mock.Anything, mock.Anything).Return([]*binance.SymbolPrice{
-			{Symbol: "BTCUSDT", Price: "60000"},
+		mockBroker.On("ListTickerPrices", mock.Anything, mock.Anything).Return([]broker.Ticker{
+			{Symbol: "BTCUSDT", Price: 60000},
 		}, nil).Once()
-		mockRepo.On("Update", mock.Anything).Return(nil).Once()
-		mockRepo.On("GetOpenSignals", openSignal.Symbol, openSignal.StrategyID).Return(openSignal, nil).Once()
-		mockAccountUseCase.On("AddOrder", float32(1000)).Return(nil).Once()
+		mockRepo.On("Close", mock.Anything, mock.Anything).Return(true, nil).Once()
+		mockRepo.On("GetOpenSignals", mock.Anything, openSignal.Symbol, openSignal.StrategyID).Return(openSignal, nil).Once()
+		mockAccountUseCase.On("AddOrder", mock.Anything, int64(2), worth(1000)).Return(nil).Once()
+		mockRepo.On("Transition", mock.Anything, uint(1), entities.Open, entities.Closing).Return(true, nil).Once()
+		mockRepo.On("SaveOrder", mock.Anything, mock.Anything).Return(nil).Once()
 
 		err := signalUC.Close(context.TODO(), signalID)
 		assert.NoError(t, err)
@@ -312,21 +822,24 @@ func TestSignalUseCase_Close(t *testing.T) {
 	t.Run("should return error if Update fails", func(t *testing.T) {
 		signalID := uint(4)
 		openSignal := entities.Signal{
-			ID:     signalID,
-			Status: entities.Open,
-			Symbol: "BTCUSDT",
+			ID:        signalID,
+			Status:    entities.Open,
+			Symbol:    "BTCUSDT",
+			AccountID: 2,
 			Orders: []entities.Order{
 				{
-					InvestedAmount: 1000,
+					InvestedAmount: d(1000),
 				},
 			},
 		}
 		mockRepo.On("GetByID", signalID).Return(openSignal, nil).Once()
-		mockBroker.On("ListTickerPrices", mock.Anything, mock.Anything).Return([]*binance.SymbolPrice{
-			{Symbol: "BTCUSDT", Price: "60000"},
+		mockBroker.On("ListTickerPrices", mock.Anything, mock.Anything).Return([]broker.Ticker{
+			{Symbol: "BTCUSDT", Price: 60000},
 		}, nil).Once()
-		mockRepo.On("Update", mock.Anything).Return(errors.New("update error")).Once()
-		mockRepo.On("GetOpenSignals", openSignal.Symbol, openSignal.StrategyID).Return(openSignal, nil).Once()
+		mockRepo.On("Close", mock.Anything, mock.Anything).Return(false, errors.New("update error")).Once()
+		mockRepo.On("GetOpenSignals", mock.Anything, openSignal.Symbol, openSignal.StrategyID).Return(openSignal, nil).Once()
+		mockRepo.On("Transition", mock.Anything, uint(4), entities.Open, entities.Closing).Return(true, nil).Once()
+		mockRepo.On("SaveOrder", mock.Anything, mock.Anything).Return(nil).Once()
 
 		err := signalUC.Close(context.TODO(), signalID)
 		assert.Error(t, err)
@@ -338,22 +851,25 @@ func TestSignalUseCase_Close(t *testing.T) {
 	t.Run("should return error if AddOrder fails", func(t *testing.T) {
 		signalID := uint(5)
 		openSignal := entities.Signal{
-			ID:     signalID,
-			Status: entities.Open,
-			Symbol: "BTCUSDT",
+			ID:        signalID,
+			Status:    entities.Open,
+			Symbol:    "BTCUSDT",
+			AccountID: 2,
 			Orders: []entities.Order{
 				{
-					InvestedAmount: 1000,
+					InvestedAmount: d(1000),
 				},
 			},
 		}
 		mockRepo.On("GetByID", signalID).Return(openSignal, nil).Once()
-		mockBroker.On("ListTickerPrices", mock.Anything, mock.Anything).Return([]*binance.SymbolPrice{
-			{Symbol: "BTCUSDT", Price: "60000"},
+		mockBroker.On("ListTickerPrices", mock.Anything, mock.Anything).Return([]broker.Ticker{
+			{Symbol: "BTCUSDT", Price: 60000},
 		}, nil).Once()
-		mockAccountUseCase.On("AddOrder", float32(1000)).Return(errors.New("account error")).Once()
-		mockRepo.On("GetOpenSignals", openSignal.Symbol, openSignal.StrategyID).Return(openSignal, nil).Once()
-		mockRepo.On("Update", mock.Anything).Return(nil).Once()
+		mockAccountUseCase.On("AddOrder", mock.Anything, int64(2), worth(1000)).Return(errors.New("account error")).Once()
+		mockRepo.On("GetOpenSignals", mock.Anything, openSignal.Symbol, openSignal.StrategyID).Return(openSignal, nil).Once()
+		mockRepo.On("Close", mock.Anything, mock.Anything).Return(true, nil).Once()
+		mockRepo.On("Transition", mock.Anything, uint(5), entities.Open, entities.Closing).Return(true, nil).Once()
+		mockRepo.On("SaveOrder", mock.Anything, mock.Anything).Return(nil).Once()
 
 		err := signalUC.Close(context.TODO(), signalID)
 		assert.Error(t, err)
@@ -365,12 +881,13 @@ func TestSignalUseCase_Close(t *testing.T) {
 	t.Run("should return error if ListTickerPrice fails", func(t *testing.T) {
 		signalID := uint(6)
 		openSignal := entities.Signal{
-			ID:     signalID,
-			Status: entities.Open,
-			Symbol: "BTCUSDT",
+			ID:        signalID,
+			Status:    entities.Open,
+			Symbol:    "BTCUSDT",
+			AccountID: 2,
 			Orders: []entities.Order{
 				{
-					InvestedAmount: 1000,
+					InvestedAmount: d(1000),
 				},
 			},
 		}
@@ -384,3 +901,347 @@ func TestSignalUseCase_Close(t *testing.T) {
 		mockBroker.AssertExpectations(t)
 	})
 }
+
+func TestSignalUseCase_TrackHighWaterMark(t *testing.T) {
+	mockRepo := newRepository()
+	signalUC := usecase.NewSignalUseCase(mockRepo, newAccounts(), newBroker(), transactor(), schedule(), newRisk(), nil)
+
+	t.Run("should raise the mark when the price goes above it", func(t *testing.T) {
+		signal := entities.Signal{ID: 1, HighWaterMark: d(100)}
+		mockRepo.On("UpdateHighWaterMark", uint(1), d(105)).Return(nil).Once()
+
+		mark, err := signalUC.TrackHighWaterMark(signal, d(105))
+		assert.NoError(t, err)
+		assert.Equal(t, "105", mark.String())
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should keep the mark when the price is below it", func(t *testing.T) {
+		signal := entities.Signal{ID: 2, HighWaterMark: d(100)}
+
+		mark, err := signalUC.TrackHighWaterMark(signal, d(95))
+		assert.NoError(t, err)
+		assert.Equal(t, "100", mark.String())
+		mockRepo.AssertNotCalled(t, "UpdateHighWaterMark", uint(2), mock.Anything)
+	})
+
+	t.Run("should start signals without a mark from the entry price", func(t *testing.T) {
+		signal := entities.Signal{ID: 3, Orders: []entities.Order{{EntryPrice: d(100)}}}
+		mockRepo.On("UpdateHighWaterMark", uint(3), d(100)).Return(nil).Once()
+
+		mark, err := signalUC.TrackHighWaterMark(signal, d(95))
+		assert.NoError(t, err)
+		assert.Equal(t, "100", mark.String())
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should lower the mark of shorts when the price goes below it", func(t *testing.T) {
+		signal := entities.Signal{ID: 5, Side: entities.Short, HighWaterMark: d(100)}
+		mockRepo.On("UpdateHighWaterMark", uint(5), d(95)).Return(nil).Once()
+
+		mark, err := signalUC.TrackHighWaterMark(signal, d(95))
+		assert.NoError(t, err)
+		assert.Equal(t, "95", mark.String())
+
+		mark, err = signalUC.TrackHighWaterMark(entities.Signal{ID: 5, Side: entities.Short, HighWaterMark: d(95)}, d(98))
+		assert.NoError(t, err)
+		assert.Equal(t, "95", mark.String())
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should return error if UpdateHighWaterMark fails", func(t *testing.T) {
+		signal := entities.Signal{ID: 4, HighWaterMark: d(100)}
+		mockRepo.On("UpdateHighWaterMark", uint(4), d(110)).Return(errors.New("db error")).Once()
+
+		_, err := signalUC.TrackHighWaterMark(signal, d(110))
+		assert.EqualError(t, err, "db error")
+	})
+}
+
+// transactor runs the functions it is given, as a transaction that commits.
+func transactor() *mocks.Transactor {
+	t := new(mocks.Transactor)
+	t.On("Transaction", mock.Anything, mock.Anything).Return(func(ctx context.Context, fn func(context.Context) error) error {
+		return fn(ctx)
+	})
+	return t
+}
+
+func created(ctx context.Context, signal entities.Signal) (entities.Signal, error) {
+	signal.ID = 1
+	return signal, nil
+}
+
+// worth matches the signals whose order is worth amount once closed.
+func worth(amount float64) any {
+	return mock.MatchedBy(func(signal entities.Signal) bool {
+		order := signal.Orders[0]
+		return order.InvestedAmount.Add(order.Profit).Equal(d(amount))
+	})
+}
+
+func d(value float64) decimal.Decimal {
+	return decimal.NewFromFloat(value)
+}
+
+// newBroker returns a broker whose symbols have no filters.
+func newBroker() *mocks.Broker {
+	b := new(mocks.Broker)
+	b.On("GetSymbolFilters", mock.Anything, mock.Anything).Return(func(ctx context.Context, symbol string) (broker.SymbolFilters, error) {
+		return broker.SymbolFilters{Symbol: symbol}, nil
+	}).Maybe()
+	return b
+}
+
+// newRisk returns a risk manager allowing every entry.
+func newRisk() *mocks.RiskManager {
+	r := new(mocks.RiskManager)
+	r.On("Allow", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+	return r
+}
+
+// newRepository returns a signal repository of accounts without volume.
+func newRepository() *mocks.SignalRepository {
+	r := new(mocks.SignalRepository)
+	r.On("TradedVolume", mock.Anything, mock.Anything, mock.Anything).Return(decimal.Zero, nil).Maybe()
+	return r
+}
+
+// newAccounts returns accounts charged with the default fee model.
+func newAccounts() *mocks.AccountUseCase {
+	a := new(mocks.AccountUseCase)
+	a.On("GetAccount", mock.Anything).Return(func(id int64) (entities.Account, error) {
+		return entities.Account{ID: id}, nil
+	}).Maybe()
+	return a
+}
+
+// schedule returns the fee models of an empty configuration, 0.1% on both
+// sides.
+func schedule() fees.Schedule {
+	return fees.NewSchedule(&configuration.Configuration{})
+}
+
+func TestSignalUseCase_Sizing(t *testing.T) {
+	account := entities.Account{ID: 2, Amount: d(8000), AvailableOrders: 4}
+	history := func(returns ...float64) []entities.Signal {
+		signals := make([]entities.Signal, len(returns))
+		for i, r := range returns {
+			signals[i] = entities.Signal{Status: entities.Closed, Orders: []entities.Order{{InvestedAmount: d(100), Profit: d(100 * r)}}}
+		}
+		return signals
+	}
+	wins := func(n int, win float64, loss float64, total int) []float64 {
+		returns := make([]float64, total)
+		for i := range returns {
+			returns[i] = loss
+			if i < n {
+				returns[i] = win
+			}
+		}
+		return returns
+	}
+
+	tests := []struct {
+		name     string
+		sizing   usecase.Sizing
+		closed   []entities.Signal
+		expected float64
+	}{
+		{name: "fixed notional", sizing: usecase.Sizing{Mode: usecase.FixedNotionalSizing, Notional: d(250)}, expected: 250},
+		{name: "fixed fraction of the equity", sizing: usecase.Sizing{Mode: usecase.FixedFractionSizing, FractionPct: d(10)}, expected: 1000},
+		{name: "risk per trade", sizing: usecase.Sizing{Mode: usecase.RiskSizing, RiskPct: d(1), StopLossPct: d(2)}, expected: 5000},
+		{name: "volatility targeted", sizing: usecase.Sizing{Mode: usecase.VolatilitySizing, TargetVolatilityPct: d(1), ATR: d(1000)}, expected: 5000},
+		{name: "capped at the balance", sizing: usecase.Sizing{Mode: usecase.FixedNotionalSizing, Notional: d(20000)}, expected: 8000},
+		{
+			name:     "kelly fraction",
+			sizing:   usecase.Sizing{Mode: usecase.KellySizing, KellyFraction: d(0.5), KellyMaxPct: d(25)},
+			closed:   history(wins(6, 0.1, -0.1, 10)...),
+			expected: 1000,
+		},
+		{
+			name:     "kelly capped",
+			sizing:   usecase.Sizing{Mode: usecase.KellySizing, KellyFraction: d(1), KellyMaxPct: d(5)},
+			closed:   history(wins(6, 0.1, -0.1, 10)...),
+			expected: 500,
+		},
+		{
+			name:     "kelly without history",
+			sizing:   usecase.Sizing{Mode: usecase.KellySizing, KellyFraction: d(0.5), KellyMaxPct: d(25)},
+			closed:   history(0.1, -0.1),
+			expected: 2000,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run("should size with "+tt.name, func(t *testing.T) {
+			mockRepo := newRepository()
+			mockAccountUseCase := new(mocks.AccountUseCase)
+			signalUC := usecase.NewSignalUseCase(mockRepo, mockAccountUseCase, newBroker(), transactor(), schedule(), newRisk(), nil)
+
+			mockAccountUseCase.On("CanOpenOrder", mock.Anything, int64(2)).Return(true, nil).Once()
+			mockAccountUseCase.On("GetAccount", int64(2)).Return(account, nil)
+			mockAccountUseCase.On("GetDisponibleAmout", mock.Anything, int64(2)).Return(d(2000), nil).Maybe()
+			mockAccountUseCase.On("DeductOrder", mock.Anything, int64(2), mock.Anything).Return(nil).Once()
+			mockRepo.On("GetOpenSignals", mock.Anything, "BTCUSDT", uint(1)).Return(entities.Signal{}, nil).Once()
+			mockRepo.On("Exposure", mock.Anything, int64(2)).Return(d(2000), nil).Once()
+			mockRepo.On("GetClosedSignals", mock.Anything, uint(1), mock.Anything).Return(tt.closed, nil).Maybe()
+			mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(s entities.Signal) bool {
+				return s.Orders[0].InvestedAmount.Equal(d(tt.expected))
+			})).Return(created, nil).Once()
+			mockRepo.On("Open", mock.Anything, mock.Anything).Return(true, nil).Once()
+
+			err := signalUC.GenerateBuySignal(usecase.EntrySignal{
+				Symbol:     "BTCUSDT",
+				StrategyID: 1,
+				AccountID:  2,
+				EntryPrice: d(50000),
+				Sizing:     tt.sizing,
+			})
+			assert.NoError(t, err)
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+
+	t.Run("should not open entries kelly sees no edge in", func(t *testing.T) {
+		mockRepo := newRepository()
+		mockAccountUseCase := new(mocks.AccountUseCase)
+		signalUC := usecase.NewSignalUseCase(mockRepo, mockAccountUseCase, newBroker(), transactor(), schedule(), newRisk(), nil)
+
+		mockAccountUseCase.On("CanOpenOrder", mock.Anything, int64(2)).Return(true, nil).Once()
+		mockAccountUseCase.On("GetAccount", int64(2)).Return(account, nil)
+		mockRepo.On("GetOpenSignals", mock.Anything, "BTCUSDT", uint(1)).Return(entities.Signal{}, nil).Once()
+		mockRepo.On("Exposure", mock.Anything, int64(2)).Return(d(0), nil).Once()
+		mockRepo.On("GetClosedSignals", mock.Anything, uint(1), mock.Anything).Return(history(wins(3, 0.1, -0.1, 10)...), nil).Once()
+
+		err := signalUC.GenerateBuySignal(usecase.EntrySignal{
+			Symbol:     "BTCUSDT",
+			StrategyID: 1,
+			AccountID:  2,
+			EntryPrice: d(50000),
+			Sizing:     usecase.Sizing{Mode: usecase.KellySizing, KellyFraction: d(0.5), KellyMaxPct: d(25)},
+		})
+		assert.NoError(t, err)
+
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+}
+
+func TestSignalUseCase_Resolve(t *testing.T) {
+	ctx := context.Background()
+	stale := time.Now().Add(-time.Hour)
+	stuck := func(id uint, status entities.SignalStatus) entities.Signal {
+		return entities.Signal{
+			ID:        id,
+			Symbol:    "BTCUSDT",
+			AccountID: 2,
+			Status:    status,
+			UpdatedAt: stale,
+			Orders: []entities.Order{
+				{EntryPrice: d(50000), Quantity: d(0.02), InvestedAmount: d(1000), EntryFee: d(1)},
+			},
+		}
+	}
+	fill := usecase.Resolution{Filled: true, OrderID: "42", Price: d(55000), Quantity: d(0.02), Fee: d(1)}
+
+	t.Run("should open a pending signal with the fill of its entry", func(t *testing.T) {
+		mockRepo := newRepository()
+		signalUC := usecase.NewSignalUseCase(mockRepo, newAccounts(), newBroker(), transactor(), schedule(), newRisk(), nil)
+		mockRepo.On("GetByID", uint(1)).Return(stuck(1, entities.Pending), nil).Once()
+		mockRepo.On("Open", mock.Anything, mock.MatchedBy(func(s entities.Signal) bool {
+			order := s.Orders[0]
+			return s.Status == entities.Open && order.BrokerOrderID == "42" && order.EntryPrice.Equal(d(55000)) && order.EntryFee.Equal(d(1))
+		})).Return(true, nil).Once()
+
+		signal, err := signalUC.Resolve(ctx, 1, fill)
+		assert.NoError(t, err)
+		assert.Equal(t, entities.Open, signal.Status)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should fail a pending signal without fill and release its reservation", func(t *testing.T) {
+		mockRepo := newRepository()
+		mockAccountUseCase := newAccounts()
+		signalUC := usecase.NewSignalUseCase(mockRepo, mockAccountUseCase, newBroker(), transactor(), schedule(), newRisk(), nil)
+		mockRepo.On("GetByID", uint(2)).Return(stuck(2, entities.Pending), nil).Once()
+		mockRepo.On("Transition", mock.Anything, uint(2), entities.Pending, entities.Failed).Return(true, nil).Once()
+		mockAccountUseCase.On("ReleaseOrder", mock.Anything, int64(2), worth(1000)).Return(nil).Once()
+
+		signal, err := signalUC.Resolve(ctx, 2, usecase.Resolution{})
+		assert.NoError(t, err)
+		assert.Equal(t, entities.Failed, signal.Status)
+		mockRepo.AssertExpectations(t)
+		mockAccountUseCase.AssertExpectations(t)
+	})
+
+	t.Run("should book a closing signal with the fill of its exit", func(t *testing.T) {
+		mockRepo := newRepository()
+		mockAccountUseCase := newAccounts()
+		signalUC := usecase.NewSignalUseCase(mockRepo, mockAccountUseCase, newBroker(), transactor(), schedule(), newRisk(), nil)
+		mockRepo.On("GetByID", uint(3)).Return(stuck(3, entities.Closing), nil).Once()
+		mockRepo.On("SaveOrder", mock.Anything, mock.MatchedBy(func(o entities.Order) bool {
+			return o.IsClosing && o.ExitOrderID == "42" && o.Profit.Equal(d(98))
+		})).Return(nil).Once()
+		mockRepo.On("Close", mock.Anything, mock.Anything).Return(true, nil).Once()
+		mockAccountUseCase.On("AddOrder", mock.Anything, int64(2), worth(1098)).Return(nil).Once()
+
+		signal, err := signalUC.Resolve(ctx, 3, fill)
+		assert.NoError(t, err)
+		assert.Equal(t, entities.Closed, signal.Status)
+		mockRepo.AssertExpectations(t)
+		mockAccountUseCase.AssertExpectations(t)
+	})
+
+	t.Run("should book a saved exit as it is", func(t *testing.T) {
+		mockRepo := newRepository()
+		mockAccountUseCase := newAccounts()
+		signalUC := usecase.NewSignalUseCase(mockRepo, mockAccountUseCase, newBroker(), transactor(), schedule(), newRisk(), nil)
+		saved := stuck(4, entities.Closing)
+		saved.Orders[0].IsClosing = true
+		saved.Orders[0].Profit = d(50)
+		mockRepo.On("GetByID", uint(4)).Return(saved, nil).Once()
+		mockRepo.On("Close", mock.Anything, mock.Anything).Return(true, nil).Once()
+		mockAccountUseCase.On("AddOrder", mock.Anything, int64(2), worth(1050)).Return(nil).Once()
+
+		_, err := signalUC.Resolve(ctx, 4, usecase.Resolution{})
+		assert.NoError(t, err)
+		mockRepo.AssertNotCalled(t, "SaveOrder", mock.Anything, mock.Anything)
+		mockAccountUseCase.AssertExpectations(t)
+	})
+
+	t.Run("should reopen a closing signal without fill", func(t *testing.T) {
+		mockRepo := newRepository()
+		signalUC := usecase.NewSignalUseCase(mockRepo, newAccounts(), newBroker(), transactor(), schedule(), newRisk(), nil)
+		mockRepo.On("GetByID", uint(5)).Return(stuck(5, entities.Closing), nil).Once()
+		mockRepo.On("Transition", mock.Anything, uint(5), entities.Closing, entities.Open).Return(true, nil).Once()
+
+		signal, err := signalUC.Resolve(ctx, 5, usecase.Resolution{})
+		assert.NoError(t, err)
+		assert.Equal(t, entities.Open, signal.Status)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should refuse signals that aren't stuck", func(t *testing.T) {
+		mockRepo := newRepository()
+		signalUC := usecase.NewSignalUseCase(mockRepo, newAccounts(), newBroker(), transactor(), schedule(), newRisk(), nil)
+		recent := stuck(6, entities.Pending)
+		recent.UpdatedAt = time.Now()
+		mockRepo.On("GetByID", uint(6)).Return(recent, nil).Once()
+		mockRepo.On("GetByID", uint(7)).Return(stuck(7, entities.Open), nil).Once()
+		mockRepo.On("GetByID", uint(8)).Return(entities.Signal{}, gorm.ErrRecordNotFound).Once()
+
+		_, err := signalUC.Resolve(ctx, 6, usecase.Resolution{})
+		assert.Equal(t, customerror.New(http.StatusConflict, "Signal 6 changed less than 1m0s ago, its order may still be on its way"), err)
+
+		_, err = signalUC.Resolve(ctx, 7, usecase.Resolution{})
+		assert.Equal(t, customerror.New(http.StatusConflict, "Signal 7 is open, only pending and closing signals can be resolved"), err)
+
+		_, err = signalUC.Resolve(ctx, 8, usecase.Resolution{})
+		assert.Equal(t, customerror.New(http.StatusNotFound, "Signal not found"), err)
+
+		_, err = signalUC.Resolve(ctx, 6, usecase.Resolution{Filled: true, Price: d(55000)})
+		assert.Equal(t, customerror.New(http.StatusBadRequest, "A fill needs a positive price and quantity and a fee of at least 0"), err)
+		mockRepo.AssertNotCalled(t, "Transition", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
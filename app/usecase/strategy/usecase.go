@@ -3,6 +3,8 @@ package usecase
 import (
 	"context"
 	"go-trade-bot/app/entities"
+	"go-trade-bot/app/services/algorithm"
+	"go-trade-bot/internal/broker"
 	"go-trade-bot/internal/customerror"
 	"net/http"
 	"time"
@@ -19,20 +21,33 @@ type StrategyWorker interface {
 	EnqueueStrategyTask(strategy entities.Strategy) error
 }
 
+type Algorithms interface {
+	Get(name entities.Algorithm) (algorithm.Definition, bool)
+}
+
+type AccountRepository interface {
+	GetAccountByID(id int64) (entities.Account, error)
+}
+
 type StrategyUseCase struct {
 	Repository StrategyRepository
 	Worker     StrategyWorker
+	Algorithms Algorithms
+	Accounts   AccountRepository
 }
 
-func NewStrategyUseCase(repository StrategyRepository, worker StrategyWorker) StrategyUseCase {
+func NewStrategyUseCase(repository StrategyRepository, worker StrategyWorker, algorithms Algorithms, accounts AccountRepository) StrategyUseCase {
 	return StrategyUseCase{
 		Repository: repository,
 		Worker:     worker,
+		Algorithms: algorithms,
+		Accounts:   accounts,
 	}
 }
 
 func (u StrategyUseCase) Save(ctx context.Context, strategy entities.Strategy) error {
-	if err := u.validateStrategy(strategy); err != nil {
+	strategy, err := u.validateStrategy(strategy)
+	if err != nil {
 		return err
 	}
 	strategy.CreatedAt = time.Now()
@@ -49,7 +64,8 @@ func (u StrategyUseCase) Save(ctx context.Context, strategy entities.Strategy) e
 }
 
 func (u StrategyUseCase) Update(ctx context.Context, strategy entities.Strategy) error {
-	if err := u.validateStrategy(strategy); err != nil {
+	strategy, err := u.validateStrategy(strategy)
+	if err != nil {
 		return err
 	}
 	strategy.UpdatedAt = time.Now()
@@ -87,32 +103,55 @@ func (u StrategyUseCase) GetAll(ctx context.Context) ([]entities.Strategy, error
 	return u.Repository.GetAll(ctx)
 }
 
-func (u StrategyUseCase) validateStrategy(strategy entities.Strategy) error {
+// validateStrategy checks the strategy and its configuration against the
+// schema of its algorithm, returning it with the defaults filled in.
+func (u StrategyUseCase) validateStrategy(strategy entities.Strategy) (entities.Strategy, error) {
 	if strategy.Name == "" {
-		return customerror.New(http.StatusBadRequest, "Strategy has to have a name")
+		return strategy, customerror.New(http.StatusBadRequest, "Strategy has to have a name")
 	}
 	if strategy.Description == "" {
-		return customerror.New(http.StatusBadRequest, "Description must be filled")
+		return strategy, customerror.New(http.StatusBadRequest, "Description must be filled")
 	}
 
 	if len(strategy.MonitoredSymbols) == 0 {
-		return customerror.New(http.StatusBadRequest, "Please define a set of symbols to monitor")
+		return strategy, customerror.New(http.StatusBadRequest, "Please define a set of symbols to monitor")
+	}
+
+	if strategy.AccountID == 0 {
+		return strategy, customerror.New(http.StatusBadRequest, "Please define the account of the strategy")
+	}
+
+	if _, err := u.Accounts.GetAccountByID(strategy.AccountID); err != nil {
+		return strategy, customerror.New(http.StatusBadRequest, "Account not found")
 	}
 
 	if strategy.Algorithm == "" {
-		return customerror.New(http.StatusBadRequest, "Please define a altorigthm to be used")
+		return strategy, customerror.New(http.StatusBadRequest, "Please define a altorigthm to be used")
 	}
 
-	if !entities.IsValidAlgorithm(string(strategy.Algorithm)) {
-		return customerror.New(http.StatusBadRequest, "Invalid algorithm option")
+	definition, ok := u.Algorithms.Get(strategy.Algorithm)
+	if !ok {
+		return strategy, customerror.New(http.StatusBadRequest, "Invalid algorithm option")
 	}
 
 	if strategy.StrategyConfiguration.Cycle == 0 {
-		return customerror.New(http.StatusBadRequest, "Cycle can't be zero")
+		return strategy, customerror.New(http.StatusBadRequest, "Cycle can't be zero")
 	}
 
 	if !entities.IsValidCycle(int(strategy.StrategyConfiguration.Cycle)) {
-		return customerror.New(http.StatusBadRequest, "Invalid cycle option")
+		return strategy, customerror.New(http.StatusBadRequest, "Invalid cycle option")
 	}
-	return nil
+
+	if interval := strategy.StrategyConfiguration.CandleInterval; interval != "" {
+		if _, ok := broker.IntervalDuration(interval); !ok {
+			return strategy, customerror.New(http.StatusBadRequest, "Invalid candle interval "+interval)
+		}
+	}
+
+	configuration, fields := definition.Validate(strategy.StrategyConfiguration.Configuration)
+	if len(fields) > 0 {
+		return strategy, customerror.NewWithFields(http.StatusBadRequest, "Invalid configuration for algorithm "+string(strategy.Algorithm), fields)
+	}
+	strategy.StrategyConfiguration.Configuration = configuration
+	return strategy, nil
 }
@@ -4,8 +4,11 @@ import (
 	"context"
 	"errors"
 	"go-trade-bot/app/entities"
+	"go-trade-bot/app/services/algorithm"
 	usecase "go-trade-bot/app/usecase/strategy"
 	"go-trade-bot/app/usecase/strategy/mocks"
+	"go-trade-bot/internal/customerror"
+	"net/http"
 
 	"testing"
 	"time"
@@ -14,9 +17,31 @@ import (
 	"github.com/stretchr/testify/mock"
 )
 
+type gridConfig struct {
+	GridLevels int    `json:"grid_levels" default:"8" min:"2"`
+	OrderType  string `json:"order_type" default:"market" oneof:"market limit"`
+}
+
+func newRegistry() *algorithm.Registry {
+	registry := algorithm.NewRegistry()
+	registry.Register(algorithm.Definition{
+		Name:   entities.Grid,
+		Config: gridConfig{},
+		New:    func(strategy entities.Strategy, deps algorithm.Dependencies) algorithm.Processor { return nil },
+	})
+	return registry
+}
+
+func newAccounts() *mocks.AccountRepository {
+	accounts := new(mocks.AccountRepository)
+	accounts.On("GetAccountByID", int64(1)).Return(entities.Account{ID: 1}, nil).Maybe()
+	accounts.On("GetAccountByID", mock.Anything).Return(entities.Account{}, errors.New("record not found")).Maybe()
+	return accounts
+}
+
 func TestStrategyUseCase_GetAll(t *testing.T) {
 	mockRepo := new(mocks.StrategyRepository)
-	strategyUC := usecase.NewStrategyUseCase(mockRepo, nil)
+	strategyUC := usecase.NewStrategyUseCase(mockRepo, nil, newRegistry(), newAccounts())
 
 	ctx := context.Background()
 	strategies := []entities.Strategy{
@@ -26,8 +51,9 @@ func TestStrategyUseCase_GetAll(t *testing.T) {
 			Description:      "A test strategy",
 			MonitoredSymbols: []string{"BTCUSDT", "ETHUSDT"},
 			Algorithm:        entities.Grid,
+			AccountID:        1,
 			StrategyConfiguration: entities.StrategyConfiguration{
-				Cycle: 10,
+				Cycle: 15,
 			},
 			CreatedAt: time.Now(),
 			UpdatedAt: time.Now(),
@@ -59,7 +85,7 @@ func TestStrategyUseCase_GetAll(t *testing.T) {
 func TestStrategyUseCase_Enqueue(t *testing.T) {
 	mockRepo := new(mocks.StrategyRepository)
 	mockWorker := new(mocks.StrategyWorker)
-	strategyUC := usecase.NewStrategyUseCase(mockRepo, mockWorker)
+	strategyUC := usecase.NewStrategyUseCase(mockRepo, mockWorker, newRegistry(), newAccounts())
 
 	ctx := context.Background()
 	strategy := entities.Strategy{
@@ -68,8 +94,9 @@ func TestStrategyUseCase_Enqueue(t *testing.T) {
 		Description:      "A test strategy",
 		MonitoredSymbols: []string{"BTCUSDT", "ETHUSDT"},
 		Algorithm:        entities.Grid,
+		AccountID:        1,
 		StrategyConfiguration: entities.StrategyConfiguration{
-			Cycle: 10,
+			Cycle: 15,
 		},
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
@@ -98,7 +125,7 @@ func TestStrategyUseCase_Enqueue(t *testing.T) {
 func TestStrategyUseCase_Save(t *testing.T) {
 	mockRepo := new(mocks.StrategyRepository)
 	mockWorker := new(mocks.StrategyWorker)
-	strategyUC := usecase.NewStrategyUseCase(mockRepo, mockWorker)
+	strategyUC := usecase.NewStrategyUseCase(mockRepo, mockWorker, newRegistry(), newAccounts())
 
 	ctx := context.Background()
 	strategy := entities.Strategy{
@@ -107,8 +134,9 @@ func TestStrategyUseCase_Save(t *testing.T) {
 		Description:      "A test strategy",
 		MonitoredSymbols: []string{"BTCUSDT", "ETHUSDT"},
 		Algorithm:        entities.Grid,
+		AccountID:        1,
 		StrategyConfiguration: entities.StrategyConfiguration{
-			Cycle: 10,
+			Cycle: 15,
 		},
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
@@ -134,6 +162,64 @@ func TestStrategyUseCase_Save(t *testing.T) {
 		assert.Contains(t, err.Error(), "Strategy has to have a name")
 	})
 
+	t.Run("should fill the configuration with the algorithm defaults", func(t *testing.T) {
+		withConfig := strategy
+		withConfig.StrategyConfiguration.Configuration = []byte(`{"grid_levels": 4, "order_type": "limit"}`)
+		mockRepo.On("Save", ctx, mock.MatchedBy(func(s entities.Strategy) bool {
+			return string(s.StrategyConfiguration.Configuration) == `{"grid_levels":4,"order_type":"limit"}`
+		})).Return(nil).Once()
+		mockWorker.On("EnqueueStrategyTask", mock.AnythingOfType("entities.Strategy")).Return(nil).Once()
+
+		err := strategyUC.Save(ctx, withConfig)
+		assert.NoError(t, err)
+
+		withoutConfig := strategy
+		mockRepo.On("Save", ctx, mock.MatchedBy(func(s entities.Strategy) bool {
+			return string(s.StrategyConfiguration.Configuration) == `{"grid_levels":8,"order_type":"market"}`
+		})).Return(nil).Once()
+		mockWorker.On("EnqueueStrategyTask", mock.AnythingOfType("entities.Strategy")).Return(nil).Once()
+
+		err = strategyUC.Save(ctx, withoutConfig)
+		assert.NoError(t, err)
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should return field errors for an invalid configuration", func(t *testing.T) {
+		invalidStrategy := strategy
+		invalidStrategy.StrategyConfiguration.Configuration = []byte(`{"grid_levels": 1, "order_type": "stop", "grid_level": 4}`)
+
+		err := strategyUC.Save(ctx, invalidStrategy)
+		assert.Equal(t, customerror.NewWithFields(http.StatusBadRequest, "Invalid configuration for algorithm grid", []customerror.FieldError{
+			{Field: "configuration.grid_levels", Message: "must be at least 2"},
+			{Field: "configuration.order_type", Message: "must be one of market, limit"},
+			{Field: "configuration.grid_level", Message: "is not a parameter of the algorithm"},
+		}), err)
+	})
+
+	t.Run("should return error when the exchange has no such interval", func(t *testing.T) {
+		invalidStrategy := strategy
+		invalidStrategy.StrategyConfiguration.Cycle = entities.TenMinutes
+
+		err := strategyUC.Save(ctx, invalidStrategy)
+		assert.Equal(t, customerror.New(http.StatusBadRequest, "Invalid cycle option"), err)
+
+		invalidStrategy = strategy
+		invalidStrategy.StrategyConfiguration.CandleInterval = "10m"
+
+		err = strategyUC.Save(ctx, invalidStrategy)
+		assert.Equal(t, customerror.New(http.StatusBadRequest, "Invalid candle interval 10m"), err)
+	})
+
+	t.Run("should return error when algorithm is not registered", func(t *testing.T) {
+		invalidStrategy := strategy
+		invalidStrategy.Algorithm = "martingale"
+
+		err := strategyUC.Save(ctx, invalidStrategy)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "Invalid algorithm option")
+	})
+
 	t.Run("should return error when repository fails", func(t *testing.T) {
 		mockRepo.On("Save", ctx, mock.AnythingOfType("entities.Strategy")).Return(errors.New("database error")).Once()
 
@@ -158,7 +244,7 @@ func TestStrategyUseCase_Save(t *testing.T) {
 }
 func TestStrategyUseCase_GetByID(t *testing.T) {
 	mockRepo := new(mocks.StrategyRepository)
-	strategyUC := usecase.NewStrategyUseCase(mockRepo, nil)
+	strategyUC := usecase.NewStrategyUseCase(mockRepo, nil, newRegistry(), newAccounts())
 
 	ctx := context.Background()
 	strategy := entities.Strategy{
@@ -167,8 +253,9 @@ func TestStrategyUseCase_GetByID(t *testing.T) {
 		Description:      "A test strategy",
 		MonitoredSymbols: []string{"BTCUSDT", "ETHUSDT"},
 		Algorithm:        entities.Grid,
+		AccountID:        1,
 		StrategyConfiguration: entities.StrategyConfiguration{
-			Cycle: 10,
+			Cycle: 15,
 		},
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
@@ -198,7 +285,7 @@ func TestStrategyUseCase_GetByID(t *testing.T) {
 
 func TestStrategyUseCase_Update(t *testing.T) {
 	mockRepo := new(mocks.StrategyRepository)
-	strategyUC := usecase.NewStrategyUseCase(mockRepo, nil)
+	strategyUC := usecase.NewStrategyUseCase(mockRepo, nil, newRegistry(), newAccounts())
 
 	ctx := context.Background()
 	strategy := entities.Strategy{
@@ -207,8 +294,9 @@ func TestStrategyUseCase_Update(t *testing.T) {
 		Description:      "A test strategy",
 		MonitoredSymbols: []string{"BTCUSDT", "ETHUSDT"},
 		Algorithm:        entities.Grid,
+		AccountID:        1,
 		StrategyConfiguration: entities.StrategyConfiguration{
-			Cycle: 10,
+			Cycle: 15,
 		},
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
@@ -232,6 +320,14 @@ func TestStrategyUseCase_Update(t *testing.T) {
 		assert.Contains(t, err.Error(), "Strategy has to have a name")
 	})
 
+	t.Run("should return error when the account does not exist", func(t *testing.T) {
+		invalidStrategy := strategy
+		invalidStrategy.AccountID = 7
+
+		err := strategyUC.Update(ctx, invalidStrategy)
+		assert.Equal(t, customerror.New(http.StatusBadRequest, "Account not found"), err)
+	})
+
 	t.Run("should return error when repository fails", func(t *testing.T) {
 		mockRepo.On("Update", ctx, mock.AnythingOfType("entities.Strategy")).Return(errors.New("database error")).Once()
 
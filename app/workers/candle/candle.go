@@ -0,0 +1,51 @@
+package tasks
+
+import (
+	"encoding/json"
+	"go-trade-bot/internal/configuration"
+	"log"
+	"time"
+
+	"github.com/hibiken/asynq"
+)
+
+const (
+	BackfillTask = "candle:backfill"
+)
+
+type BackfillPayload struct {
+	Symbol   string
+	Interval string
+	From     time.Time
+}
+
+type CandleWorker struct {
+	client *asynq.Client
+}
+
+func NewCandleWorker(cfg *configuration.Configuration) CandleWorker {
+	client := asynq.NewClient(asynq.RedisClientOpt{Addr: cfg.Redis.Addr})
+
+	return CandleWorker{
+		client: client,
+	}
+}
+
+func (w CandleWorker) EnqueueBackfill(symbol string, interval string, from time.Time) error {
+	payload, err := json.Marshal(BackfillPayload{
+		Symbol:   symbol,
+		Interval: interval,
+		From:     from,
+	})
+	if err != nil {
+		return err
+	}
+	// Backfills can page through years of klines, one at a time per series.
+	t := asynq.NewTask(BackfillTask, payload, asynq.Timeout(time.Hour))
+	info, err := w.client.Enqueue(t, asynq.TaskID(BackfillTask+":"+symbol+":"+interval))
+	if err != nil {
+		return err
+	}
+	log.Printf(" [*] Successfully enqueued task: %+v", info.ID)
+	return nil
+}
@@ -0,0 +1,36 @@
+package tasks
+
+import (
+	"go-trade-bot/internal/configuration"
+	"log"
+
+	"github.com/hibiken/asynq"
+)
+
+const (
+	KillSwitchTask = "killswitch:engage"
+)
+
+type KillSwitchWorker struct {
+	client *asynq.Client
+}
+
+func NewKillSwitchWorker(cfg *configuration.Configuration) KillSwitchWorker {
+	client := asynq.NewClient(asynq.RedisClientOpt{Addr: cfg.Redis.Addr})
+
+	return KillSwitchWorker{
+		client: client,
+	}
+}
+
+// EnqueueKillSwitchTask asks a worker to cancel the open orders and, when the
+// switch says so, close the open signals right away. The task reads the
+// switch when it runs, it carries no payload.
+func (w KillSwitchWorker) EnqueueKillSwitchTask() error {
+	info, err := w.client.Enqueue(asynq.NewTask(KillSwitchTask, nil))
+	if err != nil {
+		return err
+	}
+	log.Printf(" [*] Successfully enqueued task: %+v", info.ID)
+	return nil
+}
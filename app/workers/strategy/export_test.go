@@ -0,0 +1,3 @@
+package tasks
+
+var NextCycle = nextCycle
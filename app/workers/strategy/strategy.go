@@ -2,7 +2,10 @@ package tasks
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"go-trade-bot/app/entities"
+	"go-trade-bot/internal/broker"
 	"go-trade-bot/internal/configuration"
 	"log"
 	"time"
@@ -12,14 +15,18 @@ import (
 
 // TODO: Implement integration test with redis
 type StrategyWorker struct {
-	client *asynq.Client
+	client    *asynq.Client
+	inspector *asynq.Inspector
+	execution configuration.Execution
 }
 
 func NewStrategyWorker(cfg *configuration.Configuration) StrategyWorker {
-	client := asynq.NewClient(asynq.RedisClientOpt{Addr: cfg.Redis.Addr})
+	opt := asynq.RedisClientOpt{Addr: cfg.Redis.Addr}
 
 	return StrategyWorker{
-		client: client,
+		client:    asynq.NewClient(opt),
+		inspector: asynq.NewInspector(opt),
+		execution: cfg.Execution,
 	}
 }
 
@@ -27,6 +34,10 @@ const (
 	StrategyTask = "strategy:task:"
 )
 
+// EnqueueStrategyTask schedules the next run of the strategy, a cycle from
+// now when polling or right after the end of its current cycle. Runs on
+// candle close are keyed by the end of the cycle, so a strategy is never
+// scheduled twice for the same candle.
 func (w StrategyWorker) EnqueueStrategyTask(strategy entities.Strategy) error {
 	payload, err := json.Marshal(strategy)
 	if err != nil {
@@ -34,12 +45,55 @@ func (w StrategyWorker) EnqueueStrategyTask(strategy entities.Strategy) error {
 	}
 	task := StrategyTask + strategy.Name
 	t1 := asynq.NewTask(task, payload)
-	cycle := time.Duration(strategy.StrategyConfiguration.Cycle)
-	time := cycle * time.Minute
-	info, err := w.client.Enqueue(t1, asynq.ProcessIn(time))
+
+	cycle := strategy.StrategyConfiguration.Cycle.Duration()
+	opts := []asynq.Option{asynq.ProcessIn(cycle)}
+	if w.execution.Mode == configuration.CandleCloseExecution {
+		closeTime := nextCycle(strategy, time.Now())
+		opts = []asynq.Option{
+			asynq.TaskID(closeTaskID(strategy, closeTime)),
+			asynq.ProcessAt(closeTime.Add(w.execution.CloseDelay)),
+			// Completed runs keep their ID until the next close, a late
+			// trigger must not run them again.
+			asynq.Retention(cycle),
+		}
+	}
+
+	info, err := w.client.Enqueue(t1, opts...)
+	if errors.Is(err, asynq.ErrTaskIDConflict) {
+		return nil
+	}
 	if err != nil {
 		return err
 	}
 	log.Printf(" [*] Successfully enqueued task: %+v", info.ID)
 	return nil
 }
+
+// RunStrategyTask runs at once the run of the strategy scheduled for the
+// cycle ending at closeTime, when it is still waiting for its time.
+func (w StrategyWorker) RunStrategyTask(strategy entities.Strategy, closeTime time.Time) error {
+	id := closeTaskID(strategy, closeTime)
+	info, err := w.inspector.GetTaskInfo("default", id)
+	if errors.Is(err, asynq.ErrTaskNotFound) || errors.Is(err, asynq.ErrQueueNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if info.State != asynq.TaskStateScheduled {
+		return nil
+	}
+	return w.inspector.RunTask("default", id)
+}
+
+// nextCycle returns the end of the cycle of the strategy holding now, cycles
+// are aligned like the candles of the same interval.
+func nextCycle(strategy entities.Strategy, now time.Time) time.Time {
+	cycle := strategy.StrategyConfiguration.Cycle
+	return broker.IntervalStart(now.UTC(), cycle.Interval()).Add(cycle.Duration())
+}
+
+func closeTaskID(strategy entities.Strategy, closeTime time.Time) string {
+	return fmt.Sprintf("%s%d:%d", StrategyTask, strategy.ID, closeTime.Unix())
+}
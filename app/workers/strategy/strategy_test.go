@@ -0,0 +1,47 @@
+package tasks_test
+
+import (
+	"go-trade-bot/app/entities"
+	tasks "go-trade-bot/app/workers/strategy"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNextCycle(t *testing.T) {
+	// A Thursday, 3 day cycles are counted from the Unix epoch and weeks
+	// start on Mondays.
+	now := time.Date(2024, 1, 4, 10, 14, 59, 0, time.UTC)
+
+	tests := []struct {
+		cycle entities.Cycle
+		want  time.Time
+	}{
+		{entities.OneMinute, time.Date(2024, 1, 4, 10, 15, 0, 0, time.UTC)},
+		{entities.ThreeMinutes, time.Date(2024, 1, 4, 10, 15, 0, 0, time.UTC)},
+		{entities.FiveMinutes, time.Date(2024, 1, 4, 10, 15, 0, 0, time.UTC)},
+		{entities.TenMinutes, time.Date(2024, 1, 4, 10, 15, 0, 0, time.UTC)},
+		{entities.FifteenMinutes, time.Date(2024, 1, 4, 10, 15, 0, 0, time.UTC)},
+		{entities.ThirtyMinutes, time.Date(2024, 1, 4, 10, 30, 0, 0, time.UTC)},
+		{entities.OneHour, time.Date(2024, 1, 4, 11, 0, 0, 0, time.UTC)},
+		{entities.TwoHours, time.Date(2024, 1, 4, 12, 0, 0, 0, time.UTC)},
+		{entities.FourHours, time.Date(2024, 1, 4, 12, 0, 0, 0, time.UTC)},
+		{entities.SixHours, time.Date(2024, 1, 4, 12, 0, 0, 0, time.UTC)},
+		{entities.EightHours, time.Date(2024, 1, 4, 16, 0, 0, 0, time.UTC)},
+		{entities.TwelveHours, time.Date(2024, 1, 4, 12, 0, 0, 0, time.UTC)},
+		{entities.OneDay, time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC)},
+		{entities.ThreeDays, time.Date(2024, 1, 6, 0, 0, 0, 0, time.UTC)},
+		{entities.OneWeek, time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.cycle.Interval(), func(t *testing.T) {
+			strategy := entities.Strategy{StrategyConfiguration: entities.StrategyConfiguration{Cycle: tt.cycle}}
+
+			end := tasks.NextCycle(strategy, now)
+			assert.Equal(t, tt.want, end)
+			// The end of a cycle starts the next one.
+			assert.Equal(t, end.Add(tt.cycle.Duration()), tasks.NextCycle(strategy, end))
+		})
+	}
+}
@@ -5,11 +5,18 @@ import (
 	"fmt"
 	"go-trade-bot/app/entities"
 	account "go-trade-bot/app/handler/web/account"
+	algorithm "go-trade-bot/app/handler/web/algorithm"
+	backtest "go-trade-bot/app/handler/web/backtest"
 	broker "go-trade-bot/app/handler/web/broker"
+	candle "go-trade-bot/app/handler/web/candle"
+	grid "go-trade-bot/app/handler/web/grid"
+	killswitch "go-trade-bot/app/handler/web/killswitch"
+	risk "go-trade-bot/app/handler/web/risk"
 	signal "go-trade-bot/app/handler/web/signal"
 	strategy "go-trade-bot/app/handler/web/strategy"
 	"go-trade-bot/cmd/api/modules"
 	config "go-trade-bot/internal/configuration"
+	"go-trade-bot/internal/db"
 	"go-trade-bot/internal/handler"
 	"go-trade-bot/internal/metrics"
 	"go-trade-bot/internal/middleware"
@@ -32,16 +39,29 @@ func main() {
 		modules.ConfigurationModule,
 		modules.DbModule,
 		modules.BrokerModule,
+		modules.FeesModule,
 		modules.StrategyModule,
 		modules.MetricsModule,
 		modules.AccountModule,
 		modules.SignalModule,
+		modules.BacktestModule,
+		modules.CandleModule,
+		modules.AlgorithmModule,
+		modules.GridModule,
+		modules.RiskModule,
+		modules.KillSwitchModule,
 		fx.Provide(
 			NewHTTPServer,
 			AsRoute(strategy.NewStrategyHandler),
 			AsRoute(broker.NewBrokerHandler),
 			AsRoute(account.NewAccountHandler),
 			AsRoute(signal.NewSignalHandler),
+			AsRoute(backtest.NewBacktestHandler),
+			AsRoute(candle.NewCandleHandler),
+			AsRoute(algorithm.NewAlgorithmHandler),
+			AsRoute(grid.NewGridHandler),
+			AsRoute(risk.NewRiskHandler),
+			AsRoute(killswitch.NewKillSwitchHandler),
 			fx.Annotate(
 				NewServeMux,
 				fx.ParamTags(`group:"routes"`),
@@ -49,7 +69,7 @@ func main() {
 		),
 		fx.Invoke(func(db *gorm.DB) {
 			if err := Migrate(db); err != nil {
-				log.Fatalf("failed to migrate database: %w", err)
+				log.Fatalf("failed to migrate database: %v", err)
 			}
 		}),
 		fx.Invoke(func(*http.Server) {}),
@@ -102,12 +122,30 @@ func AsRoute(f any) any {
 	)
 }
 
-func Migrate(db *gorm.DB) error {
-	return db.AutoMigrate(
+// decimalColumns held float32 values before money became decimal.
+var decimalColumns = map[string][]string{
+	"accounts":       {"amount"},
+	"signals":        {"high_water_mark"},
+	"orders":         {"entry_price", "exit_price", "quantity", "invested_amount", "entry_fee", "exit_fee", "executed_qty", "profit"},
+	"ledger_entries": {"amount", "balance"},
+}
+
+func Migrate(conn *gorm.DB) error {
+	for table, columns := range decimalColumns {
+		if err := db.ConvertToNumeric(conn, table, columns...); err != nil {
+			return err
+		}
+	}
+	return conn.AutoMigrate(
 		&entities.Strategy{},
 		&entities.StrategyExecution{},
 		&entities.Signal{},
 		&entities.Order{},
 		&entities.Account{},
+		&entities.LedgerEntry{},
+		&entities.Candle{},
+		&entities.GridState{},
+		&entities.RiskState{},
+		&entities.RiskEvent{},
 	)
 }
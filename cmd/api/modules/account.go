@@ -3,7 +3,11 @@ package modules
 import (
 	handler "go-trade-bot/app/handler/web/account"
 	repository "go-trade-bot/app/repository/account"
+	ledger "go-trade-bot/app/repository/ledger"
 	usecase "go-trade-bot/app/usecase/account"
+	strategy "go-trade-bot/app/usecase/strategy"
+	"go-trade-bot/internal/db"
+	"go-trade-bot/internal/fees"
 
 	"go.uber.org/fx"
 )
@@ -11,8 +15,13 @@ import (
 var AccountModule = fx.Module("account",
 	fx.Provide(
 		repository.NewAccountRepository,
+		ledger.NewLedgerRepository,
 		usecase.NewAccountUseCase,
 		func(s repository.AccountRepository) usecase.AccountRepository { return s },
+		func(l ledger.LedgerRepository) usecase.LedgerRepository { return l },
 		func(s *usecase.AccountUseCase) handler.UseCase { return s },
+		func(s repository.AccountRepository) strategy.AccountRepository { return s },
+		func(t db.Transactor) usecase.Transactor { return t },
+		func(f fees.Schedule) usecase.FeeSchedule { return f },
 	),
 )
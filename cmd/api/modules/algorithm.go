@@ -0,0 +1,22 @@
+package modules
+
+import (
+	handler "go-trade-bot/app/handler/web/algorithm"
+	"go-trade-bot/app/services/algorithm"
+	_ "go-trade-bot/app/services/algorithm/builtin"
+	"go-trade-bot/app/services/backtest"
+	backtestUseCase "go-trade-bot/app/usecase/backtest"
+	strategyUseCase "go-trade-bot/app/usecase/strategy"
+
+	"go.uber.org/fx"
+)
+
+var AlgorithmModule = fx.Module("algorithm",
+	fx.Provide(
+		algorithm.DefaultRegistry,
+		func(r *algorithm.Registry) handler.Registry { return r },
+		func(r *algorithm.Registry) strategyUseCase.Algorithms { return r },
+		func(r *algorithm.Registry) backtestUseCase.Algorithms { return r },
+		func(r *algorithm.Registry) backtest.Algorithms { return r },
+	),
+)
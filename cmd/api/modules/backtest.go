@@ -0,0 +1,22 @@
+package modules
+
+import (
+	handler "go-trade-bot/app/handler/web/backtest"
+	repository "go-trade-bot/app/repository/strategy"
+	"go-trade-bot/app/services/backtest"
+	usecase "go-trade-bot/app/usecase/backtest"
+	candle "go-trade-bot/app/usecase/candle"
+
+	"go.uber.org/fx"
+)
+
+var BacktestModule = fx.Module("backtest",
+	fx.Provide(
+		backtest.NewEngine,
+		usecase.NewBacktestUseCase,
+		func(e backtest.Engine) usecase.Engine { return e },
+		func(c candle.CandleUseCase) usecase.History { return c },
+		func(s repository.StrategyRepository) usecase.StrategyRepository { return s },
+		func(s usecase.BacktestUseCase) handler.UseCase { return s },
+	),
+)
@@ -0,0 +1,23 @@
+package modules
+
+import (
+	handler "go-trade-bot/app/handler/web/candle"
+	repository "go-trade-bot/app/repository/candle"
+	usecase "go-trade-bot/app/usecase/candle"
+	worker "go-trade-bot/app/workers/candle"
+	"go-trade-bot/internal/broker"
+
+	"go.uber.org/fx"
+)
+
+var CandleModule = fx.Module("candle",
+	fx.Provide(
+		repository.NewCandleRepository,
+		worker.NewCandleWorker,
+		usecase.NewCandleUseCase,
+		func(s repository.CandleRepository) usecase.CandleRepository { return s },
+		func(b broker.Broker) usecase.Broker { return b },
+		func(w worker.CandleWorker) usecase.CandleWorker { return w },
+		func(s usecase.CandleUseCase) handler.UseCase { return s },
+	),
+)
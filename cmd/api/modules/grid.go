@@ -0,0 +1,20 @@
+package modules
+
+import (
+	handler "go-trade-bot/app/handler/web/grid"
+	repository "go-trade-bot/app/repository/grid"
+	strategy "go-trade-bot/app/repository/strategy"
+	usecase "go-trade-bot/app/usecase/grid"
+
+	"go.uber.org/fx"
+)
+
+var GridModule = fx.Module("grid",
+	fx.Provide(
+		repository.NewGridRepository,
+		usecase.NewGridUseCase,
+		func(r repository.GridRepository) usecase.GridRepository { return r },
+		func(s strategy.StrategyRepository) usecase.StrategyRepository { return s },
+		func(u usecase.GridUseCase) handler.UseCase { return u },
+	),
+)
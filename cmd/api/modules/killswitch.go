@@ -0,0 +1,21 @@
+package modules
+
+import (
+	handler "go-trade-bot/app/handler/web/killswitch"
+	usecase "go-trade-bot/app/usecase/killswitch"
+	worker "go-trade-bot/app/workers/killswitch"
+	"go-trade-bot/internal/killswitch"
+
+	"go.uber.org/fx"
+)
+
+var KillSwitchModule = fx.Module("killswitch",
+	fx.Provide(
+		killswitch.NewSwitch,
+		worker.NewKillSwitchWorker,
+		usecase.NewKillSwitchUseCase,
+		func(s killswitch.Switch) usecase.Switch { return s },
+		func(w worker.KillSwitchWorker) usecase.Worker { return w },
+		func(u usecase.KillSwitchUseCase) handler.UseCase { return u },
+	),
+)
@@ -22,6 +22,55 @@ var MetricsModule = fx.Module("metrics",
 				LabelNames: []string{"path"},
 				Buckets:    []float64{0.1, 0.3, 1.2, 5.0},
 			},
+			{
+				Name: "risk_halted",
+				Help: "Whether a risk breaker halts new entries",
+				Type: metrics.Gauge,
+			},
+			{
+				Name: "risk_equity",
+				Help: "Equity of every account, balance plus open positions",
+				Type: metrics.Gauge,
+			},
+			{
+				Name: "risk_drawdown_pct",
+				Help: "Drawdown of the equity from its peak in percent",
+				Type: metrics.Gauge,
+			},
+			{
+				Name: "risk_daily_pnl_pct",
+				Help: "Profit realized today in percent of the equity",
+				Type: metrics.Gauge,
+			},
+			{
+				Name: "risk_exposure_pct",
+				Help: "Open positions in percent of the equity",
+				Type: metrics.Gauge,
+			},
+			{
+				Name:       "risk_trips_total",
+				Help:       "Total of risk breakers tripped",
+				Type:       metrics.Counter,
+				LabelNames: []string{"rule"},
+			},
+			{
+				Name:       "broker_request_weight",
+				Help:       "Request weight spent on the exchange API in the current minute",
+				Type:       metrics.Gauge,
+				LabelNames: []string{"api"},
+			},
+			{
+				Name:       "broker_rate_limited_total",
+				Help:       "Total of exchange API calls paused by a 418 or 429",
+				Type:       metrics.Counter,
+				LabelNames: []string{"api", "status"},
+			},
+			{
+				Name:       "broker_retries_total",
+				Help:       "Total of exchange API reads retried",
+				Type:       metrics.Counter,
+				LabelNames: []string{"api"},
+			},
 		})
 	}),
 )
@@ -6,6 +6,8 @@ import (
 	account "go-trade-bot/app/usecase/account"
 	usecase "go-trade-bot/app/usecase/signal"
 	"go-trade-bot/internal/broker"
+	"go-trade-bot/internal/db"
+	"go-trade-bot/internal/fees"
 
 	"go.uber.org/fx"
 )
@@ -13,10 +15,15 @@ import (
 var SignalModule = fx.Module("signal",
 	fx.Provide(
 		repository.NewSignalRepository,
-		usecase.NewSignalUseCase,
+		usecase.NewPaperSignalUseCase,
 		func(b broker.Broker) usecase.Broker { return b },
+		func(s *broker.PaperBroker) usecase.OrderBroker { return s },
+		func(b broker.FuturesBroker) usecase.FuturesBroker { return b },
 		func(a *account.AccountUseCase) usecase.AccountUseCase { return a },
 		func(s repository.SignalRepository) usecase.SignalRepository { return s },
+		func(s repository.SignalRepository) broker.PaperBook { return s },
 		func(s usecase.SignalUseCase) handler.UseCase { return s },
+		func(t db.Transactor) usecase.Transactor { return t },
+		func(f fees.Schedule) usecase.FeeSchedule { return f },
 	),
 )
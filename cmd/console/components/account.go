@@ -12,23 +12,24 @@ import (
 
 func Account(d *dependencies.Dependencies) *widgets.List {
 	list := widgets.NewList()
-	list.Title = "Account Data"
+	list.Title = "Accounts"
 
 	accountRepository := repository.NewAccountRepository(d.Db)
-	account, err := accountRepository.GetAccountByID(1)
+	accounts, err := accountRepository.GetAll()
 
 	if err != nil {
 		list.Rows = []string{"Error fetching account data: " + err.Error()}
 		return list
 	}
 
-	list.Rows = []string{
-		"ID: " + strconv.FormatInt(account.ID, 10),
-		"Amount: " + strconv.FormatFloat(float64(account.Amount), 'f', 2, 64),
-		"Available: " + strconv.FormatInt(account.AvailableOrders, 10),
-		"Currency: " + account.Currency,
-		"Created At: " + account.CreatedAt.String(),
-		"Updated At: " + account.UpdatedAt.String(),
+	list.Rows = []string{}
+	for _, account := range accounts {
+		list.Rows = append(list.Rows,
+			"["+strconv.FormatInt(account.ID, 10)+"] "+account.Name,
+			"  Amount: "+account.Amount.StringFixed(2)+" "+account.Currency,
+			"  Available: "+strconv.FormatInt(account.AvailableOrders, 10),
+			"  Updated At: "+account.UpdatedAt.Format("2006-01-02 15:04:05"),
+		)
 	}
 
 	list.TextStyle = ui.NewStyle(ui.ColorWhite)
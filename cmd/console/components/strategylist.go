@@ -31,7 +31,7 @@ func getStrategies(d *dependencies.Dependencies) []string {
 
 	var strategyNames []string
 	for _, strategy := range strategies {
-		strategyNames = append(strategyNames, fmt.Sprintf("%d - %s - %s", strategy.ID, strategy.Name, string(strategy.Status)))
+		strategyNames = append(strategyNames, fmt.Sprintf("%d - %s - %s - account %d", strategy.ID, strategy.Name, string(strategy.Status), strategy.AccountID))
 	}
 
 	return strategyNames
@@ -1,8 +1,16 @@
 package dependencies
 
 import (
+	"context"
+	usecase "go-trade-bot/app/usecase/killswitch"
+	worker "go-trade-bot/app/workers/killswitch"
+	"go-trade-bot/internal/broker"
 	"go-trade-bot/internal/configuration"
 	"go-trade-bot/internal/db"
+	"go-trade-bot/internal/killswitch"
+	"go-trade-bot/internal/marketdata"
+	"go-trade-bot/internal/metrics"
+	"go-trade-bot/internal/ratelimit"
 
 	"gorm.io/gorm"
 )
@@ -10,6 +18,12 @@ import (
 type Dependencies struct {
 	Cfg *configuration.Configuration
 	Db  *gorm.DB
+	// KillSwitch pauses every strategy through the same flag and task as
+	// the API.
+	KillSwitch usecase.KillSwitchUseCase
+	// Prices streams the prices of the symbols shown, polling the exchange
+	// when MARKET_DATA.STREAMING is off.
+	Prices *marketdata.Feed
 }
 
 func Init() *Dependencies {
@@ -19,8 +33,19 @@ func Init() *Dependencies {
 		panic("Failed to connect to database: " + err.Error())
 	}
 
+	limiters := ratelimit.NewLimiters(cfg, metrics.NewMetricsCollector(nil))
+	prices := marketdata.NewFeed(cfg, broker.NewBinanceBroker(cfg, limiters))
+	if cfg.MarketData.Streaming {
+		go prices.Run(context.Background())
+	}
+
 	return &Dependencies{
 		Cfg: cfg,
 		Db:  db,
+		KillSwitch: usecase.NewKillSwitchUseCase(
+			killswitch.NewSwitch(cfg),
+			worker.NewKillSwitchWorker(cfg),
+		),
+		Prices: prices,
 	}
 }
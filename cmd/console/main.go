@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	dependencies "go-trade-bot/cmd/console/dependencies"
 	"go-trade-bot/cmd/console/pages"
 	"log"
@@ -17,8 +18,8 @@ func main() {
 	defer ui.Close()
 
 	header := widgets.NewParagraph()
-	header.Text = "Press ESC to quit, Press h or l to switch tabs"
-	header.SetRect(0, 0, 50, 1)
+	header.Text = "Press ESC to quit, Press h or l to switch tabs, k to kill (K also closes signals), u to resume"
+	header.SetRect(0, 0, 100, 1)
 	header.Border = false
 	header.TextStyle.Bg = ui.ColorRed
 
@@ -64,6 +65,19 @@ func main() {
 			ui.Clear()
 			ui.Render(header, tabPane)
 			renderTab()
+		case "k", "K":
+			_, err := dependencies.KillSwitch.Engage(context.Background(), "Engaged from the console", e.ID == "K")
+			header.Text = "Kill switch engaged, strategies paused. Press u to resume"
+			if err != nil {
+				header.Text = "Failed to engage the kill switch: " + err.Error()
+			}
+			ui.Render(header)
+		case "u":
+			header.Text = "Kill switch released, strategies resume on their next execution"
+			if err := dependencies.KillSwitch.Release(context.Background()); err != nil {
+				header.Text = "Failed to release the kill switch: " + err.Error()
+			}
+			ui.Render(header)
 		}
 	}
 }
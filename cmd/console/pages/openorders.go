@@ -7,12 +7,12 @@ import (
 	repository "go-trade-bot/app/repository/signal"
 	"go-trade-bot/cmd/console/components"
 	"go-trade-bot/cmd/console/dependencies"
-	"go-trade-bot/internal/broker"
 	"strconv"
 	"time"
 
 	ui "github.com/gizak/termui/v3"
 	"github.com/gizak/termui/v3/widgets"
+	"github.com/shopspring/decimal"
 )
 
 type OpenOrdersPage struct {
@@ -57,7 +57,7 @@ func (p *OpenOrdersPage) Render() ui.Drawable {
 }
 
 func (p *OpenOrdersPage) renderOpenSignals() []ui.GridItem {
-	broker := broker.NewBroker(p.Dependencies.Cfg)
+	feed := p.Dependencies.Prices
 	signals, err := p.getOpenSignals()
 	if err != nil {
 		return []ui.GridItem{
@@ -69,16 +69,16 @@ func (p *OpenOrdersPage) renderOpenSignals() []ui.GridItem {
 	for _, signal := range signals {
 		strategy := widgets.NewParagraph()
 		strategy.Text = fmt.Sprintf(
-			"ID - %d - %s - %s - %s", signal.ID, signal.Strategy.Name, string(signal.Status), signal.Symbol,
+			"ID - %d - %s - %s - %s %s", signal.ID, signal.Strategy.Name, string(signal.Status), signal.Symbol, sideOf(signal),
 		)
 		strategy.TextStyle.Fg = ui.ColorGreen
 
 		invested := widgets.NewParagraph()
 		invested.Text = fmt.Sprintf(
-			"Inv: %s - Q: %.2f - EP: $%.2f",
-			fmt.Sprintf("$%.2f", signal.Orders[0].InvestedAmount),
-			signal.Orders[0].Quantity,
-			signal.Orders[0].EntryPrice,
+			"Inv: $%s - Q: %s - EP: $%s",
+			signal.Orders[0].InvestedAmount.StringFixed(2),
+			signal.Orders[0].Quantity.StringFixed(2),
+			signal.Orders[0].EntryPrice.StringFixed(2),
 		)
 		invested.TextStyle.Fg = ui.ColorGreen
 		current := widgets.NewParagraph()
@@ -87,21 +87,24 @@ func (p *OpenOrdersPage) renderOpenSignals() []ui.GridItem {
 				if p.Stop {
 					return
 				}
-				prices, err := broker.ListTickerPrices(context.TODO(), signal.Symbol)
+				prices, err := feed.ListTickerPrices(context.TODO(), signal.Symbol)
 				if err != nil {
 					current.Text = "Error fetching price: " + err.Error()
 					ui.Render(current)
 					return
 				}
-				priceFloat, err := strconv.ParseFloat(prices[0].Price, 32)
-				if err != nil {
-					current.Text = "Error parsing price: " + err.Error()
+				if len(prices) == 0 {
+					current.Text = "No price available for " + signal.Symbol
 					ui.Render(current)
 					return
 				}
-				pnl := (float32(priceFloat) * signal.Orders[0].Quantity) - (signal.Orders[0].Quantity * signal.Orders[0].EntryPrice)
-				current.Text = "Current: $" + prices[0].Price + " PnL: $" + fmt.Sprintf("%.2f", pnl)
-				if pnl < 0 {
+				order := signal.Orders[0]
+				pnl := decimal.NewFromFloat(prices[0].Price).Sub(order.EntryPrice).Mul(order.Quantity)
+				if signal.Side == entities.Short {
+					pnl = pnl.Neg()
+				}
+				current.Text = "Current: $" + strconv.FormatFloat(prices[0].Price, 'f', -1, 64) + " PnL: $" + pnl.StringFixed(2)
+				if pnl.IsNegative() {
 					current.TextStyle.Fg = ui.ColorRed
 				} else {
 					current.TextStyle.Fg = ui.ColorBlue
@@ -121,6 +124,18 @@ func (p *OpenOrdersPage) renderOpenSignals() []ui.GridItem {
 	return items
 }
 
+// sideOf labels the side of the signal, with the leverage of futures.
+func sideOf(signal entities.Signal) string {
+	side := entities.Long
+	if signal.Side != "" {
+		side = signal.Side
+	}
+	if signal.Market != entities.Futures {
+		return string(side)
+	}
+	return fmt.Sprintf("%s %gx", side, signal.Orders[0].Leverage)
+}
+
 func (p *OpenOrdersPage) getOpenSignals() ([]entities.Signal, error) {
 	r := repository.NewSignalRepository(p.Dependencies.Db)
 
@@ -2,16 +2,24 @@ package main
 
 import (
 	"context"
+	candleHandler "go-trade-bot/app/handler/tasks/candle"
+	killSwitchHandler "go-trade-bot/app/handler/tasks/killswitch"
 	handler "go-trade-bot/app/handler/tasks/strategy"
 	repository "go-trade-bot/app/repository/strategy"
+	"go-trade-bot/app/services/algorithm"
+	candle "go-trade-bot/app/usecase/candle"
 	usecase "go-trade-bot/app/usecase/signal"
+	candleTasks "go-trade-bot/app/workers/candle"
+	killSwitchTasks "go-trade-bot/app/workers/killswitch"
 	tasks "go-trade-bot/app/workers/strategy"
 	"go-trade-bot/cmd/worker/modules"
 	"go-trade-bot/internal/broker"
 	config "go-trade-bot/internal/configuration"
+	"go-trade-bot/internal/marketdata"
 	"go-trade-bot/internal/memcache"
 	"go-trade-bot/internal/metrics"
 	"go-trade-bot/internal/middleware"
+	"log"
 	"net/http"
 
 	"github.com/gorilla/mux"
@@ -50,15 +58,27 @@ func RegisterHandlers(
 	collector *metrics.MetricsCollector,
 	worker tasks.StrategyWorker,
 	repository repository.StrategyRepository,
-	broker broker.Broker,
+	feed *marketdata.Feed,
 	signalUC usecase.SignalUseCase,
 	cache memcache.Cache,
+	candleUC candle.CandleUseCase,
+	algorithms handler.Algorithms,
+	grids algorithm.GridStore,
+	risk handler.RiskManager,
+	killSwitch handler.KillSwitch,
+	killSwitchProcessor *killSwitchHandler.KillSwitchProcessor,
 ) {
 	StartMetricsServer(cfg)
 	lc.Append(fx.Hook{
 		OnStart: func(ctx context.Context) error {
 			mux := asynq.NewServeMux()
-			processor := handler.NewStrategyProcessor(collector, worker, repository, broker, signalUC, cache)
+			// Strategies share their reads, and only see closed candles when
+			// they run on candle close.
+			var klines broker.Broker = marketdata.NewCache(feed, cfg.MarketData.CacheMaxAge)
+			if cfg.Execution.Mode == config.CandleCloseExecution {
+				klines = marketdata.NewClosed(klines)
+			}
+			processor := handler.NewStrategyProcessor(collector, worker, repository, klines, signalUC, cache, algorithms, grids, risk, killSwitch)
 
 			mux.Handle(tasks.StrategyTask, middleware.AsynqConfigMiddleware(
 				asynq.HandlerFunc(processor.HandleStrategyTask),
@@ -66,6 +86,19 @@ func RegisterHandlers(
 				collector,
 			))
 
+			backfill := candleHandler.NewBackfillProcessor(candleUC)
+			mux.Handle(candleTasks.BackfillTask, middleware.AsynqConfigMiddleware(
+				asynq.HandlerFunc(backfill.HandleBackfillTask),
+				cfg,
+				collector,
+			))
+
+			mux.Handle(killSwitchTasks.KillSwitchTask, middleware.AsynqConfigMiddleware(
+				asynq.HandlerFunc(killSwitchProcessor.HandleKillSwitchTask),
+				cfg,
+				collector,
+			))
+
 			go server.Run(mux)
 			return nil
 		},
@@ -76,6 +109,43 @@ func RegisterHandlers(
 	})
 }
 
+// RunMarketData streams the klines and prices of every monitored symbol
+// while the worker runs, symbols of strategies created later are subscribed
+// on their first read. On candle close the closes of the stream run the
+// strategies without waiting for their scheduled time.
+func RunMarketData(lc fx.Lifecycle, cfg *config.Configuration, feed *marketdata.Feed, repository repository.StrategyRepository, trigger handler.CandleTrigger) {
+	if !cfg.MarketData.Streaming {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	if cfg.Execution.Mode == config.CandleCloseExecution {
+		feed.OnCandleClose(func(symbol string, interval string, candle broker.Candle) {
+			if err := trigger.HandleCandleClose(ctx, symbol, interval, candle); err != nil {
+				log.Printf("Error triggering strategies on the close of %s: %v", symbol, err)
+			}
+		})
+	}
+	lc.Append(fx.Hook{
+		OnStart: func(startCtx context.Context) error {
+			strategies, err := repository.GetAll(startCtx)
+			if err != nil {
+				return err
+			}
+			for _, strategy := range strategies {
+				for _, symbol := range strategy.MonitoredSymbols {
+					feed.Subscribe(symbol, strategy.GetBrokerInterval())
+				}
+			}
+			go feed.Run(ctx)
+			return nil
+		},
+		OnStop: func(context.Context) error {
+			cancel()
+			return nil
+		},
+	})
+}
+
 func StartMetricsServer(cfg *config.Configuration) {
 	h := asynqmon.New(asynqmon.Options{
 		RootPath:          "/tasks/monitoring",
@@ -102,15 +172,23 @@ func main() {
 		modules.DbModule,
 		modules.MetricsModule,
 		modules.CacheModule,
+		modules.FeesModule,
 		modules.StrategyModule,
 		modules.SignalModule,
 		modules.BrokerModule,
 		modules.AccountModule,
+		modules.CandleModule,
+		modules.AlgorithmModule,
+		modules.GridModule,
+		modules.RiskModule,
+		modules.KillSwitchModule,
+		modules.MarketDataModule,
 		fx.Provide(
 			NewRedisClient,
 			NewAsynqServer,
 		),
 		fx.Invoke(RegisterHandlers),
+		fx.Invoke(RunMarketData),
 	)
 
 	app.Run()
@@ -2,8 +2,11 @@ package modules
 
 import (
 	repository "go-trade-bot/app/repository/account"
+	ledger "go-trade-bot/app/repository/ledger"
 	usecase "go-trade-bot/app/usecase/account"
 	signal "go-trade-bot/app/usecase/signal"
+	"go-trade-bot/internal/db"
+	"go-trade-bot/internal/fees"
 
 	"go.uber.org/fx"
 )
@@ -11,8 +14,12 @@ import (
 var AccountModule = fx.Module("account",
 	fx.Provide(
 		repository.NewAccountRepository,
+		ledger.NewLedgerRepository,
 		usecase.NewAccountUseCase,
 		func(s repository.AccountRepository) usecase.AccountRepository { return s },
+		func(l ledger.LedgerRepository) usecase.LedgerRepository { return l },
 		func(s *usecase.AccountUseCase) signal.AccountUseCase { return s },
+		func(t db.Transactor) usecase.Transactor { return t },
+		func(f fees.Schedule) usecase.FeeSchedule { return f },
 	),
 )
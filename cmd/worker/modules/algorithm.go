@@ -0,0 +1,16 @@
+package modules
+
+import (
+	handler "go-trade-bot/app/handler/tasks/strategy"
+	"go-trade-bot/app/services/algorithm"
+	_ "go-trade-bot/app/services/algorithm/builtin"
+
+	"go.uber.org/fx"
+)
+
+var AlgorithmModule = fx.Module("algorithm",
+	fx.Provide(
+		algorithm.DefaultRegistry,
+		func(r *algorithm.Registry) handler.Algorithms { return r },
+	),
+)
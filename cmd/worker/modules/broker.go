@@ -2,10 +2,16 @@ package modules
 
 import (
 	"go-trade-bot/internal/broker"
+	"go-trade-bot/internal/ratelimit"
 
 	"go.uber.org/fx"
 )
 
 var BrokerModule = fx.Module("broker",
-	fx.Provide(broker.NewBroker),
+	fx.Provide(
+		ratelimit.NewLimiters,
+		broker.NewPaperBroker,
+		broker.NewBroker,
+		broker.NewFuturesBroker,
+	),
 )
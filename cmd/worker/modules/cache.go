@@ -7,5 +7,5 @@ import (
 )
 
 var CacheModule = fx.Module("cache",
-	fx.Provide(memcache.NewInMemoryCache),
+	fx.Provide(memcache.NewCache),
 )
@@ -9,5 +9,6 @@ import (
 var DbModule = fx.Module("db",
 	fx.Provide(
 		db.NewDatabase,
+		db.NewTransactor,
 	),
 )
@@ -0,0 +1,11 @@
+package modules
+
+import (
+	"go-trade-bot/internal/fees"
+
+	"go.uber.org/fx"
+)
+
+var FeesModule = fx.Module("fees",
+	fx.Provide(fees.NewSchedule),
+)
@@ -0,0 +1,15 @@
+package modules
+
+import (
+	repository "go-trade-bot/app/repository/grid"
+	"go-trade-bot/app/services/algorithm"
+
+	"go.uber.org/fx"
+)
+
+var GridModule = fx.Module("grid",
+	fx.Provide(
+		repository.NewGridRepository,
+		func(r repository.GridRepository) algorithm.GridStore { return r },
+	),
+)
@@ -0,0 +1,27 @@
+package modules
+
+import (
+	handler "go-trade-bot/app/handler/tasks/killswitch"
+	strategyHandler "go-trade-bot/app/handler/tasks/strategy"
+	signal "go-trade-bot/app/repository/signal"
+	strategy "go-trade-bot/app/repository/strategy"
+	usecase "go-trade-bot/app/usecase/signal"
+	"go-trade-bot/internal/broker"
+	"go-trade-bot/internal/killswitch"
+
+	"go.uber.org/fx"
+)
+
+var KillSwitchModule = fx.Module("killswitch",
+	fx.Provide(
+		killswitch.NewSwitch,
+		handler.NewKillSwitchProcessor,
+		func(s killswitch.Switch) handler.Switch { return s },
+		func(s killswitch.Switch) strategyHandler.KillSwitch { return s },
+		func(r strategy.StrategyRepository) handler.StrategyRepository { return r },
+		func(r signal.SignalRepository) handler.SignalRepository { return r },
+		func(u usecase.SignalUseCase) handler.SignalUseCase { return u },
+		func(b broker.Broker) handler.Broker { return b },
+		func(b broker.FuturesBroker) handler.FuturesBroker { return b },
+	),
+)
@@ -0,0 +1,17 @@
+package modules
+
+import (
+	candle "go-trade-bot/app/usecase/candle"
+	"go-trade-bot/internal/configuration"
+	"go-trade-bot/internal/marketdata"
+
+	"go.uber.org/fx"
+)
+
+var MarketDataModule = fx.Module("marketdata",
+	fx.Provide(
+		func(cfg *configuration.Configuration, b candle.StoreBroker) *marketdata.Feed {
+			return marketdata.NewFeed(cfg, b)
+		},
+	),
+)
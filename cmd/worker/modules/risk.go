@@ -0,0 +1,24 @@
+package modules
+
+import (
+	strategy "go-trade-bot/app/handler/tasks/strategy"
+	repository "go-trade-bot/app/repository/risk"
+	usecase "go-trade-bot/app/usecase/risk"
+	signal "go-trade-bot/app/usecase/signal"
+	"go-trade-bot/internal/broker"
+	"go-trade-bot/internal/metrics"
+
+	"go.uber.org/fx"
+)
+
+var RiskModule = fx.Module("risk",
+	fx.Provide(
+		repository.NewRiskRepository,
+		usecase.NewRiskUseCase,
+		func(r repository.RiskRepository) usecase.RiskRepository { return r },
+		func(b broker.Broker) usecase.Broker { return b },
+		func(m *metrics.MetricsCollector) usecase.Metrics { return m },
+		func(u *usecase.RiskUseCase) strategy.RiskManager { return u },
+		func(u *usecase.RiskUseCase) signal.RiskManager { return u },
+	),
+)
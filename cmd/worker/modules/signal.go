@@ -4,6 +4,8 @@ import (
 	repository "go-trade-bot/app/repository/signal"
 	usecase "go-trade-bot/app/usecase/signal"
 	"go-trade-bot/internal/broker"
+	"go-trade-bot/internal/db"
+	"go-trade-bot/internal/fees"
 
 	"go.uber.org/fx"
 )
@@ -11,8 +13,13 @@ import (
 var SignalModule = fx.Module("signal",
 	fx.Provide(
 		repository.NewSignalRepository,
-		usecase.NewSignalUseCase,
+		usecase.NewPaperSignalUseCase,
 		func(s broker.Broker) usecase.Broker { return s },
+		func(s *broker.PaperBroker) usecase.OrderBroker { return s },
+		func(b broker.FuturesBroker) usecase.FuturesBroker { return b },
 		func(s repository.SignalRepository) usecase.SignalRepository { return s },
+		func(s repository.SignalRepository) broker.PaperBook { return s },
+		func(t db.Transactor) usecase.Transactor { return t },
+		func(f fees.Schedule) usecase.FeeSchedule { return f },
 	),
 )
@@ -1,6 +1,7 @@
 package modules
 
 import (
+	handler "go-trade-bot/app/handler/tasks/strategy"
 	repository "go-trade-bot/app/repository/strategy"
 	worker "go-trade-bot/app/workers/strategy"
 
@@ -11,5 +12,8 @@ var StrategyModule = fx.Module("strategy",
 	fx.Provide(
 		worker.NewStrategyWorker,
 		repository.NewStrategyRepository,
+		handler.NewCandleTrigger,
+		func(r repository.StrategyRepository) handler.StrategyLister { return r },
+		func(w worker.StrategyWorker) handler.StrategyRunner { return w },
 	),
 )
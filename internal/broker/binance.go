@@ -0,0 +1,345 @@
+package broker
+
+import (
+	"context"
+	"fmt"
+	"go-trade-bot/internal/configuration"
+	"go-trade-bot/internal/ratelimit"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/adshao/go-binance/v2"
+	"github.com/adshao/go-binance/v2/common"
+	"github.com/shopspring/decimal"
+)
+
+// symbolFiltersTTL is how long the exchange info of a symbol is kept, the
+// filters of a symbol rarely change.
+const symbolFiltersTTL = time.Hour
+
+type cachedFilters struct {
+	filters   SymbolFilters
+	expiresAt time.Time
+}
+
+type BinanceBroker struct {
+	client *binance.Client
+
+	mu      sync.Mutex
+	filters map[string]cachedFilters
+}
+
+// NewBinanceBroker returns a broker whose calls are limited by the spot
+// request weight budget.
+func NewBinanceBroker(cfg *configuration.Configuration, limiters ratelimit.Limiters) *BinanceBroker {
+	client := binance.NewClient(cfg.Broker.ApiKey, cfg.Broker.ApiSecret)
+	client.HTTPClient = ratelimit.NewClient(limiters.Spot, spotWeight, cfg.RateLimit.MaxRetries)
+	return &BinanceBroker{
+		client:  client,
+		filters: map[string]cachedFilters{},
+	}
+}
+
+func (b *BinanceBroker) ListTickerPrices(ctx context.Context, symbol string) ([]Ticker, error) {
+	prices, err := b.client.NewListPricesService().Symbol(symbol).Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the price of %s: %w", symbol, err)
+	}
+
+	tickers := make([]Ticker, len(prices))
+	for i, p := range prices {
+		price, err := strconv.ParseFloat(p.Price, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse price for symbol %s: %w", p.Symbol, err)
+		}
+		tickers[i] = Ticker{
+			Symbol: p.Symbol,
+			Price:  price,
+		}
+	}
+	return tickers, nil
+}
+
+func (b *BinanceBroker) ListKline(ctx context.Context, symbol string, interval string, limit int) ([]Candle, error) {
+	klines, err := b.client.NewKlinesService().Symbol(symbol).Interval(interval).Limit(limit).Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the %s klines of %s: %w", interval, symbol, err)
+	}
+
+	candles := make([]Candle, len(klines))
+	for i, k := range klines {
+		candle, err := toCandle(k)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse kline for symbol %s: %w", symbol, err)
+		}
+		candles[i] = candle
+	}
+	return candles, nil
+}
+
+// ListKlineRange returns up to limit candles opened at or after start, used
+// to page through the history of a symbol.
+func (b *BinanceBroker) ListKlineRange(ctx context.Context, symbol string, interval string, start time.Time, limit int) ([]Candle, error) {
+	klines, err := b.client.NewKlinesService().Symbol(symbol).Interval(interval).StartTime(start.UnixMilli()).Limit(limit).Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the %s klines of %s: %w", interval, symbol, err)
+	}
+
+	candles := make([]Candle, len(klines))
+	for i, k := range klines {
+		candle, err := toCandle(k)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse kline for symbol %s: %w", symbol, err)
+		}
+		candles[i] = candle
+	}
+	return candles, nil
+}
+
+func (b *BinanceBroker) Get24hVolume(ctx context.Context, symbol string) (float64, error) {
+	candles, err := b.ListKline(ctx, symbol, "1d", 1)
+	if err != nil {
+		return 0, err
+	}
+	if len(candles) == 0 {
+		return 0, fmt.Errorf("no klines found for symbol %s", symbol)
+	}
+	return candles[0].Volume, nil
+}
+
+func (b *BinanceBroker) GetOrderBook(ctx context.Context, symbol string, limit int) (OrderBook, error) {
+	depth, err := b.client.NewDepthService().Symbol(symbol).Limit(limit).Do(ctx)
+	if err != nil {
+		return OrderBook{}, fmt.Errorf("failed to get the order book of %s: %w", symbol, err)
+	}
+
+	bids, err := toPriceLevels(depth.Bids)
+	if err != nil {
+		return OrderBook{}, fmt.Errorf("failed to parse bids for symbol %s: %w", symbol, err)
+	}
+	asks, err := toPriceLevels(depth.Asks)
+	if err != nil {
+		return OrderBook{}, fmt.Errorf("failed to parse asks for symbol %s: %w", symbol, err)
+	}
+
+	return OrderBook{
+		Symbol: symbol,
+		Bids:   bids,
+		Asks:   asks,
+	}, nil
+}
+
+func (b *BinanceBroker) PlaceOrder(ctx context.Context, order OrderRequest) (OrderResult, error) {
+	service := b.client.NewCreateOrderService().
+		Symbol(order.Symbol).
+		Side(toBinanceSide(order.Side)).
+		NewOrderRespType(binance.NewOrderRespTypeFULL)
+
+	switch order.Type {
+	case Market:
+		service = service.Type(binance.OrderTypeMarket)
+		if order.QuoteQuantity > 0 {
+			service = service.QuoteOrderQty(formatFloat(order.QuoteQuantity))
+		} else {
+			service = service.Quantity(formatFloat(order.Quantity))
+		}
+	case Limit:
+		service = service.Type(binance.OrderTypeLimit).
+			TimeInForce(binance.TimeInForceTypeIOC).
+			Quantity(formatFloat(order.Quantity)).
+			Price(formatFloat(order.Price))
+	default:
+		return OrderResult{}, fmt.Errorf("unsupported order type %s", order.Type)
+	}
+
+	res, err := service.Do(ctx)
+	if err != nil {
+		return OrderResult{}, fmt.Errorf("failed to place %s order for %s: %w", order.Side, order.Symbol, err)
+	}
+
+	executedQty, err := strconv.ParseFloat(res.ExecutedQuantity, 64)
+	if err != nil {
+		return OrderResult{}, fmt.Errorf("failed to parse executed quantity: %w", err)
+	}
+	quoteQty, err := strconv.ParseFloat(res.CummulativeQuoteQuantity, 64)
+	if err != nil {
+		return OrderResult{}, fmt.Errorf("failed to parse quote quantity: %w", err)
+	}
+
+	fills := make([]Fill, len(res.Fills))
+	for i, f := range res.Fills {
+		fill, err := toFill(f)
+		if err != nil {
+			return OrderResult{}, fmt.Errorf("failed to parse fill: %w", err)
+		}
+		fills[i] = fill
+	}
+
+	return OrderResult{
+		OrderID:       strconv.FormatInt(res.OrderID, 10),
+		Symbol:        res.Symbol,
+		Side:          order.Side,
+		Status:        string(res.Status),
+		ExecutedQty:   executedQty,
+		QuoteQuantity: quoteQty,
+		Fills:         fills,
+	}, nil
+}
+
+// CancelOpenOrders cancels every order of the symbol still open on the
+// exchange and returns how many were cancelled.
+func (b *BinanceBroker) CancelOpenOrders(ctx context.Context, symbol string) (int, error) {
+	orders, err := b.client.NewListOpenOrdersService().Symbol(symbol).Do(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if len(orders) == 0 {
+		return 0, nil
+	}
+	if _, err := b.client.NewCancelOpenOrdersService().Symbol(symbol).Do(ctx); err != nil {
+		return 0, err
+	}
+	return len(orders), nil
+}
+
+// GetSymbolFilters returns the lot size, price and notional filters of the
+// symbol, loaded from the exchange info at most once per symbolFiltersTTL.
+func (b *BinanceBroker) GetSymbolFilters(ctx context.Context, symbol string) (SymbolFilters, error) {
+	b.mu.Lock()
+	cached, ok := b.filters[symbol]
+	b.mu.Unlock()
+	if ok && time.Now().Before(cached.expiresAt) {
+		return cached.filters, nil
+	}
+
+	info, err := b.client.NewExchangeInfoService().Symbol(symbol).Do(ctx)
+	if err != nil {
+		return SymbolFilters{}, fmt.Errorf("failed to load exchange info for %s: %w", symbol, err)
+	}
+	if len(info.Symbols) == 0 {
+		return SymbolFilters{}, fmt.Errorf("no exchange info found for symbol %s", symbol)
+	}
+
+	filters, err := toSymbolFilters(info.Symbols[0])
+	if err != nil {
+		return SymbolFilters{}, fmt.Errorf("failed to parse filters for symbol %s: %w", symbol, err)
+	}
+
+	b.mu.Lock()
+	b.filters[symbol] = cachedFilters{filters: filters, expiresAt: time.Now().Add(symbolFiltersTTL)}
+	b.mu.Unlock()
+	return filters, nil
+}
+
+func toBinanceSide(side OrderSide) binance.SideType {
+	if side == Sell {
+		return binance.SideTypeSell
+	}
+	return binance.SideTypeBuy
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+func toFill(f *binance.Fill) (Fill, error) {
+	price, err := strconv.ParseFloat(f.Price, 64)
+	if err != nil {
+		return Fill{}, err
+	}
+	quantity, err := strconv.ParseFloat(f.Quantity, 64)
+	if err != nil {
+		return Fill{}, err
+	}
+	commission, err := strconv.ParseFloat(f.Commission, 64)
+	if err != nil {
+		return Fill{}, err
+	}
+	return Fill{
+		TradeID:         strconv.FormatInt(f.TradeID, 10),
+		Price:           price,
+		Quantity:        quantity,
+		Commission:      commission,
+		CommissionAsset: f.CommissionAsset,
+	}, nil
+}
+
+func toCandle(k *binance.Kline) (Candle, error) {
+	values := make([]float64, 5)
+	for i, s := range []string{k.Open, k.High, k.Low, k.Close, k.Volume} {
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return Candle{}, err
+		}
+		values[i] = v
+	}
+
+	return Candle{
+		OpenTime:  time.UnixMilli(k.OpenTime).UTC(),
+		CloseTime: time.UnixMilli(k.CloseTime).UTC(),
+		Open:      values[0],
+		High:      values[1],
+		Low:       values[2],
+		Close:     values[3],
+		Volume:    values[4],
+	}, nil
+}
+
+func toPriceLevels(levels []common.PriceLevel) ([]PriceLevel, error) {
+	result := make([]PriceLevel, len(levels))
+	for i, l := range levels {
+		price, quantity, err := l.Parse()
+		if err != nil {
+			return nil, err
+		}
+		result[i] = PriceLevel{
+			Price:    price,
+			Quantity: quantity,
+		}
+	}
+	return result, nil
+}
+
+func toSymbolFilters(s binance.Symbol) (SymbolFilters, error) {
+	filters := SymbolFilters{Symbol: s.Symbol}
+	var err error
+	if f := s.PriceFilter(); f != nil {
+		if filters.TickSize, err = parseDecimal(f.TickSize); err != nil {
+			return SymbolFilters{}, err
+		}
+	}
+	if f := s.LotSizeFilter(); f != nil {
+		if filters.StepSize, err = parseDecimal(f.StepSize); err != nil {
+			return SymbolFilters{}, err
+		}
+		if filters.MinQty, err = parseDecimal(f.MinQuantity); err != nil {
+			return SymbolFilters{}, err
+		}
+		if filters.MaxQty, err = parseDecimal(f.MaxQuantity); err != nil {
+			return SymbolFilters{}, err
+		}
+	}
+	if f := s.NotionalFilter(); f != nil {
+		if filters.MinNotional, err = parseDecimal(f.MinNotional); err != nil {
+			return SymbolFilters{}, err
+		}
+	}
+	// Older symbols still publish MIN_NOTIONAL instead of NOTIONAL.
+	for _, f := range s.Filters {
+		if f["filterType"] == string(binance.SymbolFilterTypeMinNotional) {
+			value, _ := f["minNotional"].(string)
+			if filters.MinNotional, err = parseDecimal(value); err != nil {
+				return SymbolFilters{}, err
+			}
+		}
+	}
+	return filters, nil
+}
+
+func parseDecimal(s string) (decimal.Decimal, error) {
+	if s == "" {
+		return decimal.Zero, nil
+	}
+	return decimal.NewFromString(s)
+}
@@ -2,54 +2,30 @@ package broker
 
 import (
 	"context"
-	"fmt"
 	"go-trade-bot/internal/configuration"
-	"strconv"
-
-	"github.com/adshao/go-binance/v2"
+	"go-trade-bot/internal/ratelimit"
+	"time"
 )
 
-type Broker struct {
-	client *binance.Client
-}
-
-func NewBroker(cfg *configuration.Configuration) Broker {
-	client := binance.NewClient(cfg.Broker.ApiKey, cfg.Broker.ApiSecret)
-	return Broker{
-		client: client,
-	}
-}
-
-func (b Broker) ListTickerPrices(ctx context.Context, symbol string) ([]*binance.SymbolPrice, error) {
-	prices, err := b.client.NewListPricesService().Symbol(symbol).Do(ctx)
-	if err != nil {
-		fmt.Println(err)
-		return nil, err
-	}
-	return prices, nil
-}
-
-func (b Broker) ListKline(ctx context.Context, symbol string, interval string, limit int) ([]*binance.Kline, error) {
-	klines, err := b.client.NewKlinesService().Symbol(symbol).Interval(interval).Limit(limit).Do(ctx)
-	if err != nil {
-		fmt.Println(err)
-		return nil, err
-	}
-	return klines, nil
+// Broker is the exchange-neutral contract used by the application. Each
+// venue provides an adapter that converts its own payloads into the domain
+// types declared in this package.
+type Broker interface {
+	ListTickerPrices(ctx context.Context, symbol string) ([]Ticker, error)
+	ListKline(ctx context.Context, symbol string, interval string, limit int) ([]Candle, error)
+	ListKlineRange(ctx context.Context, symbol string, interval string, start time.Time, limit int) ([]Candle, error)
+	Get24hVolume(ctx context.Context, symbol string) (float64, error)
+	GetOrderBook(ctx context.Context, symbol string, limit int) (OrderBook, error)
+	PlaceOrder(ctx context.Context, order OrderRequest) (OrderResult, error)
+	GetSymbolFilters(ctx context.Context, symbol string) (SymbolFilters, error)
+	CancelOpenOrders(ctx context.Context, symbol string) (int, error)
 }
 
-func (b Broker) Get24hVolume(ctx context.Context, symbol string) (float64, error) {
-	klines, err := b.client.NewKlinesService().Symbol(symbol).Interval("1d").Limit(1).Do(ctx)
-	if err != nil {
-		fmt.Println(err)
-		return 0, err
-	}
-	if len(klines) == 0 {
-		return 0, fmt.Errorf("no klines found for symbol %s", symbol)
-	}
-	volume, err := strconv.ParseFloat(klines[0].Volume, 64)
-	if err != nil {
-		return 0, err
+// NewBroker returns the broker selected by BROKER.MODE, the paper exchange
+// is shared so every consumer sees the same simulated wallet.
+func NewBroker(cfg *configuration.Configuration, paper *PaperBroker, limiters ratelimit.Limiters) Broker {
+	if cfg.Broker.Mode == configuration.PaperMode {
+		return paper
 	}
-	return volume, nil
+	return NewBinanceBroker(cfg, limiters)
 }
@@ -0,0 +1,53 @@
+package broker
+
+import (
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// SymbolFilters are the trading rules the exchange enforces on the orders of
+// a symbol. A zero value leaves the matching rule out, so symbols without
+// filters accept any order.
+type SymbolFilters struct {
+	Symbol      string
+	TickSize    decimal.Decimal
+	StepSize    decimal.Decimal
+	MinQty      decimal.Decimal
+	MaxQty      decimal.Decimal
+	MinNotional decimal.Decimal
+}
+
+// RoundPrice rounds the price down to a multiple of the tick size.
+func (f SymbolFilters) RoundPrice(price decimal.Decimal) decimal.Decimal {
+	return roundDown(price, f.TickSize)
+}
+
+// RoundQuantity rounds the quantity down to a multiple of the step size and
+// caps it at the maximum quantity, so orders never spend more than planned.
+func (f SymbolFilters) RoundQuantity(quantity decimal.Decimal) decimal.Decimal {
+	if f.MaxQty.IsPositive() && quantity.GreaterThan(f.MaxQty) {
+		quantity = f.MaxQty
+	}
+	return roundDown(quantity, f.StepSize)
+}
+
+// Check refuses orders below the minimum quantity or notional of the symbol,
+// the exchange would reject them anyway.
+func (f SymbolFilters) Check(quantity decimal.Decimal, price decimal.Decimal) error {
+	if quantity.LessThan(f.MinQty) {
+		return fmt.Errorf("order of %s %s is below the minimum quantity of %s", quantity, f.Symbol, f.MinQty)
+	}
+	notional := quantity.Mul(price)
+	if notional.LessThan(f.MinNotional) {
+		return fmt.Errorf("order of %s %s worth %s is below the minimum notional of %s", quantity, f.Symbol, notional.StringFixed(2), f.MinNotional)
+	}
+	return nil
+}
+
+func roundDown(value decimal.Decimal, step decimal.Decimal) decimal.Decimal {
+	if !step.IsPositive() {
+		return value
+	}
+	return value.Div(step).Floor().Mul(step)
+}
@@ -0,0 +1,44 @@
+package broker_test
+
+import (
+	"go-trade-bot/internal/broker"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSymbolFilters(t *testing.T) {
+	filters := broker.SymbolFilters{
+		Symbol:      "BTCUSDT",
+		TickSize:    decimal.RequireFromString("0.01"),
+		StepSize:    decimal.RequireFromString("0.00001"),
+		MinQty:      decimal.RequireFromString("0.00001"),
+		MaxQty:      decimal.RequireFromString("9000"),
+		MinNotional: decimal.RequireFromString("5"),
+	}
+
+	t.Run("should round prices down to the tick size", func(t *testing.T) {
+		assert.Equal(t, "50123.45", filters.RoundPrice(decimal.RequireFromString("50123.4567")).String())
+	})
+
+	t.Run("should round quantities down to the step size", func(t *testing.T) {
+		assert.Equal(t, "0.01995", filters.RoundQuantity(decimal.RequireFromString("0.019959")).String())
+	})
+
+	t.Run("should cap quantities at the maximum quantity", func(t *testing.T) {
+		assert.Equal(t, "9000", filters.RoundQuantity(decimal.NewFromInt(12000)).String())
+	})
+
+	t.Run("should refuse orders below the minimum notional", func(t *testing.T) {
+		err := filters.Check(decimal.RequireFromString("0.00009"), decimal.NewFromInt(50000))
+		assert.EqualError(t, err, "order of 0.00009 BTCUSDT worth 4.50 is below the minimum notional of 5")
+	})
+
+	t.Run("should accept orders without filters", func(t *testing.T) {
+		none := broker.SymbolFilters{Symbol: "BTCUSDT"}
+		quantity := decimal.RequireFromString("0.0123456789")
+		assert.True(t, quantity.Equal(none.RoundQuantity(quantity)))
+		assert.NoError(t, none.Check(quantity, decimal.NewFromInt(1)))
+	})
+}
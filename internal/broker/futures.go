@@ -0,0 +1,312 @@
+package broker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-trade-bot/internal/configuration"
+	"go-trade-bot/internal/ratelimit"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/adshao/go-binance/v2/common"
+	"github.com/adshao/go-binance/v2/futures"
+)
+
+type MarginType string
+
+const (
+	IsolatedMargin MarginType = "isolated"
+	CrossMargin    MarginType = "cross"
+)
+
+// Position is the futures position held on a symbol. Quantity is negative
+// for shorts and zero when the symbol is flat.
+type Position struct {
+	Symbol           string
+	Quantity         float64
+	EntryPrice       float64
+	LiquidationPrice float64
+	Leverage         int
+	MarginType       MarginType
+}
+
+type FundingRate struct {
+	Symbol string
+	Rate   float64
+	Time   time.Time
+}
+
+// FuturesBroker trades USD-M perpetual futures. Positions are kept in
+// one-way mode, so the orders of a symbol all net into a single position.
+type FuturesBroker interface {
+	PlaceOrder(ctx context.Context, order OrderRequest) (OrderResult, error)
+	GetSymbolFilters(ctx context.Context, symbol string) (SymbolFilters, error)
+	SetLeverage(ctx context.Context, symbol string, leverage int) error
+	SetMarginType(ctx context.Context, symbol string, marginType MarginType) error
+	GetPosition(ctx context.Context, symbol string) (Position, error)
+	CancelOpenOrders(ctx context.Context, symbol string) (int, error)
+	ListFundingRates(ctx context.Context, symbol string, start time.Time) ([]FundingRate, error)
+	FundingFees(ctx context.Context, symbol string, start time.Time) (float64, error)
+}
+
+// NewFuturesBroker returns the futures exchange of BROKER.MODE. The paper
+// exchange has no futures, it returns nil and futures signals are then
+// simulated.
+func NewFuturesBroker(cfg *configuration.Configuration, limiters ratelimit.Limiters) FuturesBroker {
+	if cfg.Broker.Mode == configuration.PaperMode {
+		return nil
+	}
+	return NewBinanceFuturesBroker(cfg, limiters)
+}
+
+// errNoMarginTypeChange is returned by Binance when the symbol already has
+// the margin type asked for.
+const errNoMarginTypeChange = -4046
+
+type BinanceFuturesBroker struct {
+	client *futures.Client
+
+	mu      sync.Mutex
+	filters map[string]cachedFilters
+}
+
+// NewBinanceFuturesBroker returns a broker whose calls are limited by the
+// futures request weight budget.
+func NewBinanceFuturesBroker(cfg *configuration.Configuration, limiters ratelimit.Limiters) *BinanceFuturesBroker {
+	client := futures.NewClient(cfg.Broker.ApiKey, cfg.Broker.ApiSecret)
+	client.HTTPClient = ratelimit.NewClient(limiters.Futures, futuresWeight, cfg.RateLimit.MaxRetries)
+	return &BinanceFuturesBroker{
+		client:  client,
+		filters: map[string]cachedFilters{},
+	}
+}
+
+// PlaceOrder sends a market or immediate-or-cancel limit order. Futures are
+// always sized in the base asset, QuoteQuantity is not supported.
+func (b *BinanceFuturesBroker) PlaceOrder(ctx context.Context, order OrderRequest) (OrderResult, error) {
+	if order.Quantity <= 0 {
+		return OrderResult{}, fmt.Errorf("futures order for %s needs a quantity", order.Symbol)
+	}
+
+	service := b.client.NewCreateOrderService().
+		Symbol(order.Symbol).
+		Side(futures.SideType(toBinanceSide(order.Side))).
+		Quantity(formatFloat(order.Quantity)).
+		ReduceOnly(order.ReduceOnly).
+		NewOrderResponseType(futures.NewOrderRespTypeRESULT)
+
+	switch order.Type {
+	case Market:
+		service = service.Type(futures.OrderTypeMarket)
+	case Limit:
+		service = service.Type(futures.OrderTypeLimit).
+			TimeInForce(futures.TimeInForceTypeIOC).
+			Price(formatFloat(order.Price))
+	default:
+		return OrderResult{}, fmt.Errorf("unsupported order type %s", order.Type)
+	}
+
+	res, err := service.Do(ctx)
+	if err != nil {
+		return OrderResult{}, err
+	}
+
+	executedQty, err := strconv.ParseFloat(res.ExecutedQuantity, 64)
+	if err != nil {
+		return OrderResult{}, fmt.Errorf("failed to parse executed quantity: %w", err)
+	}
+	quoteQty, err := strconv.ParseFloat(res.CumQuote, 64)
+	if err != nil {
+		return OrderResult{}, fmt.Errorf("failed to parse quote quantity: %w", err)
+	}
+
+	return OrderResult{
+		OrderID:       strconv.FormatInt(res.OrderID, 10),
+		Symbol:        res.Symbol,
+		Side:          order.Side,
+		Status:        string(res.Status),
+		ExecutedQty:   executedQty,
+		QuoteQuantity: quoteQty,
+	}, nil
+}
+
+// GetSymbolFilters returns the trading rules of the futures symbol. The
+// exchange info lists every symbol, they are all cached at once.
+func (b *BinanceFuturesBroker) GetSymbolFilters(ctx context.Context, symbol string) (SymbolFilters, error) {
+	b.mu.Lock()
+	cached, ok := b.filters[symbol]
+	b.mu.Unlock()
+	if ok && time.Now().Before(cached.expiresAt) {
+		return cached.filters, nil
+	}
+
+	info, err := b.client.NewExchangeInfoService().Do(ctx)
+	if err != nil {
+		return SymbolFilters{}, fmt.Errorf("failed to load futures exchange info: %w", err)
+	}
+
+	expiresAt := time.Now().Add(symbolFiltersTTL)
+	loaded := make(map[string]cachedFilters, len(info.Symbols))
+	for _, s := range info.Symbols {
+		filters, err := toFuturesFilters(s)
+		if err != nil {
+			return SymbolFilters{}, fmt.Errorf("failed to parse filters for symbol %s: %w", s.Symbol, err)
+		}
+		loaded[s.Symbol] = cachedFilters{filters: filters, expiresAt: expiresAt}
+	}
+
+	b.mu.Lock()
+	b.filters = loaded
+	b.mu.Unlock()
+
+	cached, ok = loaded[symbol]
+	if !ok {
+		return SymbolFilters{}, fmt.Errorf("no futures exchange info found for symbol %s", symbol)
+	}
+	return cached.filters, nil
+}
+
+func (b *BinanceFuturesBroker) SetLeverage(ctx context.Context, symbol string, leverage int) error {
+	_, err := b.client.NewChangeLeverageService().Symbol(symbol).Leverage(leverage).Do(ctx)
+	return err
+}
+
+// SetMarginType switches the margin of the symbol, setting the margin type
+// it already has is not an error.
+func (b *BinanceFuturesBroker) SetMarginType(ctx context.Context, symbol string, marginType MarginType) error {
+	binanceType := futures.MarginTypeIsolated
+	if marginType == CrossMargin {
+		binanceType = futures.MarginTypeCrossed
+	}
+	err := b.client.NewChangeMarginTypeService().Symbol(symbol).MarginType(binanceType).Do(ctx)
+	var apiErr *common.APIError
+	if errors.As(err, &apiErr) && apiErr.Code == errNoMarginTypeChange {
+		return nil
+	}
+	return err
+}
+
+func (b *BinanceFuturesBroker) GetPosition(ctx context.Context, symbol string) (Position, error) {
+	positions, err := b.client.NewGetPositionRiskService().Symbol(symbol).Do(ctx)
+	if err != nil {
+		return Position{}, err
+	}
+	if len(positions) == 0 {
+		return Position{Symbol: symbol}, nil
+	}
+
+	p := positions[0]
+	values := make([]float64, 4)
+	for i, s := range []string{p.PositionAmt, p.EntryPrice, p.LiquidationPrice, p.Leverage} {
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return Position{}, fmt.Errorf("failed to parse position of %s: %w", symbol, err)
+		}
+		values[i] = v
+	}
+	marginType := CrossMargin
+	if p.MarginType == "isolated" {
+		marginType = IsolatedMargin
+	}
+
+	return Position{
+		Symbol:           p.Symbol,
+		Quantity:         values[0],
+		EntryPrice:       values[1],
+		LiquidationPrice: values[2],
+		Leverage:         int(values[3]),
+		MarginType:       marginType,
+	}, nil
+}
+
+// CancelOpenOrders cancels every futures order of the symbol still open on
+// the exchange and returns how many were cancelled.
+func (b *BinanceFuturesBroker) CancelOpenOrders(ctx context.Context, symbol string) (int, error) {
+	orders, err := b.client.NewListOpenOrdersService().Symbol(symbol).Do(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if len(orders) == 0 {
+		return 0, nil
+	}
+	if err := b.client.NewCancelAllOpenOrdersService().Symbol(symbol).Do(ctx); err != nil {
+		return 0, err
+	}
+	return len(orders), nil
+}
+
+// ListFundingRates returns the funding rates of the symbol settled since
+// start, oldest first.
+func (b *BinanceFuturesBroker) ListFundingRates(ctx context.Context, symbol string, start time.Time) ([]FundingRate, error) {
+	rates, err := b.client.NewFundingRateService().Symbol(symbol).StartTime(start.UnixMilli()).Limit(1000).Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]FundingRate, len(rates))
+	for i, r := range rates {
+		rate, err := strconv.ParseFloat(r.FundingRate, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse funding rate of %s: %w", symbol, err)
+		}
+		result[i] = FundingRate{
+			Symbol: r.Symbol,
+			Rate:   rate,
+			Time:   time.UnixMilli(r.FundingTime).UTC(),
+		}
+	}
+	return result, nil
+}
+
+// FundingFees returns the funding paid on the symbol since start, negative
+// when it was received.
+func (b *BinanceFuturesBroker) FundingFees(ctx context.Context, symbol string, start time.Time) (float64, error) {
+	incomes, err := b.client.NewGetIncomeHistoryService().
+		Symbol(symbol).
+		IncomeType("FUNDING_FEE").
+		StartTime(start.UnixMilli()).
+		Limit(1000).
+		Do(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	paid := 0.0
+	for _, i := range incomes {
+		income, err := strconv.ParseFloat(i.Income, 64)
+		if err != nil {
+			return 0, fmt.Errorf("failed to parse funding fee of %s: %w", symbol, err)
+		}
+		paid -= income
+	}
+	return paid, nil
+}
+
+func toFuturesFilters(s futures.Symbol) (SymbolFilters, error) {
+	filters := SymbolFilters{Symbol: s.Symbol}
+	var err error
+	if f := s.PriceFilter(); f != nil {
+		if filters.TickSize, err = parseDecimal(f.TickSize); err != nil {
+			return SymbolFilters{}, err
+		}
+	}
+	if f := s.LotSizeFilter(); f != nil {
+		if filters.StepSize, err = parseDecimal(f.StepSize); err != nil {
+			return SymbolFilters{}, err
+		}
+		if filters.MinQty, err = parseDecimal(f.MinQuantity); err != nil {
+			return SymbolFilters{}, err
+		}
+		if filters.MaxQty, err = parseDecimal(f.MaxQuantity); err != nil {
+			return SymbolFilters{}, err
+		}
+	}
+	if f := s.MinNotionalFilter(); f != nil {
+		if filters.MinNotional, err = parseDecimal(f.Notional); err != nil {
+			return SymbolFilters{}, err
+		}
+	}
+	return filters, nil
+}
@@ -0,0 +1,43 @@
+package broker
+
+import "time"
+
+var intervals = map[string]time.Duration{
+	"1m":  time.Minute,
+	"3m":  3 * time.Minute,
+	"5m":  5 * time.Minute,
+	"15m": 15 * time.Minute,
+	"30m": 30 * time.Minute,
+	"1h":  time.Hour,
+	"2h":  2 * time.Hour,
+	"4h":  4 * time.Hour,
+	"6h":  6 * time.Hour,
+	"8h":  8 * time.Hour,
+	"12h": 12 * time.Hour,
+	"1d":  24 * time.Hour,
+	"3d":  3 * 24 * time.Hour,
+	"1w":  7 * 24 * time.Hour,
+}
+
+// IntervalDuration returns the length of a kline interval of the exchange.
+// Monthly klines have no fixed length and are not supported.
+func IntervalDuration(interval string) (time.Duration, bool) {
+	d, ok := intervals[interval]
+	return d, ok
+}
+
+// IntervalStart returns the open time of the candle of the interval that
+// holds t. Candles are aligned to the Unix epoch, except weekly ones that
+// open on Mondays.
+func IntervalStart(t time.Time, interval string) time.Time {
+	step, ok := intervals[interval]
+	if !ok {
+		return t
+	}
+	if interval == "1w" {
+		// The zero time is a Monday.
+		return t.Truncate(step)
+	}
+	ms := step.Milliseconds()
+	return time.UnixMilli(t.UnixMilli() / ms * ms).In(t.Location())
+}
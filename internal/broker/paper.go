@@ -0,0 +1,381 @@
+package broker
+
+import (
+	"context"
+	"fmt"
+	"go-trade-bot/internal/configuration"
+	"go-trade-bot/internal/ratelimit"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	paperBookInterval = "1m"
+	defaultBookDepth  = 10
+)
+
+var quoteAssets = []string{"USDT", "FDUSD", "USDC", "BUSD", "BTC", "ETH", "BNB"}
+
+// MarketData is where the paper exchange reads prices from. It can be a
+// real exchange (public endpoints only) or a replay of recorded candles.
+type MarketData interface {
+	ListKline(ctx context.Context, symbol string, interval string, limit int) ([]Candle, error)
+	ListKlineRange(ctx context.Context, symbol string, interval string, start time.Time, limit int) ([]Candle, error)
+}
+
+// FilterSource provides the trading rules of the symbols.
+type FilterSource interface {
+	GetSymbolFilters(ctx context.Context, symbol string) (SymbolFilters, error)
+}
+
+// PaperBook is where the paper exchange reads its wallet from before each
+// order, so the wallet follows the accounts and the open signals across
+// restarts and between the processes sharing them.
+type PaperBook interface {
+	PaperWallet(ctx context.Context) (PaperWallet, error)
+}
+
+// PaperWallet is what the books hold: the free amount of the accounts by
+// currency, the amount reserved by entries not filled yet and the quantity
+// of open positions, both by symbol.
+type PaperWallet struct {
+	Cash      map[string]float64
+	Reserved  map[string]float64
+	Positions map[string]float64
+}
+
+type PaperConfig struct {
+	QuoteAsset     string
+	InitialBalance float64
+	SlippagePct    float64
+	BookDepth      int
+}
+
+// PaperBroker is a local exchange simulator. It builds an order book for each
+// symbol out of the latest candle of its market data, fills market and
+// immediate-or-cancel limit orders against it and keeps the balances of the
+// simulated wallet in memory. With a book the wallet is reloaded from it
+// before each order, without one it starts from the initial balance.
+type PaperBroker struct {
+	feed    MarketData
+	config  PaperConfig
+	filters FilterSource
+	book    PaperBook
+
+	mu       sync.Mutex
+	balances map[string]float64
+	orderID  int64
+	tradeID  int64
+}
+
+// NewPaperBroker borrows the symbol filters of Binance so paper orders are
+// sized like live ones, replays of recorded klines run without filters. The
+// wallet follows the accounts and signals of the book.
+func NewPaperBroker(cfg *configuration.Configuration, limiters ratelimit.Limiters, book PaperBook) *PaperBroker {
+	binance := NewBinanceBroker(cfg, limiters)
+	var feed MarketData = binance
+	var filters FilterSource = binance
+	if cfg.Paper.RecordedKlines != "" {
+		replay, err := LoadReplayFeed(cfg.Paper.RecordedKlines, 1)
+		if err != nil {
+			panic("Failed to load recorded klines: " + err.Error())
+		}
+		feed = replay
+		filters = nil
+	}
+
+	paper := NewPaperBrokerWithFeed(feed, PaperConfig{
+		QuoteAsset:  cfg.Paper.QuoteAsset,
+		SlippagePct: cfg.Paper.SlippagePct,
+		BookDepth:   cfg.Paper.BookDepth,
+	})
+	paper.filters = filters
+	paper.UseBook(book)
+	return paper
+}
+
+func NewPaperBrokerWithFeed(feed MarketData, config PaperConfig) *PaperBroker {
+	if config.QuoteAsset == "" {
+		config.QuoteAsset = "USDT"
+	}
+	if config.BookDepth <= 0 {
+		config.BookDepth = defaultBookDepth
+	}
+
+	return &PaperBroker{
+		feed:   feed,
+		config: config,
+		balances: map[string]float64{
+			config.QuoteAsset: config.InitialBalance,
+		},
+	}
+}
+
+// UseBook reloads the wallet from the book before each order.
+func (b *PaperBroker) UseBook(book PaperBook) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.book = book
+}
+
+func (b *PaperBroker) ListTickerPrices(ctx context.Context, symbol string) ([]Ticker, error) {
+	candle, err := b.lastCandle(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+	return []Ticker{{Symbol: symbol, Price: candle.Close}}, nil
+}
+
+func (b *PaperBroker) ListKline(ctx context.Context, symbol string, interval string, limit int) ([]Candle, error) {
+	return b.feed.ListKline(ctx, symbol, interval, limit)
+}
+
+func (b *PaperBroker) ListKlineRange(ctx context.Context, symbol string, interval string, start time.Time, limit int) ([]Candle, error) {
+	return b.feed.ListKlineRange(ctx, symbol, interval, start, limit)
+}
+
+func (b *PaperBroker) Get24hVolume(ctx context.Context, symbol string) (float64, error) {
+	candles, err := b.feed.ListKline(ctx, symbol, "1d", 1)
+	if err != nil {
+		return 0, err
+	}
+	if len(candles) == 0 {
+		return 0, fmt.Errorf("no klines found for symbol %s", symbol)
+	}
+	return candles[0].Volume, nil
+}
+
+// GetOrderBook returns a synthetic book around the last close. Every level
+// moves the price by SlippagePct and offers a slice of the candle volume, so
+// bigger orders walk further into the book.
+func (b *PaperBroker) GetOrderBook(ctx context.Context, symbol string, limit int) (OrderBook, error) {
+	candle, err := b.lastCandle(ctx, symbol)
+	if err != nil {
+		return OrderBook{}, err
+	}
+
+	depth := b.config.BookDepth
+	if limit > 0 && limit < depth {
+		depth = limit
+	}
+
+	quantity := candle.Volume / float64(2*depth)
+	if quantity <= 0 {
+		quantity = math.MaxFloat64 / float64(2*depth)
+	}
+
+	book := OrderBook{
+		Symbol: symbol,
+		Bids:   make([]PriceLevel, depth),
+		Asks:   make([]PriceLevel, depth),
+	}
+	for i := 0; i < depth; i++ {
+		offset := b.config.SlippagePct * float64(i) / 100
+		book.Asks[i] = PriceLevel{Price: candle.Close * (1 + offset), Quantity: quantity}
+		book.Bids[i] = PriceLevel{Price: candle.Close * (1 - offset), Quantity: quantity}
+	}
+	return book, nil
+}
+
+func (b *PaperBroker) PlaceOrder(ctx context.Context, order OrderRequest) (OrderResult, error) {
+	if order.Type != Market && order.Type != Limit {
+		return OrderResult{}, fmt.Errorf("unsupported order type %s", order.Type)
+	}
+	if order.Type == Limit && order.Price <= 0 {
+		return OrderResult{}, fmt.Errorf("limit order for %s needs a price", order.Symbol)
+	}
+
+	book, err := b.GetOrderBook(ctx, order.Symbol, 0)
+	if err != nil {
+		return OrderResult{}, err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err := b.reload(ctx); err != nil {
+		return OrderResult{}, err
+	}
+
+	base, quote := b.splitSymbol(order.Symbol)
+
+	levels := book.Asks
+	if order.Side == Sell {
+		levels = book.Bids
+	}
+
+	if order.Side == Sell && order.Quantity > b.balances[base] {
+		return OrderResult{}, fmt.Errorf("insufficient %s balance to sell %f", base, order.Quantity)
+	}
+
+	// The quote budget of a buy includes the fee charged on top of it. The
+	// accounts of a book already reserved the order and pay its fees when
+	// the signal closes.
+	budget := math.MaxFloat64
+	if order.Side == Buy {
+		budget = b.balances[quote]
+		if b.book == nil {
+			budget /= 1 + order.FeeRate
+		}
+		if order.QuoteQuantity > 0 {
+			if order.QuoteQuantity > budget {
+				return OrderResult{}, fmt.Errorf("insufficient %s balance to spend %f", quote, order.QuoteQuantity)
+			}
+			budget = order.QuoteQuantity
+		}
+	}
+
+	remaining := order.Quantity
+	if order.Side == Buy && order.QuoteQuantity > 0 {
+		remaining = math.MaxFloat64
+	}
+
+	result := OrderResult{
+		Symbol: order.Symbol,
+		Side:   order.Side,
+	}
+	for _, level := range levels {
+		if remaining <= 0 || budget <= 0 {
+			break
+		}
+		if order.Type == Limit && !crosses(order.Side, level.Price, order.Price) {
+			break
+		}
+
+		quantity := math.Min(remaining, level.Quantity)
+		quantity = math.Min(quantity, budget/level.Price)
+		if quantity <= 0 {
+			break
+		}
+
+		amount := quantity * level.Price
+		fee := amount * order.FeeRate
+		b.tradeID++
+		result.Fills = append(result.Fills, Fill{
+			TradeID:         strconv.FormatInt(b.tradeID, 10),
+			Price:           level.Price,
+			Quantity:        quantity,
+			Commission:      fee,
+			CommissionAsset: quote,
+		})
+		result.ExecutedQty += quantity
+		result.QuoteQuantity += amount
+		remaining -= quantity
+		if order.Side == Buy {
+			budget -= amount
+		}
+	}
+
+	var fees float64
+	for _, f := range result.Fills {
+		fees += f.Commission
+	}
+	if order.Side == Buy {
+		b.balances[quote] -= result.QuoteQuantity + fees
+		b.balances[base] += result.ExecutedQty
+	} else {
+		b.balances[base] -= result.ExecutedQty
+		b.balances[quote] += result.QuoteQuantity - fees
+	}
+
+	b.orderID++
+	result.OrderID = "paper-" + strconv.FormatInt(b.orderID, 10)
+	result.Status = orderStatus(order, result)
+	return result, nil
+}
+
+// CancelOpenOrders has nothing to cancel, paper orders are filled or
+// dropped when they are placed.
+func (b *PaperBroker) CancelOpenOrders(ctx context.Context, symbol string) (int, error) {
+	return 0, nil
+}
+
+// GetSymbolFilters returns the filters of the filter source, symbols have no
+// filters without one.
+func (b *PaperBroker) GetSymbolFilters(ctx context.Context, symbol string) (SymbolFilters, error) {
+	if b.filters == nil {
+		return SymbolFilters{Symbol: symbol}, nil
+	}
+	return b.filters.GetSymbolFilters(ctx, symbol)
+}
+
+// Balances returns a copy of the simulated wallet.
+func (b *PaperBroker) Balances() map[string]float64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	balances := make(map[string]float64, len(b.balances))
+	for asset, amount := range b.balances {
+		balances[asset] = amount
+	}
+	return balances
+}
+
+// reload replaces the wallet with the one of the book: the free cash of the
+// accounts, the reservations of pending entries in their quote asset and
+// the open positions in their base asset.
+func (b *PaperBroker) reload(ctx context.Context) error {
+	if b.book == nil {
+		return nil
+	}
+	wallet, err := b.book.PaperWallet(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load the paper wallet: %w", err)
+	}
+
+	balances := make(map[string]float64, len(wallet.Cash)+len(wallet.Positions))
+	for asset, amount := range wallet.Cash {
+		balances[asset] += amount
+	}
+	for symbol, amount := range wallet.Reserved {
+		_, quote := b.splitSymbol(symbol)
+		balances[quote] += amount
+	}
+	for symbol, quantity := range wallet.Positions {
+		base, _ := b.splitSymbol(symbol)
+		balances[base] += quantity
+	}
+	b.balances = balances
+	return nil
+}
+
+func (b *PaperBroker) lastCandle(ctx context.Context, symbol string) (Candle, error) {
+	candles, err := b.feed.ListKline(ctx, symbol, paperBookInterval, 1)
+	if err != nil {
+		return Candle{}, err
+	}
+	if len(candles) == 0 {
+		return Candle{}, fmt.Errorf("no market data for symbol %s", symbol)
+	}
+	return candles[len(candles)-1], nil
+}
+
+func (b *PaperBroker) splitSymbol(symbol string) (string, string) {
+	for _, quote := range quoteAssets {
+		if strings.HasSuffix(symbol, quote) && len(symbol) > len(quote) {
+			return strings.TrimSuffix(symbol, quote), quote
+		}
+	}
+	return strings.TrimSuffix(symbol, b.config.QuoteAsset), b.config.QuoteAsset
+}
+
+func crosses(side OrderSide, levelPrice float64, limitPrice float64) bool {
+	if side == Buy {
+		return levelPrice <= limitPrice
+	}
+	return levelPrice >= limitPrice
+}
+
+func orderStatus(order OrderRequest, result OrderResult) string {
+	switch {
+	case result.ExecutedQty == 0:
+		return "EXPIRED"
+	case order.Quantity > 0 && result.ExecutedQty < order.Quantity:
+		return "PARTIALLY_FILLED"
+	default:
+		return "FILLED"
+	}
+}
@@ -0,0 +1,152 @@
+package broker_test
+
+import (
+	"context"
+	"go-trade-bot/internal/broker"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fixedClock struct {
+	now time.Time
+}
+
+func (c fixedClock) Now() time.Time {
+	return c.now
+}
+
+type book struct {
+	wallet broker.PaperWallet
+}
+
+func (b book) PaperWallet(ctx context.Context) (broker.PaperWallet, error) {
+	return b.wallet, nil
+}
+
+func newPaperBroker(balance float64) *broker.PaperBroker {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	feed := broker.NewReplayFeed([]broker.RecordedSeries{
+		{
+			Symbol:   "BTCUSDT",
+			Interval: "1m",
+			Candles: []broker.Candle{
+				{OpenTime: start, CloseTime: start.Add(time.Minute), Close: 100, Volume: 20},
+				{OpenTime: start.Add(time.Minute), CloseTime: start.Add(2 * time.Minute), Close: 200, Volume: 20},
+			},
+		},
+	}, fixedClock{now: start.Add(90 * time.Second)})
+
+	return broker.NewPaperBrokerWithFeed(feed, broker.PaperConfig{
+		QuoteAsset:     "USDT",
+		InitialBalance: balance,
+		SlippagePct:    1,
+		BookDepth:      2,
+	})
+}
+
+func TestPaperBroker_ListTickerPrices(t *testing.T) {
+	b := newPaperBroker(1000)
+
+	prices, err := b.ListTickerPrices(context.Background(), "BTCUSDT")
+	require.NoError(t, err)
+	assert.Equal(t, 100.0, prices[0].Price)
+}
+
+func TestPaperBroker_PlaceOrder(t *testing.T) {
+	t.Run("market buy walks the book and charges the fee rate", func(t *testing.T) {
+		b := newPaperBroker(1000)
+
+		result, err := b.PlaceOrder(context.Background(), broker.OrderRequest{
+			Symbol:   "BTCUSDT",
+			Side:     broker.Buy,
+			Type:     broker.Market,
+			Quantity: 6,
+			FeeRate:  0.001,
+		})
+		require.NoError(t, err)
+
+		require.Len(t, result.Fills, 2)
+		assert.Equal(t, 100.0, result.Fills[0].Price)
+		assert.Equal(t, 101.0, result.Fills[1].Price)
+		assert.InDelta(t, 6, result.ExecutedQty, 1e-9)
+		assert.InDelta(t, 601, result.QuoteQuantity, 1e-9)
+		assert.Equal(t, "FILLED", result.Status)
+
+		balances := b.Balances()
+		assert.InDelta(t, 1000-601-0.601, balances["USDT"], 1e-9)
+		assert.InDelta(t, 6, balances["BTC"], 1e-9)
+	})
+
+	t.Run("limit buy below the book is not filled", func(t *testing.T) {
+		b := newPaperBroker(1000)
+
+		result, err := b.PlaceOrder(context.Background(), broker.OrderRequest{
+			Symbol:   "BTCUSDT",
+			Side:     broker.Buy,
+			Type:     broker.Limit,
+			Quantity: 1,
+			Price:    99,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 0.0, result.ExecutedQty)
+		assert.Equal(t, "EXPIRED", result.Status)
+		assert.Equal(t, 1000.0, b.Balances()["USDT"])
+	})
+
+	t.Run("rejects buys above the balance", func(t *testing.T) {
+		b := newPaperBroker(50)
+
+		_, err := b.PlaceOrder(context.Background(), broker.OrderRequest{
+			Symbol:        "BTCUSDT",
+			Side:          broker.Buy,
+			Type:          broker.Market,
+			QuoteQuantity: 100,
+		})
+		assert.Error(t, err)
+	})
+
+	t.Run("rejects sells without holdings", func(t *testing.T) {
+		b := newPaperBroker(1000)
+
+		_, err := b.PlaceOrder(context.Background(), broker.OrderRequest{
+			Symbol:   "BTCUSDT",
+			Side:     broker.Sell,
+			Type:     broker.Market,
+			Quantity: 1,
+		})
+		assert.Error(t, err)
+	})
+
+	t.Run("reloads the wallet from the book", func(t *testing.T) {
+		b := newPaperBroker(0)
+		b.UseBook(book{wallet: broker.PaperWallet{
+			Cash:      map[string]float64{"USDT": 20},
+			Reserved:  map[string]float64{"BTCUSDT": 80},
+			Positions: map[string]float64{"BTCUSDT": 0.5},
+		}})
+
+		// The reservation is spent whole, the account pays the fee on close.
+		bought, err := b.PlaceOrder(context.Background(), broker.OrderRequest{
+			Symbol:        "BTCUSDT",
+			Side:          broker.Buy,
+			Type:          broker.Market,
+			QuoteQuantity: 100,
+			FeeRate:       0.001,
+		})
+		require.NoError(t, err)
+		assert.InDelta(t, 100, bought.QuoteQuantity, 1e-9)
+
+		// A restart kept the positions of the book.
+		sold, err := b.PlaceOrder(context.Background(), broker.OrderRequest{
+			Symbol:   "BTCUSDT",
+			Side:     broker.Sell,
+			Type:     broker.Market,
+			Quantity: 0.5,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 0.5, sold.ExecutedQty)
+	})
+}
@@ -0,0 +1,157 @@
+package broker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+type Clock interface {
+	Now() time.Time
+}
+
+type RecordedSeries struct {
+	Symbol   string
+	Interval string
+	Candles  []Candle
+}
+
+// ReplayFeed serves recorded candles as if they were live: only candles
+// closed before the current time of its clock are visible.
+type ReplayFeed struct {
+	series map[string][]Candle
+	clock  Clock
+}
+
+func NewReplayFeed(series []RecordedSeries, clock Clock) *ReplayFeed {
+	feed := &ReplayFeed{
+		series: make(map[string][]Candle, len(series)),
+		clock:  clock,
+	}
+	for _, s := range series {
+		candles := append([]Candle(nil), s.Candles...)
+		sort.Slice(candles, func(i, j int) bool {
+			return candles[i].OpenTime.Before(candles[j].OpenTime)
+		})
+		feed.series[seriesKey(s.Symbol, s.Interval)] = candles
+	}
+	return feed
+}
+
+// LoadReplayFeed reads a JSON file with a list of RecordedSeries and replays
+// it from the first recorded close, speed times faster than the wall clock.
+func LoadReplayFeed(path string, speed float64) (*ReplayFeed, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var series []RecordedSeries
+	if err := json.Unmarshal(data, &series); err != nil {
+		return nil, fmt.Errorf("invalid recorded klines file %s: %w", path, err)
+	}
+
+	var start time.Time
+	for _, s := range series {
+		for _, c := range s.Candles {
+			if start.IsZero() || c.CloseTime.Before(start) {
+				start = c.CloseTime
+			}
+		}
+	}
+
+	if speed <= 0 {
+		speed = 1
+	}
+	return NewReplayFeed(series, &replayClock{start: start, began: time.Now(), speed: speed}), nil
+}
+
+func (f *ReplayFeed) ListKline(ctx context.Context, symbol string, interval string, limit int) ([]Candle, error) {
+	candles, ok := f.series[seriesKey(symbol, interval)]
+	if !ok {
+		return nil, fmt.Errorf("no recorded klines for %s %s", symbol, interval)
+	}
+
+	now := f.clock.Now()
+	end := sort.Search(len(candles), func(i int) bool {
+		return candles[i].CloseTime.After(now)
+	})
+
+	start := end - limit
+	if limit <= 0 || start < 0 {
+		start = 0
+	}
+	return append([]Candle(nil), candles[start:end]...), nil
+}
+
+// ListKlineRange returns the visible candles opened at or after start.
+func (f *ReplayFeed) ListKlineRange(ctx context.Context, symbol string, interval string, start time.Time, limit int) ([]Candle, error) {
+	visible, err := f.ListKline(ctx, symbol, interval, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	first := sort.Search(len(visible), func(i int) bool {
+		return !visible[i].OpenTime.Before(start)
+	})
+	end := len(visible)
+	if limit > 0 && first+limit < end {
+		end = first + limit
+	}
+	return visible[first:end], nil
+}
+
+// ListTickerPrices returns the close of the most recent candle closed for
+// the symbol across all recorded intervals.
+func (f *ReplayFeed) ListTickerPrices(ctx context.Context, symbol string) ([]Ticker, error) {
+	now := f.clock.Now()
+	var last *Candle
+	for key, candles := range f.series {
+		if !strings.HasPrefix(key, symbol+"|") {
+			continue
+		}
+		end := sort.Search(len(candles), func(i int) bool {
+			return candles[i].CloseTime.After(now)
+		})
+		if end == 0 {
+			continue
+		}
+		if last == nil || candles[end-1].CloseTime.After(last.CloseTime) {
+			last = &candles[end-1]
+		}
+	}
+	if last == nil {
+		return nil, fmt.Errorf("no recorded prices for %s", symbol)
+	}
+	return []Ticker{{Symbol: symbol, Price: last.Close}}, nil
+}
+
+func (f *ReplayFeed) Get24hVolume(ctx context.Context, symbol string) (float64, error) {
+	candles, err := f.ListKline(ctx, symbol, "1d", 1)
+	if err != nil {
+		return 0, err
+	}
+	if len(candles) == 0 {
+		return 0, fmt.Errorf("no klines found for symbol %s", symbol)
+	}
+	return candles[0].Volume, nil
+}
+
+func seriesKey(symbol string, interval string) string {
+	return symbol + "|" + interval
+}
+
+type replayClock struct {
+	start time.Time
+	began time.Time
+	speed float64
+}
+
+func (c *replayClock) Now() time.Time {
+	elapsed := time.Duration(float64(time.Since(c.began)) * c.speed)
+	return c.start.Add(elapsed)
+}
@@ -0,0 +1,148 @@
+package broker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/adshao/go-binance/v2"
+)
+
+// KlineStream is a kline interval of a symbol to stream.
+type KlineStream struct {
+	Symbol   string
+	Interval string
+}
+
+// KlineEvent is an update of the candle of a stream, Closed once the candle
+// is final.
+type KlineEvent struct {
+	Symbol   string
+	Interval string
+	Candle   Candle
+	Closed   bool
+}
+
+// StreamHandlers receive the events of a stream. Connected is called once
+// every connection is open, before any event.
+type StreamHandlers struct {
+	Connected func()
+	Kline     func(KlineEvent)
+	Ticker    func(Ticker)
+}
+
+// BinanceStream serves the public kline and ticker WebSocket streams of
+// Binance, no credentials are needed.
+type BinanceStream struct{}
+
+func NewBinanceStream() BinanceStream {
+	return BinanceStream{}
+}
+
+// Serve streams the klines and tickers until ctx is done, which returns nil,
+// or until a connection fails. Binance takes one interval per symbol on a
+// combined kline stream, so each interval gets its own connection.
+func (s BinanceStream) Serve(ctx context.Context, klines []KlineStream, tickers []string, h StreamHandlers) error {
+	byInterval := map[string]map[string]string{}
+	for _, k := range klines {
+		if byInterval[k.Interval] == nil {
+			byInterval[k.Interval] = map[string]string{}
+		}
+		byInterval[k.Interval][k.Symbol] = k.Interval
+	}
+
+	var (
+		once   sync.Once
+		failed = make(chan error, 1)
+		stops  []chan struct{}
+		dones  []chan struct{}
+	)
+	fail := func(err error) {
+		once.Do(func() { failed <- err })
+	}
+	stopAll := func() {
+		for _, stop := range stops {
+			close(stop)
+		}
+	}
+
+	for interval, symbols := range byInterval {
+		done, stop, err := binance.WsCombinedKlineServe(symbols, func(e *binance.WsKlineEvent) {
+			event, err := toKlineEvent(e)
+			if err != nil {
+				fail(fmt.Errorf("failed to parse kline of %s: %w", e.Symbol, err))
+				return
+			}
+			h.Kline(event)
+		}, fail)
+		if err != nil {
+			stopAll()
+			return fmt.Errorf("failed to open the %s kline stream: %w", interval, err)
+		}
+		stops = append(stops, stop)
+		dones = append(dones, done)
+	}
+
+	if len(tickers) > 0 {
+		done, stop, err := binance.WsCombinedMarketStatServe(tickers, func(e *binance.WsMarketStatEvent) {
+			price, err := strconv.ParseFloat(e.LastPrice, 64)
+			if err != nil {
+				fail(fmt.Errorf("failed to parse price of %s: %w", e.Symbol, err))
+				return
+			}
+			h.Ticker(Ticker{Symbol: e.Symbol, Price: price})
+		}, fail)
+		if err != nil {
+			stopAll()
+			return fmt.Errorf("failed to open the ticker stream: %w", err)
+		}
+		stops = append(stops, stop)
+		dones = append(dones, done)
+	}
+
+	for _, done := range dones {
+		go func(done chan struct{}) {
+			<-done
+			fail(errors.New("stream closed by the exchange"))
+		}(done)
+	}
+	h.Connected()
+
+	select {
+	case <-ctx.Done():
+		stopAll()
+		return nil
+	case err := <-failed:
+		stopAll()
+		return err
+	}
+}
+
+func toKlineEvent(e *binance.WsKlineEvent) (KlineEvent, error) {
+	values := make([]float64, 5)
+	for i, s := range []string{e.Kline.Open, e.Kline.High, e.Kline.Low, e.Kline.Close, e.Kline.Volume} {
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return KlineEvent{}, err
+		}
+		values[i] = v
+	}
+
+	return KlineEvent{
+		Symbol:   e.Symbol,
+		Interval: e.Kline.Interval,
+		Candle: Candle{
+			OpenTime:  time.UnixMilli(e.Kline.StartTime).UTC(),
+			CloseTime: time.UnixMilli(e.Kline.EndTime).UTC(),
+			Open:      values[0],
+			High:      values[1],
+			Low:       values[2],
+			Close:     values[3],
+			Volume:    values[4],
+		},
+		Closed: e.Kline.IsFinal,
+	}, nil
+}
@@ -0,0 +1,95 @@
+package broker
+
+import "time"
+
+type Candle struct {
+	OpenTime  time.Time
+	CloseTime time.Time
+	Open      float64
+	High      float64
+	Low       float64
+	Close     float64
+	Volume    float64
+}
+
+type Ticker struct {
+	Symbol string
+	Price  float64
+}
+
+type PriceLevel struct {
+	Price    float64
+	Quantity float64
+}
+
+type OrderBook struct {
+	Symbol string
+	Bids   []PriceLevel
+	Asks   []PriceLevel
+}
+
+type Fill struct {
+	TradeID         string
+	Price           float64
+	Quantity        float64
+	Commission      float64
+	CommissionAsset string
+}
+
+type OrderSide string
+
+const (
+	Buy  OrderSide = "buy"
+	Sell OrderSide = "sell"
+)
+
+type OrderType string
+
+const (
+	Market OrderType = "market"
+	Limit  OrderType = "limit"
+)
+
+// OrderRequest describes an order to be sent to the exchange. Market buys
+// may be sized by QuoteQuantity (amount of quote asset to spend) instead of
+// Quantity. Limit orders are immediate-or-cancel, so the result is final.
+// ReduceOnly futures orders can only shrink the position of the symbol.
+// FeeRate is the fee of the account as a fraction of the notional, only the
+// paper exchange charges it, real exchanges charge their own.
+type OrderRequest struct {
+	Symbol        string
+	Side          OrderSide
+	Type          OrderType
+	Quantity      float64
+	QuoteQuantity float64
+	Price         float64
+	ReduceOnly    bool
+	FeeRate       float64
+}
+
+type OrderResult struct {
+	OrderID       string
+	Symbol        string
+	Side          OrderSide
+	Status        string
+	ExecutedQty   float64
+	QuoteQuantity float64
+	Fills         []Fill
+}
+
+// AveragePrice returns the volume weighted fill price of the order.
+func (o OrderResult) AveragePrice() float64 {
+	if o.ExecutedQty == 0 {
+		return 0
+	}
+	return o.QuoteQuantity / o.ExecutedQty
+}
+
+// Closes returns the close prices of the given candles, oldest first.
+func Closes(candles []Candle) []float64 {
+	closes := make([]float64, len(candles))
+	for i, c := range candles {
+		closes[i] = c.Close
+	}
+	return closes
+}
@@ -0,0 +1,74 @@
+package broker
+
+import (
+	"net/http"
+	"strconv"
+)
+
+// spotWeight is the request weight Binance charges for the spot endpoints
+// the broker calls, 1 for the others.
+func spotWeight(r *http.Request) int {
+	query := r.URL.Query()
+	switch r.URL.Path {
+	case "/api/v3/ticker/price":
+		if query.Has("symbol") {
+			return 2
+		}
+		return 4
+	case "/api/v3/klines":
+		return 2
+	case "/api/v3/depth":
+		return depthWeight(query.Get("limit"))
+	case "/api/v3/openOrders":
+		if r.Method == http.MethodDelete {
+			return 1
+		}
+		if query.Has("symbol") {
+			return 6
+		}
+		return 80
+	case "/api/v3/exchangeInfo", "/api/v3/account":
+		return 20
+	default:
+		return 1
+	}
+}
+
+// futuresWeight is the request weight Binance charges for the USD-M
+// futures endpoints the broker calls, 1 for the others.
+func futuresWeight(r *http.Request) int {
+	switch r.URL.Path {
+	case "/fapi/v2/positionRisk":
+		return 5
+	case "/fapi/v1/income":
+		return 30
+	case "/fapi/v1/klines":
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		switch {
+		case limit < 100:
+			return 1
+		case limit < 500:
+			return 2
+		case limit <= 1000:
+			return 5
+		default:
+			return 10
+		}
+	default:
+		return 1
+	}
+}
+
+func depthWeight(limit string) int {
+	n, _ := strconv.Atoi(limit)
+	switch {
+	case n <= 100:
+		return 5
+	case n <= 500:
+		return 25
+	case n <= 1000:
+		return 50
+	default:
+		return 250
+	}
+}
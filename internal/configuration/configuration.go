@@ -3,22 +3,109 @@ package configuration
 import (
 	"log"
 	"os"
+	"time"
 
 	"github.com/spf13/viper"
 )
 
 type Configuration struct {
 	Broker     Broker
+	Paper      Paper
 	DB         DB
 	Redis      Redis
+	Cache      Cache
+	Fees       Fees
+	Risk       Risk
+	MarketData MarketData
+	Execution  Execution
+	RateLimit  RateLimit
 	Prometheus Prometheus
 }
 
+const (
+	BinanceMode = "binance"
+	PaperMode   = "paper"
+)
+
+const (
+	PollingExecution     = "polling"
+	CandleCloseExecution = "candle_close"
+)
+
+const (
+	MemoryCache = "memory"
+	RedisCache  = "redis"
+)
+
 type Broker struct {
+	Mode      string
 	ApiKey    string
 	ApiSecret string
 }
 
+type Paper struct {
+	QuoteAsset     string
+	SlippagePct    float64
+	BookDepth      int
+	RecordedKlines string
+}
+
+// Fees holds the fee models accounts can be charged with, by name.
+type Fees struct {
+	Models map[string]FeeModel
+}
+
+type FeeModel struct {
+	MakerPct      float64        `mapstructure:"maker_pct"`
+	TakerPct      float64        `mapstructure:"taker_pct"`
+	DiscountAsset string         `mapstructure:"discount_asset"`
+	DiscountPct   float64        `mapstructure:"discount_pct"`
+	Tiers         []FeeModelTier `mapstructure:"tiers"`
+}
+
+type FeeModelTier struct {
+	MinVolume float64 `mapstructure:"min_volume"`
+	MakerPct  float64 `mapstructure:"maker_pct"`
+	TakerPct  float64 `mapstructure:"taker_pct"`
+}
+
+// Risk holds the limits of the risk manager, zero disables a limit.
+type Risk struct {
+	MaxDailyLossPct         float64
+	MaxDrawdownPct          float64
+	MaxPositionsPerSymbol   int
+	MaxPositionsPerStrategy int
+	MaxExposurePct          float64
+	FlattenOnTrip           bool
+}
+
+// MarketData tells whether processors read klines and prices from the
+// WebSocket streams, Window is how many candles are kept per symbol and
+// interval and StaleAfter how long a streamed price is served. Reads of the
+// strategies are shared for CacheMaxAge.
+type MarketData struct {
+	Streaming   bool
+	Window      int
+	StaleAfter  time.Duration
+	CacheMaxAge time.Duration
+}
+
+// Execution tells when strategies run: every cycle after their previous run
+// when polling, or as soon as a candle of their interval closes, CloseDelay
+// after the boundary at the latest.
+type Execution struct {
+	Mode       string
+	CloseDelay time.Duration
+}
+
+// RateLimit is the request weight per minute the spot and futures APIs may
+// spend, shared by every replica, and how often failed reads are retried.
+type RateLimit struct {
+	SpotWeight    int
+	FuturesWeight int
+	MaxRetries    int
+}
+
 type Prometheus struct {
 	Address string
 }
@@ -35,22 +122,42 @@ type Redis struct {
 	Addr string
 }
 
+type Cache struct {
+	Driver    string
+	Namespace string
+}
+
 func NewConfiguration() *Configuration {
 	if err := setupViper(); err != nil {
 		log.Printf("Critical error reading configuration")
 		panic("Error reading config file")
 	}
 
+	mode := viper.GetString("BROKER.MODE")
+	if mode == "" {
+		mode = BinanceMode
+	}
+	if mode != BinanceMode && mode != PaperMode {
+		log.Fatalf("Invalid broker mode %s", mode)
+	}
+
+	// The paper exchange only reads public market data, so it runs without
+	// credentials.
 	key, ok := viper.Get("BROKER.KEY").(string)
-	if !ok {
+	if !ok && mode != PaperMode {
 		log.Fatalf("Invalid broker key")
 	}
 
 	secret, ok := viper.Get("BROKER.SECRET").(string)
-	if !ok {
+	if !ok && mode != PaperMode {
 		log.Fatalf("Invalid broker secret")
 	}
 
+	var feeModels map[string]FeeModel
+	if err := viper.UnmarshalKey("FEES.MODELS", &feeModels); err != nil {
+		log.Fatalf("Invalid fee models")
+	}
+
 	host, ok := viper.Get("DB.HOST").(string)
 	if !ok {
 		log.Fatalf("Invalid db host")
@@ -86,6 +193,61 @@ func NewConfiguration() *Configuration {
 		log.Fatalf("Invalid redis configuration")
 	}
 
+	cacheDriver := viper.GetString("CACHE.DRIVER")
+	if cacheDriver == "" {
+		cacheDriver = MemoryCache
+	}
+	if cacheDriver != MemoryCache && cacheDriver != RedisCache {
+		log.Fatalf("Invalid cache driver %s", cacheDriver)
+	}
+
+	cacheNamespace := viper.GetString("CACHE.NAMESPACE")
+	if cacheNamespace == "" {
+		cacheNamespace = "go-trade-bot"
+	}
+
+	window := viper.GetInt("MARKET_DATA.WINDOW")
+	if window <= 0 {
+		window = 500
+	}
+
+	staleAfter := viper.GetInt("MARKET_DATA.STALE_AFTER_SECONDS")
+	if staleAfter <= 0 {
+		staleAfter = 30
+	}
+
+	cacheSeconds := 5
+	if viper.IsSet("MARKET_DATA.CACHE_SECONDS") {
+		cacheSeconds = viper.GetInt("MARKET_DATA.CACHE_SECONDS")
+	}
+
+	executionMode := viper.GetString("EXECUTION.MODE")
+	if executionMode == "" {
+		executionMode = PollingExecution
+	}
+	if executionMode != PollingExecution && executionMode != CandleCloseExecution {
+		log.Fatalf("Invalid execution mode %s", executionMode)
+	}
+
+	closeDelay := 2
+	if viper.IsSet("EXECUTION.CLOSE_DELAY_SECONDS") {
+		closeDelay = viper.GetInt("EXECUTION.CLOSE_DELAY_SECONDS")
+	}
+
+	// Binance allows 6000 spot and 2400 futures weight a minute per IP.
+	spotWeight := viper.GetInt("RATE_LIMIT.SPOT_WEIGHT")
+	if spotWeight <= 0 {
+		spotWeight = 5000
+	}
+	futuresWeight := viper.GetInt("RATE_LIMIT.FUTURES_WEIGHT")
+	if futuresWeight <= 0 {
+		futuresWeight = 2000
+	}
+	maxRetries := 3
+	if viper.IsSet("RATE_LIMIT.MAX_RETRIES") {
+		maxRetries = viper.GetInt("RATE_LIMIT.MAX_RETRIES")
+	}
+
 	prometheus, ok := viper.Get("PROMETHEUS.ADDRESS").(string)
 	if !ok {
 		log.Fatalf("Invalid prometheus address")
@@ -93,9 +255,16 @@ func NewConfiguration() *Configuration {
 
 	return &Configuration{
 		Broker: Broker{
+			Mode:      mode,
 			ApiKey:    key,
 			ApiSecret: secret,
 		},
+		Paper: Paper{
+			QuoteAsset:     viper.GetString("PAPER.QUOTE_ASSET"),
+			SlippagePct:    viper.GetFloat64("PAPER.SLIPPAGE_PCT"),
+			BookDepth:      viper.GetInt("PAPER.BOOK_DEPTH"),
+			RecordedKlines: viper.GetString("PAPER.RECORDED_KLINES"),
+		},
 		DB: DB{
 			Host:     host,
 			Port:     port,
@@ -107,6 +276,36 @@ func NewConfiguration() *Configuration {
 		Redis: Redis{
 			Addr: redisAddr,
 		},
+		Cache: Cache{
+			Driver:    cacheDriver,
+			Namespace: cacheNamespace,
+		},
+		Fees: Fees{
+			Models: feeModels,
+		},
+		Risk: Risk{
+			MaxDailyLossPct:         viper.GetFloat64("RISK.MAX_DAILY_LOSS_PCT"),
+			MaxDrawdownPct:          viper.GetFloat64("RISK.MAX_DRAWDOWN_PCT"),
+			MaxPositionsPerSymbol:   viper.GetInt("RISK.MAX_POSITIONS_PER_SYMBOL"),
+			MaxPositionsPerStrategy: viper.GetInt("RISK.MAX_POSITIONS_PER_STRATEGY"),
+			MaxExposurePct:          viper.GetFloat64("RISK.MAX_EXPOSURE_PCT"),
+			FlattenOnTrip:           viper.GetBool("RISK.FLATTEN_ON_TRIP"),
+		},
+		MarketData: MarketData{
+			Streaming:   viper.GetBool("MARKET_DATA.STREAMING"),
+			Window:      window,
+			StaleAfter:  time.Duration(staleAfter) * time.Second,
+			CacheMaxAge: time.Duration(cacheSeconds) * time.Second,
+		},
+		Execution: Execution{
+			Mode:       executionMode,
+			CloseDelay: time.Duration(closeDelay) * time.Second,
+		},
+		RateLimit: RateLimit{
+			SpotWeight:    spotWeight,
+			FuturesWeight: futuresWeight,
+			MaxRetries:    maxRetries,
+		},
 		Prometheus: Prometheus{
 			Address: prometheus,
 		},
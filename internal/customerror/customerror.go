@@ -2,9 +2,16 @@ package customerror
 
 import "fmt"
 
+// FieldError points at the input field that failed validation.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
 type CustomError struct {
 	Code    int
 	Message string
+	Fields  []FieldError
 }
 
 func (e *CustomError) Error() string {
@@ -17,3 +24,11 @@ func New(code int, message string) error {
 		Message: message,
 	}
 }
+
+func NewWithFields(code int, message string, fields []FieldError) error {
+	return &CustomError{
+		Code:    code,
+		Message: message,
+		Fields:  fields,
+	}
+}
@@ -0,0 +1,37 @@
+package db
+
+import (
+	"slices"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ConvertToNumeric turns the real columns of table into numeric(32,12) on
+// Postgres. Values go through double precision and are rounded to 8
+// decimals, a plain cast would keep only 6 significant digits of the floats.
+// Columns already converted are left alone, so it runs before every
+// AutoMigrate.
+func ConvertToNumeric(db *gorm.DB, table string, columns ...string) error {
+	if db.Dialector.Name() != "postgres" || !db.Migrator().HasTable(table) {
+		return nil
+	}
+
+	types, err := db.Migrator().ColumnTypes(table)
+	if err != nil {
+		return err
+	}
+	for _, c := range types {
+		name := strings.ToLower(c.DatabaseTypeName())
+		if !slices.Contains(columns, c.Name()) || (name != "float4" && name != "real") {
+			continue
+		}
+		column := clause.Column{Name: c.Name()}
+		err := db.Exec("ALTER TABLE ? ALTER COLUMN ? TYPE numeric(32,12) USING round(?::float8::numeric, 8)", clause.Table{Name: table}, column, column).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package db_test
+
+import (
+	"context"
+	"errors"
+	"go-trade-bot/app/entities"
+	"go-trade-bot/internal/db"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func open(t *testing.T) *gorm.DB {
+	conn, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, conn.AutoMigrate(&entities.Account{}))
+	return conn
+}
+
+func TestTransactor_Transaction(t *testing.T) {
+	t.Run("should commit when the function succeeds", func(t *testing.T) {
+		conn := open(t)
+		transactor := db.NewTransactor(conn)
+
+		err := transactor.Transaction(context.Background(), func(ctx context.Context) error {
+			return db.Conn(ctx, conn).Create(&entities.Account{Name: "paper", Currency: "USDT"}).Error
+		})
+		require.NoError(t, err)
+
+		var count int64
+		conn.Model(&entities.Account{}).Count(&count)
+		assert.Equal(t, int64(1), count)
+	})
+
+	t.Run("should roll back nested calls with the outer transaction", func(t *testing.T) {
+		conn := open(t)
+		transactor := db.NewTransactor(conn)
+
+		err := transactor.Transaction(context.Background(), func(ctx context.Context) error {
+			err := transactor.Transaction(ctx, func(ctx context.Context) error {
+				return db.Conn(ctx, conn).Create(&entities.Account{Name: "paper", Currency: "USDT"}).Error
+			})
+			require.NoError(t, err)
+			return errors.New("order rejected")
+		})
+		require.EqualError(t, err, "order rejected")
+
+		var count int64
+		conn.Model(&entities.Account{}).Count(&count)
+		assert.Equal(t, int64(0), count)
+	})
+}
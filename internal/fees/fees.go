@@ -0,0 +1,120 @@
+package fees
+
+import (
+	"go-trade-bot/internal/configuration"
+	"sort"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// DefaultModel is the name of the model used by accounts without one.
+const DefaultModel = "default"
+
+// Liquidity tells whether an order added liquidity to the book (maker) or
+// took it (taker), exchanges charge them different rates.
+type Liquidity string
+
+const (
+	Maker Liquidity = "maker"
+	Taker Liquidity = "taker"
+)
+
+// Tier holds the maker and taker rates, as fractions of the notional, from a
+// traded volume on.
+type Tier struct {
+	MinVolume decimal.Decimal
+	Maker     decimal.Decimal
+	Taker     decimal.Decimal
+}
+
+// Model is the fee schedule of an exchange. Paying the fees in the discount
+// asset (e.g. BNB) takes Discount, a fraction, off every rate.
+type Model struct {
+	Name          string
+	Tiers         []Tier
+	DiscountAsset string
+	Discount      decimal.Decimal
+}
+
+// Rates are the rates charged to an account once its tier and discount are
+// applied.
+type Rates struct {
+	Maker         decimal.Decimal
+	Taker         decimal.Decimal
+	DiscountAsset string
+}
+
+// Fee returns the fee of an order of the given notional.
+func (r Rates) Fee(notional decimal.Decimal, liquidity Liquidity) decimal.Decimal {
+	if liquidity == Maker {
+		return notional.Mul(r.Maker)
+	}
+	return notional.Mul(r.Taker)
+}
+
+// Rates picks the highest tier reached by the 30 day volume.
+func (m Model) Rates(volume decimal.Decimal) Rates {
+	var tier Tier
+	for i, t := range m.Tiers {
+		if i == 0 || volume.GreaterThanOrEqual(t.MinVolume) {
+			tier = t
+		}
+	}
+	discount := decimal.NewFromInt(1).Sub(m.Discount)
+	return Rates{
+		Maker:         tier.Maker.Mul(discount),
+		Taker:         tier.Taker.Mul(discount),
+		DiscountAsset: m.DiscountAsset,
+	}
+}
+
+// Schedule holds the configured fee models by name.
+type Schedule struct {
+	models map[string]Model
+}
+
+// NewSchedule builds the models of FEES.MODELS. The default model charges
+// 0.1% on both sides unless FEES.MODELS overrides it.
+func NewSchedule(cfg *configuration.Configuration) Schedule {
+	models := map[string]Model{
+		DefaultModel: {
+			Name:  DefaultModel,
+			Tiers: []Tier{{Maker: pct(0.1), Taker: pct(0.1)}},
+		},
+	}
+	for name, m := range cfg.Fees.Models {
+		model := Model{
+			Name:          strings.ToLower(name),
+			Tiers:         []Tier{{Maker: pct(m.MakerPct), Taker: pct(m.TakerPct)}},
+			DiscountAsset: m.DiscountAsset,
+			Discount:      pct(m.DiscountPct),
+		}
+		for _, t := range m.Tiers {
+			model.Tiers = append(model.Tiers, Tier{
+				MinVolume: decimal.NewFromFloat(t.MinVolume),
+				Maker:     pct(t.MakerPct),
+				Taker:     pct(t.TakerPct),
+			})
+		}
+		sort.SliceStable(model.Tiers, func(i, j int) bool {
+			return model.Tiers[i].MinVolume.LessThan(model.Tiers[j].MinVolume)
+		})
+		models[model.Name] = model
+	}
+	return Schedule{models: models}
+}
+
+// Model returns the model of the given name, the default one for an empty
+// name. Names are case insensitive.
+func (s Schedule) Model(name string) (Model, bool) {
+	if name == "" {
+		name = DefaultModel
+	}
+	m, ok := s.models[strings.ToLower(name)]
+	return m, ok
+}
+
+func pct(value float64) decimal.Decimal {
+	return decimal.NewFromFloat(value).Div(decimal.NewFromInt(100))
+}
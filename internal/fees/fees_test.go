@@ -0,0 +1,61 @@
+package fees_test
+
+import (
+	"go-trade-bot/internal/configuration"
+	"go-trade-bot/internal/fees"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchedule(t *testing.T) {
+	schedule := fees.NewSchedule(&configuration.Configuration{
+		Fees: configuration.Fees{
+			Models: map[string]configuration.FeeModel{
+				"binance": {
+					MakerPct:      0.1,
+					TakerPct:      0.1,
+					DiscountAsset: "BNB",
+					DiscountPct:   25,
+					Tiers: []configuration.FeeModelTier{
+						{MinVolume: 1000000, MakerPct: 0.09, TakerPct: 0.1},
+					},
+				},
+			},
+		},
+	})
+	notional := decimal.NewFromInt(1000)
+
+	t.Run("should charge 0.1% with the default model", func(t *testing.T) {
+		model, ok := schedule.Model("")
+		require.True(t, ok)
+
+		rates := model.Rates(decimal.Zero)
+		assert.Equal(t, "1", rates.Fee(notional, fees.Maker).String())
+		assert.Equal(t, "1", rates.Fee(notional, fees.Taker).String())
+	})
+
+	t.Run("should apply the discount of the model", func(t *testing.T) {
+		model, ok := schedule.Model("Binance")
+		require.True(t, ok)
+
+		rates := model.Rates(decimal.NewFromInt(5000))
+		assert.Equal(t, "0.75", rates.Fee(notional, fees.Taker).String())
+		assert.Equal(t, "BNB", rates.DiscountAsset)
+	})
+
+	t.Run("should pick the highest tier reached by the volume", func(t *testing.T) {
+		model, _ := schedule.Model("binance")
+
+		rates := model.Rates(decimal.NewFromInt(2000000))
+		assert.Equal(t, "0.675", rates.Fee(notional, fees.Maker).String())
+		assert.Equal(t, "0.75", rates.Fee(notional, fees.Taker).String())
+	})
+
+	t.Run("should not find unknown models", func(t *testing.T) {
+		_, ok := schedule.Model("kraken")
+		assert.False(t, ok)
+	})
+}
@@ -0,0 +1,35 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"go-trade-bot/internal/customerror"
+	"net/http"
+)
+
+type errorResponse struct {
+	Message string                   `json:"message"`
+	Fields  []customerror.FieldError `json:"fields"`
+}
+
+// WriteError answers with the status of a CustomError, listing its fields
+// as JSON when it has any. Any other error is an internal server error.
+func WriteError(w http.ResponseWriter, err error) {
+	var customErr *customerror.CustomError
+	if !errors.As(err, &customErr) {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if len(customErr.Fields) == 0 {
+		http.Error(w, customErr.Message, customErr.Code)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(customErr.Code)
+	json.NewEncoder(w).Encode(errorResponse{
+		Message: customErr.Message,
+		Fields:  customErr.Fields,
+	})
+}
@@ -0,0 +1,61 @@
+package killswitch
+
+import (
+	"context"
+	"go-trade-bot/internal/configuration"
+	"go-trade-bot/internal/memcache"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const key = "killswitch"
+
+// State is the kill switch as stored in Redis. CloseSignals asks for the
+// open signals to be sold at market on top of pausing the strategies.
+type State struct {
+	Engaged      bool
+	Reason       string
+	CloseSignals bool
+	EngagedAt    time.Time
+}
+
+// Switch keeps the kill switch in Redis whatever CACHE.DRIVER is, so the
+// API, the console and every worker see the same flag.
+type Switch struct {
+	cache memcache.Cache
+}
+
+func NewSwitch(cfg *configuration.Configuration) Switch {
+	client := redis.NewClient(&redis.Options{Addr: cfg.Redis.Addr})
+	return New(memcache.NewRedisCache(client, cfg.Cache.Namespace))
+}
+
+// New keeps the flag in the given cache.
+func New(cache memcache.Cache) Switch {
+	return Switch{cache: cache}
+}
+
+// Engage pauses every strategy until the switch is released.
+func (s Switch) Engage(ctx context.Context, reason string, closeSignals bool) (State, error) {
+	state := State{
+		Engaged:      true,
+		Reason:       reason,
+		CloseSignals: closeSignals,
+		EngagedAt:    time.Now(),
+	}
+	return state, s.cache.Set(ctx, key, state, 0)
+}
+
+func (s Switch) Release(ctx context.Context) error {
+	return s.cache.Delete(ctx, key)
+}
+
+// State returns the switch, released when no flag is stored.
+func (s Switch) State(ctx context.Context) (State, error) {
+	var state State
+	if _, err := s.cache.Get(ctx, key, &state); err != nil {
+		return State{}, err
+	}
+	return state, nil
+}
@@ -0,0 +1,34 @@
+package killswitch_test
+
+import (
+	"context"
+	"go-trade-bot/internal/killswitch"
+	"go-trade-bot/internal/memcache"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSwitch(t *testing.T) {
+	ctx := context.Background()
+	s := killswitch.New(memcache.NewInMemoryCache())
+
+	state, err := s.State(ctx)
+	require.NoError(t, err)
+	assert.False(t, state.Engaged)
+
+	_, err = s.Engage(ctx, "exchange outage", true)
+	require.NoError(t, err)
+
+	state, err = s.State(ctx)
+	require.NoError(t, err)
+	assert.True(t, state.Engaged)
+	assert.True(t, state.CloseSignals)
+	assert.Equal(t, "exchange outage", state.Reason)
+
+	require.NoError(t, s.Release(ctx))
+	state, err = s.State(ctx)
+	require.NoError(t, err)
+	assert.False(t, state.Engaged)
+}
@@ -0,0 +1,108 @@
+package marketdata
+
+import (
+	"context"
+	"fmt"
+	"go-trade-bot/internal/broker"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+type cachedKlines struct {
+	candles   []broker.Candle
+	period    time.Time
+	fetchedAt time.Time
+}
+
+// Cache shares the klines and prices read by the strategies of a worker.
+// Concurrent reads of a series wait for a single upstream call, and what it
+// returned is served for MaxAge as long as no candle closed in between.
+type Cache struct {
+	broker.Broker
+	MaxAge time.Duration
+	Now    func() time.Time
+
+	group  singleflight.Group
+	mu     sync.Mutex
+	klines map[string]cachedKlines
+	prices map[string]price
+}
+
+func NewCache(upstream broker.Broker, maxAge time.Duration) *Cache {
+	return &Cache{
+		Broker: upstream,
+		MaxAge: maxAge,
+		Now:    time.Now,
+		klines: map[string]cachedKlines{},
+		prices: map[string]price{},
+	}
+}
+
+// ListKline serves the last limit candles of the series from the read of a
+// longer or equal window made in the current candle.
+func (c *Cache) ListKline(ctx context.Context, symbol string, interval string, limit int) ([]broker.Candle, error) {
+	key := symbol + ":" + interval
+	period := broker.IntervalStart(c.Now(), interval)
+
+	c.mu.Lock()
+	cached, ok := c.klines[key]
+	c.mu.Unlock()
+	if ok && cached.period.Equal(period) && c.fresh(cached.fetchedAt) && len(cached.candles) >= limit {
+		return last(cached.candles, limit), nil
+	}
+
+	flight := fmt.Sprintf("%s:%d:%d", key, period.Unix(), limit)
+	result, err, _ := c.group.Do(flight, func() (any, error) {
+		candles, err := c.Broker.ListKline(ctx, symbol, interval, limit)
+		if err != nil {
+			return nil, err
+		}
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		if current, ok := c.klines[key]; !ok || !current.period.Equal(period) || len(candles) >= len(current.candles) {
+			c.klines[key] = cachedKlines{candles: candles, period: period, fetchedAt: c.Now()}
+		}
+		return candles, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return last(result.([]broker.Candle), limit), nil
+}
+
+// ListTickerPrices serves the price of the symbol read in the last MaxAge.
+func (c *Cache) ListTickerPrices(ctx context.Context, symbol string) ([]broker.Ticker, error) {
+	if symbol == "" {
+		return c.Broker.ListTickerPrices(ctx, symbol)
+	}
+
+	c.mu.Lock()
+	cached, ok := c.prices[symbol]
+	c.mu.Unlock()
+	if ok && c.fresh(cached.updatedAt) {
+		return []broker.Ticker{{Symbol: symbol, Price: cached.value}}, nil
+	}
+
+	result, err, _ := c.group.Do("price:"+symbol, func() (any, error) {
+		tickers, err := c.Broker.ListTickerPrices(ctx, symbol)
+		if err != nil {
+			return nil, err
+		}
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		for _, t := range tickers {
+			c.prices[t.Symbol] = price{value: t.Price, updatedAt: c.Now()}
+		}
+		return tickers, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return append([]broker.Ticker(nil), result.([]broker.Ticker)...), nil
+}
+
+func (c *Cache) fresh(at time.Time) bool {
+	return c.Now().Sub(at) <= c.MaxAge
+}
@@ -0,0 +1,103 @@
+package marketdata_test
+
+import (
+	"context"
+	"go-trade-bot/internal/broker"
+	"go-trade-bot/internal/marketdata"
+	"go-trade-bot/internal/marketdata/mocks"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCache_ListKline(t *testing.T) {
+	t.Run("should share one upstream call between concurrent reads", func(t *testing.T) {
+		release := make(chan struct{})
+		upstream := new(mocks.Broker)
+		upstream.On("ListKline", mock.Anything, "BTCUSDT", "5m", 3).Run(func(mock.Arguments) { <-release }).Return(candles(7, 10), nil).Once()
+		cache := newCache(upstream, time.Minute)
+
+		var wg sync.WaitGroup
+		for range 5 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				result, err := cache.ListKline(context.Background(), "BTCUSDT", "5m", 3)
+				assert.NoError(t, err)
+				assert.Equal(t, candles(7, 10), result)
+			}()
+		}
+		close(release)
+		wg.Wait()
+		upstream.AssertExpectations(t)
+	})
+
+	t.Run("should serve shorter windows from the cached one", func(t *testing.T) {
+		upstream := new(mocks.Broker)
+		upstream.On("ListKline", mock.Anything, "BTCUSDT", "5m", 5).Return(candles(5, 10), nil).Once()
+		upstream.On("ListKline", mock.Anything, "BTCUSDT", "5m", 8).Return(candles(2, 10), nil).Once()
+		cache := newCache(upstream, time.Minute)
+
+		_, err := cache.ListKline(context.Background(), "BTCUSDT", "5m", 5)
+		assert.NoError(t, err)
+		result, err := cache.ListKline(context.Background(), "BTCUSDT", "5m", 2)
+		assert.NoError(t, err)
+		assert.Equal(t, candles(8, 10), result)
+
+		result, err = cache.ListKline(context.Background(), "BTCUSDT", "5m", 8)
+		assert.NoError(t, err)
+		assert.Equal(t, candles(2, 10), result)
+		upstream.AssertExpectations(t)
+	})
+
+	t.Run("should read again once a candle closed", func(t *testing.T) {
+		upstream := new(mocks.Broker)
+		upstream.On("ListKline", mock.Anything, "BTCUSDT", "5m", 3).Return(candles(7, 10), nil).Once()
+		upstream.On("ListKline", mock.Anything, "BTCUSDT", "5m", 3).Return(candles(8, 11), nil).Once()
+		cache := newCache(upstream, time.Minute)
+
+		_, err := cache.ListKline(context.Background(), "BTCUSDT", "5m", 3)
+		assert.NoError(t, err)
+		cache.Now = func() time.Time { return start.Add(50 * time.Minute) }
+		result, err := cache.ListKline(context.Background(), "BTCUSDT", "5m", 3)
+		assert.NoError(t, err)
+		assert.Equal(t, candles(8, 11), result)
+		upstream.AssertExpectations(t)
+	})
+
+	t.Run("should read again after the max age", func(t *testing.T) {
+		upstream := new(mocks.Broker)
+		upstream.On("ListKline", mock.Anything, "BTCUSDT", "5m", 3).Return(candles(7, 10), nil).Twice()
+		cache := newCache(upstream, time.Second)
+
+		_, err := cache.ListKline(context.Background(), "BTCUSDT", "5m", 3)
+		assert.NoError(t, err)
+		cache.Now = func() time.Time { return start.Add(47*time.Minute + 2*time.Second) }
+		_, err = cache.ListKline(context.Background(), "BTCUSDT", "5m", 3)
+		assert.NoError(t, err)
+		upstream.AssertExpectations(t)
+	})
+}
+
+func TestCache_ListTickerPrices(t *testing.T) {
+	upstream := new(mocks.Broker)
+	upstream.On("ListTickerPrices", mock.Anything, "BTCUSDT").Return([]broker.Ticker{{Symbol: "BTCUSDT", Price: 101}}, nil).Once()
+	cache := newCache(upstream, time.Minute)
+
+	for range 2 {
+		result, err := cache.ListTickerPrices(context.Background(), "BTCUSDT")
+		assert.NoError(t, err)
+		assert.Equal(t, []broker.Ticker{{Symbol: "BTCUSDT", Price: 101}}, result)
+	}
+	upstream.AssertExpectations(t)
+}
+
+// newCache returns a cache while candle 9 is open.
+func newCache(upstream *mocks.Broker, maxAge time.Duration) *marketdata.Cache {
+	cache := marketdata.NewCache(upstream, maxAge)
+	cache.Now = func() time.Time { return start.Add(47 * time.Minute) }
+	return cache
+}
@@ -0,0 +1,32 @@
+package marketdata
+
+import (
+	"context"
+	"go-trade-bot/internal/broker"
+	"time"
+)
+
+// Closed is the upstream broker without the candle still open, so
+// strategies run on candle close only evaluate final candles.
+type Closed struct {
+	broker.Broker
+	Now func() time.Time
+}
+
+func NewClosed(upstream broker.Broker) Closed {
+	return Closed{
+		Broker: upstream,
+		Now:    time.Now,
+	}
+}
+
+func (c Closed) ListKline(ctx context.Context, symbol string, interval string, limit int) ([]broker.Candle, error) {
+	candles, err := c.Broker.ListKline(ctx, symbol, interval, limit+1)
+	if err != nil {
+		return nil, err
+	}
+	if n := len(candles); n > 0 && !candles[n-1].CloseTime.Before(c.Now()) {
+		candles = candles[:n-1]
+	}
+	return last(candles, limit), nil
+}
@@ -0,0 +1,38 @@
+package marketdata_test
+
+import (
+	"context"
+	"go-trade-bot/internal/marketdata"
+	"go-trade-bot/internal/marketdata/mocks"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestClosed_ListKline(t *testing.T) {
+	t.Run("should drop the candle still open", func(t *testing.T) {
+		upstream := new(mocks.Broker)
+		upstream.On("ListKline", mock.Anything, "BTCUSDT", "5m", 4).Return(candles(6, 10), nil).Once()
+		closed := marketdata.NewClosed(upstream)
+		closed.Now = func() time.Time { return start.Add(47 * time.Minute) }
+
+		result, err := closed.ListKline(context.Background(), "BTCUSDT", "5m", 3)
+
+		assert.NoError(t, err)
+		assert.Equal(t, candles(6, 9), result)
+	})
+
+	t.Run("should keep the last candle once closed", func(t *testing.T) {
+		upstream := new(mocks.Broker)
+		upstream.On("ListKline", mock.Anything, "BTCUSDT", "5m", 4).Return(candles(6, 10), nil).Once()
+		closed := marketdata.NewClosed(upstream)
+		closed.Now = func() time.Time { return start.Add(50 * time.Minute) }
+
+		result, err := closed.ListKline(context.Background(), "BTCUSDT", "5m", 3)
+
+		assert.NoError(t, err)
+		assert.Equal(t, candles(7, 10), result)
+	})
+}
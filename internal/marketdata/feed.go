@@ -0,0 +1,327 @@
+package marketdata
+
+import (
+	"context"
+	"go-trade-bot/internal/broker"
+	"go-trade-bot/internal/configuration"
+	"log"
+	"sync"
+	"time"
+)
+
+// Stream serves the kline and ticker streams of the exchange until ctx is
+// done or a connection fails.
+type Stream interface {
+	Serve(ctx context.Context, klines []broker.KlineStream, tickers []string, h broker.StreamHandlers) error
+}
+
+// series is the rolling window of candles of a symbol and interval. Final
+// tells whether the last candle received its close event.
+type series struct {
+	candles []broker.Candle
+	size    int
+	live    bool
+	final   bool
+}
+
+type price struct {
+	value     float64
+	updatedAt time.Time
+}
+
+// Feed is the upstream broker with klines and ticker prices served from the
+// WebSocket streams. Reads subscribe to the symbol, a window is only served
+// once it was fetched from upstream while the stream was connected, until a
+// disconnect or a gap in the events asks for a new fetch. Everything else is
+// read from upstream.
+type Feed struct {
+	broker.Broker
+	stream Stream
+	config configuration.MarketData
+
+	// RetryDelay is the wait before reconnecting a failed stream and
+	// SettleDelay the wait before resubscribing, so the symbols read in a
+	// burst share a connection.
+	RetryDelay  time.Duration
+	SettleDelay time.Duration
+	Now         func() time.Time
+
+	mu         sync.Mutex
+	series     map[broker.KlineStream]*series
+	tickers    map[string]bool
+	prices     map[string]price
+	connected  bool
+	generation int
+	changed    chan struct{}
+	onClose    []func(symbol string, interval string, candle broker.Candle)
+}
+
+func NewFeed(cfg *configuration.Configuration, upstream broker.Broker) *Feed {
+	return New(upstream, broker.NewBinanceStream(), cfg.MarketData)
+}
+
+// New serves upstream from the stream, everything is read from upstream
+// when streaming is off.
+func New(upstream broker.Broker, stream Stream, config configuration.MarketData) *Feed {
+	return &Feed{
+		Broker:      upstream,
+		stream:      stream,
+		config:      config,
+		RetryDelay:  5 * time.Second,
+		SettleDelay: 5 * time.Second,
+		Now:         time.Now,
+		series:      map[broker.KlineStream]*series{},
+		tickers:     map[string]bool{},
+		prices:      map[string]price{},
+		changed:     make(chan struct{}, 1),
+	}
+}
+
+// Subscribe streams the price of the symbol and its klines of the given
+// intervals ahead of the first read.
+func (f *Feed) Subscribe(symbol string, intervals ...string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.subscribeTicker(symbol)
+	for _, interval := range intervals {
+		f.subscribeKlines(broker.KlineStream{Symbol: symbol, Interval: interval})
+	}
+}
+
+// OnCandleClose calls fn with every candle the stream closes, outside of
+// the stream so fn may block.
+func (f *Feed) OnCandleClose(fn func(symbol string, interval string, candle broker.Candle)) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.onClose = append(f.onClose, fn)
+}
+
+// ListKline returns the last limit candles of the window, fetching them from
+// upstream when the window is not live or too short.
+func (f *Feed) ListKline(ctx context.Context, symbol string, interval string, limit int) ([]broker.Candle, error) {
+	if !f.config.Streaming {
+		return f.Broker.ListKline(ctx, symbol, interval, limit)
+	}
+
+	f.mu.Lock()
+	s := f.subscribeKlines(broker.KlineStream{Symbol: symbol, Interval: interval})
+	s.size = max(s.size, limit, f.config.Window)
+	if s.live && len(s.candles) >= limit {
+		candles := last(s.candles, limit)
+		f.mu.Unlock()
+		return candles, nil
+	}
+	size, connected, generation := s.size, f.connected, f.generation
+	f.mu.Unlock()
+
+	fetched, err := f.Broker.ListKline(ctx, symbol, interval, size)
+	if err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	s.candles = last(fetched, s.size)
+	s.final = len(fetched) > 0 && fetched[len(fetched)-1].CloseTime.Before(f.Now())
+	// Events received while disconnected may be missing from the window.
+	s.live = connected && generation == f.generation
+	return last(fetched, limit), nil
+}
+
+// ListTickerPrices returns the streamed price of the symbol while it is
+// fresh, all the prices are read from upstream.
+func (f *Feed) ListTickerPrices(ctx context.Context, symbol string) ([]broker.Ticker, error) {
+	if !f.config.Streaming || symbol == "" {
+		return f.Broker.ListTickerPrices(ctx, symbol)
+	}
+
+	f.mu.Lock()
+	f.subscribeTicker(symbol)
+	p, ok := f.prices[symbol]
+	if ok && f.connected && f.Now().Sub(p.updatedAt) <= f.config.StaleAfter {
+		f.mu.Unlock()
+		return []broker.Ticker{{Symbol: symbol, Price: p.value}}, nil
+	}
+	f.mu.Unlock()
+
+	tickers, err := f.Broker.ListTickerPrices(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, t := range tickers {
+		f.prices[t.Symbol] = price{value: t.Price, updatedAt: f.Now()}
+	}
+	return tickers, nil
+}
+
+// Run keeps the stream of the subscribed symbols connected until ctx is
+// done, reconnecting after failures and whenever a symbol is subscribed.
+func (f *Feed) Run(ctx context.Context) {
+	for ctx.Err() == nil {
+		// Changes made up to now are part of the subscriptions.
+		select {
+		case <-f.changed:
+		default:
+		}
+
+		klines, tickers := f.subscriptions()
+		if len(klines) == 0 && len(tickers) == 0 {
+			select {
+			case <-f.changed:
+				continue
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		streamCtx, cancel := context.WithCancel(ctx)
+		watched := make(chan struct{})
+		go func() {
+			f.resubscribeOnChange(streamCtx, cancel)
+			close(watched)
+		}()
+
+		err := f.stream.Serve(streamCtx, klines, tickers, broker.StreamHandlers{
+			Connected: f.connect,
+			Kline:     f.onKline,
+			Ticker:    f.onTicker,
+		})
+		cancel()
+		<-watched
+		f.disconnect()
+
+		if err != nil && ctx.Err() == nil {
+			log.Printf("Market data stream failed, reconnecting in %s: %v", f.RetryDelay, err)
+			select {
+			case <-time.After(f.RetryDelay):
+			case <-ctx.Done():
+			}
+		}
+	}
+}
+
+func (f *Feed) resubscribeOnChange(ctx context.Context, cancel context.CancelFunc) {
+	select {
+	case <-f.changed:
+	case <-ctx.Done():
+		return
+	}
+	select {
+	case <-time.After(f.SettleDelay):
+	case <-ctx.Done():
+	}
+	cancel()
+}
+
+func (f *Feed) subscriptions() ([]broker.KlineStream, []string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	klines := make([]broker.KlineStream, 0, len(f.series))
+	for key := range f.series {
+		klines = append(klines, key)
+	}
+	tickers := make([]string, 0, len(f.tickers))
+	for symbol := range f.tickers {
+		tickers = append(tickers, symbol)
+	}
+	return klines, tickers
+}
+
+func (f *Feed) subscribeKlines(key broker.KlineStream) *series {
+	s, ok := f.series[key]
+	if !ok {
+		s = &series{size: f.config.Window}
+		f.series[key] = s
+		f.notify()
+	}
+	return s
+}
+
+func (f *Feed) subscribeTicker(symbol string) {
+	if !f.tickers[symbol] {
+		f.tickers[symbol] = true
+		f.notify()
+	}
+}
+
+func (f *Feed) notify() {
+	select {
+	case f.changed <- struct{}{}:
+	default:
+	}
+}
+
+func (f *Feed) connect() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.connected = true
+}
+
+// disconnect drops every window, events may be missed until the next
+// connection.
+func (f *Feed) disconnect() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.connected = false
+	f.generation++
+	for _, s := range f.series {
+		s.live = false
+	}
+}
+
+func (f *Feed) onKline(e broker.KlineEvent) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.prices[e.Symbol] = price{value: e.Candle.Close, updatedAt: f.Now()}
+	if s, ok := f.series[broker.KlineStream{Symbol: e.Symbol, Interval: e.Interval}]; ok && !s.add(e.Candle, e.Closed) {
+		s.live = false
+	}
+	if e.Closed {
+		for _, fn := range f.onClose {
+			go fn(e.Symbol, e.Interval, e.Candle)
+		}
+	}
+}
+
+func (f *Feed) onTicker(t broker.Ticker) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.prices[t.Symbol] = price{value: t.Price, updatedAt: f.Now()}
+}
+
+// add merges an update of a candle into the window. It returns false when
+// candles were missed, the next candle arrived before the last one closed or
+// does not start where the last one ended.
+func (s *series) add(candle broker.Candle, closed bool) bool {
+	n := len(s.candles)
+	if n == 0 {
+		return false
+	}
+
+	lastCandle := s.candles[n-1]
+	switch {
+	case candle.OpenTime.Before(lastCandle.OpenTime):
+		return true
+	case candle.OpenTime.Equal(lastCandle.OpenTime):
+		s.candles[n-1] = candle
+	case s.final && candle.OpenTime.Equal(lastCandle.CloseTime.Add(time.Millisecond)):
+		s.candles = last(append(s.candles, candle), s.size)
+	default:
+		return false
+	}
+	s.final = closed
+	return true
+}
+
+// last returns a copy of the last n candles.
+func last(candles []broker.Candle, n int) []broker.Candle {
+	if len(candles) > n {
+		candles = candles[len(candles)-n:]
+	}
+	return append([]broker.Candle(nil), candles...)
+}
@@ -0,0 +1,184 @@
+package marketdata_test
+
+import (
+	"context"
+	"errors"
+	"go-trade-bot/internal/broker"
+	"go-trade-bot/internal/configuration"
+	"go-trade-bot/internal/marketdata"
+	"go-trade-bot/internal/marketdata/mocks"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var start = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// fakeStream connects at once and hands the handlers of every connection
+// to the test, it fails when an error is sent.
+type fakeStream struct {
+	served chan broker.StreamHandlers
+	fail   chan error
+}
+
+func (s *fakeStream) Serve(ctx context.Context, klines []broker.KlineStream, tickers []string, h broker.StreamHandlers) error {
+	h.Connected()
+	s.served <- h
+	select {
+	case <-ctx.Done():
+		return nil
+	case err := <-s.fail:
+		return err
+	}
+}
+
+func TestFeed_ListKline(t *testing.T) {
+	t.Run("should read from upstream when streaming is off", func(t *testing.T) {
+		upstream := new(mocks.Broker)
+		upstream.On("ListKline", mock.Anything, "BTCUSDT", "5m", 3).Return(candles(0, 3), nil).Twice()
+		feed := marketdata.New(upstream, nil, configuration.MarketData{Window: 500})
+
+		_, err := feed.ListKline(context.Background(), "BTCUSDT", "5m", 3)
+		assert.NoError(t, err)
+		result, err := feed.ListKline(context.Background(), "BTCUSDT", "5m", 3)
+		assert.NoError(t, err)
+		assert.Equal(t, candles(0, 3), result)
+		upstream.AssertExpectations(t)
+	})
+
+	t.Run("should serve the window from the stream once connected", func(t *testing.T) {
+		upstream := new(mocks.Broker)
+		upstream.On("ListKline", mock.Anything, "BTCUSDT", "5m", 500).Return(candles(0, 10), nil).Once()
+		feed, h, _ := run(t, upstream)
+
+		result, err := feed.ListKline(context.Background(), "BTCUSDT", "5m", 3)
+		assert.NoError(t, err)
+		assert.Equal(t, candles(7, 10), result)
+
+		h.Kline(event(9, true))
+		h.Kline(event(10, false))
+		result, err = feed.ListKline(context.Background(), "BTCUSDT", "5m", 3)
+		assert.NoError(t, err)
+		assert.Equal(t, candles(8, 11), result)
+		upstream.AssertExpectations(t)
+	})
+
+	t.Run("should fetch again after a gap", func(t *testing.T) {
+		upstream := new(mocks.Broker)
+		upstream.On("ListKline", mock.Anything, "BTCUSDT", "5m", 500).Return(candles(0, 10), nil).Once()
+		upstream.On("ListKline", mock.Anything, "BTCUSDT", "5m", 500).Return(candles(0, 13), nil).Once()
+		feed, h, _ := run(t, upstream)
+
+		_, err := feed.ListKline(context.Background(), "BTCUSDT", "5m", 3)
+		assert.NoError(t, err)
+
+		// The close of candle 9 was missed.
+		h.Kline(event(10, false))
+		result, err := feed.ListKline(context.Background(), "BTCUSDT", "5m", 3)
+		assert.NoError(t, err)
+		assert.Equal(t, candles(10, 13), result)
+		upstream.AssertExpectations(t)
+	})
+
+	t.Run("should fetch again after a reconnect", func(t *testing.T) {
+		upstream := new(mocks.Broker)
+		upstream.On("ListKline", mock.Anything, "BTCUSDT", "5m", 500).Return(candles(0, 10), nil).Twice()
+		feed, _, stream := run(t, upstream)
+
+		_, err := feed.ListKline(context.Background(), "BTCUSDT", "5m", 3)
+		assert.NoError(t, err)
+
+		stream.fail <- errors.New("connection reset")
+		<-stream.served
+		_, err = feed.ListKline(context.Background(), "BTCUSDT", "5m", 3)
+		assert.NoError(t, err)
+		upstream.AssertExpectations(t)
+	})
+}
+
+func TestFeed_OnCandleClose(t *testing.T) {
+	upstream := new(mocks.Broker)
+	stream := &fakeStream{served: make(chan broker.StreamHandlers), fail: make(chan error)}
+	feed := marketdata.New(upstream, stream, configuration.MarketData{Streaming: true, Window: 500})
+	closed := make(chan broker.Candle, 1)
+	feed.OnCandleClose(func(symbol string, interval string, candle broker.Candle) {
+		closed <- candle
+	})
+	feed.Subscribe("BTCUSDT", "5m")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go feed.Run(ctx)
+	h := <-stream.served
+
+	h.Kline(event(9, false))
+	h.Kline(event(9, true))
+	assert.Equal(t, candle(9), <-closed)
+	assert.Empty(t, closed)
+}
+
+func TestFeed_ListTickerPrices(t *testing.T) {
+	t.Run("should serve the streamed price while fresh", func(t *testing.T) {
+		upstream := new(mocks.Broker)
+		feed, h, _ := run(t, upstream)
+
+		h.Ticker(broker.Ticker{Symbol: "BTCUSDT", Price: 101})
+		result, err := feed.ListTickerPrices(context.Background(), "BTCUSDT")
+		assert.NoError(t, err)
+		assert.Equal(t, []broker.Ticker{{Symbol: "BTCUSDT", Price: 101}}, result)
+		upstream.AssertNotCalled(t, "ListTickerPrices", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should read stale prices from upstream", func(t *testing.T) {
+		upstream := new(mocks.Broker)
+		upstream.On("ListTickerPrices", mock.Anything, "BTCUSDT").Return([]broker.Ticker{{Symbol: "BTCUSDT", Price: 102}}, nil).Once()
+		feed, h, _ := run(t, upstream)
+
+		h.Ticker(broker.Ticker{Symbol: "BTCUSDT", Price: 101})
+		now := feed.Now().Add(time.Minute)
+		feed.Now = func() time.Time { return now }
+		result, err := feed.ListTickerPrices(context.Background(), "BTCUSDT")
+		assert.NoError(t, err)
+		assert.Equal(t, []broker.Ticker{{Symbol: "BTCUSDT", Price: 102}}, result)
+		upstream.AssertExpectations(t)
+	})
+}
+
+// run connects a feed subscribed to the 5m klines of BTCUSDT, while candle 9
+// is still open.
+func run(t *testing.T, upstream *mocks.Broker) (*marketdata.Feed, broker.StreamHandlers, *fakeStream) {
+	stream := &fakeStream{served: make(chan broker.StreamHandlers), fail: make(chan error)}
+	feed := marketdata.New(upstream, stream, configuration.MarketData{Streaming: true, Window: 500, StaleAfter: 30 * time.Second})
+	feed.RetryDelay = time.Millisecond
+	feed.Now = func() time.Time { return start.Add(47 * time.Minute) }
+	feed.Subscribe("BTCUSDT", "5m")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go feed.Run(ctx)
+	return feed, <-stream.served, stream
+}
+
+func candle(i int) broker.Candle {
+	open := start.Add(time.Duration(i) * 5 * time.Minute)
+	return broker.Candle{
+		OpenTime:  open,
+		CloseTime: open.Add(5*time.Minute - time.Millisecond),
+		Close:     float64(100 + i),
+	}
+}
+
+// candles returns the candles from i up to j excluded.
+func candles(i, j int) []broker.Candle {
+	result := []broker.Candle{}
+	for ; i < j; i++ {
+		result = append(result, candle(i))
+	}
+	return result
+}
+
+func event(i int, closed bool) broker.KlineEvent {
+	return broker.KlineEvent{Symbol: "BTCUSDT", Interval: "5m", Candle: candle(i), Closed: closed}
+}
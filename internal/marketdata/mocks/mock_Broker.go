@@ -0,0 +1,260 @@
+// Code generated by mockery v2.53.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	broker "go-trade-bot/internal/broker"
+	time "time"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// Broker is an autogenerated mock type for the Broker type
+type Broker struct {
+	mock.Mock
+}
+
+// CancelOpenOrders provides a mock function with given fields: ctx, symbol
+func (_m *Broker) CancelOpenOrders(ctx context.Context, symbol string) (int, error) {
+	ret := _m.Called(ctx, symbol)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CancelOpenOrders")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (int, error)); ok {
+		return rf(ctx, symbol)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) int); ok {
+		r0 = rf(ctx, symbol)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, symbol)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Get24hVolume provides a mock function with given fields: ctx, symbol
+func (_m *Broker) Get24hVolume(ctx context.Context, symbol string) (float64, error) {
+	ret := _m.Called(ctx, symbol)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Get24hVolume")
+	}
+
+	var r0 float64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (float64, error)); ok {
+		return rf(ctx, symbol)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) float64); ok {
+		r0 = rf(ctx, symbol)
+	} else {
+		r0 = ret.Get(0).(float64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, symbol)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetOrderBook provides a mock function with given fields: ctx, symbol, limit
+func (_m *Broker) GetOrderBook(ctx context.Context, symbol string, limit int) (broker.OrderBook, error) {
+	ret := _m.Called(ctx, symbol, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOrderBook")
+	}
+
+	var r0 broker.OrderBook
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) (broker.OrderBook, error)); ok {
+		return rf(ctx, symbol, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) broker.OrderBook); ok {
+		r0 = rf(ctx, symbol, limit)
+	} else {
+		r0 = ret.Get(0).(broker.OrderBook)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int) error); ok {
+		r1 = rf(ctx, symbol, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSymbolFilters provides a mock function with given fields: ctx, symbol
+func (_m *Broker) GetSymbolFilters(ctx context.Context, symbol string) (broker.SymbolFilters, error) {
+	ret := _m.Called(ctx, symbol)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSymbolFilters")
+	}
+
+	var r0 broker.SymbolFilters
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (broker.SymbolFilters, error)); ok {
+		return rf(ctx, symbol)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) broker.SymbolFilters); ok {
+		r0 = rf(ctx, symbol)
+	} else {
+		r0 = ret.Get(0).(broker.SymbolFilters)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, symbol)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListKline provides a mock function with given fields: ctx, symbol, interval, limit
+func (_m *Broker) ListKline(ctx context.Context, symbol string, interval string, limit int) ([]broker.Candle, error) {
+	ret := _m.Called(ctx, symbol, interval, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListKline")
+	}
+
+	var r0 []broker.Candle
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int) ([]broker.Candle, error)); ok {
+		return rf(ctx, symbol, interval, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int) []broker.Candle); ok {
+		r0 = rf(ctx, symbol, interval, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]broker.Candle)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, int) error); ok {
+		r1 = rf(ctx, symbol, interval, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListKlineRange provides a mock function with given fields: ctx, symbol, interval, start, limit
+func (_m *Broker) ListKlineRange(ctx context.Context, symbol string, interval string, start time.Time, limit int) ([]broker.Candle, error) {
+	ret := _m.Called(ctx, symbol, interval, start, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListKlineRange")
+	}
+
+	var r0 []broker.Candle
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Time, int) ([]broker.Candle, error)); ok {
+		return rf(ctx, symbol, interval, start, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Time, int) []broker.Candle); ok {
+		r0 = rf(ctx, symbol, interval, start, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]broker.Candle)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, time.Time, int) error); ok {
+		r1 = rf(ctx, symbol, interval, start, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListTickerPrices provides a mock function with given fields: ctx, symbol
+func (_m *Broker) ListTickerPrices(ctx context.Context, symbol string) ([]broker.Ticker, error) {
+	ret := _m.Called(ctx, symbol)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListTickerPrices")
+	}
+
+	var r0 []broker.Ticker
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]broker.Ticker, error)); ok {
+		return rf(ctx, symbol)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []broker.Ticker); ok {
+		r0 = rf(ctx, symbol)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]broker.Ticker)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, symbol)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PlaceOrder provides a mock function with given fields: ctx, order
+func (_m *Broker) PlaceOrder(ctx context.Context, order broker.OrderRequest) (broker.OrderResult, error) {
+	ret := _m.Called(ctx, order)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PlaceOrder")
+	}
+
+	var r0 broker.OrderResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, broker.OrderRequest) (broker.OrderResult, error)); ok {
+		return rf(ctx, order)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, broker.OrderRequest) broker.OrderResult); ok {
+		r0 = rf(ctx, order)
+	} else {
+		r0 = ret.Get(0).(broker.OrderResult)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, broker.OrderRequest) error); ok {
+		r1 = rf(ctx, order)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewBroker creates a new instance of Broker. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewBroker(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Broker {
+	mock := &Broker{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
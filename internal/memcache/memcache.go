@@ -1,41 +1,87 @@
 package memcache
 
 import (
+	"context"
+	"encoding/json"
+	"go-trade-bot/internal/configuration"
+	"log"
 	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
 )
 
+// Cache stores values as JSON, Get decodes them into target so both
+// implementations hand back the same types. A ttl of zero never expires.
 type Cache interface {
-	Get(key string) (any, bool)
-	Set(key string, value any)
-	Delete(key string)
+	Get(ctx context.Context, key string, target any) (bool, error)
+	Set(ctx context.Context, key string, value any, ttl time.Duration) error
+	Delete(ctx context.Context, key string) error
+}
+
+// NewCache returns the cache selected by CACHE.DRIVER, the Redis one is
+// shared by every worker and survives restarts.
+func NewCache(cfg *configuration.Configuration) Cache {
+	if cfg.Cache.Driver == configuration.RedisCache {
+		log.Printf("Using redis cache with namespace %s", cfg.Cache.Namespace)
+		client := redis.NewClient(&redis.Options{Addr: cfg.Redis.Addr})
+		return NewRedisCache(client, cfg.Cache.Namespace)
+	}
+	return NewInMemoryCache()
+}
+
+type entry struct {
+	data      []byte
+	expiresAt time.Time
 }
 
 type inMemoryCache struct {
-	data map[string]any
+	data map[string]entry
 	mu   sync.RWMutex
+	now  func() time.Time
 }
 
 func NewInMemoryCache() Cache {
 	return &inMemoryCache{
-		data: make(map[string]any),
+		data: make(map[string]entry),
+		now:  time.Now,
 	}
 }
 
-func (c *inMemoryCache) Get(key string) (any, bool) {
+func (c *inMemoryCache) Get(ctx context.Context, key string, target any) (bool, error) {
 	c.mu.RLock()
-	defer c.mu.RUnlock()
-	val, ok := c.data[key]
-	return val, ok
+	e, ok := c.data[key]
+	c.mu.RUnlock()
+	if !ok {
+		return false, nil
+	}
+	if !e.expiresAt.IsZero() && !c.now().Before(e.expiresAt) {
+		c.Delete(ctx, key)
+		return false, nil
+	}
+	return true, json.Unmarshal(e.data, target)
 }
 
-func (c *inMemoryCache) Set(key string, value any) {
+func (c *inMemoryCache) Set(ctx context.Context, key string, value any, ttl time.Duration) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	e := entry{data: data}
+	if ttl > 0 {
+		e.expiresAt = c.now().Add(ttl)
+	}
+
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	c.data[key] = value
+	c.data[key] = e
+	return nil
 }
 
-func (c *inMemoryCache) Delete(key string) {
+func (c *inMemoryCache) Delete(ctx context.Context, key string) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	delete(c.data, key)
+	return nil
 }
@@ -0,0 +1,107 @@
+package memcache_test
+
+import (
+	"context"
+	"errors"
+	"go-trade-bot/internal/memcache"
+	"go-trade-bot/internal/memcache/mocks"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type level struct {
+	Type  string
+	Price float64
+}
+
+func TestInMemoryCache(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should decode values into the target type", func(t *testing.T) {
+		cache := memcache.NewInMemoryCache()
+		assert.NoError(t, cache.Set(ctx, "levels", []level{{Type: "buy", Price: 10}}, 0))
+
+		var levels []level
+		ok, err := cache.Get(ctx, "levels", &levels)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, []level{{Type: "buy", Price: 10}}, levels)
+	})
+
+	t.Run("should expire values after their ttl", func(t *testing.T) {
+		cache := memcache.NewInMemoryCache()
+		assert.NoError(t, cache.Set(ctx, "price", 10.5, 10*time.Millisecond))
+
+		var price float64
+		ok, _ := cache.Get(ctx, "price", &price)
+		assert.True(t, ok)
+
+		time.Sleep(20 * time.Millisecond)
+		ok, err := cache.Get(ctx, "price", &price)
+		assert.NoError(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("should miss deleted keys", func(t *testing.T) {
+		cache := memcache.NewInMemoryCache()
+		assert.NoError(t, cache.Set(ctx, "price", 10.5, 0))
+		assert.NoError(t, cache.Delete(ctx, "price"))
+
+		var price float64
+		ok, err := cache.Get(ctx, "price", &price)
+		assert.NoError(t, err)
+		assert.False(t, ok)
+	})
+}
+
+func TestRedisCache(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should store JSON under the namespace with the ttl", func(t *testing.T) {
+		client := new(mocks.RedisClient)
+		cache := memcache.NewRedisCache(client, "bot")
+		client.On("Set", ctx, "bot:price", []byte("10.5"), time.Minute).Return(redis.NewStatusResult("OK", nil)).Once()
+
+		assert.NoError(t, cache.Set(ctx, "price", 10.5, time.Minute))
+		client.AssertExpectations(t)
+	})
+
+	t.Run("should decode stored values", func(t *testing.T) {
+		client := new(mocks.RedisClient)
+		cache := memcache.NewRedisCache(client, "bot")
+		client.On("Get", ctx, "bot:levels").Return(redis.NewStringResult(`[{"Type":"sell","Price":12}]`, nil)).Once()
+
+		var levels []level
+		ok, err := cache.Get(ctx, "levels", &levels)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, []level{{Type: "sell", Price: 12}}, levels)
+	})
+
+	t.Run("should miss absent keys", func(t *testing.T) {
+		client := new(mocks.RedisClient)
+		cache := memcache.NewRedisCache(client, "bot")
+		client.On("Get", ctx, "bot:price").Return(redis.NewStringResult("", redis.Nil)).Once()
+
+		var price float64
+		ok, err := cache.Get(ctx, "price", &price)
+		assert.NoError(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("should return redis errors", func(t *testing.T) {
+		client := new(mocks.RedisClient)
+		cache := memcache.NewRedisCache(client, "bot")
+		client.On("Get", ctx, "bot:price").Return(redis.NewStringResult("", errors.New("connection refused"))).Once()
+		client.On("Del", ctx, mock.Anything).Return(redis.NewIntResult(0, errors.New("connection refused"))).Once()
+
+		var price float64
+		_, err := cache.Get(ctx, "price", &price)
+		assert.EqualError(t, err, "connection refused")
+		assert.EqualError(t, cache.Delete(ctx, "price"), "connection refused")
+	})
+}
@@ -0,0 +1,60 @@
+package memcache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type RedisClient interface {
+	Get(ctx context.Context, key string) *redis.StringCmd
+	Set(ctx context.Context, key string, value interface{}, expiration time.Duration) *redis.StatusCmd
+	Del(ctx context.Context, keys ...string) *redis.IntCmd
+}
+
+// redisCache prefixes its keys with the namespace, so the bot can share a
+// Redis with asynq and with other deployments.
+type redisCache struct {
+	client    RedisClient
+	namespace string
+}
+
+func NewRedisCache(client RedisClient, namespace string) Cache {
+	return &redisCache{
+		client:    client,
+		namespace: namespace,
+	}
+}
+
+func (c *redisCache) Get(ctx context.Context, key string, target any) (bool, error) {
+	data, err := c.client.Get(ctx, c.key(key)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, json.Unmarshal(data, target)
+}
+
+func (c *redisCache) Set(ctx context.Context, key string, value any, ttl time.Duration) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return c.client.Set(ctx, c.key(key), data, ttl).Err()
+}
+
+func (c *redisCache) Delete(ctx context.Context, key string) error {
+	return c.client.Del(ctx, c.key(key)).Err()
+}
+
+func (c *redisCache) key(key string) string {
+	if c.namespace == "" {
+		return key
+	}
+	return c.namespace + ":" + key
+}
@@ -0,0 +1,126 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-trade-bot/internal/configuration"
+	"go-trade-bot/internal/metrics"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	broker_request_weight     = "broker_request_weight"
+	broker_rate_limited_total = "broker_rate_limited_total"
+	broker_retries_total      = "broker_retries_total"
+)
+
+// ErrPaused is returned while the exchange asked to stop calling it.
+var ErrPaused = errors.New("requests paused by the exchange rate limit")
+
+// Limiter spends the request weight budget of an API in one minute windows,
+// like the exchange counts it.
+type Limiter struct {
+	api     string
+	budget  int64
+	store   Store
+	metrics *metrics.MetricsCollector
+	Now     func() time.Time
+}
+
+// Limiters are the limiters of the spot and futures APIs, which have their
+// own budgets.
+type Limiters struct {
+	Spot    *Limiter
+	Futures *Limiter
+}
+
+func NewLimiters(cfg *configuration.Configuration, collector *metrics.MetricsCollector) Limiters {
+	client := redis.NewClient(&redis.Options{Addr: cfg.Redis.Addr})
+	store := NewRedisStore(client, cfg.Cache.Namespace)
+	return Limiters{
+		Spot:    New("spot", cfg.RateLimit.SpotWeight, store, collector),
+		Futures: New("futures", cfg.RateLimit.FuturesWeight, store, collector),
+	}
+}
+
+func New(api string, budget int, store Store, collector *metrics.MetricsCollector) *Limiter {
+	return &Limiter{
+		api:     api,
+		budget:  int64(budget),
+		store:   store,
+		metrics: collector,
+		Now:     time.Now,
+	}
+}
+
+// Wait reserves weight in the current window, waiting for the next one once
+// the budget is spent. It fails with ErrPaused while the exchange asked to
+// stop. Calls go through when the store is unreachable, the exchange still
+// enforces its own limits.
+func (l *Limiter) Wait(ctx context.Context, weight int) error {
+	for {
+		now := l.Now()
+		until, err := l.store.Get(ctx, l.pauseKey())
+		if err != nil {
+			log.Printf("Failed to read the %s rate limit: %v", l.api, err)
+			return nil
+		}
+		if now.Before(time.UnixMilli(until)) {
+			return fmt.Errorf("%w until %s", ErrPaused, time.UnixMilli(until).UTC().Format(time.RFC3339))
+		}
+
+		window := now.Truncate(time.Minute)
+		used, err := l.store.IncrBy(ctx, l.weightKey(window), int64(weight), 2*time.Minute)
+		if err != nil {
+			log.Printf("Failed to reserve %s request weight: %v", l.api, err)
+			return nil
+		}
+		// A request heavier than the budget still goes alone.
+		if used <= l.budget || used == int64(weight) {
+			l.metrics.SetGauge(broker_request_weight, map[string]string{"api": l.api}, float64(used))
+			return nil
+		}
+		if _, err := l.store.IncrBy(ctx, l.weightKey(window), -int64(weight), 2*time.Minute); err != nil {
+			log.Printf("Failed to release %s request weight: %v", l.api, err)
+		}
+
+		select {
+		case <-time.After(window.Add(time.Minute).Sub(now)):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Observe records the weight the exchange counted in the current window,
+// which includes the calls of other clients sharing the IP.
+func (l *Limiter) Observe(ctx context.Context, used int) {
+	window := l.Now().Truncate(time.Minute)
+	if err := l.store.Max(ctx, l.weightKey(window), int64(used), 2*time.Minute); err != nil {
+		log.Printf("Failed to record %s request weight: %v", l.api, err)
+		return
+	}
+	l.metrics.SetGauge(broker_request_weight, map[string]string{"api": l.api}, float64(used))
+}
+
+// Pause stops every call until the given time, on every replica.
+func (l *Limiter) Pause(ctx context.Context, until time.Time, status int) {
+	log.Printf("Binance %s API rate limited with status %d, pausing calls until %s", l.api, status, until.UTC().Format(time.RFC3339))
+	l.metrics.IncrementCounter(broker_rate_limited_total, map[string]string{"api": l.api, "status": strconv.Itoa(status)})
+	if err := l.store.Set(ctx, l.pauseKey(), until.UnixMilli(), until.Sub(l.Now())); err != nil {
+		log.Printf("Failed to pause the %s API: %v", l.api, err)
+	}
+}
+
+func (l *Limiter) weightKey(window time.Time) string {
+	return fmt.Sprintf("ratelimit:%s:weight:%d", l.api, window.Unix())
+}
+
+func (l *Limiter) pauseKey() string {
+	return "ratelimit:" + l.api + ":paused"
+}
@@ -0,0 +1,62 @@
+package ratelimit_test
+
+import (
+	"context"
+	"go-trade-bot/internal/metrics"
+	"go-trade-bot/internal/ratelimit"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimiter_Wait(t *testing.T) {
+	t.Run("should let requests through within the budget", func(t *testing.T) {
+		limiter := newLimiter(10)
+
+		assert.NoError(t, limiter.Wait(context.Background(), 6))
+		assert.NoError(t, limiter.Wait(context.Background(), 4))
+	})
+
+	t.Run("should wait for the next minute once the budget is spent", func(t *testing.T) {
+		limiter := newLimiter(10)
+		assert.NoError(t, limiter.Wait(context.Background(), 8))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		assert.ErrorIs(t, limiter.Wait(ctx, 4), context.DeadlineExceeded)
+		// The weight of the request waiting was given back.
+		assert.NoError(t, limiter.Wait(context.Background(), 2))
+	})
+
+	t.Run("should let a request heavier than the budget go alone", func(t *testing.T) {
+		limiter := newLimiter(10)
+
+		assert.NoError(t, limiter.Wait(context.Background(), 20))
+	})
+
+	t.Run("should count the weight reported by the exchange", func(t *testing.T) {
+		limiter := newLimiter(10)
+		limiter.Observe(context.Background(), 9)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		assert.ErrorIs(t, limiter.Wait(ctx, 2), context.DeadlineExceeded)
+	})
+
+	t.Run("should refuse requests while paused", func(t *testing.T) {
+		limiter := newLimiter(10)
+		limiter.Pause(context.Background(), limiter.Now().Add(time.Minute), 429)
+
+		err := limiter.Wait(context.Background(), 1)
+		assert.ErrorIs(t, err, ratelimit.ErrPaused)
+		assert.EqualError(t, err, "requests paused by the exchange rate limit until 2024-01-01T10:01:30Z")
+	})
+}
+
+// newLimiter returns a limiter in the middle of a minute.
+func newLimiter(budget int) *ratelimit.Limiter {
+	limiter := ratelimit.New("spot", budget, ratelimit.NewMemoryStore(), metrics.NewMetricsCollector(nil))
+	limiter.Now = func() time.Time { return time.Date(2024, 1, 1, 10, 0, 30, 0, time.UTC) }
+	return limiter
+}
@@ -0,0 +1,130 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Store keeps the counters of the limiter, in Redis so every replica
+// spends from the same budget.
+type Store interface {
+	// IncrBy adds value to the counter and returns its total.
+	IncrBy(ctx context.Context, key string, value int64, ttl time.Duration) (int64, error)
+	// Max raises the counter to value when it is lower.
+	Max(ctx context.Context, key string, value int64, ttl time.Duration) error
+	Set(ctx context.Context, key string, value int64, ttl time.Duration) error
+	// Get returns zero for missing counters.
+	Get(ctx context.Context, key string) (int64, error)
+}
+
+var maxScript = redis.NewScript(`
+local current = tonumber(redis.call("GET", KEYS[1]) or "0")
+if tonumber(ARGV[1]) > current then
+	redis.call("SET", KEYS[1], ARGV[1], "PX", ARGV[2])
+end
+return 0
+`)
+
+type redisStore struct {
+	client    *redis.Client
+	namespace string
+}
+
+func NewRedisStore(client *redis.Client, namespace string) Store {
+	return &redisStore{
+		client:    client,
+		namespace: namespace,
+	}
+}
+
+func (s *redisStore) IncrBy(ctx context.Context, key string, value int64, ttl time.Duration) (int64, error) {
+	pipe := s.client.TxPipeline()
+	incr := pipe.IncrBy(ctx, s.key(key), value)
+	pipe.PExpire(ctx, s.key(key), ttl)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, err
+	}
+	return incr.Val(), nil
+}
+
+func (s *redisStore) Max(ctx context.Context, key string, value int64, ttl time.Duration) error {
+	return maxScript.Run(ctx, s.client, []string{s.key(key)}, value, ttl.Milliseconds()).Err()
+}
+
+func (s *redisStore) Set(ctx context.Context, key string, value int64, ttl time.Duration) error {
+	return s.client.Set(ctx, s.key(key), value, ttl).Err()
+}
+
+func (s *redisStore) Get(ctx context.Context, key string) (int64, error) {
+	value, err := s.client.Get(ctx, s.key(key)).Int64()
+	if errors.Is(err, redis.Nil) {
+		return 0, nil
+	}
+	return value, err
+}
+
+func (s *redisStore) key(key string) string {
+	if s.namespace == "" {
+		return key
+	}
+	return s.namespace + ":" + key
+}
+
+type counter struct {
+	value     int64
+	expiresAt time.Time
+}
+
+// memoryStore limits a single process, for tests and tools.
+type memoryStore struct {
+	mu       sync.Mutex
+	counters map[string]counter
+}
+
+func NewMemoryStore() Store {
+	return &memoryStore{counters: map[string]counter{}}
+}
+
+func (s *memoryStore) IncrBy(ctx context.Context, key string, value int64, ttl time.Duration) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c := s.get(key)
+	c.value += value
+	c.expiresAt = time.Now().Add(ttl)
+	s.counters[key] = c
+	return c.value, nil
+}
+
+func (s *memoryStore) Max(ctx context.Context, key string, value int64, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if value > s.get(key).value {
+		s.counters[key] = counter{value: value, expiresAt: time.Now().Add(ttl)}
+	}
+	return nil
+}
+
+func (s *memoryStore) Set(ctx context.Context, key string, value int64, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.counters[key] = counter{value: value, expiresAt: time.Now().Add(ttl)}
+	return nil
+}
+
+func (s *memoryStore) Get(ctx context.Context, key string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.get(key).value, nil
+}
+
+func (s *memoryStore) get(key string) counter {
+	c, ok := s.counters[key]
+	if !ok || !time.Now().Before(c.expiresAt) {
+		return counter{}
+	}
+	return c
+}
@@ -0,0 +1,94 @@
+package ratelimit
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// usedWeightHeader is the weight Binance counted for the IP in the current
+// minute.
+const usedWeightHeader = "X-MBX-USED-WEIGHT-1M"
+
+// Transport limits the requests sent to the exchange. Each request waits
+// for its weight, a 418 (IP banned) or 429 (limit hit) pauses every call for
+// the time the exchange asks, and reads failing with a network error or a
+// 5xx are retried with an exponential backoff. Orders are never retried.
+type Transport struct {
+	Base       http.RoundTripper
+	Limiter    *Limiter
+	Weight     func(r *http.Request) int
+	MaxRetries int
+	Backoff    time.Duration
+}
+
+// NewClient returns an HTTP client for the exchange client that goes
+// through a Transport.
+func NewClient(l *Limiter, weight func(r *http.Request) int, maxRetries int) *http.Client {
+	return &http.Client{
+		Transport: &Transport{
+			Base:       http.DefaultTransport,
+			Limiter:    l,
+			Weight:     weight,
+			MaxRetries: maxRetries,
+			Backoff:    200 * time.Millisecond,
+		},
+	}
+}
+
+func (t *Transport) RoundTrip(r *http.Request) (*http.Response, error) {
+	ctx := r.Context()
+	weight := t.Weight(r)
+	for attempt := 0; ; attempt++ {
+		if err := t.Limiter.Wait(ctx, weight); err != nil {
+			return nil, err
+		}
+
+		res, err := t.Base.RoundTrip(r)
+		if err == nil {
+			if used, parseErr := strconv.Atoi(res.Header.Get(usedWeightHeader)); parseErr == nil {
+				t.Limiter.Observe(ctx, used)
+			}
+			if res.StatusCode == http.StatusTeapot || res.StatusCode == http.StatusTooManyRequests {
+				t.Limiter.Pause(ctx, t.retryAfter(res), res.StatusCode)
+				return res, nil
+			}
+		}
+
+		retryable := r.Method == http.MethodGet && ctx.Err() == nil &&
+			(err != nil || res.StatusCode >= http.StatusInternalServerError)
+		if !retryable || attempt >= t.MaxRetries {
+			return res, err
+		}
+		if err == nil {
+			res.Body.Close()
+		}
+
+		delay := t.Backoff << attempt
+		log.Printf("Retrying %s in %s after attempt %d failed: %v", r.URL.Path, delay, attempt+1, failure(res, err))
+		t.Limiter.metrics.IncrementCounter(broker_retries_total, map[string]string{"api": t.Limiter.api})
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// retryAfter reads the pause asked by the exchange, until the end of the
+// minute when it gives none.
+func (t *Transport) retryAfter(res *http.Response) time.Time {
+	now := t.Limiter.Now()
+	if seconds, err := strconv.Atoi(res.Header.Get("Retry-After")); err == nil && seconds > 0 {
+		return now.Add(time.Duration(seconds) * time.Second)
+	}
+	return now.Truncate(time.Minute).Add(time.Minute)
+}
+
+func failure(res *http.Response, err error) any {
+	if err != nil {
+		return err
+	}
+	return res.Status
+}
@@ -0,0 +1,94 @@
+package ratelimit_test
+
+import (
+	"context"
+	"go-trade-bot/internal/ratelimit"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransport_RoundTrip(t *testing.T) {
+	t.Run("should retry failed reads with a backoff", func(t *testing.T) {
+		var calls atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if calls.Add(1) < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		res, err := newClient(newLimiter(100)).Get(server.URL + "/api/v3/klines")
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, int32(3), calls.Load())
+	})
+
+	t.Run("should not retry orders", func(t *testing.T) {
+		var calls atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		res, err := newClient(newLimiter(100)).Post(server.URL+"/api/v3/order", "application/x-www-form-urlencoded", strings.NewReader(""))
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusServiceUnavailable, res.StatusCode)
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("should pause calls when rate limited", func(t *testing.T) {
+		var calls atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			w.Header().Set("Retry-After", "30")
+			w.WriteHeader(http.StatusTooManyRequests)
+		}))
+		defer server.Close()
+		client := newClient(newLimiter(100))
+
+		res, err := client.Get(server.URL + "/api/v3/klines")
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusTooManyRequests, res.StatusCode)
+
+		_, err = client.Get(server.URL + "/api/v3/klines")
+		assert.ErrorIs(t, err, ratelimit.ErrPaused)
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("should track the weight used by the exchange", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-MBX-USED-WEIGHT-1M", "99")
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+		limiter := newLimiter(100)
+
+		_, err := newClient(limiter).Get(server.URL + "/api/v3/klines")
+		assert.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		assert.Error(t, limiter.Wait(ctx, 2))
+	})
+}
+
+func newClient(limiter *ratelimit.Limiter) *http.Client {
+	return &http.Client{Transport: &ratelimit.Transport{
+		Base:       http.DefaultTransport,
+		Limiter:    limiter,
+		Weight:     func(r *http.Request) int { return 1 },
+		MaxRetries: 3,
+		Backoff:    time.Millisecond,
+	}}
+}